@@ -0,0 +1,91 @@
+// Package autotitle fills in missing conversation titles and summaries in the background
+package autotitle
+
+import (
+	"chatgo/internal/llm"
+	"chatgo/pkg/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxPromptChars limits how much of a conversation is sent to the provider
+const maxPromptChars = 6000
+
+// defaultTitlePattern matches titles assigned automatically by createNewConversation
+var defaultTitlePattern = regexp.MustCompile(`^Chat-\d{14}$`)
+
+const generatePrompt = `Read the conversation below and reply with only a JSON object of the form
+{"title": "...", "summary": "..."}.
+The title must be at most 8 words. The summary must be one or two sentences.
+Write both in the main language of the conversation.`
+
+// NeedsTitle reports whether a conversation still has a missing or automatically assigned title
+func NeedsTitle(conv *models.Conversation) bool {
+	title := strings.TrimSpace(conv.Title)
+	return title == "" || defaultTitlePattern.MatchString(title)
+}
+
+// NeedsWork reports whether a conversation should be processed by the queue
+func NeedsWork(conv *models.Conversation) bool {
+	// Need at least one exchange to have something to describe
+	if conv.ReadOnly() || len(conv.Messages) < 2 {
+		return false
+	}
+	return NeedsTitle(conv) || strings.TrimSpace(conv.Summary) == ""
+}
+
+// Generate asks the provider for a title and summary of conv
+func Generate(ctx context.Context, client *llm.Client, conv *models.Conversation) (title, summary string, err error) {
+	var transcript strings.Builder
+	for _, msg := range conv.Messages {
+		if msg.Role == "system" {
+			continue
+		}
+		fmt.Fprintf(&transcript, "%s: %s\n\n", msg.Role, msg.Content)
+		if transcript.Len() > maxPromptChars {
+			break
+		}
+	}
+	text := transcript.String()
+	if runes := []rune(text); len(runes) > maxPromptChars {
+		text = string(runes[:maxPromptChars])
+	}
+
+	response, err := client.Chat(ctx, []llm.ChatMessage{
+		{Role: "system", Content: generatePrompt},
+		{Role: "user", Content: text},
+	}, nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	return parseResponse(response.Content)
+}
+
+// parseResponse extracts the title and summary from the provider's reply,
+// tolerating surrounding prose or code fences
+func parseResponse(content string) (string, string, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return "", "", fmt.Errorf("no JSON object in response")
+	}
+
+	var result struct {
+		Title   string `json:"title"`
+		Summary string `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &result); err != nil {
+		return "", "", fmt.Errorf("invalid response: %w", err)
+	}
+
+	title := strings.Trim(strings.TrimSpace(result.Title), `"`)
+	summary := strings.TrimSpace(result.Summary)
+	if title == "" || summary == "" {
+		return "", "", fmt.Errorf("response is missing title or summary")
+	}
+	return title, summary, nil
+}
@@ -0,0 +1,335 @@
+package autotitle
+
+import (
+	"chatgo/internal/llm"
+	"chatgo/internal/log"
+	"chatgo/pkg/models"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// logger logs background title and summary requests
+var logger = log.New("titles")
+
+const (
+	// DefaultRatePerMinute is used when no rate is configured
+	DefaultRatePerMinute = 5
+
+	// requestTimeout bounds a single title and summary request
+	requestTimeout = 2 * time.Minute
+
+	// retryBaseDelay is the backoff after the first failure; it doubles per attempt up to maxRetryDelay
+	retryBaseDelay = 5 * time.Minute
+	maxRetryDelay  = 6 * time.Hour
+
+	// stateFileName is stored next to the conversations directory so it isn't listed as a conversation
+	stateFileName = "title_queue.json"
+)
+
+// ClientFactory returns the client used for a request; it is called once per item
+// so provider and proxy changes apply without restarting the queue
+type ClientFactory func() (*llm.Client, error)
+
+// Progress describes the queue state for display
+type Progress struct {
+	Pending   int  // Conversations still needing a title or summary, including ones waiting to retry
+	Completed int  // Conversations updated since the queue started
+	Paused    bool // Whether processing is paused
+}
+
+// failure records a failed item so it is retried later with backoff
+type failure struct {
+	Attempts int       `json:"attempts"`
+	RetryAt  time.Time `json:"retry_at"`
+}
+
+// queueState is persisted so pausing and backoff survive restarts
+type queueState struct {
+	Paused   bool                `json:"paused"`
+	Failures map[string]*failure `json:"failures,omitempty"`
+}
+
+// Queue walks conversations without a real title or summary and fills them in
+// one at a time at a limited rate
+type Queue struct {
+	convManager *models.ConversationManager
+	newClient   ClientFactory
+	statePath   string
+
+	mu         sync.Mutex
+	state      queueState
+	completed  int
+	activeID   string // Conversation open in the UI, skipped while active
+	interval   time.Duration
+	ticker     *time.Ticker
+	onProgress func(Progress)
+	stop       chan struct{}
+}
+
+// NewQueue creates a queue that processes ratePerMinute conversations per minute.
+// Persisted state from a previous run is restored.
+func NewQueue(convManager *models.ConversationManager, newClient ClientFactory, ratePerMinute int) *Queue {
+	q := &Queue{
+		convManager: convManager,
+		newClient:   newClient,
+		statePath:   filepath.Join(filepath.Dir(convManager.DataDir()), stateFileName),
+		interval:    rateInterval(ratePerMinute),
+		state:       queueState{Failures: make(map[string]*failure)},
+	}
+
+	if data, err := os.ReadFile(q.statePath); err == nil {
+		if err := json.Unmarshal(data, &q.state); err != nil {
+			logger.Warn("Ignoring invalid queue state", "error", err)
+		}
+		if q.state.Failures == nil {
+			q.state.Failures = make(map[string]*failure)
+		}
+	}
+
+	return q
+}
+
+// rateInterval converts a per-minute rate into the delay between items
+func rateInterval(ratePerMinute int) time.Duration {
+	if ratePerMinute <= 0 {
+		ratePerMinute = DefaultRatePerMinute
+	}
+	return time.Minute / time.Duration(ratePerMinute)
+}
+
+// SetOnProgress sets a function called from the queue goroutine whenever progress changes
+func (q *Queue) SetOnProgress(onProgress func(Progress)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onProgress = onProgress
+}
+
+// SetActiveConversation tells the queue which conversation is open so it isn't modified underneath the user
+func (q *Queue) SetActiveConversation(id string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.activeID = id
+}
+
+// SetRate changes how many conversations are processed per minute
+func (q *Queue) SetRate(ratePerMinute int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.interval = rateInterval(ratePerMinute)
+	if q.ticker != nil {
+		q.ticker.Reset(q.interval)
+	}
+}
+
+// SetPaused pauses or resumes processing; the setting is persisted
+func (q *Queue) SetPaused(paused bool) {
+	q.mu.Lock()
+	q.state.Paused = paused
+	q.saveStateLocked()
+	q.mu.Unlock()
+
+	q.reportProgress()
+}
+
+// Paused reports whether processing is paused
+func (q *Queue) Paused() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.state.Paused
+}
+
+// Start begins processing in a background goroutine
+func (q *Queue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stop != nil {
+		return
+	}
+
+	q.stop = make(chan struct{})
+	q.ticker = time.NewTicker(q.interval)
+	go q.run(q.ticker, q.stop)
+}
+
+// Stop ends processing; an item in progress is abandoned and retried next time
+func (q *Queue) Stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stop == nil {
+		return
+	}
+
+	close(q.stop)
+	q.ticker.Stop()
+	q.stop = nil
+	q.ticker = nil
+}
+
+// run processes one conversation per tick until stopped
+func (q *Queue) run(ticker *time.Ticker, stop chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	q.reportProgress()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !q.Paused() {
+				q.processNext(ctx)
+			}
+		}
+	}
+}
+
+// candidates returns conversations needing work, oldest first, and how many of them are ready now
+func (q *Queue) candidates() ([]models.Conversation, int) {
+	conversations, err := q.convManager.ListConversations(models.ListOptions{})
+	if err != nil {
+		logger.Warn("Failed to list conversations", "error", err)
+		return nil, 0
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	var ready []models.Conversation
+	pending := 0
+	for i := range conversations {
+		conv := &conversations[i]
+		if !NeedsWork(conv) {
+			continue
+		}
+		pending++
+		if conv.ID == q.activeID || q.convManager.SavingPaused(conv.ID) {
+			continue
+		}
+		if f, ok := q.state.Failures[conv.ID]; ok && now.Before(f.RetryAt) {
+			continue
+		}
+		ready = append(ready, *conv)
+	}
+
+	sort.Slice(ready, func(i, j int) bool {
+		return ready[i].CreatedAt.Before(ready[j].CreatedAt)
+	})
+	return ready, pending
+}
+
+// processNext fills in the oldest ready conversation
+func (q *Queue) processNext(ctx context.Context) {
+	ready, _ := q.candidates()
+	if len(ready) == 0 {
+		q.reportProgress()
+		return
+	}
+	conv := ready[0]
+
+	if err := q.process(ctx, &conv); err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("Failed to title conversation", "id", conv.ID, "error", err)
+		q.recordFailure(conv.ID)
+	} else {
+		q.mu.Lock()
+		delete(q.state.Failures, conv.ID)
+		q.completed++
+		q.saveStateLocked()
+		q.mu.Unlock()
+	}
+
+	q.reportProgress()
+}
+
+// process generates and saves the title and summary of one conversation
+func (q *Queue) process(ctx context.Context, conv *models.Conversation) error {
+	client, err := q.newClient()
+	if err != nil {
+		return err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	title, summary, err := Generate(reqCtx, client, conv)
+	if err != nil {
+		return err
+	}
+
+	// Reload so edits made while the request was running aren't lost
+	latest, err := q.convManager.LoadConversation(conv.ID)
+	if err != nil {
+		return err
+	}
+	if NeedsTitle(latest) {
+		latest.Title = title
+	}
+	if latest.Summary == "" {
+		latest.Summary = summary
+	}
+	return q.convManager.SaveConversation(latest)
+}
+
+// recordFailure schedules a retry with exponential backoff
+func (q *Queue) recordFailure(id string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	f, ok := q.state.Failures[id]
+	if !ok {
+		f = &failure{}
+		q.state.Failures[id] = f
+	}
+	delay := retryBaseDelay << f.Attempts
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	f.Attempts++
+	f.RetryAt = time.Now().Add(delay)
+	q.saveStateLocked()
+}
+
+// reportProgress computes the current progress and passes it to the progress callback
+func (q *Queue) reportProgress() {
+	q.mu.Lock()
+	onProgress := q.onProgress
+	q.mu.Unlock()
+	if onProgress == nil {
+		return
+	}
+
+	_, pending := q.candidates()
+
+	q.mu.Lock()
+	progress := Progress{
+		Pending:   pending,
+		Completed: q.completed,
+		Paused:    q.state.Paused,
+	}
+	q.mu.Unlock()
+
+	onProgress(progress)
+}
+
+// saveStateLocked persists the queue state; q.mu must be held
+func (q *Queue) saveStateLocked() {
+	data, err := json.MarshalIndent(q.state, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(q.statePath, data, 0644); err != nil {
+		logger.Warn("Failed to save queue state", "error", err)
+	}
+}
@@ -0,0 +1,60 @@
+// Package calculator evaluates arithmetic expressions for the built-in calculator tool.
+//
+// Numbers are exact rationals of arbitrary size, quantities carry units of length, mass,
+// duration or data size, and dates can be moved by durations or subtracted from each other.
+// It is an expression evaluator only; there are no variables, loops or access to the system.
+package calculator
+
+import (
+	"fmt"
+	"time"
+)
+
+// Description explains the expression syntax to the model
+const Description = `Calculator - Evaluate arithmetic exactly, with big numbers, unit conversions and date arithmetic.
+Operators: + - * / % ^ and parentheses. Functions: sqrt, abs, floor, ceil, round(x[, digits]), min, max, factorial, ln, log, log2, exp, sin, cos, tan. Constants: pi, e.
+Units: length (mm, cm, m, km, inch, ft, yd, mi), mass (mg, g, kg, t, oz, lb), duration (ms, s, min, h, day, week, month, year), data (bit, B, KB, MB, GB, TB, KiB, MiB, GiB, TiB). Convert with "to", e.g. "5 km + 300 m to mi".
+Dates: YYYY-MM-DD, YYYY-MM-DDTHH:MM, today, now, tomorrow, yesterday, e.g. "2024-01-31 + 1 month" or "2025-12-25 - today".
+The result includes the expression as it was interpreted, fully parenthesized.`
+
+// Result is an evaluated expression
+type Result struct {
+	Interpretation string // The expression as parsed, with explicit grouping
+	Value          string // The formatted result; approximate values start with ≈
+}
+
+// String formats the result for the model
+func (r *Result) String() string {
+	return fmt.Sprintf("Interpretation: %s\nResult: %s", r.Interpretation, r.Value)
+}
+
+// Evaluate parses and evaluates an expression
+func Evaluate(expression string) (*Result, error) {
+	return evaluate(expression, time.Now())
+}
+
+// evaluate evaluates an expression with today and now resolved against the given time
+func evaluate(expression string, now time.Time) (*Result, error) {
+	tokens, err := lex(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dates are wall clock times stored as UTC so that day arithmetic ignores DST changes
+	wallClock := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
+	p := &parser{tokens: tokens, now: wallClock}
+	root, err := p.parseInput()
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := root.eval()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Interpretation: root.String(),
+		Value:          v.String(),
+	}, nil
+}
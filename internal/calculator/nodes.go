@@ -0,0 +1,339 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// node is a parsed expression. String prints it back with the grouping the parser chose,
+// which is what the model sees as the interpretation.
+type node interface {
+	eval() (*value, error)
+	String() string
+}
+
+type numberNode struct {
+	text   string
+	val    *big.Rat
+	approx bool // Constants such as pi are rounded
+}
+
+func (n *numberNode) eval() (*value, error) {
+	return number(n.val, n.approx), nil
+}
+
+func (n *numberNode) String() string {
+	return n.text
+}
+
+type dateNode struct {
+	text     string
+	t        time.Time
+	hasTime  bool
+	resolved bool // Relative dates such as today also print the date they stand for
+}
+
+func (n *dateNode) eval() (*value, error) {
+	return &value{kind: kindDate, date: n.t, hasTime: n.hasTime}, nil
+}
+
+func (n *dateNode) String() string {
+	if !n.resolved {
+		return n.text
+	}
+	if n.hasTime {
+		return fmt.Sprintf("%s (%s)", n.text, n.t.Format("2006-01-02 15:04:05"))
+	}
+	return fmt.Sprintf("%s (%s)", n.text, n.t.Format("2006-01-02"))
+}
+
+type groupNode struct {
+	x node
+}
+
+func (n *groupNode) eval() (*value, error) {
+	return n.x.eval()
+}
+
+func (n *groupNode) String() string {
+	return "(" + n.x.String() + ")"
+}
+
+type quantityNode struct {
+	x    node
+	unit *unit
+}
+
+func (n *quantityNode) eval() (*value, error) {
+	x, err := n.x.eval()
+	if err != nil {
+		return nil, err
+	}
+	if x.kind != kindNumber {
+		return nil, fmt.Errorf("cannot attach unit %s to %s", n.unit.name, x.describe())
+	}
+
+	q := &value{kind: kindQuantity, unit: n.unit, approx: x.approx}
+	if n.unit.months > 0 {
+		q.num = new(big.Rat)
+		q.months = new(big.Rat).Mul(x.num, ratInt(n.unit.months))
+	} else {
+		q.num = new(big.Rat).Mul(x.num, n.unit.factor)
+	}
+	return q, nil
+}
+
+func (n *quantityNode) String() string {
+	if num, ok := n.x.(*numberNode); ok {
+		return n.x.String() + " " + n.unit.label(num.val)
+	}
+	return n.x.String() + " " + n.unit.plural
+}
+
+type negateNode struct {
+	x node
+}
+
+func (n *negateNode) eval() (*value, error) {
+	x, err := n.x.eval()
+	if err != nil {
+		return nil, err
+	}
+	return negate(x)
+}
+
+func (n *negateNode) String() string {
+	return "-" + operand(n.x)
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval() (*value, error) {
+	left, err := n.left.eval()
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval()
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "+":
+		return add(left, right)
+	case "-":
+		return subtract(left, right)
+	case "*":
+		return multiply(left, right)
+	case "/":
+		return divide(left, right)
+	case "%":
+		return modulo(left, right)
+	default:
+		return power(left, right)
+	}
+}
+
+func (n *binaryNode) String() string {
+	return operand(n.left) + " " + n.op + " " + operand(n.right)
+}
+
+// operand prints a nested operation in parentheses so the grouping is explicit
+func operand(n node) string {
+	switch n.(type) {
+	case *binaryNode, *negateNode:
+		return "(" + n.String() + ")"
+	}
+	return n.String()
+}
+
+type convertNode struct {
+	x    node
+	unit *unit
+}
+
+func (n *convertNode) eval() (*value, error) {
+	x, err := n.x.eval()
+	if err != nil {
+		return nil, err
+	}
+	if x.kind != kindQuantity {
+		return nil, fmt.Errorf("cannot convert %s to %s; the value needs a unit, e.g. \"3 inch to cm\"", x.describe(), n.unit.plural)
+	}
+	if x.unit.dim != n.unit.dim {
+		return nil, fmt.Errorf("cannot convert %s to %s, which is a %s", x.describe(), n.unit.plural, n.unit.dim)
+	}
+
+	converted := *x
+	converted.unit = n.unit
+	return &converted, nil
+}
+
+func (n *convertNode) String() string {
+	return n.x.String() + " to " + n.unit.plural
+}
+
+type callNode struct {
+	name string
+	fn   function
+	args []node
+}
+
+func (n *callNode) eval() (*value, error) {
+	args := make([]*value, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval()
+		if err != nil {
+			return nil, err
+		}
+		if v.kind != kindNumber {
+			return nil, fmt.Errorf("%s expects numbers, got %s", n.name, v.describe())
+		}
+		args[i] = v
+	}
+	return n.fn.call(args)
+}
+
+func (n *callNode) String() string {
+	args := make([]string, len(n.args))
+	for i, arg := range n.args {
+		args[i] = arg.String()
+	}
+	return n.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// function is a built-in function over numbers
+type function struct {
+	minArgs, maxArgs int
+	call             func(args []*value) (*value, error)
+}
+
+// arity describes the accepted argument count for error messages
+func (f function) arity() string {
+	switch {
+	case f.minArgs == f.maxArgs && f.minArgs == 1:
+		return "1 argument"
+	case f.minArgs == f.maxArgs:
+		return fmt.Sprintf("%d arguments", f.minArgs)
+	case f.maxArgs == math.MaxInt && f.minArgs == 1:
+		return "at least 1 argument"
+	case f.maxArgs == math.MaxInt:
+		return fmt.Sprintf("at least %d arguments", f.minArgs)
+	default:
+		return fmt.Sprintf("%d to %d arguments", f.minArgs, f.maxArgs)
+	}
+}
+
+var constants = map[string]*big.Rat{
+	"pi": new(big.Rat).SetFloat64(math.Pi),
+	"e":  new(big.Rat).SetFloat64(math.E),
+}
+
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		"sqrt":      {1, 1, sqrt},
+		"abs":       {1, 1, exact(func(r *big.Rat) *big.Rat { return new(big.Rat).Abs(r) })},
+		"floor":     {1, 1, exact(floorRat)},
+		"ceil":      {1, 1, exact(ceilRat)},
+		"round":     {1, 2, round},
+		"min":       {1, math.MaxInt, extreme(-1)},
+		"max":       {1, math.MaxInt, extreme(1)},
+		"factorial": {1, 1, factorial},
+		"ln":        {1, 1, float("ln", math.Log)},
+		"log2":      {1, 1, float("log2", math.Log2)},
+		"exp":       {1, 1, float("exp", math.Exp)},
+		"sin":       {1, 1, float("sin", math.Sin)},
+		"cos":       {1, 1, float("cos", math.Cos)},
+		"tan":       {1, 1, float("tan", math.Tan)},
+		"log":       {1, 2, logarithm},
+	}
+	functions["fact"] = functions["factorial"]
+}
+
+// exact wraps an exact operation on a single number
+func exact(op func(*big.Rat) *big.Rat) func([]*value) (*value, error) {
+	return func(args []*value) (*value, error) {
+		return number(op(args[0].num), args[0].approx), nil
+	}
+}
+
+// float wraps a floating point function of a single number
+func float(name string, op func(float64) float64) func([]*value) (*value, error) {
+	return func(args []*value) (*value, error) {
+		x, _ := args[0].num.Float64()
+		return fromFloat(name, op(x))
+	}
+}
+
+// extreme returns the smallest (sign -1) or largest (sign 1) argument
+func extreme(sign int) func([]*value) (*value, error) {
+	return func(args []*value) (*value, error) {
+		best := args[0]
+		for _, arg := range args[1:] {
+			if arg.num.Cmp(best.num) == sign {
+				best = arg
+			}
+		}
+		return best, nil
+	}
+}
+
+func sqrt(args []*value) (*value, error) {
+	x := args[0]
+	if x.num.Sign() < 0 {
+		return nil, fmt.Errorf("sqrt of a negative number")
+	}
+
+	// Perfect squares stay exact
+	num, denom := new(big.Int).Sqrt(x.num.Num()), new(big.Int).Sqrt(x.num.Denom())
+	if !x.approx && new(big.Int).Mul(num, num).Cmp(x.num.Num()) == 0 && new(big.Int).Mul(denom, denom).Cmp(x.num.Denom()) == 0 {
+		return number(new(big.Rat).SetFrac(num, denom), false), nil
+	}
+
+	root, _ := new(big.Float).SetPrec(256).Sqrt(new(big.Float).SetPrec(256).SetRat(x.num)).Rat(nil)
+	return number(root, true), nil
+}
+
+func round(args []*value) (*value, error) {
+	x := args[0]
+	if len(args) == 1 {
+		return number(roundRat(x.num), x.approx), nil
+	}
+
+	digits := args[1].num
+	if !digits.IsInt() || !digits.Num().IsInt64() || abs64(digits.Num().Int64()) > maxExactDecimals {
+		return nil, fmt.Errorf("round digits must be a whole number between -%d and %d", maxExactDecimals, maxExactDecimals)
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(abs64(digits.Num().Int64())), nil))
+	if digits.Sign() < 0 {
+		scale.Inv(scale)
+	}
+	rounded := roundRat(new(big.Rat).Mul(x.num, scale))
+	return number(rounded.Quo(rounded, scale), x.approx), nil
+}
+
+func factorial(args []*value) (*value, error) {
+	n := args[0].num
+	if !n.IsInt() || n.Sign() < 0 || !n.Num().IsInt64() || n.Num().Int64() > 20000 {
+		return nil, fmt.Errorf("factorial needs a whole number between 0 and 20000")
+	}
+	result := new(big.Int).MulRange(1, n.Num().Int64())
+	return number(new(big.Rat).SetInt(result), args[0].approx), nil
+}
+
+func logarithm(args []*value) (*value, error) {
+	x, _ := args[0].num.Float64()
+	if len(args) == 1 {
+		return fromFloat("log", math.Log10(x))
+	}
+	base, _ := args[1].num.Float64()
+	return fromFloat("log", math.Log(x)/math.Log(base))
+}
@@ -0,0 +1,380 @@
+package calculator
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenDate
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// datePattern matches a date literal with an optional time of day
+var datePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(?:T\d{2}:\d{2}(?::\d{2})?)?`)
+
+// operatorAliases maps alternative operator spellings to the canonical ones
+var operatorAliases = map[string]string{
+	"×": "*",
+	"÷": "/",
+	"−": "-",
+}
+
+// lex splits an expression into tokens
+func lex(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+
+		case datePattern.MatchString(input[i:]):
+			text := datePattern.FindString(input[i:])
+			tokens = append(tokens, token{kind: tokenDate, text: text, pos: i})
+			i += len(text)
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(input) && isDigit(input[i+1])):
+			start := i
+			for i < len(input) && (isDigit(input[i]) || input[i] == '.' || input[i] == '_') {
+				i++
+			}
+			// Scientific notation, but not a following identifier such as the constant e
+			if i < len(input) && (input[i] == 'e' || input[i] == 'E') {
+				j := i + 1
+				if j < len(input) && (input[j] == '+' || input[j] == '-') {
+					j++
+				}
+				if j < len(input) && isDigit(input[j]) {
+					for i = j; i < len(input) && isDigit(input[i]); i++ {
+					}
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: input[start:i], pos: start})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(input) {
+				r, size := utf8.DecodeRuneInString(input[i:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: input[start:i], pos: start})
+
+		case strings.HasPrefix(input[i:], "**"):
+			tokens = append(tokens, token{kind: tokenOperator, text: "^", pos: i})
+			i += 2
+
+		case strings.ContainsRune("+-*/%^(),", r):
+			tokens = append(tokens, token{kind: tokenOperator, text: string(r), pos: i})
+			i += size
+
+		default:
+			canonical, ok := operatorAliases[string(r)]
+			if !ok {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: canonical, pos: i})
+			i += size
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// conversionKeywords introduce the target unit of a conversion, e.g. "5 km to mi"
+var conversionKeywords = map[string]bool{"to": true, "in": true, "as": true}
+
+// parser is a recursive descent parser over
+//
+//	input   = expr [ ("to" | "in" | "as") unit ]
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/" | "%") unary }
+//	unary   = "-" unary | power
+//	power   = primary [ "^" unary ]
+//	primary = number [unit] | "(" expr ")" [unit] | date | constant | function "(" args ")"
+type parser struct {
+	tokens []token
+	pos    int
+	now    time.Time
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isOperator reports whether the next token is one of the given operators
+func (p *parser) isOperator(ops ...string) bool {
+	tok := p.peek()
+	if tok.kind != tokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOperator(op) {
+		return p.unexpected()
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	tok := p.peek()
+	if tok.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	return fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+func (p *parser) parseInput() (node, error) {
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind == tokenIdent && conversionKeywords[strings.ToLower(tok.text)] {
+		p.next()
+		target := p.next()
+		u := lookupUnit(target.text)
+		if target.kind != tokenIdent || u == nil {
+			return nil, fmt.Errorf("unknown unit %q after %q", target.text, tok.text)
+		}
+		expr = &convertNode{x: expr, unit: u}
+	}
+
+	if p.peek().kind != tokenEOF {
+		return nil, p.unexpected()
+	}
+	return expr, nil
+}
+
+func (p *parser) parseExpr() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+", "-") {
+		op := p.next().text
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*", "/", "%") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isOperator("-") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &negateNode{x: x}, nil
+	}
+	if p.isOperator("+") {
+		p.next()
+		return p.parseUnary()
+	}
+	return p.parsePower()
+}
+
+func (p *parser) parsePower() (node, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOperator("^") {
+		return base, nil
+	}
+	p.next()
+	// Right associative, and allows a negative exponent as in 2^-3
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: "^", left: base, right: exponent}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		text := strings.ReplaceAll(tok.text, "_", "")
+		r, ok := new(big.Rat).SetString(text)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return p.withUnit(&numberNode{text: text, val: r}), nil
+
+	case tokenDate:
+		return parseDate(tok)
+
+	case tokenIdent:
+		return p.parseIdent(tok)
+
+	case tokenOperator:
+		if tok.text == "(" {
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return p.withUnit(&groupNode{x: x}), nil
+		}
+	}
+
+	if tok.kind != tokenEOF {
+		p.pos--
+	}
+	return nil, p.unexpected()
+}
+
+// withUnit attaches a following unit to x. A conversion keyword followed by a unit is
+// left for parseInput, so "3 in" is three inches but "3 in cm" reads as a conversion of
+// a plain number; "3 inch to cm" is unambiguous.
+func (p *parser) withUnit(x node) node {
+	tok := p.peek()
+	if tok.kind != tokenIdent {
+		return x
+	}
+	u := lookupUnit(tok.text)
+	if u == nil {
+		return x
+	}
+	if conversionKeywords[strings.ToLower(tok.text)] {
+		if following := p.tokens[p.pos+1]; following.kind == tokenIdent && lookupUnit(following.text) != nil {
+			return x
+		}
+	}
+	p.next()
+	return &quantityNode{x: x, unit: u}
+}
+
+func (p *parser) parseIdent(tok token) (node, error) {
+	name := strings.ToLower(tok.text)
+
+	if p.isOperator("(") {
+		fn, ok := functions[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown function %q", tok.text)
+		}
+		p.next()
+
+		var args []node
+		if !p.isOperator(")") {
+			for {
+				arg, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if !p.isOperator(",") {
+					break
+				}
+				p.next()
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if len(args) < fn.minArgs || len(args) > fn.maxArgs {
+			return nil, fmt.Errorf("%s takes %s", name, fn.arity())
+		}
+		return &callNode{name: name, fn: fn, args: args}, nil
+	}
+
+	if c, ok := constants[name]; ok {
+		return &numberNode{text: name, val: c, approx: true}, nil
+	}
+
+	today := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, time.UTC)
+	switch name {
+	case "today":
+		return &dateNode{text: name, t: today, resolved: true}, nil
+	case "tomorrow":
+		return &dateNode{text: name, t: today.AddDate(0, 0, 1), resolved: true}, nil
+	case "yesterday":
+		return &dateNode{text: name, t: today.AddDate(0, 0, -1), resolved: true}, nil
+	case "now":
+		return &dateNode{text: name, t: p.now, hasTime: true, resolved: true}, nil
+	}
+
+	if lookupUnit(tok.text) != nil {
+		return nil, fmt.Errorf("unit %q needs a number in front of it", tok.text)
+	}
+	return nil, fmt.Errorf("unknown name %q", tok.text)
+}
+
+// parseDate parses a date literal as a wall clock time
+func parseDate(tok token) (node, error) {
+	layouts := []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"}
+	for _, layout := range layouts {
+		if len(layout) != len(tok.text) {
+			continue
+		}
+		t, err := time.ParseInLocation(layout, tok.text, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q", tok.text)
+		}
+		return &dateNode{text: tok.text, t: t, hasTime: layout != "2006-01-02"}, nil
+	}
+	return nil, fmt.Errorf("invalid date %q", tok.text)
+}
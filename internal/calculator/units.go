@@ -0,0 +1,110 @@
+package calculator
+
+import (
+	"math/big"
+	"strings"
+)
+
+// Dimensions a quantity can have; quantities of different dimensions can't be added or converted
+const (
+	dimLength   = "length"
+	dimMass     = "mass"
+	dimDuration = "duration"
+	dimData     = "data size"
+)
+
+// avgMonthSeconds is the average Gregorian month (365.2425 days / 12), used only when
+// months have to be compared with fixed-length units
+const avgMonthSeconds = 2629746
+
+// unit is a unit of measure. Magnitudes are stored in the base unit of the dimension
+// (meter, kilogram, second, byte); months and years are calendar units and are kept
+// apart because their length depends on the date they are added to.
+type unit struct {
+	name   string
+	plural string
+	dim    string
+	factor *big.Rat // Size in base units; nil for calendar units
+	months int64    // Size in months for calendar units
+}
+
+// label returns the unit name to print after magnitude
+func (u *unit) label(magnitude *big.Rat) string {
+	if magnitude.Cmp(big.NewRat(1, 1)) == 0 {
+		return u.name
+	}
+	return u.plural
+}
+
+var units = map[string]*unit{}
+
+// defineUnit registers a unit under its names and aliases
+func defineUnit(dim, name, plural, factor string, aliases ...string) {
+	u := &unit{name: name, plural: plural, dim: dim}
+	if factor != "" {
+		u.factor, _ = new(big.Rat).SetString(factor)
+	}
+	for _, key := range append([]string{name, plural}, aliases...) {
+		units[key] = u
+	}
+}
+
+// defineCalendarUnit registers a unit measured in calendar months
+func defineCalendarUnit(name, plural string, months int64, aliases ...string) {
+	u := &unit{name: name, plural: plural, dim: dimDuration, months: months}
+	for _, key := range append([]string{name, plural}, aliases...) {
+		units[key] = u
+	}
+}
+
+func init() {
+	defineUnit(dimLength, "mm", "mm", "0.001", "millimeter", "millimeters", "millimetre", "millimetres")
+	defineUnit(dimLength, "cm", "cm", "0.01", "centimeter", "centimeters", "centimetre", "centimetres")
+	defineUnit(dimLength, "m", "m", "1", "meter", "meters", "metre", "metres")
+	defineUnit(dimLength, "km", "km", "1000", "kilometer", "kilometers", "kilometre", "kilometres")
+	defineUnit(dimLength, "inch", "inches", "0.0254", "in")
+	defineUnit(dimLength, "ft", "ft", "0.3048", "foot", "feet")
+	defineUnit(dimLength, "yd", "yd", "0.9144", "yard", "yards")
+	defineUnit(dimLength, "mi", "mi", "1609.344", "mile", "miles")
+	defineUnit(dimLength, "nmi", "nmi", "1852", "nautical_mile", "nautical_miles")
+
+	defineUnit(dimMass, "mg", "mg", "0.000001", "milligram", "milligrams")
+	defineUnit(dimMass, "g", "g", "0.001", "gram", "grams")
+	defineUnit(dimMass, "kg", "kg", "1", "kilogram", "kilograms")
+	defineUnit(dimMass, "t", "t", "1000", "tonne", "tonnes")
+	defineUnit(dimMass, "oz", "oz", "0.028349523125", "ounce", "ounces")
+	defineUnit(dimMass, "lb", "lb", "0.45359237", "lbs", "pound", "pounds")
+
+	defineUnit(dimDuration, "ms", "ms", "0.001", "millisecond", "milliseconds")
+	defineUnit(dimDuration, "second", "seconds", "1", "s", "sec", "secs")
+	defineUnit(dimDuration, "minute", "minutes", "60", "min", "mins")
+	defineUnit(dimDuration, "hour", "hours", "3600", "h", "hr", "hrs")
+	defineUnit(dimDuration, "day", "days", "86400", "d")
+	defineUnit(dimDuration, "week", "weeks", "604800", "wk", "wks")
+	defineCalendarUnit("month", "months", 1, "mo")
+	defineCalendarUnit("year", "years", 12, "yr", "yrs", "y")
+
+	defineUnit(dimData, "bit", "bits", "0.125")
+	defineUnit(dimData, "byte", "bytes", "1", "B")
+	defineUnit(dimData, "KB", "KB", "1000", "kB")
+	defineUnit(dimData, "MB", "MB", "1000000")
+	defineUnit(dimData, "GB", "GB", "1000000000")
+	defineUnit(dimData, "TB", "TB", "1000000000000")
+	defineUnit(dimData, "KiB", "KiB", "1024")
+	defineUnit(dimData, "MiB", "MiB", "1048576")
+	defineUnit(dimData, "GiB", "GiB", "1073741824")
+	defineUnit(dimData, "TiB", "TiB", "1099511627776")
+}
+
+// lookupUnit finds a unit by name, ignoring case when there's no exact match
+func lookupUnit(name string) *unit {
+	if u, ok := units[name]; ok {
+		return u
+	}
+	for key, u := range units {
+		if strings.EqualFold(key, name) {
+			return u
+		}
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// maxExactDigits limits how many digits of an exact integer are printed
+	maxExactDigits = 1000
+	// maxExactDecimals limits the decimal places of a terminating fraction printed exactly
+	maxExactDecimals = 50
+	// approxDigits is the number of significant digits printed for approximate results
+	approxDigits = 15
+	// maxResultBits guards exact powers and factorials against results too large to compute
+	maxResultBits = 4 << 20
+)
+
+type valueKind int
+
+const (
+	kindNumber valueKind = iota
+	kindQuantity
+	kindDate
+)
+
+// value is an evaluated number, quantity or date
+type value struct {
+	kind    valueKind
+	num     *big.Rat  // The number, or a quantity's magnitude in base units
+	months  *big.Rat  // Calendar months of a duration, kept apart from num; may be nil
+	unit    *unit     // The unit a quantity is shown in
+	date    time.Time // Wall clock date and time, stored as UTC so days are always 24 hours
+	hasTime bool      // The date carries a time of day
+	approx  bool      // The value went through floating point or an average month length
+}
+
+func number(r *big.Rat, approx bool) *value {
+	return &value{kind: kindNumber, num: r, approx: approx}
+}
+
+func ratInt(n int64) *big.Rat {
+	return new(big.Rat).SetInt64(n)
+}
+
+// monthsOf returns the calendar months of a duration, treating nil as zero
+func (v *value) monthsOf() *big.Rat {
+	if v.months == nil {
+		return new(big.Rat)
+	}
+	return v.months
+}
+
+// describe names the kind of value for error messages
+func (v *value) describe() string {
+	switch v.kind {
+	case kindQuantity:
+		return "a " + v.unit.dim
+	case kindDate:
+		return "a date"
+	default:
+		return "a number"
+	}
+}
+
+// magnitudeIn returns a quantity's magnitude in u and whether months had to be averaged
+func (v *value) magnitudeIn(u *unit) (*big.Rat, bool) {
+	months := v.monthsOf()
+	if u.months > 0 {
+		total := new(big.Rat).Add(months, new(big.Rat).Quo(v.num, ratInt(avgMonthSeconds)))
+		return total.Quo(total, ratInt(u.months)), v.num.Sign() != 0
+	}
+	total := new(big.Rat).Add(v.num, new(big.Rat).Mul(months, ratInt(avgMonthSeconds)))
+	return total.Quo(total, u.factor), months.Sign() != 0
+}
+
+// String formats the value for the tool result
+func (v *value) String() string {
+	switch v.kind {
+	case kindQuantity:
+		magnitude, averaged := v.magnitudeIn(v.unit)
+		return formatRat(magnitude, v.approx || averaged) + " " + v.unit.label(magnitude)
+	case kindDate:
+		if v.hasTime {
+			return v.date.Format("2006-01-02 15:04:05 (Monday)")
+		}
+		return v.date.Format("2006-01-02 (Monday)")
+	default:
+		return formatRat(v.num, v.approx)
+	}
+}
+
+// formatRat prints exact values in full, as a fraction with its decimal expansion when
+// the decimal doesn't terminate, and approximate values to approxDigits significant digits
+func formatRat(r *big.Rat, approx bool) string {
+	if approx {
+		return "≈ " + formatApprox(r)
+	}
+
+	if r.IsInt() {
+		digits := r.Num().String()
+		if len(strings.TrimPrefix(digits, "-")) > maxExactDigits {
+			return fmt.Sprintf("≈ %s (%d digits)", formatApprox(r), len(strings.TrimPrefix(digits, "-")))
+		}
+		return digits
+	}
+
+	if places, ok := terminatingDecimals(r.Denom()); ok && places <= maxExactDecimals {
+		return r.FloatString(places)
+	}
+	return fmt.Sprintf("%s ≈ %s", r.RatString(), formatApprox(r))
+}
+
+// formatApprox prints r to approxDigits significant digits without trailing zeros
+func formatApprox(r *big.Rat) string {
+	text := new(big.Float).SetPrec(256).SetRat(r).Text('g', approxDigits)
+	mantissa, exponent, hasExponent := strings.Cut(text, "e")
+	if strings.Contains(mantissa, ".") {
+		mantissa = strings.TrimRight(strings.TrimRight(mantissa, "0"), ".")
+	}
+	if hasExponent {
+		return mantissa + "e" + exponent
+	}
+	return mantissa
+}
+
+// terminatingDecimals returns the decimal places a fraction with this denominator needs,
+// or false when its decimal expansion repeats
+func terminatingDecimals(denom *big.Int) (int, bool) {
+	d := new(big.Int).Set(denom)
+	twos, fives := 0, 0
+	five := big.NewInt(5)
+	mod := new(big.Int)
+	for d.Bit(0) == 0 && d.Sign() > 0 {
+		d.Rsh(d, 1)
+		twos++
+	}
+	for {
+		q, m := new(big.Int).QuoRem(d, five, mod)
+		if m.Sign() != 0 {
+			break
+		}
+		d = q
+		fives++
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+	return max(twos, fives), true
+}
+
+// floorRat rounds r down to an integer
+func floorRat(r *big.Rat) *big.Rat {
+	// Denominators are always positive, so Euclidean division floors
+	return new(big.Rat).SetInt(new(big.Int).Div(r.Num(), r.Denom()))
+}
+
+// ceilRat rounds r up to an integer
+func ceilRat(r *big.Rat) *big.Rat {
+	return new(big.Rat).Neg(floorRat(new(big.Rat).Neg(r)))
+}
+
+// roundRat rounds r to the nearest integer, halves away from zero
+func roundRat(r *big.Rat) *big.Rat {
+	abs := new(big.Rat).Abs(r)
+	rounded := floorRat(abs.Add(abs, big.NewRat(1, 2)))
+	if r.Sign() < 0 {
+		rounded.Neg(rounded)
+	}
+	return rounded
+}
+
+// fromFloat converts a floating point result, rejecting NaN and infinities
+func fromFloat(name string, f float64) (*value, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("%s is undefined for this input", name)
+	}
+	return number(new(big.Rat).SetFloat64(f), true), nil
+}
+
+func negate(v *value) (*value, error) {
+	switch v.kind {
+	case kindDate:
+		return nil, fmt.Errorf("cannot negate a date")
+	case kindQuantity:
+		neg := *v
+		neg.num = new(big.Rat).Neg(v.num)
+		neg.months = new(big.Rat).Neg(v.monthsOf())
+		return &neg, nil
+	default:
+		return number(new(big.Rat).Neg(v.num), v.approx), nil
+	}
+}
+
+func add(a, b *value) (*value, error) {
+	approx := a.approx || b.approx
+	switch {
+	case a.kind == kindNumber && b.kind == kindNumber:
+		return number(new(big.Rat).Add(a.num, b.num), approx), nil
+
+	case a.kind == kindQuantity && b.kind == kindQuantity && a.unit.dim == b.unit.dim:
+		return &value{
+			kind:   kindQuantity,
+			num:    new(big.Rat).Add(a.num, b.num),
+			months: new(big.Rat).Add(a.monthsOf(), b.monthsOf()),
+			unit:   a.unit,
+			approx: approx,
+		}, nil
+
+	case a.kind == kindDate && b.kind == kindQuantity && b.unit.dim == dimDuration:
+		return addToDate(a, b)
+
+	case a.kind == kindQuantity && a.unit.dim == dimDuration && b.kind == kindDate:
+		return addToDate(b, a)
+	}
+	return nil, fmt.Errorf("cannot add %s and %s", a.describe(), b.describe())
+}
+
+func subtract(a, b *value) (*value, error) {
+	if a.kind == kindDate && b.kind == kindDate {
+		seconds := new(big.Rat).SetFrac64(int64(a.date.Nanosecond()-b.date.Nanosecond()), int64(time.Second))
+		seconds.Add(seconds, ratInt(a.date.Unix()-b.date.Unix()))
+		return &value{kind: kindQuantity, num: seconds, unit: units["day"]}, nil
+	}
+	if b.kind == kindDate {
+		return nil, fmt.Errorf("cannot subtract a date from %s", a.describe())
+	}
+
+	neg, err := negate(b)
+	if err != nil {
+		return nil, err
+	}
+	return add(a, neg)
+}
+
+// addToDate moves a date by a duration. Months are added on the calendar, clamping to the
+// end of shorter months, and whole days keep a date without a time of day.
+func addToDate(d, duration *value) (*value, error) {
+	t := d.date
+	hasTime := d.hasTime
+
+	if months := duration.monthsOf(); months.Sign() != 0 {
+		if !months.IsInt() {
+			return nil, fmt.Errorf("only whole months and years can be added to a date")
+		}
+		if !months.Num().IsInt64() || abs64(months.Num().Int64()) > 12*10000 {
+			return nil, fmt.Errorf("date out of range")
+		}
+		t = addMonths(t, int(months.Num().Int64()))
+	}
+
+	if duration.num.Sign() != 0 {
+		days := new(big.Rat).Quo(duration.num, ratInt(86400))
+		if days.IsInt() {
+			if !days.Num().IsInt64() || abs64(days.Num().Int64()) > 366*10000 {
+				return nil, fmt.Errorf("date out of range")
+			}
+			t = t.AddDate(0, 0, int(days.Num().Int64()))
+		} else {
+			nanos := roundRat(new(big.Rat).Mul(duration.num, ratInt(int64(time.Second))))
+			if !nanos.Num().IsInt64() {
+				return nil, fmt.Errorf("date out of range")
+			}
+			t = t.Add(time.Duration(nanos.Num().Int64()))
+			hasTime = true
+		}
+	}
+
+	return &value{kind: kindDate, date: t, hasTime: hasTime, approx: d.approx || duration.approx}, nil
+}
+
+// addMonths adds calendar months, so Jan 31 + 1 month is the last day of February
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, lastDay)-1)
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func multiply(a, b *value) (*value, error) {
+	approx := a.approx || b.approx
+	switch {
+	case a.kind == kindNumber && b.kind == kindNumber:
+		return number(new(big.Rat).Mul(a.num, b.num), approx), nil
+	case a.kind == kindQuantity && b.kind == kindNumber:
+		return scale(a, b.num, approx), nil
+	case a.kind == kindNumber && b.kind == kindQuantity:
+		return scale(b, a.num, approx), nil
+	}
+	return nil, fmt.Errorf("cannot multiply %s by %s", a.describe(), b.describe())
+}
+
+func divide(a, b *value) (*value, error) {
+	approx := a.approx || b.approx
+	switch {
+	case b.kind == kindNumber && b.num.Sign() == 0:
+		return nil, fmt.Errorf("division by zero")
+
+	case a.kind == kindNumber && b.kind == kindNumber:
+		return number(new(big.Rat).Quo(a.num, b.num), approx), nil
+
+	case a.kind == kindQuantity && b.kind == kindNumber:
+		return scale(a, new(big.Rat).Inv(b.num), approx), nil
+
+	case a.kind == kindQuantity && b.kind == kindQuantity && a.unit.dim == b.unit.dim:
+		// A ratio of like quantities, e.g. 1 GB / 1 MB
+		left, leftAveraged := a.magnitudeIn(b.unit)
+		right, rightAveraged := b.magnitudeIn(b.unit)
+		if right.Sign() == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return number(left.Quo(left, right), approx || leftAveraged || rightAveraged), nil
+	}
+	return nil, fmt.Errorf("cannot divide %s by %s", a.describe(), b.describe())
+}
+
+func scale(q *value, factor *big.Rat, approx bool) *value {
+	return &value{
+		kind:   kindQuantity,
+		num:    new(big.Rat).Mul(q.num, factor),
+		months: new(big.Rat).Mul(q.monthsOf(), factor),
+		unit:   q.unit,
+		approx: approx,
+	}
+}
+
+func modulo(a, b *value) (*value, error) {
+	if a.kind != kindNumber || b.kind != kindNumber {
+		return nil, fmt.Errorf("%% only works on numbers")
+	}
+	if b.num.Sign() == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+	quotient := floorRat(new(big.Rat).Quo(a.num, b.num))
+	return number(new(big.Rat).Sub(a.num, quotient.Mul(quotient, b.num)), a.approx || b.approx), nil
+}
+
+func power(a, b *value) (*value, error) {
+	if a.kind != kindNumber || b.kind != kindNumber {
+		return nil, fmt.Errorf("^ only works on numbers")
+	}
+
+	if b.num.IsInt() && !a.approx && !b.approx {
+		exponent := b.num.Num()
+		bits := max(a.num.Num().BitLen(), a.num.Denom().BitLen())
+		if exponent.IsInt64() && abs64(exponent.Int64())*int64(bits) <= maxResultBits {
+			if a.num.Sign() == 0 && exponent.Sign() < 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			e := new(big.Int).Abs(exponent)
+			num := new(big.Int).Exp(a.num.Num(), e, nil)
+			denom := new(big.Int).Exp(a.num.Denom(), e, nil)
+			if exponent.Sign() < 0 {
+				num, denom = denom, num
+			}
+			return number(new(big.Rat).SetFrac(num, denom), false), nil
+		}
+		if bits > 1 {
+			return nil, fmt.Errorf("result is too large to compute")
+		}
+	}
+
+	base, _ := a.num.Float64()
+	exponent, _ := b.num.Float64()
+	if base < 0 && !b.num.IsInt() {
+		return nil, fmt.Errorf("cannot raise a negative number to a fractional power")
+	}
+	return fromFloat("^", math.Pow(base, exponent))
+}
@@ -1,32 +1,183 @@
 package config
 
 import (
+	"chatgo/internal/log"
+	"chatgo/internal/paths"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
+// logger logs problems reading and saving the config file
+var logger = log.New("config")
+
 // Config represents the application configuration
 type Config struct {
-	Providers         []Provider         `yaml:"providers"`
-	MCPServers        []MCPServer        `yaml:"mcp_servers"`
-	BuiltinTools      []BuiltinTool      `yaml:"builtin_tools"`
-	CurrentProvider   string             `yaml:"current_provider"`
-	UseReactAgent     bool               `yaml:"use_react_agent"`
-	ReactAgentMaxStep int                `yaml:"react_agent_max_step"`
+	Providers          []Provider      `yaml:"providers"`
+	MCPServers         []MCPServer     `yaml:"mcp_servers"`
+	BuiltinTools       []BuiltinTool   `yaml:"builtin_tools"`
+	CurrentProvider    string          `yaml:"current_provider"`
+	UseReactAgent      bool            `yaml:"use_react_agent"`
+	ReactAgentMaxStep  int             `yaml:"react_agent_max_step"`            // Clamped to MinReactAgentMaxStep–MaxReactAgentMaxStep; 0 uses the default
+	SendOnEnter        bool            `yaml:"send_on_enter"`                   // Enter sends and Shift+Enter adds a newline; false swaps them. Ctrl/Cmd+Enter always sends
+	AttachmentMaxKB    int             `yaml:"attachment_max_kb,omitempty"`     // Largest text file that can be attached to a message; 0 uses the default
+	SystemPrompt       string          `yaml:"system_prompt,omitempty"`         // Sent before every conversation, ahead of the conversation's own system prompt
+	ExternalEditor     string          `yaml:"external_editor,omitempty"`       // Command used to open conversation files; empty uses the OS default
+	Proxy              string          `yaml:"proxy,omitempty"`                 // HTTP, HTTPS or SOCKS5 proxy URL; empty uses HTTP_PROXY/HTTPS_PROXY
+	TitleProvider      string          `yaml:"title_provider,omitempty"`        // Provider for background titles and summaries; empty uses the current provider
+	TitleRatePerMinute int             `yaml:"title_rate_per_minute,omitempty"` // Background title and summary requests per minute; 0 uses the default
+	ModelOverrides     []ModelOverride `yaml:"model_overrides,omitempty"`       // Model metadata that takes precedence over the built-in catalog
+	Theme              string          `yaml:"theme,omitempty"`                 // ThemeLight or ThemeDark; empty follows the system
+	AccentColor        string          `yaml:"accent_color,omitempty"`          // Primary color as #rrggbb; empty uses the theme's
+	MessageTextScale   float64         `yaml:"message_text_scale,omitempty"`    // Size of chat message text relative to the theme's; 0 is 1
+	Language           string          `yaml:"language,omitempty"`              // UI language such as "en" or "zh-CN"; empty follows the system locale
+	LogToFile          bool            `yaml:"log_to_file,omitempty"`           // Mirror the log to a rotating file in the logs directory
+	ToolOverrides      []ToolOverride  `yaml:"tool_overrides,omitempty"`        // Names and descriptions shown for tools in place of their own
+	ContextStrategy    string          `yaml:"context_strategy,omitempty"`      // ContextFull, ContextLastN or ContextSummarize; empty is ContextFull
+	ContextLastN       int             `yaml:"context_last_n,omitempty"`        // Messages kept by ContextLastN and ContextSummarize; 0 uses DefaultContextLastN
+	BackupKeep         int             `yaml:"backup_keep,omitempty"`           // Daily conversation backups kept; 0 uses DefaultBackupKeep
+
+	// placeholders remembers values expanded from ${VAR} references, keyed by where they appear
+	placeholders map[string]placeholder
+}
+
+// DefaultSendOnEnter is whether Enter sends a message, for new installations and config files
+// written before the setting existed alike
+const DefaultSendOnEnter = true
+
+// Bounds and default for the number of steps the React agent may take per request
+const (
+	MinReactAgentMaxStep     = 1
+	MaxReactAgentMaxStep     = 100
+	DefaultReactAgentMaxStep = 40
+)
+
+// Theme variants the app can be fixed to instead of following the system
+const (
+	ThemeLight = "light"
+	ThemeDark  = "dark"
+)
+
+// Bounds of MessageTextScale
+const (
+	MinMessageTextScale = 0.8
+	MaxMessageTextScale = 2.0
+)
+
+// MessageScale returns MessageTextScale within the supported range, or 1 when unset
+func (c *Config) MessageScale() float64 {
+	if c.MessageTextScale <= 0 {
+		return 1
+	}
+	return min(max(c.MessageTextScale, MinMessageTextScale), MaxMessageTextScale)
+}
+
+// DefaultBackupKeep is how many daily conversation backups are kept when no count is configured
+const DefaultBackupKeep = 7
+
+// BackupsKept returns how many daily conversation backups are kept
+func (c *Config) BackupsKept() int {
+	if c.BackupKeep > 0 {
+		return c.BackupKeep
+	}
+	return DefaultBackupKeep
+}
+
+// DefaultAttachmentMaxKB is the attachment size limit when none is configured
+const DefaultAttachmentMaxKB = 256
+
+// AttachmentMaxBytes returns the largest text file that can be attached to a message
+func (c *Config) AttachmentMaxBytes() int64 {
+	if c.AttachmentMaxKB > 0 {
+		return int64(c.AttachmentMaxKB) * 1024
+	}
+	return DefaultAttachmentMaxKB * 1024
+}
+
+// ReactAgentSteps returns ReactAgentMaxStep within the supported range, or the default when unset
+func (c *Config) ReactAgentSteps() int {
+	if c.ReactAgentMaxStep <= 0 {
+		return DefaultReactAgentMaxStep
+	}
+	return min(max(c.ReactAgentMaxStep, MinReactAgentMaxStep), MaxReactAgentMaxStep)
+}
+
+// Strategies for fitting long conversations into the history sent with each request. Every
+// strategy also drops the oldest messages that don't fit the provider's context limits.
+const (
+	ContextFull      = "full"      // Send all messages
+	ContextLastN     = "last_n"    // Send the last ContextLastN messages
+	ContextSummarize = "summarize" // Send the last ContextLastN messages and a summary of the rest
+)
+
+// ContextStrategies lists the context strategies in the order they are offered
+var ContextStrategies = []string{ContextFull, ContextLastN, ContextSummarize}
+
+// DefaultContextLastN is how many messages ContextLastN and ContextSummarize keep when unset
+const DefaultContextLastN = 20
+
+// ContextMode returns ContextStrategy, or ContextFull when unset or unknown
+func (c *Config) ContextMode() string {
+	if slices.Contains(ContextStrategies, c.ContextStrategy) {
+		return c.ContextStrategy
+	}
+	return ContextFull
+}
+
+// ContextMessages returns the most conversation messages sent per request under the context
+// strategy; 0 is no limit
+func (c *Config) ContextMessages() int {
+	if c.ContextMode() == ContextFull {
+		return 0
+	}
+	if c.ContextLastN > 0 {
+		return c.ContextLastN
+	}
+	return DefaultContextLastN
+}
+
+// placeholder is a config value as written in the file and as expanded from the environment
+type placeholder struct {
+	raw      string
+	expanded string
+}
+
+// ModelOverride corrects or supplies catalog metadata for a model. Unset fields keep the catalog value.
+type ModelOverride struct {
+	Model                 string   `yaml:"model"`
+	ProviderType          string   `yaml:"provider_type,omitempty"` // Empty applies to the model under any provider type
+	ContextWindow         int      `yaml:"context_window,omitempty"`
+	ToolCalling           *bool    `yaml:"tool_calling,omitempty"`
+	Vision                *bool    `yaml:"vision,omitempty"`
+	MaxTools              int      `yaml:"max_tools,omitempty"`                // Most tools per request
+	InputPricePerMillion  *float64 `yaml:"input_price_per_million,omitempty"`  // USD per million prompt tokens
+	OutputPricePerMillion *float64 `yaml:"output_price_per_million,omitempty"` // USD per million completion tokens
 }
 
 // Provider represents an LLM provider configuration
 type Provider struct {
-	Name    string `yaml:"name"`
-	Type    string `yaml:"type"` // openai, anthropic, ollama, etc.
-	APIKey  string `yaml:"api_key"`
-	BaseURL string `yaml:"base_url,omitempty"`
-	Model   string `yaml:"model"`
-	Enabled bool   `yaml:"enabled"`
+	Name                  string `yaml:"name"`
+	Type                  string `yaml:"type"` // openai, azure, anthropic, openrouter, ollama, etc.
+	APIKey                string `yaml:"api_key"`
+	BaseURL               string `yaml:"base_url,omitempty"`
+	Model                 string `yaml:"model"`
+	DeploymentName        string `yaml:"deployment_name,omitempty"` // Azure OpenAI deployment that requests are sent to
+	APIVersion            string `yaml:"api_version,omitempty"`     // Azure OpenAI API version; empty uses the default version
+	Enabled               bool   `yaml:"enabled"`
+	RequestTimeoutSeconds int    `yaml:"request_timeout_seconds,omitempty"` // Time allowed until the first streamed chunk; 0 uses the default
+	RequestsPerMinute     int    `yaml:"requests_per_minute,omitempty"`     // Shared by all conversations using the provider; 0 is unlimited
+	MaxContextMessages    int    `yaml:"max_context_messages,omitempty"`    // Most conversation messages sent per request; 0 sends all that fit
+	MaxContextTokens      int    `yaml:"max_context_tokens,omitempty"`      // Estimated tokens of history sent per request; 0 uses 3/4 of the model's known context window
+	SummarizeTrimmed      bool   `yaml:"summarize_trimmed,omitempty"`       // Send a summary of the messages left out to fit the context instead of dropping them
+	DisableStreaming      bool   `yaml:"disable_streaming,omitempty"`       // Request whole replies, for gateways that reject streaming
+
+	// Options only some provider types read, such as Ollama's num_ctx; KnownExtras lists them
+	Extras map[string]string `yaml:"extras,omitempty"`
 }
 
 // MCPServerType represents the type of MCP server connection
@@ -46,17 +197,71 @@ type MCPServer struct {
 	Command        string            `yaml:"command,omitempty"`
 	Args           []string          `yaml:"args,omitempty"`
 	Env            map[string]string `yaml:"env,omitempty"`
+	WorkingDir     string            `yaml:"working_dir,omitempty"`     // For StdIO; relative paths in Args are resolved from it
 	URL            string            `yaml:"url,omitempty"`             // For SSE and StreamableHTTP
 	Headers        map[string]string `yaml:"headers,omitempty"`         // For SSE and StreamableHTTP
-	TimeoutSeconds int               `yaml:"timeout_seconds,omitempty"` // For SSE and StreamableHTTP
+	TimeoutSeconds int               `yaml:"timeout_seconds,omitempty"` // Initialization and tool call timeout; 0 uses 30 seconds
+}
+
+// MissingPathArgs returns the indexes of a stdio server's arguments that should name a
+// directory but don't: absolute paths that no longer exist once expanded with ExpandPath, and
+// blank arguments, which is what the home directory used to be written as where HOME was not set
+func (s MCPServer) MissingPathArgs() []int {
+	if s.Type != MCPServerTypeStdIO && s.Type != "" {
+		return nil
+	}
+	var missing []int
+	for i, arg := range s.Args {
+		if strings.TrimSpace(arg) == "" {
+			missing = append(missing, i)
+			continue
+		}
+		arg = ExpandPath(arg)
+		if !filepath.IsAbs(arg) {
+			continue
+		}
+		if _, err := os.Stat(arg); err != nil {
+			missing = append(missing, i)
+		}
+	}
+	return missing
+}
+
+// ExpandPath expands a leading ~ to the home directory and $VAR or ${VAR} references to their
+// values in the environment, as a shell would in a stdio server's arguments and working
+// directory. Unset variables expand to empty.
+func ExpandPath(value string) string {
+	value = os.ExpandEnv(value)
+	if value == "~" || strings.HasPrefix(value, "~/") || strings.HasPrefix(value, `~\`) {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			value = homeDir + value[1:]
+		}
+	}
+	return value
 }
 
 // BuiltinTool represents a built-in tool configuration from Eino framework
 type BuiltinTool struct {
-	Name        string            `yaml:"name"`
-	Type        string            `yaml:"type"` // bingsearch, googlesearch, wikipedia, duckduckgosearch, httprequest, browseruse, commandline, sequentialthinking
-	Enabled     bool              `yaml:"enabled"`
-	Config      map[string]string `yaml:"config,omitempty"` // Tool-specific configuration
+	Name            string            `yaml:"name"`
+	Type            string            `yaml:"type"` // bingsearch, googlesearch, wikipedia, duckduckgosearch, httprequest, browseruse, commandline, sequentialthinking, calculator, currenttime, weather
+	Enabled         bool              `yaml:"enabled"`
+	Config          map[string]string `yaml:"config,omitempty"`           // Tool-specific configuration
+	RequireApproval *bool             `yaml:"require_approval,omitempty"` // Ask before each run; unset uses the tool type's default
+}
+
+// builtinToolsRequiringApproval lists tools that can change things outside the app,
+// so each run is confirmed by the user unless configured otherwise
+var builtinToolsRequiringApproval = map[string]bool{
+	"commandline": true,
+	"httprequest": true,
+}
+
+// NeedsApproval reports whether the user must confirm each run of the tool
+func (t BuiltinTool) NeedsApproval() bool {
+	if t.RequireApproval != nil {
+		return *t.RequireApproval
+	}
+	return builtinToolsRequiringApproval[t.Type]
 }
 
 // GetAvailableBuiltinTools returns a list of all available built-in tool types
@@ -70,20 +275,33 @@ func GetAvailableBuiltinTools() []string {
 		"browseruse",
 		"commandline",
 		"sequentialthinking",
+		"calculator",
+		"currenttime",
+		"weather",
 	}
 }
 
+// builtinToolsEnabledByDefault lists tools that need no configuration and have no side effects
+var builtinToolsEnabledByDefault = map[string]bool{
+	"calculator":  true,
+	"currenttime": true,
+	"weather":     true, // Read-only lookups on Open-Meteo, which needs no API key
+}
+
 // GetBuiltinToolDescription returns a description for the given tool type
 func GetBuiltinToolDescription(toolType string) string {
 	descriptions := map[string]string{
-		"bingsearch":          "Bing Search - Search the web using Bing search engine",
-		"googlesearch":        "Google Search - Search the web using Google search engine",
-		"wikipedia":           "Wikipedia - Search and retrieve information from Wikipedia",
-		"duckduckgosearch":    "DuckDuckGo Search - Private search using DuckDuckGo",
-		"httprequest":         "HTTP Request - Make HTTP requests to web services",
-		"browseruse":          "Browser Use - Automate browser interactions",
-		"commandline":         "Command Line - Execute shell commands (use with caution)",
-		"sequentialthinking":  "Sequential Thinking - Chain of thought reasoning tool",
+		"bingsearch":         "Bing Search - Search the web using Bing search engine",
+		"googlesearch":       "Google Search - Search the web using Google search engine",
+		"wikipedia":          "Wikipedia - Search and retrieve information from Wikipedia",
+		"duckduckgosearch":   "DuckDuckGo Search - Private search using DuckDuckGo",
+		"httprequest":        "HTTP Request - Make HTTP requests to web services",
+		"browseruse":         "Browser Use - Automate browser interactions",
+		"commandline":        "Command Line - Execute shell commands (use with caution)",
+		"sequentialthinking": "Sequential Thinking - Chain of thought reasoning tool",
+		"calculator":         "Calculator - Exact arithmetic with big numbers, unit conversions and date math",
+		"currenttime":        "Current Time - Current date and time in any time zone",
+		"weather":            "Weather - Current weather for a city or coordinates from Open-Meteo (no API key)",
 	}
 	if desc, ok := descriptions[toolType]; ok {
 		return desc
@@ -110,6 +328,8 @@ func GetBuiltinToolConfigFields(toolType string) []string {
 		return []string{"allowed_commands"}
 	case "sequentialthinking":
 		return []string{"max_iterations"}
+	case "calculator", "currenttime", "weather":
+		return []string{}
 	default:
 		return []string{}
 	}
@@ -134,6 +354,8 @@ func GetRequiredConfigFields(toolType string) []string {
 		return []string{"allowed_commands"} // security requirement
 	case "sequentialthinking":
 		return []string{} // max_iterations has default
+	case "calculator", "currenttime", "weather":
+		return []string{}
 	default:
 		return []string{}
 	}
@@ -153,105 +375,116 @@ func ValidateBuiltinToolConfig(tool BuiltinTool) error {
 	return nil
 }
 
-// LoadConfig loads the configuration from the default location
-func LoadConfig() (*Config, error) {
-	configDir, err := os.UserConfigDir()
-	if err != nil {
-		return nil, err
+// providerTypesRequiringAPIKey lists provider types that can't be used without an API key
+var providerTypesRequiringAPIKey = map[string]bool{
+	"openai":     true,
+	"azure":      true,
+	"anthropic":  true,
+	"claude":     true,
+	"openrouter": true,
+	"qwen":       true,
+	"deepseek":   true,
+	"gemini":     true,
+}
+
+// ValidateProvider checks that a provider has the fields its type needs to make requests
+func ValidateProvider(p Provider) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("provider name cannot be empty")
+	}
+	if strings.TrimSpace(p.Type) == "" {
+		return fmt.Errorf("provider type must be selected for provider '%s'", p.Name)
+	}
+	if providerTypesRequiringAPIKey[p.Type] && strings.TrimSpace(p.APIKey) == "" {
+		return fmt.Errorf("API key is required for %s provider '%s'", p.Type, p.Name)
+	}
+	if p.Type == "ollama" && strings.TrimSpace(p.BaseURL) == "" {
+		return fmt.Errorf("base URL is required for ollama provider '%s'", p.Name)
+	}
+	if p.Type == "azure" {
+		if strings.TrimSpace(p.BaseURL) == "" {
+			return fmt.Errorf("endpoint (base URL) is required for azure provider '%s'", p.Name)
+		}
+		if strings.TrimSpace(p.DeploymentName) == "" {
+			return fmt.Errorf("deployment name is required for azure provider '%s'", p.Name)
+		}
+	}
+	if strings.TrimSpace(p.Model) == "" {
+		return fmt.Errorf("model is required for provider '%s'", p.Name)
+	}
+	if err := ValidateExtras(p.Type, p.Extras); err != nil {
+		return fmt.Errorf("provider '%s': %w", p.Name, err)
 	}
 
-	configPath := filepath.Join(configDir, "chatgo", "config.yaml")
+	return nil
+}
 
-	// Create default config if it doesn't exist
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
-			return nil, err
+// CheckProviderName returns an error when a provider other than the one at index self, -1 for
+// a provider being added, already has the name. Conversations and the provider selector tell
+// providers apart by name, so names differing only in case are taken as the same.
+func (c *Config) CheckProviderName(name string, self int) error {
+	for i, p := range c.Providers {
+		if i != self && strings.EqualFold(strings.TrimSpace(p.Name), strings.TrimSpace(name)) {
+			return fmt.Errorf("a provider named '%s' already exists", p.Name)
 		}
+	}
+	return nil
+}
 
-		// Create default built-in tools
-		builtinTools := createDefaultBuiltinTools()
-
-		defaultConfig := &Config{
-			Providers: []Provider{
-				{
-					Name:    "OpenAI",
-					Type:    "openai",
-					APIKey:  "",
-					BaseURL: "https://api.openai.com/v1",
-					Model:   "gpt-4",
-					Enabled: true,
-				},
-				{
-					Name:    "Claude",
-					Type:    "claude",
-					APIKey:  "",
-					Model:   "claude-3-5-sonnet-20241022",
-					Enabled: true,
-				},
-				{
-					Name:    "Ollama",
-					Type:    "ollama",
-					BaseURL: "http://localhost:11434",
-					Model:   "llama3.2",
-					Enabled: true,
-				},
-				{
-					Name:    "Qwen",
-					Type:    "qwen",
-					APIKey:  "",
-					Model:   "qwen-max",
-					Enabled: false,
-				},
-				{
-					Name:    "DeepSeek",
-					Type:    "deepseek",
-					APIKey:  "",
-					Model:   "deepseek-chat",
-					Enabled: false,
-				},
-				{
-					Name:    "Gemini",
-					Type:    "gemini",
-					APIKey:  "",
-					Model:   "gemini-2.0-flash-exp",
-					Enabled: false,
-				},
-			},
-			MCPServers: []MCPServer{
-				{
-					Name:    "filesystem",
-					Type:    MCPServerTypeStdIO,
-					Enabled: true,
-					Command: "npx",
-					Args:    []string{"-y", "@modelcontextprotocol/server-filesystem", fmt.Sprintf("%s", os.Getenv("HOME"))},
-					Env:     map[string]string{},
-				},
-			},
-			BuiltinTools:      builtinTools,
-			CurrentProvider:   "OpenAI",
-			UseReactAgent:     false,
-			ReactAgentMaxStep: 40,
-		}
+// ProviderRenamed keeps the ${VAR} reference the API key of a provider renamed from old was
+// read from, which is remembered by provider name
+func (c *Config) ProviderRenamed(old, name string) {
+	if p, ok := c.placeholders[providerKeyLocation(old)]; ok {
+		delete(c.placeholders, providerKeyLocation(old))
+		c.placeholders[providerKeyLocation(name)] = p
+	}
+}
 
-		data, err := yaml.Marshal(defaultConfig)
-		if err != nil {
-			return nil, err
+// MCPServerRenamed keeps the ${VAR} references the environment variables and headers of an MCP
+// server renamed from old were read from, which are remembered by server name
+func (c *Config) MCPServerRenamed(old, name string) {
+	if old == name {
+		return
+	}
+	moved := make(map[string]placeholder)
+	for location, p := range c.placeholders {
+		if value, ok := strings.CutSuffix(location, " "+mcpServerLocation(old)); ok {
+			kind, key, _ := strings.Cut(value, " ")
+			delete(c.placeholders, location)
+			moved[mcpValueLocation(name, kind, key)] = p
 		}
+	}
+	maps.Copy(c.placeholders, moved)
+}
 
-		if err := os.WriteFile(configPath, data, 0644); err != nil {
-			return nil, err
-		}
+// LoadConfig loads the configuration from the default location, or from beside the executable in portable mode
+func LoadConfig() (*Config, error) {
+	configPath, err := paths.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
 
-		return defaultConfig, nil
+	// Create default config if it doesn't exist
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return writeDefaultConfig(configPath)
 	}
 
-	data, err := os.ReadFile(configPath)
+	return loadConfigFile(configPath)
+}
+
+// loadConfigFile reads and parses a config file. Problems with its contents are returned
+// as a *ParseError.
+func loadConfigFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	// Defaults for fields that older config files don't contain
+	config := Config{
+		SendOnEnter: DefaultSendOnEnter,
+	}
+	if err := parseConfig(path, data, &config); err != nil {
 		return nil, err
 	}
 
@@ -262,9 +495,195 @@ func LoadConfig() (*Config, error) {
 		config.BuiltinTools = ensureAllBuiltinTools(config.BuiltinTools)
 	}
 
+	config.expandEnv()
+
 	return &config, nil
 }
 
+// defaultConfig returns the configuration a new installation starts with
+func defaultConfig() *Config {
+	// Create default built-in tools
+	builtinTools := createDefaultBuiltinTools()
+
+	// The filesystem server is given the home directory, which HOME doesn't hold on Windows
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+
+	return &Config{
+		Providers: []Provider{
+			{
+				Name:    "OpenAI",
+				Type:    "openai",
+				APIKey:  "",
+				BaseURL: "https://api.openai.com/v1",
+				Model:   "gpt-4",
+				Enabled: true,
+			},
+			{
+				Name:    "Claude",
+				Type:    "claude",
+				APIKey:  "",
+				Model:   "claude-3-5-sonnet-20241022",
+				Enabled: true,
+			},
+			{
+				Name:    "Ollama",
+				Type:    "ollama",
+				BaseURL: "http://localhost:11434",
+				Model:   "llama3.2",
+				Enabled: true,
+			},
+			{
+				Name:    "Qwen",
+				Type:    "qwen",
+				APIKey:  "",
+				Model:   "qwen-max",
+				Enabled: false,
+			},
+			{
+				Name:    "DeepSeek",
+				Type:    "deepseek",
+				APIKey:  "",
+				Model:   "deepseek-chat",
+				Enabled: false,
+			},
+			{
+				Name:    "Gemini",
+				Type:    "gemini",
+				APIKey:  "",
+				Model:   "gemini-2.0-flash-exp",
+				Enabled: false,
+			},
+		},
+		MCPServers: []MCPServer{
+			{
+				Name:    "filesystem",
+				Type:    MCPServerTypeStdIO,
+				Enabled: true,
+				Command: "npx",
+				Args:    []string{"-y", "@modelcontextprotocol/server-filesystem", homeDir},
+				Env:     map[string]string{},
+			},
+		},
+		BuiltinTools:      builtinTools,
+		CurrentProvider:   "OpenAI",
+		UseReactAgent:     false,
+		ReactAgentMaxStep: DefaultReactAgentMaxStep,
+		SendOnEnter:       DefaultSendOnEnter,
+	}
+}
+
+// writeDefaultConfig creates the config file at configPath with the default configuration
+func writeDefaultConfig(configPath string) (*Config, error) {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return nil, err
+	}
+
+	config := defaultConfig()
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// expandEnv replaces ${VAR} references in API keys and MCP server environment variables and
+// headers with values from the environment, so secrets don't have to be kept in the file
+func (c *Config) expandEnv() {
+	c.placeholders = make(map[string]placeholder)
+	for i := range c.Providers {
+		p := &c.Providers[i]
+		p.APIKey = c.expand(providerKeyLocation(p.Name), p.APIKey)
+	}
+	for i := range c.MCPServers {
+		server := &c.MCPServers[i]
+		for key, value := range server.Env {
+			server.Env[key] = c.expand(mcpValueLocation(server.Name, "env", key), value)
+		}
+		for key, value := range server.Headers {
+			server.Headers[key] = c.expand(mcpValueLocation(server.Name, "header", key), value)
+		}
+	}
+}
+
+// expand expands the environment references in value. Unset variables expand to empty with a warning.
+func (c *Config) expand(location, value string) string {
+	if !strings.Contains(value, "$") {
+		return value
+	}
+	expanded := os.Expand(value, func(name string) string {
+		env, ok := os.LookupEnv(name)
+		if !ok {
+			logger.Warn("Environment variable is not set", "variable", name, "used_by", location)
+		}
+		return env
+	})
+	c.placeholders[location] = placeholder{raw: value, expanded: expanded}
+	return expanded
+}
+
+// restore returns the original reference for a value that is still the one expanded from it
+func (c *Config) restore(location, value string) string {
+	if p, ok := c.placeholders[location]; ok && p.expanded == value {
+		return p.raw
+	}
+	return value
+}
+
+// forSaving returns a copy of the configuration with expanded values put back as the ${VAR}
+// references they came from. Values changed since loading are saved as they are.
+func (c *Config) forSaving() *Config {
+	if len(c.placeholders) == 0 {
+		return c
+	}
+
+	saved := *c
+	saved.Providers = make([]Provider, len(c.Providers))
+	for i, p := range c.Providers {
+		p.APIKey = c.restore(providerKeyLocation(p.Name), p.APIKey)
+		saved.Providers[i] = p
+	}
+	saved.MCPServers = make([]MCPServer, len(c.MCPServers))
+	for i, server := range c.MCPServers {
+		server.Env = c.restoreMap(server.Name, "env", server.Env)
+		server.Headers = c.restoreMap(server.Name, "header", server.Headers)
+		saved.MCPServers[i] = server
+	}
+	return &saved
+}
+
+// restoreMap returns a copy of an MCP server's environment or headers with references restored
+func (c *Config) restoreMap(server, kind string, values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	restored := make(map[string]string, len(values))
+	for key, value := range values {
+		restored[key] = c.restore(mcpValueLocation(server, kind, key), value)
+	}
+	return restored
+}
+
+// providerKeyLocation names a provider's API key in warnings and placeholder keys
+func providerKeyLocation(provider string) string {
+	return fmt.Sprintf("the API key of provider '%s'", provider)
+}
+
+// mcpValueLocation names an MCP server environment variable or header in warnings and placeholder keys
+func mcpValueLocation(server, kind, key string) string {
+	return fmt.Sprintf("%s %s %s", kind, key, mcpServerLocation(server))
+}
+
+// mcpServerLocation names an MCP server at the end of its values' locations
+func mcpServerLocation(server string) string {
+	return fmt.Sprintf("of MCP server '%s'", server)
+}
+
 // createDefaultBuiltinTools creates the default list of built-in tools
 func createDefaultBuiltinTools() []BuiltinTool {
 	tools := GetAvailableBuiltinTools()
@@ -274,7 +693,7 @@ func createDefaultBuiltinTools() []BuiltinTool {
 		builtinTools[i] = BuiltinTool{
 			Name:    toolType,
 			Type:    toolType,
-			Enabled: false,
+			Enabled: builtinToolsEnabledByDefault[toolType],
 			Config:  make(map[string]string),
 		}
 	}
@@ -298,11 +717,11 @@ func ensureAllBuiltinTools(existing []BuiltinTool) []BuiltinTool {
 		if tool, exists := existingMap[toolType]; exists {
 			result = append(result, tool)
 		} else {
-			// Add missing tool as disabled
+			// Add missing tool, disabled unless it is safe to enable without configuration
 			result = append(result, BuiltinTool{
 				Name:    toolType,
 				Type:    toolType,
-				Enabled: false,
+				Enabled: builtinToolsEnabledByDefault[toolType],
 				Config:  make(map[string]string),
 			})
 		}
@@ -311,19 +730,89 @@ func ensureAllBuiltinTools(existing []BuiltinTool) []BuiltinTool {
 	return result
 }
 
-// SaveConfig saves the configuration to the default location
+// SaveConfig saves the configuration to the location LoadConfig reads from. The file it
+// replaces is kept as the backup if it was valid.
 func SaveConfig(config *Config) error {
-	configDir, err := os.UserConfigDir()
+	configPath, err := paths.ConfigFile()
 	if err != nil {
 		return err
 	}
 
-	configPath := filepath.Join(configDir, "chatgo", "config.yaml")
-
-	data, err := yaml.Marshal(config)
+	data, err := yaml.Marshal(config.forSaving())
 	if err != nil {
 		return err
 	}
 
+	if previous, err := os.ReadFile(configPath); err == nil && parseConfig(configPath, previous, &Config{}) == nil {
+		if err := os.WriteFile(backupFile(configPath), previous, 0644); err != nil {
+			logger.Warn("Failed to back up config file", "path", configPath, "error", err)
+		}
+	}
 	return os.WriteFile(configPath, data, 0644)
 }
+
+// backupFile returns the path of the copy of the config file kept from before the last save
+func backupFile(configPath string) string {
+	return configPath + ".bak"
+}
+
+// invalidFile returns the path a config file that could not be read is moved to when it is replaced
+func invalidFile(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "config.invalid.yaml")
+}
+
+// HasBackup reports whether there is a backup of the config file to restore
+func HasBackup() bool {
+	configPath, err := paths.ConfigFile()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(backupFile(configPath))
+	return err == nil
+}
+
+// RestoreBackup replaces the config file with its backup and loads it. The replaced file is
+// kept as config.invalid.yaml.
+func RestoreBackup() (*Config, error) {
+	configPath, err := paths.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := loadConfigFile(backupFile(configPath))
+	if err != nil {
+		return nil, fmt.Errorf("backup cannot be used: %w", err)
+	}
+	data, err := os.ReadFile(backupFile(configPath))
+	if err != nil {
+		return nil, err
+	}
+	if err := keepInvalidConfig(configPath); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ResetConfig replaces the config file with the default configuration. The replaced file is
+// kept as config.invalid.yaml.
+func ResetConfig() (*Config, error) {
+	configPath, err := paths.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if err := keepInvalidConfig(configPath); err != nil {
+		return nil, err
+	}
+	return writeDefaultConfig(configPath)
+}
+
+// keepInvalidConfig moves the config file aside so it can be fixed by hand later
+func keepInvalidConfig(configPath string) error {
+	if err := os.Rename(configPath, invalidFile(configPath)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to keep the invalid config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"chatgo/internal/log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadTestConfig writes contents to a config file in a temporary directory and loads it
+func loadTestConfig(t *testing.T, contents string) *Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadConfigFile(path)
+	if err != nil {
+		t.Fatalf("loadConfigFile: %v", err)
+	}
+	return config
+}
+
+const placeholderConfig = `providers:
+  - name: OpenAI
+    type: openai
+    api_key: ${CHATGO_TEST_KEY}
+    model: gpt-4
+    enabled: true
+mcp_servers:
+  - name: search
+    type: streamable_http
+    enabled: true
+    url: http://localhost:8080/mcp
+    env:
+      TOKEN: ${CHATGO_TEST_TOKEN}
+    headers:
+      Authorization: Bearer ${CHATGO_TEST_TOKEN}
+`
+
+func TestExpandMissingVariable(t *testing.T) {
+	t.Setenv("CHATGO_TEST_TOKEN", "secret")
+	t.Setenv("CHATGO_TEST_KEY", "") // Restored after the test
+	os.Unsetenv("CHATGO_TEST_KEY")
+
+	var warnings []log.Entry
+	unsubscribe := log.Subscribe(func(e log.Entry) {
+		if e.Source == "config" && e.Level == log.LevelWarn {
+			warnings = append(warnings, e)
+		}
+	})
+	defer unsubscribe()
+
+	config := loadTestConfig(t, placeholderConfig)
+
+	if got := config.Providers[0].APIKey; got != "" {
+		t.Errorf("API key = %q, want empty for an unset variable", got)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("logged %d warnings, want 1: %v", len(warnings), warnings)
+	}
+	var variable any
+	for _, f := range warnings[0].Fields {
+		if f.Key == "variable" {
+			variable = f.Value
+		}
+	}
+	if variable != "CHATGO_TEST_KEY" {
+		t.Errorf("warning names variable %v, want CHATGO_TEST_KEY", variable)
+	}
+
+	// The reference is still saved, so setting the variable later makes it work
+	if got := config.forSaving().Providers[0].APIKey; got != "${CHATGO_TEST_KEY}" {
+		t.Errorf("saved API key = %q, want the reference", got)
+	}
+}
+
+func TestSavePreservesPlaceholders(t *testing.T) {
+	t.Setenv("CHATGO_TEST_KEY", "sk-test")
+	t.Setenv("CHATGO_TEST_TOKEN", "secret")
+	config := loadTestConfig(t, placeholderConfig)
+
+	if got := config.Providers[0].APIKey; got != "sk-test" {
+		t.Fatalf("API key = %q, want the expanded variable", got)
+	}
+
+	// A value edited after loading is saved as it is, in place of its reference
+	config.MCPServers[0].Headers["Authorization"] = "Bearer typed"
+
+	data, err := yaml.Marshal(config.forSaving())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved Config
+	if err := yaml.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.Providers[0].APIKey; got != "${CHATGO_TEST_KEY}" {
+		t.Errorf("saved API key = %q, want the reference", got)
+	}
+	if got := saved.MCPServers[0].Env["TOKEN"]; got != "${CHATGO_TEST_TOKEN}" {
+		t.Errorf("saved env TOKEN = %q, want the reference", got)
+	}
+	if got := saved.MCPServers[0].Headers["Authorization"]; got != "Bearer typed" {
+		t.Errorf("saved header Authorization = %q, want the edited value", got)
+	}
+
+	// Saving doesn't change the values in use
+	if got := config.Providers[0].APIKey; got != "sk-test" {
+		t.Errorf("API key after saving = %q, want the expanded variable", got)
+	}
+}
+
+func TestMCPServerRenamedKeepsPlaceholders(t *testing.T) {
+	t.Setenv("CHATGO_TEST_TOKEN", "secret")
+	config := loadTestConfig(t, placeholderConfig)
+
+	server := &config.MCPServers[0]
+	if server.Env["TOKEN"] != "secret" || server.Headers["Authorization"] != "Bearer secret" {
+		t.Fatalf("references weren't expanded: env %v, headers %v", server.Env, server.Headers)
+	}
+
+	config.MCPServerRenamed(server.Name, "web search")
+	server.Name = "web search"
+
+	saved := config.forSaving().MCPServers[0]
+	if got := saved.Env["TOKEN"]; got != "${CHATGO_TEST_TOKEN}" {
+		t.Errorf("saved env TOKEN = %q, want the reference", got)
+	}
+	if got := saved.Headers["Authorization"]; got != "Bearer ${CHATGO_TEST_TOKEN}" {
+		t.Errorf("saved header Authorization = %q, want the reference", got)
+	}
+	for location := range config.placeholders {
+		if location == mcpValueLocation("search", "env", "TOKEN") || location == mcpValueLocation("search", "header", "Authorization") {
+			t.Errorf("placeholder %q is still kept under the old name", location)
+		}
+	}
+}
+
+func TestMCPServerRenamedLeavesOtherServers(t *testing.T) {
+	t.Setenv("CHATGO_TEST_TOKEN", "secret")
+	config := loadTestConfig(t, placeholderConfig+`  - name: other
+    type: stdio
+    enabled: true
+    command: other
+    env:
+      TOKEN: ${CHATGO_TEST_TOKEN}
+`)
+
+	config.MCPServerRenamed("search", "web search")
+	config.MCPServers[0].Name = "web search"
+
+	saved := config.forSaving()
+	for _, server := range saved.MCPServers {
+		if got := server.Env["TOKEN"]; got != "${CHATGO_TEST_TOKEN}" {
+			t.Errorf("saved env TOKEN of %s = %q, want the reference", server.Name, got)
+		}
+	}
+}
+
+func TestSendOnEnterDefault(t *testing.T) {
+	if !defaultConfig().SendOnEnter {
+		t.Error("a new installation doesn't send on Enter")
+	}
+
+	// A file written before the setting existed
+	config := loadTestConfig(t, "current_provider: OpenAI\n")
+	if config.SendOnEnter != defaultConfig().SendOnEnter {
+		t.Errorf("SendOnEnter of a file without the setting = %v, want %v as for a new installation",
+			config.SendOnEnter, defaultConfig().SendOnEnter)
+	}
+
+	config = loadTestConfig(t, "send_on_enter: false\n")
+	if config.SendOnEnter {
+		t.Error("send_on_enter: false was overridden by the default")
+	}
+}
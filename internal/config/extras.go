@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ExtraKind is the type of value a provider extra holds
+type ExtraKind string
+
+const (
+	ExtraString ExtraKind = "string"
+	ExtraInt    ExtraKind = "int"
+	ExtraBool   ExtraKind = "bool"
+)
+
+// ExtraKey describes an option a provider type reads from Provider.Extras
+type ExtraKey struct {
+	Name        string
+	Kind        ExtraKind
+	Values      []string // Values a string option accepts; empty accepts any
+	Description string
+}
+
+// Names of the provider extras, each read by its provider type's branch of llm.NewClient
+const (
+	ExtraOllamaNumCtx          = "num_ctx"
+	ExtraAnthropicMaxTokens    = "max_tokens"
+	ExtraGeminiSafetyThreshold = "safety_threshold"
+	ExtraGeminiGoogleSearch    = "google_search"
+	ExtraOpenRouterReferer     = "http_referer"
+	ExtraOpenRouterTitle       = "x_title"
+)
+
+// anthropicExtras are shared by the anthropic type and its claude alias
+var anthropicExtras = []ExtraKey{
+	{Name: ExtraAnthropicMaxTokens, Kind: ExtraInt, Description: "Most tokens in a response"},
+}
+
+// KnownExtras lists the extras each provider type reads, in the order the provider form suggests
+// them. A key added to llm.NewClient must be added here too, so the form offers and checks it.
+var KnownExtras = map[string][]ExtraKey{
+	"ollama": {
+		{Name: ExtraOllamaNumCtx, Kind: ExtraInt, Description: "Context window in tokens; the model's default when unset"},
+	},
+	"anthropic": anthropicExtras,
+	"claude":    anthropicExtras,
+	"gemini": {
+		{Name: ExtraGeminiSafetyThreshold, Kind: ExtraString, Description: "Blocks harmful content from this probability on, for every category",
+			Values: []string{"BLOCK_NONE", "BLOCK_ONLY_HIGH", "BLOCK_MEDIUM_AND_ABOVE", "BLOCK_LOW_AND_ABOVE", "OFF"}},
+		{Name: ExtraGeminiGoogleSearch, Kind: ExtraBool, Description: "Grounds answers in Google Search results"},
+	},
+	"openrouter": {
+		{Name: ExtraOpenRouterReferer, Kind: ExtraString, Description: "Site URL OpenRouter attributes requests to, in place of ChatGo's"},
+		{Name: ExtraOpenRouterTitle, Kind: ExtraString, Description: "App name OpenRouter attributes requests to, in place of ChatGo"},
+	},
+}
+
+// KnownExtra returns the description of an extra a provider type reads
+func KnownExtra(providerType, name string) (ExtraKey, bool) {
+	i := slices.IndexFunc(KnownExtras[providerType], func(key ExtraKey) bool { return key.Name == name })
+	if i < 0 {
+		return ExtraKey{}, false
+	}
+	return KnownExtras[providerType][i], true
+}
+
+// GetString returns the extra named key, or def when it is unset or blank
+func (p Provider) GetString(key, def string) string {
+	if value := strings.TrimSpace(p.Extras[key]); value != "" {
+		return value
+	}
+	return def
+}
+
+// GetInt returns the extra named key as a number, or def when it is unset or not a number
+func (p Provider) GetInt(key string, def int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(p.Extras[key]))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// GetBool returns the extra named key as a boolean, such as "true" or "0", or def when it is
+// unset or not a boolean
+func (p Provider) GetBool(key string, def bool) bool {
+	value, err := strconv.ParseBool(strings.TrimSpace(p.Extras[key]))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// ValidateExtras checks that the extras a provider type knows have values of their kind. Blank
+// values count as unset. Other keys are allowed, as a newer version may read them.
+func ValidateExtras(providerType string, extras map[string]string) error {
+	for _, key := range KnownExtras[providerType] {
+		value := strings.TrimSpace(extras[key.Name])
+		if value == "" {
+			continue
+		}
+		if err := key.validate(value); err != nil {
+			return fmt.Errorf("option %s: %w", key.Name, err)
+		}
+	}
+	return nil
+}
+
+// validate checks that a value suits the option
+func (k ExtraKey) validate(value string) error {
+	switch k.Kind {
+	case ExtraInt:
+		if n, err := strconv.Atoi(value); err != nil || n <= 0 {
+			return fmt.Errorf("'%s' is not a positive number", value)
+		}
+	case ExtraBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("'%s' is not true or false", value)
+		}
+	default:
+		if len(k.Values) > 0 && !slices.Contains(k.Values, value) {
+			return fmt.Errorf("'%s' is not one of %s", value, strings.Join(k.Values, ", "))
+		}
+	}
+	return nil
+}
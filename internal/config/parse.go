@@ -0,0 +1,216 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// legacyKeys maps key spellings found in hand-written config files, which were ignored
+// before unknown keys became errors, to the keys they meant. They are renamed on load so
+// those files keep working.
+var legacyKeys = map[string]string{
+	"apiKey":          "api_key",
+	"baseURL":         "base_url",
+	"baseUrl":         "base_url",
+	"mcpServers":      "mcp_servers",
+	"builtinTools":    "builtin_tools",
+	"currentProvider": "current_provider",
+}
+
+// ParseError is a config file that could not be read, with where the problem is
+type ParseError struct {
+	Path    string
+	Line    int    // 1-based; 0 when unknown
+	Column  int    // 1-based; 0 when unknown
+	Key     string // The offending key, if known
+	Message string
+	More    int    // Further problems found after this one
+	Source  []byte // Contents of the file
+}
+
+// Error describes the problem with its location, e.g. "config.yaml:12:5: unknown key "api-key""
+func (e *ParseError) Error() string {
+	location := e.Path
+	if e.Line > 0 {
+		location += ":" + strconv.Itoa(e.Line)
+		if e.Column > 0 {
+			location += ":" + strconv.Itoa(e.Column)
+		}
+	}
+	msg := fmt.Sprintf("%s: %s", location, e.Message)
+	if e.More > 0 {
+		msg += fmt.Sprintf(" (and %d more problems)", e.More)
+	}
+	return msg
+}
+
+// Snippet returns the lines around the error, numbered, with a caret under its column
+func (e *ParseError) Snippet(context int) string {
+	if e.Line == 0 {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSuffix(string(e.Source), "\n"), "\n")
+	first, last := max(e.Line-context, 1), min(e.Line+context, len(lines))
+	width := len(strconv.Itoa(last))
+
+	var b strings.Builder
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == e.Line {
+			marker = ">"
+		}
+		// Tabs are shown as arrows so stray ones are visible
+		line := strings.ReplaceAll(lines[n-1], "\t", "→")
+		fmt.Fprintf(&b, "%s %*d | %s\n", marker, width, n, line)
+		if n == e.Line && e.Column > 0 {
+			fmt.Fprintf(&b, "  %*s | %s^\n", width, "", strings.Repeat(" ", e.Column-1))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Patterns of the messages yaml.v3 reports problems with
+var (
+	yamlLinePattern         = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	yamlUnknownFieldPattern = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+)
+
+// parseConfig decodes a config file into config. Unknown keys are errors, except the legacy
+// spellings in legacyKeys, which are renamed. Problems are returned as a *ParseError.
+func parseConfig(path string, data []byte, config *Config) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return newParseError(path, data, &root, []string{strings.TrimPrefix(err.Error(), "yaml: ")})
+	}
+
+	// Decode strictly first to find unknown keys and values of the wrong type
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var strict Config
+	if err := decoder.Decode(&strict); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return newParseError(path, data, &root, []string{err.Error()})
+		}
+		var problems []string
+		for _, problem := range typeErr.Errors {
+			if !isLegacyKeyError(problem) {
+				problems = append(problems, problem)
+			}
+		}
+		if len(problems) > 0 {
+			return newParseError(path, data, &root, problems)
+		}
+	}
+
+	if renameLegacyKeys(&root) {
+		logger.Info("Renamed legacy keys; they are written with their current names on the next save", "path", path)
+	}
+	if len(root.Content) == 0 {
+		return nil
+	}
+	if err := root.Decode(config); err != nil {
+		return newParseError(path, data, &root, []string{err.Error()})
+	}
+	return nil
+}
+
+// isLegacyKeyError reports whether a strict decoding problem is a key that renameLegacyKeys renames
+func isLegacyKeyError(problem string) bool {
+	m := yamlLinePattern.FindStringSubmatch(problem)
+	if m == nil {
+		return false
+	}
+	field := yamlUnknownFieldPattern.FindStringSubmatch(m[2])
+	return field != nil && legacyKeys[field[1]] != ""
+}
+
+// renameLegacyKeys renames the legacy keys in every mapping of the document, unless the
+// current key is also present. It reports whether any were renamed.
+func renameLegacyKeys(node *yaml.Node) bool {
+	renamed := false
+	if node.Kind == yaml.MappingNode {
+		present := make(map[string]bool)
+		for i := 0; i < len(node.Content); i += 2 {
+			present[node.Content[i].Value] = true
+		}
+		for i := 0; i < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if current := legacyKeys[key.Value]; current != "" && !present[current] {
+				key.Value = current
+				present[current] = true
+				renamed = true
+			}
+		}
+	}
+	for _, child := range node.Content {
+		renamed = renameLegacyKeys(child) || renamed
+	}
+	return renamed
+}
+
+// newParseError builds the error for the first of the problems yaml.v3 reported. The line
+// comes from the message; the column and key are found in the parsed document when it
+// could be parsed, or from a tab in the line's indentation.
+func newParseError(path string, data []byte, root *yaml.Node, problems []string) *ParseError {
+	e := &ParseError{Path: path, Message: problems[0], More: len(problems) - 1, Source: data}
+	m := yamlLinePattern.FindStringSubmatch(problems[0])
+	if m == nil {
+		return e
+	}
+	e.Line, _ = strconv.Atoi(m[1])
+	e.Message = m[2]
+
+	if field := yamlUnknownFieldPattern.FindStringSubmatch(e.Message); field != nil {
+		e.Key = field[1]
+		e.Message = fmt.Sprintf("unknown key %q", e.Key)
+		if key := findKeyNode(root, e.Line, e.Key); key != nil {
+			e.Column = key.Column
+		}
+	} else if key := findKeyNode(root, e.Line, ""); key != nil {
+		e.Key = key.Value
+		e.Column = key.Column
+		e.Message = fmt.Sprintf("invalid value for %q: %s", e.Key, e.Message)
+	}
+
+	// The parser reports a tab in the indentation on the line before it when it ends a block
+	lines := strings.Split(string(data), "\n")
+	for n := e.Line; e.Column == 0 && n <= min(e.Line+1, len(lines)); n++ {
+		line := lines[n-1]
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if tab := strings.IndexByte(indent, '\t'); tab >= 0 {
+			e.Line, e.Column = n, tab+1
+			e.Message = "tab character in indentation; indent with spaces"
+		}
+	}
+	return e
+}
+
+// findKeyNode finds the mapping key on the given line whose value is name, or the key of the
+// value on that line when name is empty
+func findKeyNode(node *yaml.Node, line int, name string) *yaml.Node {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Line == line && (name == "" || key.Value == name) {
+				return key
+			}
+			if name == "" && value.Line == line && value.Kind == yaml.ScalarNode {
+				return key
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if found := findKeyNode(child, line, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
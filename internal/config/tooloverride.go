@@ -0,0 +1,41 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// ToolOverride replaces the name and description a tool is shown with, such as a translation of an
+// MCP server's English description. Models are still sent the tool's own name and description.
+type ToolOverride struct {
+	Tool        string `yaml:"tool"`                   // Tool selection ID, as in "builtin:calculator" or "mcp:server:tool"
+	DisplayName string `yaml:"display_name,omitempty"` // Empty shows the tool's own name
+	Description string `yaml:"description,omitempty"`  // Empty shows the tool's own description
+}
+
+// ToolOverride returns the override of the tool with the given selection ID
+func (c *Config) ToolOverride(tool string) (ToolOverride, bool) {
+	i := slices.IndexFunc(c.ToolOverrides, func(o ToolOverride) bool { return o.Tool == tool })
+	if i < 0 {
+		return ToolOverride{}, false
+	}
+	return c.ToolOverrides[i], true
+}
+
+// SetToolOverride adds or replaces the override of o.Tool. An override with neither a name nor
+// a description removes it.
+func (c *Config) SetToolOverride(o ToolOverride) {
+	o.DisplayName = strings.TrimSpace(o.DisplayName)
+	o.Description = strings.TrimSpace(o.Description)
+	i := slices.IndexFunc(c.ToolOverrides, func(existing ToolOverride) bool { return existing.Tool == o.Tool })
+	switch {
+	case o.DisplayName == "" && o.Description == "":
+		if i >= 0 {
+			c.ToolOverrides = slices.Delete(c.ToolOverrides, i, i+1)
+		}
+	case i >= 0:
+		c.ToolOverrides[i] = o
+	default:
+		c.ToolOverrides = append(c.ToolOverrides, o)
+	}
+}
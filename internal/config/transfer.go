@@ -0,0 +1,188 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExportConfig writes the whole configuration to path in the config file format, for moving it
+// to another machine. ${VAR} references are written as references. With redactKeys set, API
+// keys typed into the config are left out, so the file can be shared; ImportConfig then keeps
+// the keys already configured for providers of the same name.
+func ExportConfig(config *Config, path string, redactKeys bool) error {
+	saved := config.forSaving()
+	exported := *saved
+	if redactKeys {
+		exported.Providers = make([]Provider, len(saved.Providers))
+		for i, p := range saved.Providers {
+			if !isEnvReference(p.APIKey) {
+				p.APIKey = ""
+			}
+			exported.Providers[i] = p
+		}
+	}
+
+	data, err := yaml.Marshal(&exported)
+	if err != nil {
+		return err
+	}
+	// Exports may hold API keys, so they are only readable by the user
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// isEnvReference reports whether value is made only of ${VAR} references, which hold no secret
+func isEnvReference(value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false
+	}
+	return os.Expand(value, func(string) string { return "" }) == ""
+}
+
+// ImportConfig reads a configuration written by ExportConfig, or a config file copied from
+// another machine. Problems with its contents are returned as a *ParseError; providers and MCP
+// servers must have unique, non-empty names. The result is not saved; apply it with Merge or
+// Replace.
+func ImportConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+
+	imported, err := loadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := imported.validateNames(); err != nil {
+		return nil, fmt.Errorf("%s cannot be imported: %w", path, err)
+	}
+	return imported, nil
+}
+
+// validateNames checks that providers and MCP servers can be told apart by name
+func (c *Config) validateNames() error {
+	providers := make(map[string]bool)
+	for i, p := range c.Providers {
+		if strings.TrimSpace(p.Name) == "" {
+			return fmt.Errorf("provider %d has no name", i+1)
+		}
+		if providers[p.Name] {
+			return fmt.Errorf("provider '%s' appears more than once", p.Name)
+		}
+		providers[p.Name] = true
+	}
+
+	servers := make(map[string]bool)
+	for i, server := range c.MCPServers {
+		if strings.TrimSpace(server.Name) == "" {
+			return fmt.Errorf("MCP server %d has no name", i+1)
+		}
+		if servers[server.Name] {
+			return fmt.Errorf("MCP server '%s' appears more than once", server.Name)
+		}
+		switch server.Type {
+		case "", MCPServerTypeStdIO, MCPServerTypeSSE, MCPServerTypeStreamableHTTP:
+		default:
+			return fmt.Errorf("MCP server '%s' has unknown type '%s'", server.Name, server.Type)
+		}
+		servers[server.Name] = true
+	}
+	return nil
+}
+
+// Merge adds the providers, MCP servers, built-in tool settings and tool overrides of an
+// imported configuration. Imported entries replace the ones of the same name; the rest are kept,
+// as are the other settings. A provider imported without an API key keeps the key it had.
+func (c *Config) Merge(imported *Config) {
+	imported.keepRedactedKeys(c)
+
+	for _, p := range imported.Providers {
+		if i := c.providerIndex(p.Name); i >= 0 {
+			c.Providers[i] = p
+		} else {
+			c.Providers = append(c.Providers, p)
+		}
+	}
+	for _, server := range imported.MCPServers {
+		if i := c.mcpServerIndex(server.Name); i >= 0 {
+			c.MCPServers[i] = server
+		} else {
+			c.MCPServers = append(c.MCPServers, server)
+		}
+	}
+	for _, tool := range imported.BuiltinTools {
+		for i := range c.BuiltinTools {
+			if c.BuiltinTools[i].Type == tool.Type {
+				c.BuiltinTools[i] = tool
+			}
+		}
+	}
+	for _, override := range imported.ToolOverrides {
+		c.SetToolOverride(override)
+	}
+
+	if c.placeholders == nil {
+		c.placeholders = make(map[string]placeholder)
+	}
+	for location, p := range imported.placeholders {
+		c.placeholders[location] = p
+	}
+}
+
+// Replace replaces the whole configuration with an imported one. A provider imported without
+// an API key keeps the key of the provider of the same name, if there was one.
+func (c *Config) Replace(imported *Config) {
+	imported.keepRedactedKeys(c)
+	*c = *imported
+}
+
+// keepRedactedKeys fills in the API keys left out of an export from the providers of the same
+// name in current, along with the ${VAR} references they were expanded from
+func (c *Config) keepRedactedKeys(current *Config) {
+	for i := range c.Providers {
+		p := &c.Providers[i]
+		j := current.providerIndex(p.Name)
+		if p.APIKey != "" || j < 0 {
+			continue
+		}
+		p.APIKey = current.Providers[j].APIKey
+		location := providerKeyLocation(p.Name)
+		if ref, ok := current.placeholders[location]; ok {
+			if c.placeholders == nil {
+				c.placeholders = make(map[string]placeholder)
+			}
+			c.placeholders[location] = ref
+		}
+	}
+}
+
+// providerIndex returns the position of the provider named name, ignoring case, or -1 when
+// there is none
+func (c *Config) providerIndex(name string) int {
+	for i, p := range c.Providers {
+		if strings.EqualFold(p.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// mcpServerIndex returns the position of the MCP server named name, or -1 when there is none
+func (c *Config) mcpServerIndex(name string) int {
+	for i, server := range c.MCPServers {
+		if server.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,66 @@
+// Package httpclient builds the HTTP clients used for outbound LLM and MCP requests
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// supportedProxySchemes lists the proxy URL schemes understood by net/http
+var supportedProxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+// ValidateProxyURL checks that raw is a usable HTTP, HTTPS or SOCKS5 proxy URL.
+// An empty string is valid and means the standard proxy environment variables are used.
+func ValidateProxyURL(raw string) error {
+	_, err := parseProxyURL(raw)
+	return err
+}
+
+// New returns an HTTP client that sends requests through the given proxy.
+// When proxy is empty, HTTP_PROXY, HTTPS_PROXY and NO_PROXY from the environment apply.
+func New(proxy string) (*http.Client, error) {
+	proxyURL, err := parseProxyURL(proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	} else {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// parseProxyURL parses and validates a proxy URL, returning nil for an empty string
+func parseProxyURL(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", raw, err)
+	}
+
+	supported := false
+	for _, scheme := range supportedProxySchemes {
+		if proxyURL.Scheme == scheme {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be one of %s", raw, strings.Join(supportedProxySchemes, ", "))
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", raw)
+	}
+
+	return proxyURL, nil
+}
@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// toolDeclinedResult is returned to the model in place of the result of a declined tool call
+const toolDeclinedResult = "The user declined to run this tool call. Do not retry it; continue without its result or ask the user how to proceed."
+
+// ToolApprover decides whether the agent may run a tool call. It is called from the tool's
+// goroutine before the tool runs and may block until the user has answered.
+type ToolApprover func(ctx context.Context, name, arguments string) (bool, error)
+
+// toolApproverKey is the context key of the approver for the current request
+type toolApproverKey struct{}
+
+// WithToolApprover returns a context whose tool calls run only once approve allows them.
+// A declined call is not run; the model is told the user declined it instead.
+func WithToolApprover(ctx context.Context, approve ToolApprover) context.Context {
+	return context.WithValue(ctx, toolApproverKey{}, approve)
+}
+
+// approveToolCall asks the approver of the current request about a call.
+// Requests without an approver run every call.
+func approveToolCall(ctx context.Context, input *compose.ToolInput) (bool, error) {
+	approve, _ := ctx.Value(toolApproverKey{}).(ToolApprover)
+	if approve == nil {
+		return true, nil
+	}
+	return approve(ctx, input.Name, input.Arguments)
+}
+
+// toolApprovalMiddleware holds back every tool invocation until the request's approver allows it
+var toolApprovalMiddleware = compose.ToolMiddleware{
+	Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+		return func(ctx context.Context, input *compose.ToolInput) (*compose.ToolOutput, error) {
+			approved, err := approveToolCall(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+			if !approved {
+				return &compose.ToolOutput{Result: toolDeclinedResult}, nil
+			}
+			return next(ctx, input)
+		}
+	},
+	Streamable: func(next compose.StreamableToolEndpoint) compose.StreamableToolEndpoint {
+		return func(ctx context.Context, input *compose.ToolInput) (*compose.StreamToolOutput, error) {
+			approved, err := approveToolCall(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+			if !approved {
+				return &compose.StreamToolOutput{Result: schema.StreamReaderFromArray([]string{toolDeclinedResult})}, nil
+			}
+			return next(ctx, input)
+		}
+	},
+}
@@ -0,0 +1,5 @@
+package llm
+
+// AzureDefaultAPIVersion is the Azure OpenAI API version used when an azure provider sets none.
+// It is a GA version that supports tool calling and image input.
+const AzureDefaultAPIVersion = "2024-10-21"
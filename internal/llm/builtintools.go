@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"chatgo/internal/calculator"
+	"chatgo/internal/config"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrBuiltinToolNotWired is returned by BuildEinoTool for tool types that can be configured
+// but have no implementation in this build yet
+var ErrBuiltinToolNotWired = errors.New("built-in tool is not available in this build")
+
+// builtinToolComponents names the eino-ext component each unwired tool type is meant to use
+var builtinToolComponents = map[string]string{
+	"bingsearch":         "components/tool/bingsearch",
+	"googlesearch":       "components/tool/googlesearch",
+	"wikipedia":          "components/tool/wikipedia",
+	"duckduckgosearch":   "components/tool/duckduckgo/v2",
+	"httprequest":        "components/tool/httprequest",
+	"browseruse":         "components/tool/browseruse",
+	"commandline":        "components/tool/commandline",
+	"sequentialthinking": "components/tool/sequentialthinking",
+}
+
+// BuildEinoTool creates the eino tool for a configured built-in tool, named after the
+// tool's configured name. Tools that make requests use opts.HTTPClient. Tool types that
+// are not wired yet return an error wrapping ErrBuiltinToolNotWired that names the missing component.
+func BuildEinoTool(t config.BuiltinTool, opts ClientOptions) (tool.BaseTool, error) {
+	if err := config.ValidateBuiltinToolConfig(t); err != nil {
+		return nil, err
+	}
+
+	switch t.Type {
+	case "calculator":
+		return newToolWrapper(calculatorToolDefinition(t.Name)), nil
+	case "currenttime":
+		return newToolWrapper(currentTimeToolDefinition(t.Name)), nil
+	case "weather":
+		return newToolWrapper(weatherToolDefinition(t.Name, opts.HTTPClient)), nil
+	}
+
+	if component, ok := builtinToolComponents[t.Type]; ok {
+		return nil, fmt.Errorf("%w: %s needs github.com/cloudwego/eino-ext/%s", ErrBuiltinToolNotWired, t.Type, component)
+	}
+	return nil, fmt.Errorf("unknown built-in tool type: %s", t.Type)
+}
+
+// calculatorToolDefinition creates the calculator tool, which evaluates expressions locally
+func calculatorToolDefinition(name string) ToolDefinition {
+	return ToolDefinition{
+		Name:        name,
+		Description: calculator.Description,
+		Parameters: map[string]*schema.ParameterInfo{
+			"expression": {
+				Type:     schema.String,
+				Desc:     `The expression to evaluate, e.g. "2^64 - 1", "5 km + 300 m to mi" or "2024-01-31 + 1 month"`,
+				Required: true,
+			},
+		},
+		Handler: func(ctx context.Context, arguments string) (string, error) {
+			var args struct {
+				Expression string `json:"expression"`
+			}
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("invalid calculator arguments: %w", err)
+			}
+
+			result, err := calculator.Evaluate(args.Expression)
+			if err != nil {
+				// Return the error as the result so the model can correct the expression
+				return fmt.Sprintf("Error: %v", err), nil
+			}
+			return result.String(), nil
+		},
+	}
+}
@@ -0,0 +1,130 @@
+package llm
+
+import (
+	"strings"
+)
+
+// Capability is something a request may need that not every model has
+type Capability string
+
+const (
+	CapabilityVision  Capability = "vision"  // Reading images sent with a message
+	CapabilityTools   Capability = "tools"   // Calling the tools sent with the request
+	CapabilityContext Capability = "context" // A context window holding the whole request
+)
+
+// Requirements are the capabilities a request needs from the model it is sent to
+type Requirements struct {
+	Vision bool // A message carries images
+	Tools  bool // Tools are sent with the request
+	Tokens int  // Estimated tokens of the messages
+}
+
+// RequirementsOf returns what sending messages needs from the model; withTools is whether
+// tools are sent along
+func RequirementsOf(messages []ChatMessage, withTools bool) Requirements {
+	req := Requirements{Tools: withTools}
+	for _, msg := range messages {
+		if len(msg.Images) > 0 {
+			req.Vision = true
+		}
+		req.Tokens += msg.estimatedTokens()
+	}
+	return req
+}
+
+// MetBy reports whether a model the catalog describes can take the request: it reads images
+// and calls tools when the request needs to, and its context window, when known, holds the
+// request with room for the reply
+func (r Requirements) MetBy(info ModelInfo) bool {
+	if r.Vision && !info.Vision || r.Tools && !info.ToolCalling {
+		return false
+	}
+	return info.ContextWindow == 0 || r.Tokens < info.ContextWindow*3/4
+}
+
+// capabilityRefusals are phrases in errors refusing a request for lack of a capability, per
+// capability, matched in lower case. Each entry lists phrases that must all appear.
+var capabilityRefusals = []struct {
+	capability Capability
+	phrases    [][]string
+}{
+	{CapabilityContext, [][]string{
+		{"context_length_exceeded"},
+		{"maximum context length"},
+		{"context length"},
+		{"context window"},
+		{"prompt is too long"},
+		{"input is too long"},
+		{"too many tokens"},
+	}},
+	{CapabilityVision, [][]string{
+		{"image", "not support"},
+		{"image", "unsupported"},
+		{"image", "only supported"},
+		{"vision", "not support"},
+		{"multimodal", "not support"},
+	}},
+	{CapabilityTools, [][]string{
+		{"tool", "not support"},
+		{"tool", "unsupported"},
+		{"function", "not support"},
+		{"function call", "unsupported"},
+	}},
+}
+
+// CapabilityError is a request refused because the model lacks a capability the request needs
+type CapabilityError struct {
+	Missing      Capability
+	Requirements Requirements
+	Err          error
+}
+
+// Error returns the provider's error
+func (e *CapabilityError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the provider's error
+func (e *CapabilityError) Unwrap() error {
+	return e.Err
+}
+
+// AsCapabilityError returns err as a CapabilityError when it refuses a request with
+// requirements req for lack of a capability the request needs, or nil. Only capabilities
+// the request needed are considered, so an unrelated mention of images or tools isn't taken
+// for one.
+func AsCapabilityError(err error, req Requirements) *CapabilityError {
+	if err == nil {
+		return nil
+	}
+	msg := strings.ToLower(err.Error())
+	for _, refusal := range capabilityRefusals {
+		switch refusal.capability {
+		case CapabilityVision:
+			if !req.Vision {
+				continue
+			}
+		case CapabilityTools:
+			if !req.Tools {
+				continue
+			}
+		}
+		for _, phrases := range refusal.phrases {
+			if containsAll(msg, phrases) {
+				return &CapabilityError{Missing: refusal.capability, Requirements: req, Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+// containsAll reports whether s contains every one of substrs
+func containsAll(s string, substrs []string) bool {
+	for _, substr := range substrs {
+		if !strings.Contains(s, substr) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,342 @@
+package llm
+
+import (
+	"chatgo/internal/config"
+	"chatgo/internal/paths"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// bundledCatalog is the model catalog shipped with the application. A model_catalog.json
+// of the same format in the config directory updates it without a new release.
+//
+//go:embed model_catalog.json
+var bundledCatalog []byte
+
+// ModelInfo is what the catalog knows about a model. Zero values mean unknown.
+type ModelInfo struct {
+	ID            string        `json:"id"`
+	ContextWindow int           `json:"context_window,omitempty"` // Tokens, prompt and completion together
+	ToolCalling   bool          `json:"tool_calling"`
+	Vision        bool          `json:"vision"`
+	MaxTools      int           `json:"max_tools,omitempty"` // Most tools per request; 0 uses the provider type's limit
+	Pricing       *ModelPricing `json:"pricing,omitempty"`   // nil when the price is unknown
+}
+
+// ModelPricing is the list price in USD per million tokens
+type ModelPricing struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// EstimateCost returns the list price of the given usage in USD. ok is false without pricing data.
+func (m ModelInfo) EstimateCost(usage TokenUsage) (cost float64, ok bool) {
+	if m.Pricing == nil {
+		return 0, false
+	}
+	return (float64(usage.PromptTokens)*m.Pricing.Input + float64(usage.CompletionTokens)*m.Pricing.Output) / 1e6, true
+}
+
+// catalogFile is the JSON layout of the bundled and user catalogs
+type catalogFile struct {
+	Updated    string                 `json:"updated"`
+	Providers  map[string][]ModelInfo `json:"providers"`
+	ToolLimits map[string]int         `json:"tool_limits,omitempty"` // Most tools per request by provider type
+}
+
+// Catalog holds model metadata per provider type, with user overrides from the configuration
+type Catalog struct {
+	once       sync.Once
+	mu         sync.RWMutex
+	updated    string
+	providers  map[string][]ModelInfo
+	toolLimits map[string]int
+	overrides  []config.ModelOverride
+}
+
+// ModelCatalog is the catalog of popular models, loaded on first use
+var ModelCatalog = &Catalog{}
+
+// catalogAliases maps provider types to the catalog section describing their models
+var catalogAliases = map[string]string{
+	"claude": "anthropic",
+	"azure":  "openai", // Azure OpenAI deployments serve OpenAI's models
+}
+
+// load reads the bundled catalog and merges the user catalog on top of it
+func (c *Catalog) load() {
+	c.once.Do(func() {
+		var bundled catalogFile
+		if err := json.Unmarshal(bundledCatalog, &bundled); err != nil {
+			logger.Error("Failed to parse bundled model catalog", "error", err)
+		}
+		c.updated = bundled.Updated
+		c.providers = bundled.Providers
+		if c.providers == nil {
+			c.providers = make(map[string][]ModelInfo)
+		}
+		c.toolLimits = bundled.ToolLimits
+		if c.toolLimits == nil {
+			c.toolLimits = make(map[string]int)
+		}
+
+		user, err := loadUserCatalog()
+		if err != nil {
+			logger.Warn("Ignoring model catalog update", "error", err)
+			return
+		}
+		if user == nil {
+			return
+		}
+		if user.Updated != "" {
+			c.updated = user.Updated
+		}
+		for providerType, models := range user.Providers {
+			for _, model := range models {
+				c.providers[providerType] = upsertModel(c.providers[providerType], model)
+			}
+		}
+		for providerType, limit := range user.ToolLimits {
+			c.toolLimits[providerType] = limit
+		}
+	})
+}
+
+// loadUserCatalog reads model_catalog.json from the config directory. It returns nil if there is none.
+func loadUserCatalog() (*catalogFile, error) {
+	path, err := paths.ModelCatalogFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var file catalogFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// upsertModel replaces the entry with the same ID or appends the model
+func upsertModel(models []ModelInfo, model ModelInfo) []ModelInfo {
+	for i := range models {
+		if models[i].ID == model.ID {
+			models[i] = model
+			return models
+		}
+	}
+	return append(models, model)
+}
+
+// SetOverrides sets the user's model overrides, which take precedence over catalog data
+func (c *Catalog) SetOverrides(overrides []config.ModelOverride) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overrides = append([]config.ModelOverride(nil), overrides...)
+}
+
+// Updated returns the date of the catalog data
+func (c *Catalog) Updated() string {
+	c.load()
+	return c.updated
+}
+
+// Models returns the catalog models for a provider type, sorted by ID.
+// The "custom" type is OpenAI-compatible and may serve any model, so it lists every model.
+func (c *Catalog) Models(providerType string) []ModelInfo {
+	c.load()
+	var models []ModelInfo
+	for _, section := range c.sections(providerType) {
+		models = append(models, c.providers[section]...)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models
+}
+
+// Search returns the catalog models for a provider type whose ID contains query, ignoring case
+func (c *Catalog) Search(providerType, query string) []ModelInfo {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var matches []ModelInfo
+	for _, model := range c.Models(providerType) {
+		if strings.Contains(strings.ToLower(model.ID), query) {
+			matches = append(matches, model)
+		}
+	}
+	return matches
+}
+
+// Lookup returns the metadata for a model, with user overrides applied. Dated or tagged
+// variants such as gpt-4o-2024-08-06 or llama3.1:8b match their base entry. ok is false
+// when neither the catalog nor an override knows the model.
+func (c *Catalog) Lookup(providerType, model string) (info ModelInfo, ok bool) {
+	c.load()
+	model = strings.TrimSpace(model)
+	if model == "" {
+		return ModelInfo{}, false
+	}
+
+	info, ok = c.find(providerType, model)
+	info.ID = model
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, override := range c.overrides {
+		if !strings.EqualFold(override.Model, model) {
+			continue
+		}
+		if override.ProviderType != "" && override.ProviderType != providerType {
+			continue
+		}
+		applyOverride(&info, override)
+		ok = true
+	}
+	return info, ok
+}
+
+// ToolLimit returns the most tools a request to the model may carry, or 0 when no limit is
+// known. A limit for the model itself, from the catalog or an override, wins over the limit
+// of its provider type.
+func (c *Catalog) ToolLimit(providerType, model string) int {
+	c.load()
+	if info, _ := c.Lookup(providerType, model); info.MaxTools > 0 {
+		return info.MaxTools
+	}
+	if alias, ok := catalogAliases[providerType]; ok {
+		providerType = alias
+	}
+	return c.toolLimits[providerType]
+}
+
+// ForProvider returns the metadata for a provider's configured model
+func (c *Catalog) ForProvider(provider config.Provider) (ModelInfo, bool) {
+	return c.Lookup(provider.Type, provider.Model)
+}
+
+// find searches the catalog by exact ID, then ignoring case, then by the longest ID the
+// model name extends at a separator
+func (c *Catalog) find(providerType, model string) (ModelInfo, bool) {
+	var candidates []ModelInfo
+	for _, section := range c.sections(providerType) {
+		candidates = append(candidates, c.providers[section]...)
+	}
+
+	for _, candidate := range candidates {
+		if candidate.ID == model {
+			return candidate, true
+		}
+	}
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate.ID, model) {
+			return candidate, true
+		}
+	}
+
+	lower := strings.ToLower(model)
+	// Ollama names are often namespaced, as in library/llama3.1:8b
+	if i := strings.LastIndex(lower, "/"); i >= 0 {
+		lower = lower[i+1:]
+	}
+	var best ModelInfo
+	found := false
+	for _, candidate := range candidates {
+		id := strings.ToLower(candidate.ID)
+		if len(lower) > len(id) && strings.HasPrefix(lower, id) && strings.ContainsRune("-:@", rune(lower[len(id)])) {
+			if !found || len(id) > len(best.ID) {
+				best, found = candidate, true
+			}
+		}
+	}
+	return best, found
+}
+
+// sections returns the catalog sections that describe a provider type's models
+func (c *Catalog) sections(providerType string) []string {
+	if providerType == "custom" {
+		sections := make([]string, 0, len(c.providers))
+		for section := range c.providers {
+			sections = append(sections, section)
+		}
+		sort.Strings(sections)
+		return sections
+	}
+	if alias, ok := catalogAliases[providerType]; ok {
+		providerType = alias
+	}
+	return []string{providerType}
+}
+
+// applyOverride copies the fields set in an override onto info
+func applyOverride(info *ModelInfo, override config.ModelOverride) {
+	if override.ContextWindow > 0 {
+		info.ContextWindow = override.ContextWindow
+	}
+	if override.ToolCalling != nil {
+		info.ToolCalling = *override.ToolCalling
+	}
+	if override.Vision != nil {
+		info.Vision = *override.Vision
+	}
+	if override.MaxTools > 0 {
+		info.MaxTools = override.MaxTools
+	}
+	if override.InputPricePerMillion != nil || override.OutputPricePerMillion != nil {
+		pricing := ModelPricing{}
+		if info.Pricing != nil {
+			pricing = *info.Pricing
+		}
+		if override.InputPricePerMillion != nil {
+			pricing.Input = *override.InputPricePerMillion
+		}
+		if override.OutputPricePerMillion != nil {
+			pricing.Output = *override.OutputPricePerMillion
+		}
+		info.Pricing = &pricing
+	}
+}
+
+// Describe summarizes a model's metadata for display, e.g.
+// "128K context · tools · vision · $2.50 / $10.00 per 1M tokens"
+func (m ModelInfo) Describe() string {
+	var parts []string
+	if m.ContextWindow > 0 {
+		parts = append(parts, formatTokenCount(m.ContextWindow)+" context")
+	}
+	if m.ToolCalling {
+		parts = append(parts, "tools")
+	}
+	if m.Vision {
+		parts = append(parts, "vision")
+	}
+	if m.Pricing != nil {
+		if m.Pricing.Input == 0 && m.Pricing.Output == 0 {
+			parts = append(parts, "free")
+		} else {
+			parts = append(parts, fmt.Sprintf("$%.2f / $%.2f per 1M tokens", m.Pricing.Input, m.Pricing.Output))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// formatTokenCount abbreviates a token count, e.g. 128000 as 128K and 1048576 as 1M
+func formatTokenCount(n int) string {
+	switch {
+	case n >= 1000000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1000000), ".0") + "M"
+	case n >= 1000:
+		return fmt.Sprintf("%dK", n/1000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
@@ -2,10 +2,13 @@ package llm
 
 import (
 	"chatgo/internal/config"
+	"chatgo/internal/log"
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/cloudwego/eino-ext/components/model/claude"
 	"github.com/cloudwego/eino-ext/components/model/deepseek"
@@ -18,25 +21,40 @@ import (
 	"google.golang.org/genai"
 )
 
+// logger logs the requests sent to providers
+var logger = log.New("llm")
+
 // Client represents an LLM client using eino
 type Client struct {
 	provider config.Provider
 	model    model.ChatModel
+	limiter  *rateLimiter // Shared by all clients of the provider; nil when unlimited
+}
+
+// ClientOptions holds settings that apply to every provider type
+type ClientOptions struct {
+	HTTPClient *http.Client // Used for all outbound requests; nil uses the library default
 }
 
 // NewClient creates a new LLM client using eino
-func NewClient(provider config.Provider) (*Client, error) {
+func NewClient(provider config.Provider, opts ClientOptions) (*Client, error) {
 	var chatModel model.ChatModel
 	var err error
 
 	ctx := context.Background()
 
+	// Free tiers report their remaining requests, which is recorded from every response
+	if parse := quotaParserFor(provider); parse != nil {
+		opts.HTTPClient = withQuotaTracking(opts.HTTPClient, provider.Name, parse)
+	}
+
 	switch provider.Type {
 	case "openai", "custom":
 		// OpenAI and custom providers use OpenAI-compatible API
 		cfg := &openai.Config{
-			APIKey: provider.APIKey,
-			Model:  provider.Model,
+			APIKey:     provider.APIKey,
+			Model:      provider.Model,
+			HTTPClient: opts.HTTPClient,
 		}
 		if provider.BaseURL != "" {
 			cfg.BaseURL = provider.BaseURL
@@ -47,11 +65,49 @@ func NewClient(provider config.Provider) (*Client, error) {
 		}
 		chatModel = client
 
+	case "openrouter":
+		// OpenRouter routes to many vendors' models through an OpenAI-compatible API
+		cfg := &openai.Config{
+			APIKey:     provider.APIKey,
+			Model:      provider.Model,
+			BaseURL:    baseURLOrDefault(provider.BaseURL, OpenRouterBaseURL),
+			HTTPClient: withHeaders(opts.HTTPClient, openRouterHeadersFor(provider)),
+		}
+		client, err := openai.NewClient(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create openrouter client: %w", err)
+		}
+		chatModel = client
+
+	case "azure":
+		// Azure OpenAI sends every request to the deployment, whatever the model
+		deployment := strings.TrimSpace(provider.DeploymentName)
+		apiVersion := strings.TrimSpace(provider.APIVersion)
+		if apiVersion == "" {
+			apiVersion = AzureDefaultAPIVersion
+		}
+		cfg := &openai.Config{
+			ByAzure:              true,
+			APIKey:               provider.APIKey,
+			BaseURL:              baseURLOrDefault(provider.BaseURL, ""),
+			APIVersion:           apiVersion,
+			Model:                provider.Model,
+			AzureModelMapperFunc: func(string) string { return deployment },
+			HTTPClient:           opts.HTTPClient,
+		}
+		client, err := openai.NewClient(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create azure client: %w", err)
+		}
+		chatModel = client
+
 	case "anthropic", "claude":
 		// Anthropic Claude
 		cfg := &claude.Config{
-			APIKey: provider.APIKey,
-			Model:  provider.Model,
+			APIKey:     provider.APIKey,
+			Model:      provider.Model,
+			MaxTokens:  provider.GetInt(config.ExtraAnthropicMaxTokens, 0),
+			HTTPClient: opts.HTTPClient,
 		}
 		if provider.BaseURL != "" {
 			cfg.BaseURL = &provider.BaseURL
@@ -64,11 +120,16 @@ func NewClient(provider config.Provider) (*Client, error) {
 	case "ollama":
 		// Ollama - no APIKey needed
 		cfg := &ollama.ChatModelConfig{
-			Model: provider.Model,
+			Model:      provider.Model,
+			HTTPClient: opts.HTTPClient,
 		}
 		if provider.BaseURL != "" {
 			cfg.BaseURL = provider.BaseURL
 		}
+		if numCtx := provider.GetInt(config.ExtraOllamaNumCtx, 0); numCtx > 0 {
+			cfg.Options = &ollama.Options{}
+			cfg.Options.NumCtx = numCtx
+		}
 		chatModel, err = ollama.NewChatModel(ctx, cfg)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ollama client: %w", err)
@@ -77,8 +138,9 @@ func NewClient(provider config.Provider) (*Client, error) {
 	case "qwen":
 		// Alibaba Qwen
 		cfg := &qwen.ChatModelConfig{
-			APIKey: provider.APIKey,
-			Model:  provider.Model,
+			APIKey:     provider.APIKey,
+			Model:      provider.Model,
+			HTTPClient: opts.HTTPClient,
 		}
 		if provider.BaseURL != "" {
 			cfg.BaseURL = provider.BaseURL
@@ -91,8 +153,9 @@ func NewClient(provider config.Provider) (*Client, error) {
 	case "deepseek":
 		// DeepSeek
 		cfg := &deepseek.ChatModelConfig{
-			APIKey: provider.APIKey,
-			Model:  provider.Model,
+			APIKey:     provider.APIKey,
+			Model:      provider.Model,
+			HTTPClient: opts.HTTPClient,
 		}
 		if provider.BaseURL != "" {
 			cfg.BaseURL = provider.BaseURL
@@ -105,14 +168,19 @@ func NewClient(provider config.Provider) (*Client, error) {
 	case "gemini":
 		// Google Gemini - need to create genai client first
 		genaiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
-			APIKey: provider.APIKey,
+			APIKey:     provider.APIKey,
+			HTTPClient: opts.HTTPClient,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to create genai client: %w", err)
 		}
 		cfg := &gemini.Config{
-			Client: genaiClient,
-			Model:  provider.Model,
+			Client:         genaiClient,
+			Model:          provider.Model,
+			SafetySettings: geminiSafetySettings(provider.GetString(config.ExtraGeminiSafetyThreshold, "")),
+		}
+		if provider.GetBool(config.ExtraGeminiGoogleSearch, false) {
+			cfg.EnableGoogleSearch = &genai.GoogleSearch{}
 		}
 		chatModel, err = gemini.NewChatModel(ctx, cfg)
 		if err != nil {
@@ -126,77 +194,295 @@ func NewClient(provider config.Provider) (*Client, error) {
 	return &Client{
 		provider: provider,
 		model:    chatModel,
+		limiter:  limiterFor(provider),
 	}, nil
 }
 
+// toolCallingModel returns the client's model for use by an agent, keeping the provider's
+// rate limit. ok is false when the model does not support tool calling.
+func (c *Client) toolCallingModel() (m model.ToolCallingChatModel, ok bool) {
+	m, ok = c.model.(model.ToolCallingChatModel)
+	if !ok || c.limiter == nil {
+		return m, ok
+	}
+	return &rateLimitedModel{ToolCallingChatModel: m, limiter: c.limiter}, true
+}
+
 // ChatMessage represents a chat message
 type ChatMessage struct {
-	Role    string // user, assistant, system
-	Content string
+	Role      string // user, assistant, system
+	Content   string
+	Images    []ChatImage      // Images sent with a user message to a multimodal model
+	ToolCalls []ToolCallRecord // Tools an assistant message called before replying, sent again ahead of the reply
+}
+
+// ChatImage is an image in a chat message
+type ChatImage struct {
+	MimeType string
+	Data     string // Base64-encoded, without a data: prefix
 }
 
 // ChatResponse represents the response from a chat completion
 type ChatResponse struct {
-	Content string
-	Done    bool
+	Content      string
+	Done         bool
+	ToolCalls    []ToolCallRecord // Tools called by the React Agent, in call order
+	FinishReason string           // Why generation stopped, e.g. "stop" or "length"; empty when unknown
+	Usage        *TokenUsage      // Token usage reported by the provider; nil when unknown
+	NotStreamed  bool             // Streaming was asked for, but the provider answered in one piece
+}
+
+// TokenUsage is the token count reported by a provider
+type TokenUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// ChunkEvent is one piece of a streamed response together with any metadata the provider sent with it
+type ChunkEvent struct {
+	Content      string      // Text delta; may be empty for metadata-only chunks
+	FinishReason string      // Set on the chunk that ends generation, when the provider reports it
+	Usage        *TokenUsage // Usage so far, when the provider reports it on this chunk
+}
+
+// tokenUsageFrom converts the usage in eino response metadata
+func tokenUsageFrom(meta *schema.ResponseMeta) *TokenUsage {
+	if meta == nil || meta.Usage == nil {
+		return nil
+	}
+	return &TokenUsage{
+		PromptTokens:     meta.Usage.PromptTokens,
+		CompletionTokens: meta.Usage.CompletionTokens,
+		TotalTokens:      meta.Usage.TotalTokens,
+	}
 }
 
 // Chat sends a chat completion request with streaming support
 func (c *Client) Chat(ctx context.Context, messages []ChatMessage, onChunk func(string)) (*ChatResponse, error) {
-	// Convert messages to eino format
-	einoMessages := make([]*schema.Message, len(messages))
-	for i, msg := range messages {
-		einoMessages[i] = &schema.Message{
+	// If streaming callback is provided, use Stream
+	if onChunk != nil {
+		return c.StreamChat(ctx, messages, func(event ChunkEvent) {
+			if event.Content != "" {
+				onChunk(event.Content)
+			}
+		})
+	}
+
+	// Otherwise use Generate
+	started := c.logRequestStart(len(messages), false)
+	response, err := c.chatWithoutStream(ctx, toEinoMessages(messages, false))
+	c.logRequestEnd(started, response, err)
+	return response, err
+}
+
+// StreamChat sends a streaming chat completion request, calling onEvent for every chunk
+// including those that only carry a finish reason or token usage. Cancelling ctx stops
+// reading and returns the context error.
+func (c *Client) StreamChat(ctx context.Context, messages []ChatMessage, onEvent func(ChunkEvent)) (*ChatResponse, error) {
+	started := c.logRequestStart(len(messages), true)
+	response, err := c.chatWithStream(ctx, toEinoMessages(messages, false), onEvent)
+	c.logRequestEnd(started, response, err)
+	return response, err
+}
+
+// logRequestStart logs a request about to be sent and returns when it started
+func (c *Client) logRequestStart(messages int, stream bool) time.Time {
+	logger.Debug("Request started", "provider", c.provider.Name, "model", c.provider.Model,
+		"messages", messages, "stream", stream)
+	return time.Now()
+}
+
+// logRequestEnd logs how a request that started at started ended, with its duration and the
+// tokens the provider reported
+func (c *Client) logRequestEnd(started time.Time, response *ChatResponse, err error) {
+	duration := time.Since(started).Round(time.Millisecond)
+	if err != nil {
+		logger.Error("Request failed", "provider", c.provider.Name, "model", c.provider.Model,
+			"duration", duration, "error", err)
+		return
+	}
+
+	keyValues := []any{"provider", c.provider.Name, "model", c.provider.Model, "duration", duration,
+		"finish_reason", response.FinishReason}
+	if usage := response.Usage; usage != nil {
+		keyValues = append(keyValues, "prompt_tokens", usage.PromptTokens, "completion_tokens", usage.CompletionTokens)
+	}
+	if response.NotStreamed {
+		keyValues = append(keyValues, "not_streamed", true)
+	}
+	logger.Info("Request finished", keyValues...)
+}
+
+// WithSystemPrompt returns messages with prompt as the system prompt. A system message already
+// leading the messages, such as a conversation's own prompt, is kept after prompt in the same
+// message. An empty prompt leaves messages unchanged.
+func WithSystemPrompt(prompt string, messages []ChatMessage) []ChatMessage {
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return messages
+	}
+
+	if len(messages) > 0 && messages[0].Role == "system" {
+		merged := append([]ChatMessage(nil), messages...)
+		if own := strings.TrimSpace(messages[0].Content); own != "" {
+			merged[0].Content = prompt + "\n\n" + own
+		} else {
+			merged[0].Content = prompt
+		}
+		return merged
+	}
+
+	return append([]ChatMessage{{Role: "system", Content: prompt}}, messages...)
+}
+
+// toEinoMessages converts messages to eino format. The tool calls of assistant messages are
+// sent as the tool turns they were with withToolTurns set, for requests that carry tools, and
+// otherwise as text ahead of the reply.
+func toEinoMessages(messages []ChatMessage, withToolTurns bool) []*schema.Message {
+	einoMessages := make([]*schema.Message, 0, len(messages))
+	for _, msg := range messages {
+		switch {
+		case len(msg.ToolCalls) == 0:
+			einoMessages = append(einoMessages, toEinoMessage(msg))
+		case withToolTurns:
+			einoMessages = append(einoMessages, toolTurns(msg)...)
+		default:
+			einoMessages = append(einoMessages, toEinoMessage(withToolCallsAsText(msg)))
+		}
+	}
+	return einoMessages
+}
+
+// toEinoMessage converts a message to eino format. A message with images is sent as content
+// parts, the text first, which is the form every multimodal provider accepts.
+func toEinoMessage(msg ChatMessage) *schema.Message {
+	if len(msg.Images) == 0 {
+		return &schema.Message{
 			Role:    schema.RoleType(msg.Role),
 			Content: msg.Content,
 		}
 	}
 
-	// If streaming callback is provided, use Stream
-	if onChunk != nil {
-		return c.chatWithStream(ctx, einoMessages, onChunk)
+	parts := make([]schema.MessageInputPart, 0, len(msg.Images)+1)
+	if msg.Content != "" {
+		parts = append(parts, schema.MessageInputPart{Type: schema.ChatMessagePartTypeText, Text: msg.Content})
+	}
+	for _, image := range msg.Images {
+		data := image.Data
+		parts = append(parts, schema.MessageInputPart{
+			Type: schema.ChatMessagePartTypeImageURL,
+			Image: &schema.MessageInputImage{
+				MessagePartCommon: schema.MessagePartCommon{
+					Base64Data: &data,
+					MIMEType:   image.MimeType,
+				},
+			},
+		})
+	}
+	return &schema.Message{
+		Role:                  schema.RoleType(msg.Role),
+		UserInputMultiContent: parts,
 	}
-
-	// Otherwise use Generate
-	return c.chatWithoutStream(ctx, einoMessages)
 }
 
-// chatWithStream sends a streaming chat completion request
-func (c *Client) chatWithStream(ctx context.Context, messages []*schema.Message, onChunk func(string)) (*ChatResponse, error) {
+// chatWithStream sends a streaming chat completion request. A provider that rejects streaming,
+// or has it disabled, is sent a non-streaming request whose reply arrives as a single event.
+// Other errors, such as authentication, quota or context length errors, are returned as they are.
+func (c *Client) chatWithStream(ctx context.Context, messages []*schema.Message, onEvent func(ChunkEvent)) (*ChatResponse, error) {
+	if !shouldStream(c.provider) {
+		logger.Debug("Sending request without streaming", "provider", c.provider.Name,
+			"disabled", c.provider.DisableStreaming)
+		return c.chatWithoutStreamAsEvent(ctx, messages, onEvent)
+	}
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
 	// Create stream reader
 	streamReader, err := c.model.Stream(ctx, messages)
 	if err != nil {
+		if ctx.Err() == nil && rejectsStreaming(c.provider, err) {
+			return c.chatWithoutStreamAsEvent(ctx, messages, onEvent)
+		}
 		return nil, fmt.Errorf("failed to create stream: %w", err)
 	}
 
 	defer streamReader.Close()
 
 	var fullContent strings.Builder
+	response := &ChatResponse{}
+	received := false
 
 	// Read from stream
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		chunk, err := streamReader.Recv()
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			// Some gateways accept the request and then fail the stream before its first chunk
+			if !received && rejectsStreaming(c.provider, err) {
+				streamReader.Close()
+				return c.chatWithoutStreamAsEvent(ctx, messages, onEvent)
+			}
 			return nil, fmt.Errorf("failed to receive from stream: %w", err)
 		}
+		received = true
+		if chunk == nil {
+			continue
+		}
+
+		event := ChunkEvent{Content: chunk.Content, Usage: tokenUsageFrom(chunk.ResponseMeta)}
+		if chunk.ResponseMeta != nil {
+			event.FinishReason = chunk.ResponseMeta.FinishReason
+		}
+		if event.Content == "" && event.FinishReason == "" && event.Usage == nil {
+			continue
+		}
 
-		if chunk != nil && chunk.Content != "" {
-			fullContent.WriteString(chunk.Content)
-			onChunk(chunk.Content)
+		fullContent.WriteString(event.Content)
+		if event.FinishReason != "" {
+			response.FinishReason = event.FinishReason
+		}
+		if event.Usage != nil {
+			response.Usage = event.Usage
 		}
+		onEvent(event)
 	}
 
-	return &ChatResponse{
-		Content: fullContent.String(),
-		Done:    true,
-	}, nil
+	response.Content = fullContent.String()
+	response.Done = true
+	return response, nil
+}
+
+// chatWithoutStreamAsEvent sends a non-streaming request in place of a streaming one and
+// reports the whole reply to onEvent as one chunk
+func (c *Client) chatWithoutStreamAsEvent(ctx context.Context, messages []*schema.Message, onEvent func(ChunkEvent)) (*ChatResponse, error) {
+	response, err := c.chatWithoutStream(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+	response.NotStreamed = true
+	if response.Content != "" || response.FinishReason != "" || response.Usage != nil {
+		onEvent(ChunkEvent{Content: response.Content, FinishReason: response.FinishReason, Usage: response.Usage})
+	}
+	return response, nil
 }
 
 // chatWithoutStream sends a non-streaming chat completion request
 func (c *Client) chatWithoutStream(ctx context.Context, messages []*schema.Message) (*ChatResponse, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
 	// Generate response
 	response, err := c.model.Generate(ctx, messages)
 	if err != nil {
@@ -204,13 +490,21 @@ func (c *Client) chatWithoutStream(ctx context.Context, messages []*schema.Messa
 	}
 
 	content := ""
+	finishReason := ""
+	var usage *TokenUsage
 	if response != nil {
 		content = response.Content
+		usage = tokenUsageFrom(response.ResponseMeta)
+		if response.ResponseMeta != nil {
+			finishReason = response.ResponseMeta.FinishReason
+		}
 	}
 
 	return &ChatResponse{
-		Content: content,
-		Done:    true,
+		Content:      content,
+		Done:         true,
+		FinishReason: finishReason,
+		Usage:        usage,
 	}, nil
 }
 
@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"chatgo/internal/config"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// fakeModel fails every stream with streamErr and answers whole requests with reply
+type fakeModel struct {
+	streamErr error
+	reply     string
+	generated int // Whole requests received
+}
+
+func (m *fakeModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.generated++
+	return &schema.Message{Role: schema.Assistant, Content: m.reply}, nil
+}
+
+func (m *fakeModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, m.streamErr
+}
+
+func (m *fakeModel) BindTools(tools []*schema.ToolInfo) error {
+	return nil
+}
+
+func TestChatWithStreamReturnsNonStreamingErrors(t *testing.T) {
+	for _, streamErr := range []error{
+		errors.New("error, status code: 401, message: Incorrect API key provided"),
+		errors.New("error, status code: 429, message: You exceeded your current quota"),
+		errors.New("error, status code: 400, message: This model's maximum context length is 8192 tokens"),
+	} {
+		fake := &fakeModel{streamErr: streamErr, reply: "whole"}
+		c := &Client{provider: config.Provider{Name: t.Name()}, model: fake}
+
+		var events []ChunkEvent
+		_, err := c.chatWithStream(context.Background(), nil, func(e ChunkEvent) { events = append(events, e) })
+		if !errors.Is(err, streamErr) {
+			t.Errorf("chatWithStream error = %v, want %v", err, streamErr)
+		}
+		if fake.generated != 0 || len(events) != 0 {
+			t.Errorf("%v was retried without streaming", streamErr)
+		}
+	}
+	if StreamingUnsupported(t.Name()) {
+		t.Error("provider was marked as rejecting streaming")
+	}
+}
+
+func TestChatWithStreamFallsBackWhenStreamingRejected(t *testing.T) {
+	fake := &fakeModel{
+		streamErr: errors.New("error, status code: 400, message: Unsupported parameter: 'stream'"),
+		reply:     "whole",
+	}
+	c := &Client{provider: config.Provider{Name: t.Name()}, model: fake}
+
+	var content strings.Builder
+	response, err := c.chatWithStream(context.Background(), nil, func(e ChunkEvent) { content.WriteString(e.Content) })
+	if err != nil {
+		t.Fatalf("chatWithStream: %v", err)
+	}
+	if fake.generated != 1 || !response.NotStreamed || content.String() != "whole" {
+		t.Errorf("got %d whole requests, NotStreamed %v, content %q; want 1, true, %q",
+			fake.generated, response.NotStreamed, content.String(), "whole")
+	}
+	if !StreamingUnsupported(t.Name()) {
+		t.Error("provider wasn't remembered as rejecting streaming")
+	}
+}
@@ -0,0 +1,24 @@
+package llm
+
+import "google.golang.org/genai"
+
+// geminiHarmCategories are the categories a Gemini safety threshold applies to
+var geminiHarmCategories = []genai.HarmCategory{
+	genai.HarmCategoryHarassment,
+	genai.HarmCategoryHateSpeech,
+	genai.HarmCategorySexuallyExplicit,
+	genai.HarmCategoryDangerousContent,
+}
+
+// geminiSafetySettings applies a block threshold, such as "BLOCK_ONLY_HIGH", to every harm
+// category. An empty threshold keeps Gemini's defaults.
+func geminiSafetySettings(threshold string) []*genai.SafetySetting {
+	if threshold == "" {
+		return nil
+	}
+	settings := make([]*genai.SafetySetting, len(geminiHarmCategories))
+	for i, category := range geminiHarmCategories {
+		settings[i] = &genai.SafetySetting{Category: category, Threshold: genai.HarmBlockThreshold(threshold)}
+	}
+	return settings
+}
@@ -0,0 +1,320 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	_ "time/tzdata" // Time zones resolve even where the system has no tz database, e.g. on Windows
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// weatherRequestTimeout bounds the geocoding and forecast lookups of a weather call
+const weatherRequestTimeout = 10 * time.Second
+
+// Open-Meteo endpoints; neither needs an API key
+const (
+	geocodingURL = "https://geocoding-api.open-meteo.com/v1/search"
+	forecastURL  = "https://api.open-meteo.com/v1/forecast"
+)
+
+// currentTimeToolDefinition creates the currenttime tool, which reads the local clock
+func currentTimeToolDefinition(name string) ToolDefinition {
+	return ToolDefinition{
+		Name:        name,
+		Description: "Current Time - Get the current date and time, optionally in a given IANA time zone such as \"Asia/Shanghai\" or \"America/New_York\".",
+		Parameters: map[string]*schema.ParameterInfo{
+			"timezone": {
+				Type: schema.String,
+				Desc: "IANA time zone name; omit for the user's local time zone",
+			},
+		},
+		Handler: func(ctx context.Context, arguments string) (string, error) {
+			var args struct {
+				Timezone string `json:"timezone"`
+			}
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("invalid currenttime arguments: %w", err)
+			}
+			return currentTime(time.Now(), args.Timezone), nil
+		},
+	}
+}
+
+// currentTime describes now in the named time zone, or in the local zone when name is empty
+func currentTime(now time.Time, name string) string {
+	loc := time.Local
+	if name = strings.TrimSpace(name); name != "" {
+		var err error
+		loc, err = time.LoadLocation(name)
+		if err != nil {
+			// Returned as the result so the model can correct the zone name
+			return fmt.Sprintf("Error: unknown time zone %q; use an IANA name such as \"Europe/Berlin\"", name)
+		}
+	}
+
+	t := now.In(loc)
+	abbrev, _ := t.Zone()
+	return fmt.Sprintf("Time zone: %s (%s, UTC%s)\nDate: %s (%s)\nTime: %s\nISO 8601: %s",
+		loc.String(), abbrev, t.Format("-07:00"),
+		t.Format("2006-01-02"), t.Weekday(),
+		t.Format("15:04:05"),
+		t.Format(time.RFC3339))
+}
+
+// weatherToolDefinition creates the weather tool, which looks up current conditions on Open-Meteo.
+// httpClient may be nil to use the default client.
+func weatherToolDefinition(name string, httpClient *http.Client) ToolDefinition {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return ToolDefinition{
+		Name:        name,
+		Description: "Weather - Get the current weather for a city, or for a latitude and longitude. Temperatures are in °C and wind speeds in km/h.",
+		Parameters: map[string]*schema.ParameterInfo{
+			"city": {
+				Type: schema.String,
+				Desc: "City name, optionally with country, e.g. \"Paris\" or \"Portland, US\"",
+			},
+			"latitude": {
+				Type: schema.Number,
+				Desc: "Latitude in degrees; used with longitude when no city is given",
+			},
+			"longitude": {
+				Type: schema.Number,
+				Desc: "Longitude in degrees; used with latitude when no city is given",
+			},
+		},
+		Handler: func(ctx context.Context, arguments string) (string, error) {
+			var args weatherQuery
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("invalid weather arguments: %w", err)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, weatherRequestTimeout)
+			defer cancel()
+			report, err := lookupWeather(ctx, httpClient, args)
+			if err != nil {
+				// Failures are returned as the result so the model can tell the user
+				return weatherError(err), nil
+			}
+			return report, nil
+		},
+	}
+}
+
+// weatherQuery is a weather tool call: a city, or coordinates
+type weatherQuery struct {
+	City      string   `json:"city"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// weatherLookupError is a failed weather lookup, reported to the model as structured JSON
+type weatherLookupError struct {
+	Kind      string `json:"error"` // invalid_arguments, location_not_found or network
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+}
+
+// Error returns the message
+func (e *weatherLookupError) Error() string {
+	return e.Message
+}
+
+// weatherError formats a lookup failure as the tool result
+func weatherError(err error) string {
+	lookupErr, ok := err.(*weatherLookupError)
+	if !ok {
+		lookupErr = &weatherLookupError{Kind: "network", Message: err.Error(), Retryable: true}
+	}
+	data, _ := json.Marshal(lookupErr)
+	return string(data)
+}
+
+// weatherReport is the weather tool's result
+type weatherReport struct {
+	Location        string  `json:"location"`
+	Latitude        float64 `json:"latitude"`
+	Longitude       float64 `json:"longitude"`
+	Time            string  `json:"time"` // Local time of the observation
+	Conditions      string  `json:"conditions"`
+	TemperatureC    float64 `json:"temperature_c"`
+	FeelsLikeC      float64 `json:"feels_like_c"`
+	HumidityPercent float64 `json:"humidity_percent"`
+	WindSpeedKmh    float64 `json:"wind_speed_kmh"`
+	PrecipitationMm float64 `json:"precipitation_mm"`
+}
+
+// lookupWeather resolves the query to coordinates and fetches the current conditions there
+func lookupWeather(ctx context.Context, httpClient *http.Client, query weatherQuery) (string, error) {
+	report := weatherReport{}
+	switch {
+	case strings.TrimSpace(query.City) != "":
+		place, err := geocode(ctx, httpClient, query.City)
+		if err != nil {
+			return "", err
+		}
+		report.Location = place.label()
+		report.Latitude, report.Longitude = place.Latitude, place.Longitude
+	case query.Latitude != nil && query.Longitude != nil:
+		report.Latitude, report.Longitude = *query.Latitude, *query.Longitude
+		report.Location = fmt.Sprintf("%.4f, %.4f", report.Latitude, report.Longitude)
+	default:
+		return "", &weatherLookupError{Kind: "invalid_arguments", Message: "give either a city or both latitude and longitude"}
+	}
+
+	params := url.Values{}
+	params.Set("latitude", fmt.Sprintf("%f", report.Latitude))
+	params.Set("longitude", fmt.Sprintf("%f", report.Longitude))
+	params.Set("current", "temperature_2m,apparent_temperature,relative_humidity_2m,wind_speed_10m,precipitation,weather_code")
+	params.Set("timezone", "auto")
+
+	var forecast struct {
+		Current struct {
+			Time                string  `json:"time"`
+			Temperature         float64 `json:"temperature_2m"`
+			ApparentTemperature float64 `json:"apparent_temperature"`
+			Humidity            float64 `json:"relative_humidity_2m"`
+			WindSpeed           float64 `json:"wind_speed_10m"`
+			Precipitation       float64 `json:"precipitation"`
+			WeatherCode         int     `json:"weather_code"`
+		} `json:"current"`
+	}
+	if err := getWeatherJSON(ctx, httpClient, forecastURL+"?"+params.Encode(), &forecast); err != nil {
+		return "", err
+	}
+
+	current := forecast.Current
+	report.Time = current.Time
+	report.Conditions = weatherCodeText(current.WeatherCode)
+	report.TemperatureC = current.Temperature
+	report.FeelsLikeC = current.ApparentTemperature
+	report.HumidityPercent = current.Humidity
+	report.WindSpeedKmh = current.WindSpeed
+	report.PrecipitationMm = current.Precipitation
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// geocodedPlace is a match from the Open-Meteo geocoding API
+type geocodedPlace struct {
+	Name      string  `json:"name"`
+	Admin1    string  `json:"admin1"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// label names the place with its region and country, e.g. "Portland, Oregon, United States"
+func (p geocodedPlace) label() string {
+	parts := []string{p.Name}
+	for _, part := range []string{p.Admin1, p.Country} {
+		if part != "" && part != p.Name {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// geocode finds the best match for a city name. A country after a comma, e.g. "Portland, US",
+// narrows the search, as the geocoding API only matches place names.
+func geocode(ctx context.Context, httpClient *http.Client, city string) (*geocodedPlace, error) {
+	name, country, _ := strings.Cut(city, ",")
+	name, country = strings.TrimSpace(name), strings.TrimSpace(country)
+
+	params := url.Values{}
+	params.Set("name", name)
+	params.Set("count", "10")
+	params.Set("format", "json")
+
+	var result struct {
+		Results []struct {
+			geocodedPlace
+			CountryCode string `json:"country_code"`
+		} `json:"results"`
+	}
+	if err := getWeatherJSON(ctx, httpClient, geocodingURL+"?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	for _, r := range result.Results {
+		if country == "" || strings.EqualFold(r.CountryCode, country) || strings.EqualFold(r.Country, country) || strings.EqualFold(r.Admin1, country) {
+			place := r.geocodedPlace
+			return &place, nil
+		}
+	}
+	return nil, &weatherLookupError{Kind: "location_not_found", Message: fmt.Sprintf("no place named %q was found", city)}
+}
+
+// getWeatherJSON fetches a weather service URL and decodes its JSON body into v
+func getWeatherJSON(ctx context.Context, httpClient *http.Client, rawURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return &weatherLookupError{Kind: "network", Message: fmt.Sprintf("weather service unreachable: %v", err), Retryable: true}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &weatherLookupError{
+			Kind:      "network",
+			Message:   fmt.Sprintf("weather service returned %s", resp.Status),
+			Retryable: resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500,
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return &weatherLookupError{Kind: "network", Message: fmt.Sprintf("unexpected response from weather service: %v", err)}
+	}
+	return nil
+}
+
+// weatherCodeText describes a WMO weather interpretation code as used by Open-Meteo
+func weatherCodeText(code int) string {
+	switch code {
+	case 0:
+		return "Clear sky"
+	case 1:
+		return "Mainly clear"
+	case 2:
+		return "Partly cloudy"
+	case 3:
+		return "Overcast"
+	case 45, 48:
+		return "Fog"
+	case 51, 53, 55:
+		return "Drizzle"
+	case 56, 57:
+		return "Freezing drizzle"
+	case 61, 63, 65:
+		return "Rain"
+	case 66, 67:
+		return "Freezing rain"
+	case 71, 73, 75:
+		return "Snow"
+	case 77:
+		return "Snow grains"
+	case 80, 81, 82:
+		return "Rain showers"
+	case 85, 86:
+		return "Snow showers"
+	case 95:
+		return "Thunderstorm"
+	case 96, 99:
+		return "Thunderstorm with hail"
+	default:
+		return fmt.Sprintf("Unknown (WMO code %d)", code)
+	}
+}
@@ -0,0 +1,131 @@
+package llm
+
+import (
+	"chatgo/internal/config"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrModelListingUnsupported is returned by ListModels for providers without a models endpoint
+var ErrModelListingUnsupported = errors.New("provider does not support listing models")
+
+// listModelsTimeout bounds how long a models endpoint request may take
+const listModelsTimeout = 15 * time.Second
+
+// SupportsModelListing reports whether ListModels can query the given provider type
+func SupportsModelListing(providerType string) bool {
+	switch providerType {
+	case "openai", "custom", "openrouter", "deepseek", "ollama":
+		return true
+	default:
+		return false
+	}
+}
+
+// ListModels queries the provider's models endpoint and returns the available model names, sorted.
+// OpenAI-compatible providers use GET /models and Ollama uses GET /api/tags.
+func ListModels(provider config.Provider, opts ClientOptions) ([]string, error) {
+	client := &http.Client{Timeout: listModelsTimeout}
+	if opts.HTTPClient != nil {
+		client.Transport = opts.HTTPClient.Transport
+	}
+
+	switch provider.Type {
+	case "openai", "custom":
+		return listOpenAIModels(client, baseURLOrDefault(provider.BaseURL, "https://api.openai.com/v1"), provider.APIKey)
+	case "openrouter":
+		client.Transport = &headerTransport{base: client.Transport, headers: openRouterHeadersFor(provider)}
+		return listOpenAIModels(client, baseURLOrDefault(provider.BaseURL, OpenRouterBaseURL), provider.APIKey)
+	case "deepseek":
+		return listOpenAIModels(client, baseURLOrDefault(provider.BaseURL, "https://api.deepseek.com"), provider.APIKey)
+	case "ollama":
+		return OllamaListModels(provider.BaseURL, opts)
+	default:
+		return nil, ErrModelListingUnsupported
+	}
+}
+
+// listOpenAIModels lists models from an OpenAI-compatible /models endpoint
+func listOpenAIModels(client *http.Client, baseURL, apiKey string) ([]string, error) {
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["Authorization"] = "Bearer " + apiKey
+	}
+	if err := getJSON(client, baseURL+"/models", headers, &result); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(result.Data))
+	for _, m := range result.Data {
+		names = append(names, m.ID)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// listOllamaModels lists locally available models from Ollama's /api/tags endpoint
+func listOllamaModels(client *http.Client, baseURL string) ([]string, error) {
+	var result struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+
+	if err := getJSON(client, baseURL+"/api/tags", nil, &result); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(result.Models))
+	for _, m := range result.Models {
+		names = append(names, m.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// getJSON performs a GET request and decodes the JSON response body into v
+func getJSON(client *http.Client, url string, headers map[string]string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, val := range headers {
+		req.Header.Set(k, val)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("request to %s failed with status %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", url, err)
+	}
+	return nil
+}
+
+// baseURLOrDefault trims a trailing slash from baseURL, falling back to def when empty
+func baseURLOrDefault(baseURL, def string) string {
+	if strings.TrimSpace(baseURL) == "" {
+		return def
+	}
+	return strings.TrimRight(strings.TrimSpace(baseURL), "/")
+}
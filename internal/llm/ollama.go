@@ -0,0 +1,135 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OllamaBaseURL is the local Ollama server, used when an ollama provider has no base URL
+const OllamaBaseURL = "http://localhost:11434"
+
+// OllamaListModels lists the models pulled to the Ollama server at baseURL, sorted
+func OllamaListModels(baseURL string, opts ClientOptions) ([]string, error) {
+	client := &http.Client{Timeout: listModelsTimeout}
+	if opts.HTTPClient != nil {
+		client.Transport = opts.HTTPClient.Transport
+	}
+	return listOllamaModels(client, baseURLOrDefault(baseURL, OllamaBaseURL))
+}
+
+// IsOllamaModelNotFound reports whether err is Ollama refusing a chat because the model
+// hasn't been pulled, as in `model "llama3" not found, try pulling it first`
+func IsOllamaModelNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "model") && strings.Contains(msg, "not found")
+}
+
+// OllamaModelMissingError is a chat refused because its model isn't pulled to the Ollama server
+type OllamaModelMissingError struct {
+	BaseURL string
+	Model   string
+	Err     error
+}
+
+// Error returns Ollama's error
+func (e *OllamaModelMissingError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns Ollama's error
+func (e *OllamaModelMissingError) Unwrap() error {
+	return e.Err
+}
+
+// OllamaPullProgress is one status line Ollama streams while pulling a model
+type OllamaPullProgress struct {
+	Status    string `json:"status"`              // Such as "pulling manifest" or "success"
+	Digest    string `json:"digest,omitempty"`    // The layer being downloaded
+	Total     int64  `json:"total,omitempty"`     // Bytes in the layer; 0 when not downloading
+	Completed int64  `json:"completed,omitempty"` // Bytes of the layer downloaded so far
+	Error     string `json:"error,omitempty"`
+}
+
+// Fraction returns how much of the current layer is downloaded, from 0 to 1, or -1 when
+// the step has no size
+func (p OllamaPullProgress) Fraction() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+	return float64(p.Completed) / float64(p.Total)
+}
+
+// OllamaPullModel pulls model to the Ollama server at baseURL through POST /api/pull, calling
+// onProgress with each status line. Cancelling ctx stops the pull; Ollama keeps the layers
+// downloaded so far and resumes from them next time.
+func OllamaPullModel(ctx context.Context, baseURL, model string, opts ClientOptions, onProgress func(OllamaPullProgress)) error {
+	body, err := json.Marshal(map[string]interface{}{"model": model, "stream": true})
+	if err != nil {
+		return fmt.Errorf("failed to encode pull request: %w", err)
+	}
+	url := baseURLOrDefault(baseURL, OllamaBaseURL) + "/api/pull"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// A pull can take many minutes, so only ctx bounds it
+	client := &http.Client{}
+	if opts.HTTPClient != nil {
+		client.Transport = opts.HTTPClient.Transport
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("request to %s failed with status %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	succeeded := false
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var progress OllamaPullProgress
+		if err := json.Unmarshal(line, &progress); err != nil {
+			return fmt.Errorf("failed to decode pull status: %w", err)
+		}
+		if progress.Error != "" {
+			return fmt.Errorf("failed to pull %s: %s", model, progress.Error)
+		}
+		succeeded = progress.Status == "success"
+		if onProgress != nil {
+			onProgress(progress)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("failed to read pull status: %w", err)
+	}
+	if !succeeded {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return errors.New("pull ended before the model was ready")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package llm
+
+import (
+	"chatgo/internal/config"
+	"maps"
+	"net/http"
+)
+
+// OpenRouterBaseURL is OpenRouter's OpenAI-compatible API, used when an openrouter provider has no base URL
+const OpenRouterBaseURL = "https://openrouter.ai/api/v1"
+
+// openRouterHeaders identify the application to OpenRouter on every request
+var openRouterHeaders = map[string]string{
+	"HTTP-Referer": "https://github.com/tk103331/ChatGo",
+	"X-Title":      "ChatGo",
+}
+
+// openRouterHeadersFor returns the headers identifying the application, replaced by the
+// provider's http_referer and x_title extras when set
+func openRouterHeadersFor(provider config.Provider) map[string]string {
+	headers := maps.Clone(openRouterHeaders)
+	headers["HTTP-Referer"] = provider.GetString(config.ExtraOpenRouterReferer, headers["HTTP-Referer"])
+	headers["X-Title"] = provider.GetString(config.ExtraOpenRouterTitle, headers["X-Title"])
+	return headers
+}
+
+// DefaultBaseURL returns the base URL a provider type uses when none is configured,
+// or "" when the type has no default worth showing
+func DefaultBaseURL(providerType string) string {
+	if providerType == "openrouter" {
+		return OpenRouterBaseURL
+	}
+	return ""
+}
+
+// headerTransport adds fixed headers to requests that don't already set them
+type headerTransport struct {
+	base    http.RoundTripper // nil uses http.DefaultTransport
+	headers map[string]string
+}
+
+// RoundTrip sends a copy of req with the headers added
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for key, value := range t.headers {
+		if req.Header.Get(key) == "" {
+			req.Header.Set(key, value)
+		}
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// withHeaders returns a copy of client, or of the default client when nil, that adds headers to every request
+func withHeaders(client *http.Client, headers map[string]string) *http.Client {
+	withHeaders := &http.Client{}
+	if client != nil {
+		*withHeaders = *client
+	}
+	withHeaders.Transport = &headerTransport{base: withHeaders.Transport, headers: headers}
+	return withHeaders
+}
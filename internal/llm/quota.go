@@ -0,0 +1,384 @@
+package llm
+
+import (
+	"bytes"
+	"chatgo/internal/config"
+	"chatgo/internal/paths"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxQuotaErrorBody bounds how much of a refused request's body is read for quota details
+const maxQuotaErrorBody = 64 << 10
+
+// Quota is the latest request quota a provider reported, such as a free tier's daily cap
+type Quota struct {
+	Remaining   int       `json:"remaining"`              // Requests left until ResetAt; -1 when unknown
+	Limit       int       `json:"limit,omitempty"`        // Requests allowed until ResetAt; 0 when unknown
+	ResetAt     time.Time `json:"reset_at,omitzero"`      // When Remaining returns to Limit; zero when unknown
+	ResetsDaily bool      `json:"resets_daily,omitempty"` // ResetAt comes round again each day
+	RetryAt     time.Time `json:"retry_at,omitzero"`      // When requests are accepted again after the provider refused one
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Exhausted reports whether the provider is refusing requests for lack of quota at now
+func (q Quota) Exhausted(now time.Time) bool {
+	return now.Before(q.RetryAt)
+}
+
+// at returns the quota as of now. Once ResetAt has passed, the whole limit is available again.
+func (q Quota) at(now time.Time) Quota {
+	if q.ResetAt.IsZero() || now.Before(q.ResetAt) {
+		return q
+	}
+	q.Remaining = -1
+	if q.Limit > 0 {
+		q.Remaining = q.Limit
+	}
+	if q.ResetsDaily {
+		for !now.Before(q.ResetAt) {
+			q.ResetAt = q.ResetAt.AddDate(0, 0, 1)
+		}
+	} else {
+		q.ResetAt = time.Time{}
+	}
+	return q
+}
+
+// QuotaError is a request refused because the provider's quota ran out
+type QuotaError struct {
+	Provider string
+	Quota    Quota
+	Err      error
+}
+
+// Error returns the provider's error
+func (e *QuotaError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the provider's error
+func (e *QuotaError) Unwrap() error {
+	return e.Err
+}
+
+// QuotaStore keeps the latest quota of each provider, saved in quota.json so it survives restarts
+type QuotaStore struct {
+	once   sync.Once
+	mu     sync.Mutex
+	quotas map[string]Quota // By provider name
+
+	listenersMu    sync.Mutex
+	listeners      map[int]func(provider string)
+	nextListenerID int
+}
+
+// Quotas is the quota store, loaded on first use
+var Quotas = &QuotaStore{}
+
+// load reads the saved quotas, if any
+func (s *QuotaStore) load() {
+	s.once.Do(func() {
+		s.quotas = make(map[string]Quota)
+		path, err := paths.QuotaFile()
+		if err != nil {
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				logger.Warn("Failed to read quotas", "path", path, "error", err)
+			}
+			return
+		}
+		if err := json.Unmarshal(data, &s.quotas); err != nil {
+			logger.Warn("Ignoring invalid quotas", "path", path, "error", err)
+			s.quotas = make(map[string]Quota)
+		}
+	})
+}
+
+// Get returns the latest quota of a provider as of now. ok is false when it never reported one.
+func (s *QuotaStore) Get(provider string) (quota Quota, ok bool) {
+	s.load()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	quota, ok = s.quotas[provider]
+	return quota.at(time.Now()), ok
+}
+
+// ExhaustedSince returns the quota of a provider when it refused a request for lack of quota
+// at or after since, so a failed request can be told apart from one refused for its quota
+func (s *QuotaStore) ExhaustedSince(provider string, since time.Time) (Quota, bool) {
+	quota, ok := s.Get(provider)
+	if !ok || quota.UpdatedAt.Before(since) || !quota.Exhausted(time.Now()) {
+		return Quota{}, false
+	}
+	return quota, true
+}
+
+// record updates a provider's quota from a response and saves it
+func (s *QuotaStore) record(provider string, parse quotaParser, resp *http.Response, body []byte, now time.Time) {
+	s.load()
+	s.mu.Lock()
+	previous, ok := s.quotas[provider]
+	if !ok {
+		previous = Quota{Remaining: -1}
+	}
+	quota, changed := parse(previous.at(now), resp, body, now)
+	if !changed {
+		s.mu.Unlock()
+		return
+	}
+	quota.UpdatedAt = now
+	s.quotas[provider] = quota
+	data, err := json.MarshalIndent(s.quotas, "", "  ")
+	s.mu.Unlock()
+
+	if err == nil {
+		err = writeQuotaFile(data)
+	}
+	if err != nil {
+		logger.Warn("Failed to save quota", "provider", provider, "error", err)
+	}
+	s.notify(provider)
+}
+
+// writeQuotaFile replaces quota.json with data
+func writeQuotaFile(data []byte) error {
+	path, err := paths.QuotaFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Subscribe registers a listener called with the provider name whenever a provider's quota
+// changes, and returns a function that removes it. Listeners are called from request goroutines.
+func (s *QuotaStore) Subscribe(listener func(provider string)) (unsubscribe func()) {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+
+	if s.listeners == nil {
+		s.listeners = make(map[int]func(string))
+	}
+	id := s.nextListenerID
+	s.nextListenerID++
+	s.listeners[id] = listener
+
+	return func() {
+		s.listenersMu.Lock()
+		defer s.listenersMu.Unlock()
+		delete(s.listeners, id)
+	}
+}
+
+// notify calls every listener with the provider whose quota changed
+func (s *QuotaStore) notify(provider string) {
+	s.listenersMu.Lock()
+	listeners := make([]func(string), 0, len(s.listeners))
+	for _, listener := range s.listeners {
+		listeners = append(listeners, listener)
+	}
+	s.listenersMu.Unlock()
+
+	for _, listener := range listeners {
+		listener(provider)
+	}
+}
+
+// quotaParser reads the quota reported in a response, given the previous one as of now. body
+// is the body of a refused request and nil otherwise. It reports false when the response
+// says nothing about the quota.
+type quotaParser func(previous Quota, resp *http.Response, body []byte, now time.Time) (Quota, bool)
+
+// quotaParserFor returns the parser for the quota a provider reports, or nil when its
+// responses carry no quota. Groq is used through its OpenAI-compatible API.
+func quotaParserFor(provider config.Provider) quotaParser {
+	switch provider.Type {
+	case "gemini":
+		return parseGeminiQuota
+	case "openai", "custom":
+		if strings.Contains(strings.ToLower(provider.BaseURL), "api.groq.com") {
+			return parseGroqQuota
+		}
+	}
+	return nil
+}
+
+// groqRetryIn finds the wait in a Groq rate limit message, e.g. "Please try again in 6m0.192s."
+var groqRetryIn = regexp.MustCompile(`try again in ((?:[0-9.]+(?:h|ms|m|s))+)`)
+
+// parseGroqQuota reads the request quota from Groq's x-ratelimit headers, which come with
+// every response, and the wait from a refused request's Retry-After header or message
+func parseGroqQuota(previous Quota, resp *http.Response, body []byte, now time.Time) (Quota, bool) {
+	quota, found := previous, false
+	if n, err := strconv.Atoi(resp.Header.Get("x-ratelimit-limit-requests")); err == nil {
+		quota.Limit, found = n, true
+	}
+	if n, err := strconv.Atoi(resp.Header.Get("x-ratelimit-remaining-requests")); err == nil {
+		quota.Remaining, found = n, true
+	}
+	if d, err := time.ParseDuration(resp.Header.Get("x-ratelimit-reset-requests")); err == nil {
+		quota.ResetAt, quota.ResetsDaily, found = now.Add(d), false, true
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return quota, found
+	}
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+			Type    string `json:"type"` // "requests" or "tokens"
+		} `json:"error"`
+	}
+	json.Unmarshal(body, &payload)
+
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	}
+	if m := groqRetryIn.FindStringSubmatch(payload.Error.Message); m != nil {
+		if d, err := time.ParseDuration(m[1]); err == nil {
+			wait = max(wait, d)
+		}
+	}
+	if wait <= 0 {
+		return quota, found
+	}
+	if payload.Error.Type == "requests" {
+		quota.Remaining = 0
+	}
+	quota.RetryAt = now.Add(wait)
+	return quota, true
+}
+
+// geminiQuotaZone is where Gemini's daily quotas reset at midnight
+const geminiQuotaZone = "America/Los_Angeles"
+
+// parseGeminiQuota reads a refused request's RESOURCE_EXHAUSTED error. Gemini sends no quota
+// with successful responses, so once the daily limit is known from an error, the requests
+// sent since are counted against it.
+func parseGeminiQuota(previous Quota, resp *http.Response, body []byte, now time.Time) (Quota, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests {
+		if resp.StatusCode >= 300 || previous.Limit <= 0 || previous.Remaining <= 0 {
+			return previous, false
+		}
+		previous.Remaining--
+		return previous, true
+	}
+
+	var payload struct {
+		Error struct {
+			Status  string `json:"status"`
+			Details []struct {
+				Type       string `json:"@type"`
+				RetryDelay string `json:"retryDelay"`
+				Violations []struct {
+					QuotaID    string `json:"quotaId"`
+					QuotaValue string `json:"quotaValue"`
+				} `json:"violations"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Error.Status != "RESOURCE_EXHAUSTED" {
+		return previous, false
+	}
+
+	quota := previous
+	quota.RetryAt = now
+	for _, detail := range payload.Error.Details {
+		switch {
+		case strings.HasSuffix(detail.Type, "google.rpc.RetryInfo"):
+			if d, err := time.ParseDuration(detail.RetryDelay); err == nil {
+				quota.RetryAt = maxTime(quota.RetryAt, now.Add(d))
+			}
+		case strings.HasSuffix(detail.Type, "google.rpc.QuotaFailure"):
+			for _, v := range detail.Violations {
+				// Per-minute and token quotas pass quickly; only the daily request cap is tracked
+				if !strings.Contains(v.QuotaID, "Requests") || !strings.Contains(v.QuotaID, "PerDay") {
+					continue
+				}
+				if n, err := strconv.Atoi(v.QuotaValue); err == nil {
+					quota.Limit = n
+				}
+				quota.Remaining = 0
+				quota.ResetAt = nextMidnight(now, geminiQuotaZone)
+				quota.ResetsDaily = true
+				quota.RetryAt = maxTime(quota.RetryAt, quota.ResetAt)
+			}
+		}
+	}
+	return quota, true
+}
+
+// nextMidnight returns the start of the day after now in the named time zone
+func nextMidnight(now time.Time, zone string) time.Time {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+}
+
+// maxTime returns the later of a and b
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// quotaTransport records the quota reported in each of a provider's responses
+type quotaTransport struct {
+	base     http.RoundTripper // nil uses http.DefaultTransport
+	provider string
+	parse    quotaParser
+}
+
+// RoundTrip sends req and records the quota in the response. The body of a refused request
+// is read for the details and replaced, so the caller still sees the provider's error.
+func (t *quotaTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	var body []byte
+	if resp.StatusCode == http.StatusTooManyRequests {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, maxQuotaErrorBody))
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	Quotas.record(t.provider, t.parse, resp, body, time.Now())
+	return resp, nil
+}
+
+// withQuotaTracking returns a copy of client, or of the default client when nil, that records
+// the provider's quota from every response
+func withQuotaTracking(client *http.Client, provider string, parse quotaParser) *http.Client {
+	tracking := &http.Client{}
+	if client != nil {
+		*tracking = *client
+	}
+	tracking.Transport = &quotaTransport{base: tracking.Transport, provider: provider, parse: parse}
+	return tracking
+}
@@ -0,0 +1,258 @@
+package llm
+
+import (
+	"chatgo/internal/config"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata" // Gemini's quota zone, whatever the machine has installed
+)
+
+// quotaNow is when the recorded responses arrive: 08:00 in Los Angeles, where it is still
+// October 17, so Gemini's daily quota resets at 07:00 UTC on October 18
+var quotaNow = time.Date(2026, 10, 17, 15, 0, 0, 0, time.UTC)
+
+// quotaResponse returns a response with the status and headers, given as name and value pairs
+func quotaResponse(status int, headers ...string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: make(http.Header)}
+	for i := 0; i+1 < len(headers); i += 2 {
+		resp.Header.Set(headers[i], headers[i+1])
+	}
+	return resp
+}
+
+// quotaFixture reads a recorded response body from testdata
+func quotaFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	if name == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// sameQuota reports whether a and b are the same quota, with times in any zone
+func sameQuota(a, b Quota) bool {
+	return a.Remaining == b.Remaining && a.Limit == b.Limit && a.ResetsDaily == b.ResetsDaily &&
+		a.ResetAt.Equal(b.ResetAt) && a.RetryAt.Equal(b.RetryAt) && a.UpdatedAt.Equal(b.UpdatedAt)
+}
+
+// unknownQuota is the quota of a provider that never reported one
+var unknownQuota = Quota{Remaining: -1}
+
+func TestParseGroqQuota(t *testing.T) {
+	// The headers Groq sends with every response
+	rateLimits := []string{
+		"x-ratelimit-limit-requests", "1000",
+		"x-ratelimit-remaining-requests", "993",
+		"x-ratelimit-reset-requests", "10m4.8s",
+		"x-ratelimit-limit-tokens", "12000",
+		"x-ratelimit-remaining-tokens", "11727",
+		"x-ratelimit-reset-tokens", "1.365s",
+	}
+	exhausted := []string{
+		"x-ratelimit-limit-requests", "1000",
+		"x-ratelimit-remaining-requests", "0",
+		"x-ratelimit-reset-requests", "6m0.192s",
+		"retry-after", "360",
+	}
+
+	tests := []struct {
+		name     string
+		previous Quota
+		resp     *http.Response
+		body     string // File in testdata
+		want     Quota
+		changed  bool
+	}{
+		{
+			name:    "headers",
+			resp:    quotaResponse(http.StatusOK, rateLimits...),
+			want:    Quota{Remaining: 993, Limit: 1000, ResetAt: quotaNow.Add(10*time.Minute + 4800*time.Millisecond)},
+			changed: true,
+		},
+		{
+			name:     "no headers",
+			previous: Quota{Remaining: 12, Limit: 1000},
+			resp:     quotaResponse(http.StatusOK),
+			want:     Quota{Remaining: 12, Limit: 1000},
+		},
+		{
+			name: "daily requests used up",
+			resp: quotaResponse(http.StatusTooManyRequests, exhausted...),
+			body: "groq_429_requests.json",
+			want: Quota{
+				Remaining: 0,
+				Limit:     1000,
+				ResetAt:   quotaNow.Add(6*time.Minute + 192*time.Millisecond),
+				// The message's 6m0.192s is later than Retry-After's 360s
+				RetryAt: quotaNow.Add(6*time.Minute + 192*time.Millisecond),
+			},
+			changed: true,
+		},
+		{
+			name:     "message without headers",
+			previous: unknownQuota,
+			resp:     quotaResponse(http.StatusTooManyRequests),
+			body:     "groq_429_requests.json",
+			want:     Quota{Remaining: 0, RetryAt: quotaNow.Add(6*time.Minute + 192*time.Millisecond)},
+			changed:  true,
+		},
+		{
+			name:    "tokens per minute used up",
+			resp:    quotaResponse(http.StatusTooManyRequests, rateLimits...),
+			body:    "groq_429_tokens.json",
+			want:    Quota{Remaining: 993, Limit: 1000, ResetAt: quotaNow.Add(10*time.Minute + 4800*time.Millisecond), RetryAt: quotaNow.Add(4400 * time.Millisecond)},
+			changed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := parseGroqQuota(tt.previous, tt.resp, quotaFixture(t, tt.body), quotaNow)
+			if changed != tt.changed {
+				t.Errorf("changed = %v, want %v", changed, tt.changed)
+			}
+			if !sameQuota(got, tt.want) {
+				t.Errorf("quota = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGeminiQuota(t *testing.T) {
+	midnight := time.Date(2026, 10, 18, 7, 0, 0, 0, time.UTC)
+	known := Quota{Remaining: 5, Limit: 200, ResetAt: midnight, ResetsDaily: true}
+
+	tests := []struct {
+		name     string
+		previous Quota
+		resp     *http.Response
+		body     string // File in testdata
+		want     Quota
+		changed  bool
+	}{
+		{
+			name:     "daily requests used up",
+			previous: unknownQuota,
+			resp:     quotaResponse(http.StatusTooManyRequests),
+			body:     "gemini_429_per_day.json",
+			// The 27s RetryInfo delay passes long before the daily quota resets
+			want:    Quota{Remaining: 0, Limit: 200, ResetAt: midnight, ResetsDaily: true, RetryAt: midnight},
+			changed: true,
+		},
+		{
+			name:     "requests per minute used up",
+			previous: known,
+			resp:     quotaResponse(http.StatusTooManyRequests),
+			body:     "gemini_429_per_minute.json",
+			want:     Quota{Remaining: 5, Limit: 200, ResetAt: midnight, ResetsDaily: true, RetryAt: quotaNow.Add(27 * time.Second)},
+			changed:  true,
+		},
+		{
+			name:     "success counts against a known limit",
+			previous: known,
+			resp:     quotaResponse(http.StatusOK),
+			want:     Quota{Remaining: 4, Limit: 200, ResetAt: midnight, ResetsDaily: true},
+			changed:  true,
+		},
+		{
+			name:     "success without a known limit",
+			previous: unknownQuota,
+			resp:     quotaResponse(http.StatusOK),
+			want:     unknownQuota,
+		},
+		{
+			name:     "success with nothing left",
+			previous: Quota{Remaining: 0, Limit: 200},
+			resp:     quotaResponse(http.StatusOK),
+			want:     Quota{Remaining: 0, Limit: 200},
+		},
+		{
+			name:     "other error",
+			previous: known,
+			resp:     quotaResponse(http.StatusServiceUnavailable),
+			body:     "gemini_500.json",
+			want:     known,
+		},
+		{
+			name:     "429 that isn't RESOURCE_EXHAUSTED",
+			previous: known,
+			resp:     quotaResponse(http.StatusTooManyRequests),
+			body:     "gemini_500.json",
+			want:     known,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := parseGeminiQuota(tt.previous, tt.resp, quotaFixture(t, tt.body), quotaNow)
+			if changed != tt.changed {
+				t.Errorf("changed = %v, want %v", changed, tt.changed)
+			}
+			if !sameQuota(got, tt.want) {
+				t.Errorf("quota = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeminiQuotaResetsAtMidnight(t *testing.T) {
+	quota, _ := parseGeminiQuota(unknownQuota, quotaResponse(http.StatusTooManyRequests),
+		quotaFixture(t, "gemini_429_per_day.json"), quotaNow)
+
+	if got := quota.at(quotaNow.Add(time.Hour)); got.Remaining != 0 || !got.Exhausted(quotaNow.Add(time.Hour)) {
+		t.Errorf("quota an hour later = %+v, want still exhausted", got)
+	}
+
+	after := quota.ResetAt.Add(time.Minute)
+	got := quota.at(after)
+	if got.Remaining != 200 || got.Exhausted(after) {
+		t.Errorf("quota after midnight = %+v, want the whole limit available", got)
+	}
+	if want := quota.ResetAt.AddDate(0, 0, 1); !got.ResetAt.Equal(want) {
+		t.Errorf("next reset = %s, want %s", got.ResetAt, want)
+	}
+}
+
+func TestQuotaParserFor(t *testing.T) {
+	tests := []struct {
+		provider config.Provider
+		want     quotaParser
+	}{
+		{config.Provider{Type: "gemini"}, parseGeminiQuota},
+		{config.Provider{Type: "openai", BaseURL: "https://api.groq.com/openai/v1"}, parseGroqQuota},
+		{config.Provider{Type: "custom", BaseURL: "https://API.Groq.com/openai/v1/"}, parseGroqQuota},
+		{config.Provider{Type: "openai"}, nil},
+		{config.Provider{Type: "openai", BaseURL: "https://api.openai.com/v1"}, nil},
+		{config.Provider{Type: "claude", BaseURL: "https://api.groq.com/openai/v1"}, nil},
+		{config.Provider{Type: "ollama"}, nil},
+	}
+
+	for _, tt := range tests {
+		got := quotaParserFor(tt.provider)
+		if reflect.ValueOf(got).Pointer() != reflect.ValueOf(tt.want).Pointer() {
+			t.Errorf("quotaParserFor(%s at %q) = %s, want %s", tt.provider.Type, tt.provider.BaseURL,
+				parserName(got), parserName(tt.want))
+		}
+	}
+}
+
+// parserName names a quota parser for test failures
+func parserName(parse quotaParser) string {
+	switch reflect.ValueOf(parse).Pointer() {
+	case reflect.ValueOf(quotaParser(nil)).Pointer():
+		return "nil"
+	case reflect.ValueOf(parseGroqQuota).Pointer():
+		return "parseGroqQuota"
+	case reflect.ValueOf(parseGeminiQuota).Pointer():
+		return "parseGeminiQuota"
+	}
+	return "an unknown parser"
+}
@@ -0,0 +1,118 @@
+package llm
+
+import (
+	"chatgo/internal/config"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// rateLimiter is a token bucket allowing a burst of up to rpm requests, refilled evenly over a minute
+type rateLimiter struct {
+	mu     sync.Mutex
+	rpm    int
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiters holds one limiter per provider name, shared by every client of that provider
+var (
+	rateLimitersMu sync.Mutex
+	rateLimiters   = make(map[string]*rateLimiter)
+)
+
+// limiterFor returns the shared limiter for a provider, or nil when its requests are not limited.
+// A changed requests-per-minute setting applies to the existing limiter.
+func limiterFor(provider config.Provider) *rateLimiter {
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+
+	if provider.RequestsPerMinute <= 0 {
+		delete(rateLimiters, provider.Name)
+		return nil
+	}
+
+	l, ok := rateLimiters[provider.Name]
+	if !ok {
+		l = &rateLimiter{rpm: provider.RequestsPerMinute, tokens: float64(provider.RequestsPerMinute), last: time.Now()}
+		rateLimiters[provider.Name] = l
+		return l
+	}
+	l.mu.Lock()
+	l.rpm = provider.RequestsPerMinute
+	l.tokens = min(l.tokens, float64(l.rpm))
+	l.mu.Unlock()
+	return l
+}
+
+// Wait blocks until a request may be sent or ctx is done. A nil limiter never blocks.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available, otherwise returns how long until the next one
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	perToken := time.Minute / time.Duration(l.rpm)
+	l.tokens = min(float64(l.rpm), l.tokens+float64(now.Sub(l.last))/float64(perToken))
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) * float64(perToken))
+}
+
+// rateLimitedModel waits for the provider's limiter before each model request. The ReAct agent
+// calls the model once per step, so every step counts against the limit.
+type rateLimitedModel struct {
+	model.ToolCallingChatModel
+	limiter *rateLimiter
+}
+
+// Generate waits for the limiter, then generates a response
+func (m *rateLimitedModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if err := m.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return m.ToolCallingChatModel.Generate(ctx, input, opts...)
+}
+
+// Stream waits for the limiter, then starts a streaming response
+func (m *rateLimitedModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	if err := m.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return m.ToolCallingChatModel.Stream(ctx, input, opts...)
+}
+
+// WithTools binds tools and keeps the rate limit on the returned model
+func (m *rateLimitedModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	withTools, err := m.ToolCallingChatModel.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &rateLimitedModel{ToolCallingChatModel: withTools, limiter: m.limiter}, nil
+}
@@ -3,6 +3,7 @@ package llm
 import (
 	"chatgo/internal/config"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -16,10 +17,11 @@ import (
 
 // ReactClient wraps a React Agent for tool-enabled conversations
 type ReactClient struct {
-	agent   *react.Agent
-	model   model.ToolCallingChatModel
-	tools   *compose.ToolsNodeConfig
-	config  *ReactAgentConfig
+	agent    *react.Agent
+	model    model.ToolCallingChatModel
+	tools    *compose.ToolsNodeConfig
+	config   *ReactAgentConfig
+	provider config.Provider // Decides whether replies are streamed
 }
 
 // ReactAgentConfig holds configuration for the React Agent
@@ -39,17 +41,17 @@ type ToolDefinition struct {
 }
 
 // NewReactClient creates a new React Agent client
-func NewReactClient(provider config.Provider, tools []ToolDefinition, agentConfig *ReactAgentConfig) (*ReactClient, error) {
+func NewReactClient(provider config.Provider, opts ClientOptions, tools []ToolDefinition, agentConfig *ReactAgentConfig) (*ReactClient, error) {
 	ctx := context.Background()
 
 	// First, create the base chat model
-	baseClient, err := NewClient(provider)
+	baseClient, err := NewClient(provider, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create base client: %w", err)
 	}
 
 	// Check if the model supports tool calling
-	toolableModel, ok := baseClient.model.(model.ToolCallingChatModel)
+	toolableModel, ok := baseClient.toolCallingModel()
 	if !ok {
 		return nil, fmt.Errorf("model %s does not support tool calling", provider.Type)
 	}
@@ -60,26 +62,36 @@ func NewReactClient(provider config.Provider, tools []ToolDefinition, agentConfi
 		einoTools[i] = newToolWrapper(toolDef)
 	}
 
-	return createReactClientWithTools(ctx, toolableModel, einoTools, agentConfig)
+	client, err := createReactClientWithTools(ctx, toolableModel, einoTools, agentConfig)
+	if err != nil {
+		return nil, err
+	}
+	client.provider = provider
+	return client, nil
 }
 
 // NewReactClientWithEinoTools creates a new React Agent client with pre-built Eino tools
-func NewReactClientWithEinoTools(provider config.Provider, einoTools []tool.BaseTool, agentConfig *ReactAgentConfig) (*ReactClient, error) {
+func NewReactClientWithEinoTools(provider config.Provider, opts ClientOptions, einoTools []tool.BaseTool, agentConfig *ReactAgentConfig) (*ReactClient, error) {
 	ctx := context.Background()
 
 	// First, create the base chat model
-	baseClient, err := NewClient(provider)
+	baseClient, err := NewClient(provider, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create base client: %w", err)
 	}
 
 	// Check if the model supports tool calling
-	toolableModel, ok := baseClient.model.(model.ToolCallingChatModel)
+	toolableModel, ok := baseClient.toolCallingModel()
 	if !ok {
 		return nil, fmt.Errorf("model %s does not support tool calling", provider.Type)
 	}
 
-	return createReactClientWithTools(ctx, toolableModel, einoTools, agentConfig)
+	client, err := createReactClientWithTools(ctx, toolableModel, einoTools, agentConfig)
+	if err != nil {
+		return nil, err
+	}
+	client.provider = provider
+	return client, nil
 }
 
 // createReactClientWithTools creates a ReactClient with given Eino tools
@@ -87,6 +99,8 @@ func createReactClientWithTools(ctx context.Context, toolableModel model.ToolCal
 	// Build tools config
 	toolsConfig := &compose.ToolsNodeConfig{
 		Tools: einoTools,
+		// Calls are recorded before approval so declined calls are listed too
+		ToolCallMiddlewares: []compose.ToolMiddleware{toolCallRecordingMiddleware, toolApprovalMiddleware},
 	}
 
 	// Set default message modifier if system prompt is provided
@@ -134,22 +148,42 @@ func createReactClientWithTools(ctx context.Context, toolableModel model.ToolCal
 
 // Chat sends a chat completion request with streaming support using React Agent
 func (c *ReactClient) Chat(ctx context.Context, messages []ChatMessage, onChunk func(string)) (*ChatResponse, error) {
+	return c.ChatWithToolCalls(ctx, messages, onChunk, nil)
+}
+
+// ChatWithToolCalls is Chat that also reports the agent's tool calls while it works.
+// onToolCall receives a call's record when it starts and again with Done set when it
+// returns; it is called from the goroutine running the tool.
+func (c *ReactClient) ChatWithToolCalls(ctx context.Context, messages []ChatMessage, onChunk func(string), onToolCall func(ToolCallRecord)) (*ChatResponse, error) {
 	// Convert messages to eino format
-	einoMessages := make([]*schema.Message, len(messages))
-	for i, msg := range messages {
-		einoMessages[i] = &schema.Message{
-			Role:    schema.RoleType(msg.Role),
-			Content: msg.Content,
+	einoMessages := toEinoMessages(messages, true)
+
+	// Record the tools the agent calls while answering
+	ctx, recorder := withToolCallRecorder(ctx, onToolCall)
+
+	var response *ChatResponse
+	var err error
+
+	// If streaming callback is provided, use Stream, unless the provider doesn't stream
+	if onChunk != nil && shouldStream(c.provider) {
+		response, err = c.chatWithStream(ctx, einoMessages, onChunk)
+		if errors.Is(err, errStreamRejected) {
+			response, err = c.chatWithoutStreamAsChunk(ctx, einoMessages, onChunk)
 		}
+	} else if onChunk != nil {
+		logger.Debug("Sending agent request without streaming", "provider", c.provider.Name,
+			"disabled", c.provider.DisableStreaming)
+		response, err = c.chatWithoutStreamAsChunk(ctx, einoMessages, onChunk)
+	} else {
+		// Otherwise use Generate
+		response, err = c.chatWithoutStream(ctx, einoMessages)
 	}
-
-	// If streaming callback is provided, use Stream
-	if onChunk != nil {
-		return c.chatWithStream(ctx, einoMessages, onChunk)
+	if err != nil {
+		return nil, err
 	}
 
-	// Otherwise use Generate
-	return c.chatWithoutStream(ctx, einoMessages)
+	response.ToolCalls = recorder.records()
+	return response, nil
 }
 
 // chatWithStream sends a streaming chat completion request via React Agent
@@ -157,12 +191,16 @@ func (c *ReactClient) chatWithStream(ctx context.Context, messages []*schema.Mes
 	// Create stream reader
 	streamReader, err := c.agent.Stream(ctx, messages)
 	if err != nil {
+		if ctx.Err() == nil && rejectsStreaming(c.provider, err) {
+			return nil, fmt.Errorf("%w: %w", errStreamRejected, err)
+		}
 		return nil, fmt.Errorf("failed to create stream: %w", err)
 	}
 
 	defer streamReader.Close()
 
 	var fullContent strings.Builder
+	received := false
 
 	// Read from stream
 	for {
@@ -171,8 +209,12 @@ func (c *ReactClient) chatWithStream(ctx context.Context, messages []*schema.Mes
 			if err == io.EOF {
 				break
 			}
+			if !received && ctx.Err() == nil && rejectsStreaming(c.provider, err) {
+				return nil, fmt.Errorf("%w: %w", errStreamRejected, err)
+			}
 			return nil, fmt.Errorf("failed to receive from stream: %w", err)
 		}
+		received = true
 
 		if chunk != nil && chunk.Content != "" {
 			fullContent.WriteString(chunk.Content)
@@ -186,6 +228,20 @@ func (c *ReactClient) chatWithStream(ctx context.Context, messages []*schema.Mes
 	}, nil
 }
 
+// chatWithoutStreamAsChunk sends a non-streaming request in place of a streaming one and
+// passes the whole reply to onChunk
+func (c *ReactClient) chatWithoutStreamAsChunk(ctx context.Context, messages []*schema.Message, onChunk func(string)) (*ChatResponse, error) {
+	response, err := c.chatWithoutStream(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+	response.NotStreamed = true
+	if response.Content != "" {
+		onChunk(response.Content)
+	}
+	return response, nil
+}
+
 // chatWithoutStream sends a non-streaming chat completion request via React Agent
 func (c *ReactClient) chatWithoutStream(ctx context.Context, messages []*schema.Message) (*ChatResponse, error) {
 	// Generate response
@@ -257,11 +313,6 @@ func (w *toolWrapper) Info(ctx context.Context) (*schema.ToolInfo, error) {
 }
 
 // InvokableRun executes the tool (required by InvokableTool interface)
-func (w *toolWrapper) InvokableRun(ctx context.Context, arguments string) (string, error) {
+func (w *toolWrapper) InvokableRun(ctx context.Context, arguments string, opts ...tool.Option) (string, error) {
 	return w.handler(ctx, arguments)
 }
-
-// StreamableRun executes the tool with streaming (optional, returns not supported)
-func (w *toolWrapper) StreamableRun(ctx context.Context, arguments string) (*schema.StreamReader[string], error) {
-	return nil, fmt.Errorf("streaming not supported for this tool")
-}
@@ -0,0 +1,164 @@
+package llm
+
+import (
+	"chatgo/internal/config"
+	"chatgo/internal/mcp"
+	"chatgo/internal/mcp/mcptest"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	einomcp "github.com/cloudwego/eino-ext/components/tool/mcp"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// toolCallingModel asks for one call of the add tool, then answers with the tool's result
+type toolCallingModel struct {
+	mu     sync.Mutex
+	tools  []*schema.ToolInfo
+	inputs [][]*schema.Message // Messages of each request
+}
+
+func (m *toolCallingModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inputs = append(m.inputs, input)
+
+	last := input[len(input)-1]
+	if last.Role != schema.Tool {
+		return &schema.Message{
+			Role: schema.Assistant,
+			ToolCalls: []schema.ToolCall{{
+				ID:       "call_add",
+				Type:     "function",
+				Function: schema.FunctionCall{Name: mcptest.AddTool, Arguments: `{"a": 2, "b": 3}`},
+			}},
+		}, nil
+	}
+	return schema.AssistantMessage("The tool said: "+last.Content, nil), nil
+}
+
+func (m *toolCallingModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func (m *toolCallingModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tools = tools
+	return m, nil
+}
+
+// mcpAgentTools connects a scripted MCP server and returns its tools as the chat window
+// hands them to the agent
+func mcpAgentTools(t *testing.T) ([]tool.BaseTool, *mcptest.Server) {
+	t.Helper()
+	srv := mcptest.NewServer()
+	t.Cleanup(srv.Close)
+
+	m := mcp.NewManager()
+	t.Cleanup(m.DisconnectAll)
+	if _, err := m.InitializeServer(srv.Config("test")); err != nil {
+		t.Fatalf("InitializeServer: %v", err)
+	}
+	toolClient, ok := m.ToolClient("test")
+	if !ok {
+		t.Fatal("ToolClient found no initialized server")
+	}
+	tools, err := einomcp.GetTools(context.Background(), &einomcp.Config{
+		Cli:          toolClient,
+		ToolNameList: []string{mcptest.AddTool, mcptest.EchoTool},
+	})
+	if err != nil {
+		t.Fatalf("GetTools: %v", err)
+	}
+	return tools, srv
+}
+
+func TestReactAgentCallsMCPTools(t *testing.T) {
+	tools, srv := mcpAgentTools(t)
+	fake := &toolCallingModel{}
+	c, err := createReactClientWithTools(context.Background(), fake, tools, &ReactAgentConfig{MaxStep: 5})
+	if err != nil {
+		t.Fatalf("createReactClientWithTools: %v", err)
+	}
+	c.provider = config.Provider{Name: t.Name()}
+
+	for _, stream := range []bool{true, false} {
+		var chunks strings.Builder
+		onChunk := func(s string) { chunks.WriteString(s) }
+		if !stream {
+			onChunk = nil
+		}
+		var updates []ToolCallRecord
+		var updatesMu sync.Mutex
+		response, err := c.ChatWithToolCalls(context.Background(), []ChatMessage{{Role: "user", Content: "What is 2 + 3?"}},
+			onChunk, func(r ToolCallRecord) {
+				updatesMu.Lock()
+				updates = append(updates, r)
+				updatesMu.Unlock()
+			})
+		if err != nil {
+			t.Fatalf("ChatWithToolCalls (stream %v): %v", stream, err)
+		}
+
+		if !strings.HasPrefix(response.Content, "The tool said: ") || !strings.Contains(response.Content, "5") {
+			t.Errorf("reply (stream %v) = %q, want the add tool's result", stream, response.Content)
+		}
+		if stream && chunks.String() != response.Content {
+			t.Errorf("streamed %q, want the reply %q", chunks.String(), response.Content)
+		}
+		if len(response.ToolCalls) != 1 {
+			t.Fatalf("recorded %d tool calls (stream %v), want 1", len(response.ToolCalls), stream)
+		}
+		call := response.ToolCalls[0]
+		if call.ID != "call_add" || call.Name != mcptest.AddTool || !call.Done || call.Error != "" || !strings.Contains(call.Result, "5") {
+			t.Errorf("recorded call (stream %v) = %+v, want a finished add call returning 5", stream, call)
+		}
+		if len(updates) != 2 || updates[0].Done || !updates[1].Done {
+			t.Errorf("tool call updates (stream %v) = %+v, want its start and its end", stream, updates)
+		}
+	}
+
+	if calls := srv.Calls(); calls != 2 {
+		t.Errorf("server answered %d calls, want 2", calls)
+	}
+	var offered []string
+	for _, info := range fake.tools {
+		offered = append(offered, info.Name)
+	}
+	if len(offered) != 2 {
+		t.Errorf("model was offered tools %v, want add and echo", offered)
+	}
+}
+
+func TestReactAgentDeclinedMCPCallIsNotSent(t *testing.T) {
+	tools, srv := mcpAgentTools(t)
+	fake := &toolCallingModel{}
+	c, err := createReactClientWithTools(context.Background(), fake, tools, &ReactAgentConfig{MaxStep: 5})
+	if err != nil {
+		t.Fatalf("createReactClientWithTools: %v", err)
+	}
+	c.provider = config.Provider{Name: t.Name()}
+
+	ctx := WithToolApprover(context.Background(), func(ctx context.Context, name, arguments string) (bool, error) {
+		return false, nil
+	})
+	response, err := c.Chat(ctx, []ChatMessage{{Role: "user", Content: "What is 2 + 3?"}}, nil)
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if calls := srv.Calls(); calls != 0 {
+		t.Errorf("server answered %d calls, want none for a declined call", calls)
+	}
+	if response.Content != "The tool said: "+toolDeclinedResult {
+		t.Errorf("reply = %q, want the model told the call was declined", response.Content)
+	}
+}
@@ -0,0 +1,59 @@
+package llm
+
+import (
+	"chatgo/internal/config"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// errStreamRejected marks a streaming request the provider refused, to be sent again without streaming
+var errStreamRejected = errors.New("provider rejected streaming")
+
+// streamingRejections are phrases gateways use when refusing `stream: true`, matched case-insensitively
+var streamingRejections = []string{
+	"stream is not supported",
+	"streaming is not supported",
+	"stream not supported",
+	"streaming not supported",
+	"does not support stream",
+	"doesn't support stream",
+	"unsupported parameter: 'stream'",
+	"unsupported parameter: stream",
+	"unknown parameter: 'stream'",
+	"unrecognized request argument supplied: stream",
+	"'stream' is not allowed",
+	"stream must be false",
+}
+
+// streamingUnsupported records the providers found this session to reject streaming, by name
+var streamingUnsupported sync.Map
+
+// StreamingUnsupported reports whether the named provider rejected a streaming request this session
+func StreamingUnsupported(providerName string) bool {
+	_, ok := streamingUnsupported.Load(providerName)
+	return ok
+}
+
+// shouldStream reports whether requests to provider are streamed: not when the provider
+// disables streaming or has rejected it this session
+func shouldStream(provider config.Provider) bool {
+	return !provider.DisableStreaming && !StreamingUnsupported(provider.Name)
+}
+
+// rejectsStreaming reports whether err, returned before any chunk arrived, is the provider
+// refusing a streaming request: a known phrase, or a 400 naming the stream parameter. The
+// provider is then remembered for the session.
+func rejectsStreaming(provider config.Provider, err error) bool {
+	msg := strings.ToLower(err.Error())
+	rejected := strings.Contains(msg, "400") && (strings.Contains(msg, "'stream'") || strings.Contains(msg, `"stream"`))
+	for _, phrase := range streamingRejections {
+		rejected = rejected || strings.Contains(msg, phrase)
+	}
+	if rejected {
+		streamingUnsupported.Store(provider.Name, true)
+		logger.Warn("Provider rejected streaming; sending whole requests for the rest of the session",
+			"provider", provider.Name, "error", err)
+	}
+	return rejected
+}
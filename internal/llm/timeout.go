@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"chatgo/internal/config"
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultRequestTimeout is the time allowed until the first chunk when a provider has no timeout configured
+	DefaultRequestTimeout = 60 * time.Second
+
+	// idleTimeoutFactor scales the request timeout into the allowed gap between chunks.
+	// Once a response is streaming, pauses are expected to be longer than the initial wait, e.g. while tools run.
+	idleTimeoutFactor = 3
+)
+
+// ErrRequestTimeout is returned when the provider stops responding within the configured timeout
+var ErrRequestTimeout = errors.New("request timed out")
+
+// RequestTimeout returns the time-to-first-chunk timeout configured for a provider
+func RequestTimeout(provider config.Provider) time.Duration {
+	if provider.RequestTimeoutSeconds > 0 {
+		return time.Duration(provider.RequestTimeoutSeconds) * time.Second
+	}
+	return DefaultRequestTimeout
+}
+
+// StreamTimeout cancels a streaming request when the first chunk takes longer than the
+// request timeout, or when a later chunk takes longer than the inactivity timeout
+type StreamTimeout struct {
+	timer    *time.Timer
+	idle     time.Duration
+	cancel   context.CancelFunc
+	timedOut atomic.Bool
+}
+
+// NewStreamTimeout returns a context that is cancelled when the request stalls.
+// Call Touch on every chunk and Stop when the request has finished.
+func NewStreamTimeout(parent context.Context, timeout time.Duration) (context.Context, *StreamTimeout) {
+	ctx, cancel := context.WithCancel(parent)
+	t := &StreamTimeout{idle: timeout * idleTimeoutFactor, cancel: cancel}
+	t.timer = time.AfterFunc(timeout, func() {
+		t.timedOut.Store(true)
+		cancel()
+	})
+	return ctx, t
+}
+
+// Touch records activity, switching to the inactivity timeout
+func (t *StreamTimeout) Touch() {
+	t.timer.Reset(t.idle)
+}
+
+// Pause disarms the timeout while the request waits on the user, e.g. to approve a tool call.
+// The next Touch arms it again.
+func (t *StreamTimeout) Pause() {
+	t.timer.Stop()
+}
+
+// Stop disarms the timeout and releases the context
+func (t *StreamTimeout) Stop() {
+	t.timer.Stop()
+	t.cancel()
+}
+
+// Err returns ErrRequestTimeout in place of err when the request was cancelled by the timeout
+func (t *StreamTimeout) Err(err error) error {
+	if err != nil && t.timedOut.Load() {
+		return ErrRequestTimeout
+	}
+	return err
+}
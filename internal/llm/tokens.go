@@ -0,0 +1,18 @@
+package llm
+
+import "unicode"
+
+// EstimateTokens roughly counts the tokens text will take up, for showing before it is sent.
+// Tokenizers differ between models; about four characters of Latin script per token and one
+// token per CJK character is close for the common ones.
+func EstimateTokens(text string) int {
+	var cjk, other int
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
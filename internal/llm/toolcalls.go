@@ -0,0 +1,157 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ToolCallRecord describes a tool invocation made by the agent while answering
+type ToolCallRecord struct {
+	ID        string
+	Name      string
+	Arguments string // Arguments as JSON
+	Result    string
+	Error     string
+	Duration  time.Duration
+	Done      bool // Set once the tool has returned
+}
+
+// toolCallRecorderKey is the context key of the recorder for the current request
+type toolCallRecorderKey struct{}
+
+// toolCallRecorder collects tool invocations in the order they started
+type toolCallRecorder struct {
+	mu       sync.Mutex
+	calls    []ToolCallRecord
+	onUpdate func(ToolCallRecord) // Called when a call starts and when it finishes; may be nil
+}
+
+// withToolCallRecorder returns a context whose tool invocations are recorded by the returned
+// recorder. onUpdate, if not nil, is called from the tool's goroutine as calls progress.
+func withToolCallRecorder(ctx context.Context, onUpdate func(ToolCallRecord)) (context.Context, *toolCallRecorder) {
+	recorder := &toolCallRecorder{onUpdate: onUpdate}
+	return context.WithValue(ctx, toolCallRecorderKey{}, recorder), recorder
+}
+
+// start records the beginning of a call and returns a function that completes it
+func (r *toolCallRecorder) start(input *compose.ToolInput) func(result string, err error) {
+	r.mu.Lock()
+	index := len(r.calls)
+	record := ToolCallRecord{
+		ID:        input.CallID,
+		Name:      input.Name,
+		Arguments: input.Arguments,
+	}
+	// Updates are matched to their call by ID, so every call needs one
+	if record.ID == "" {
+		record.ID = fmt.Sprintf("call_%d", index+1)
+	}
+	r.calls = append(r.calls, record)
+	r.mu.Unlock()
+	r.notify(record)
+
+	started := time.Now()
+	return func(result string, err error) {
+		r.mu.Lock()
+		r.calls[index].Result = result
+		r.calls[index].Duration = time.Since(started)
+		r.calls[index].Done = true
+		if err != nil {
+			r.calls[index].Error = err.Error()
+		}
+		record := r.calls[index]
+		r.mu.Unlock()
+		r.notify(record)
+	}
+}
+
+// notify passes a changed record to onUpdate, outside the lock so the callback may block
+func (r *toolCallRecorder) notify(record ToolCallRecord) {
+	if r.onUpdate != nil {
+		r.onUpdate(record)
+	}
+}
+
+// records returns a copy of the recorded calls
+func (r *toolCallRecorder) records() []ToolCallRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.calls) == 0 {
+		return nil
+	}
+	return append([]ToolCallRecord(nil), r.calls...)
+}
+
+// recorderFrom returns the recorder of the current request, if any
+func recorderFrom(ctx context.Context) *toolCallRecorder {
+	recorder, _ := ctx.Value(toolCallRecorderKey{}).(*toolCallRecorder)
+	return recorder
+}
+
+// toolCallRecordingMiddleware records every tool invocation made under a context from withToolCallRecorder
+var toolCallRecordingMiddleware = compose.ToolMiddleware{
+	Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+		return func(ctx context.Context, input *compose.ToolInput) (*compose.ToolOutput, error) {
+			recorder := recorderFrom(ctx)
+			if recorder == nil {
+				return next(ctx, input)
+			}
+
+			finish := recorder.start(input)
+			output, err := next(ctx, input)
+			result := ""
+			if output != nil {
+				result = output.Result
+			}
+			finish(result, err)
+			return output, err
+		}
+	},
+	Streamable: func(next compose.StreamableToolEndpoint) compose.StreamableToolEndpoint {
+		return func(ctx context.Context, input *compose.ToolInput) (*compose.StreamToolOutput, error) {
+			recorder := recorderFrom(ctx)
+			if recorder == nil {
+				return next(ctx, input)
+			}
+
+			finish := recorder.start(input)
+			output, err := next(ctx, input)
+			if err != nil || output == nil || output.Result == nil {
+				finish("", err)
+				return output, err
+			}
+
+			// Read a copy of the stream to record the complete result
+			readers := output.Result.Copy(2)
+			go recordStream(readers[1], finish)
+			return &compose.StreamToolOutput{Result: readers[0]}, nil
+		}
+	},
+}
+
+// recordStream drains a tool result stream and completes its record
+func recordStream(reader *schema.StreamReader[string], finish func(result string, err error)) {
+	defer reader.Close()
+
+	var result strings.Builder
+	for {
+		chunk, err := reader.Recv()
+		if errors.Is(err, io.EOF) {
+			finish(result.String(), nil)
+			return
+		}
+		if err != nil {
+			finish(result.String(), err)
+			return
+		}
+		result.WriteString(chunk)
+	}
+}
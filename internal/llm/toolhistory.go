@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// maxReplayedToolResult limits how much of a tool's result is sent again with later requests
+const maxReplayedToolResult = 8000
+
+// replayedResult is a tool call's result as sent again with later requests, cut to
+// maxReplayedToolResult characters
+func replayedResult(call ToolCallRecord) string {
+	result := call.Result
+	if call.Error != "" {
+		result = "Error: " + call.Error
+	}
+	if runes := []rune(result); len(runes) > maxReplayedToolResult {
+		result = string(runes[:maxReplayedToolResult]) + "\n[result truncated]"
+	}
+	return result
+}
+
+// estimatedTokens is EstimateTokens of a message's content and the tool calls replayed with it
+func (m ChatMessage) estimatedTokens() int {
+	tokens := EstimateTokens(m.Content)
+	for _, call := range m.ToolCalls {
+		tokens += EstimateTokens(call.Name) + EstimateTokens(call.Arguments) + EstimateTokens(replayedResult(call))
+	}
+	return tokens
+}
+
+// toolTurns converts an assistant message that called tools into the turns the model took: a
+// message with the calls, a tool message with each result, then the reply itself
+func toolTurns(msg ChatMessage) []*schema.Message {
+	calls := make([]schema.ToolCall, len(msg.ToolCalls))
+	results := make([]*schema.Message, len(msg.ToolCalls))
+	for i, call := range msg.ToolCalls {
+		id := call.ID
+		if id == "" {
+			id = fmt.Sprintf("call_%d", i+1)
+		}
+		calls[i] = schema.ToolCall{
+			ID:       id,
+			Type:     "function",
+			Function: schema.FunctionCall{Name: call.Name, Arguments: call.Arguments},
+		}
+		results[i] = schema.ToolMessage(replayedResult(call), id, schema.WithToolName(call.Name))
+	}
+
+	turns := append([]*schema.Message{schema.AssistantMessage("", calls)}, results...)
+	if msg.Content != "" {
+		turns = append(turns, schema.AssistantMessage(msg.Content, nil))
+	}
+	return turns
+}
+
+// withToolCallsAsText puts the tool calls of an assistant message in front of its content, for
+// models sent no tools, which may not accept tool turns
+func withToolCallsAsText(msg ChatMessage) ChatMessage {
+	var b strings.Builder
+	b.WriteString("[Tools called for this reply]\n")
+	for _, call := range msg.ToolCalls {
+		fmt.Fprintf(&b, "- %s(%s) returned:\n%s\n", call.Name, call.Arguments, replayedResult(call))
+	}
+	if msg.Content != "" {
+		b.WriteString("\n")
+		b.WriteString(msg.Content)
+	}
+	msg.Content = b.String()
+	msg.ToolCalls = nil
+	return msg
+}
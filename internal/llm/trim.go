@@ -0,0 +1,100 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxHistorySummaryChars limits how much of the left-out messages is sent to be summarized
+const maxHistorySummaryChars = 12000
+
+const historySummaryPrompt = `The messages below are the oldest part of a conversation, which no longer fits the
+model's context. Summarize them in a short paragraph or a few bullet points, keeping facts,
+decisions, names and open questions the rest of the conversation may depend on. If a summary
+of even earlier messages is given, fold it into yours. Reply with only the summary, written
+in the main language of the conversation.`
+
+// TrimHistory leaves out the oldest messages of a conversation so the rest fit within
+// maxMessages messages and maxTokens estimated tokens; a limit of 0 is no limit. System
+// messages are always kept and count towards the tokens but not the messages. The last user
+// message and everything after it are kept even if they don't fit, and the kept messages
+// start with a user message.
+//
+// It returns the messages to send and how many leading messages were considered: the
+// non-system messages among them are the ones left out.
+func TrimHistory(messages []ChatMessage, maxMessages, maxTokens int) (kept []ChatMessage, dropped int) {
+	lastUser := len(messages)
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			lastUser = i
+			break
+		}
+	}
+
+	count, tokens := 0, 0
+	for _, msg := range messages {
+		tokens += msg.estimatedTokens()
+		if msg.Role != "system" {
+			count++
+		}
+	}
+	fits := func() bool {
+		return (maxMessages <= 0 || count <= maxMessages) && (maxTokens <= 0 || tokens <= maxTokens)
+	}
+
+	for dropped < lastUser && !fits() {
+		if msg := messages[dropped]; msg.Role != "system" {
+			count--
+			tokens -= msg.estimatedTokens()
+		}
+		dropped++
+	}
+	if dropped == 0 {
+		return messages, 0
+	}
+	// A reply left at the front after the limits are met is dropped with the message it answered
+	for dropped < lastUser && messages[dropped].Role != "user" && messages[dropped].Role != "system" {
+		dropped++
+	}
+
+	kept = make([]ChatMessage, 0, len(messages)-dropped+1)
+	for _, msg := range messages[:dropped] {
+		if msg.Role == "system" {
+			kept = append(kept, msg)
+		}
+	}
+	return append(kept, messages[dropped:]...), dropped
+}
+
+// SummarizeHistory asks the model for a summary of messages left out of a conversation's
+// context, folding in previous, the summary of messages left out before them, if any
+func SummarizeHistory(ctx context.Context, client *Client, previous string, messages []ChatMessage) (string, error) {
+	var transcript strings.Builder
+	if previous = strings.TrimSpace(previous); previous != "" {
+		fmt.Fprintf(&transcript, "Summary of even earlier messages:\n%s\n\n", previous)
+	}
+	for _, msg := range messages {
+		if msg.Role == "system" {
+			continue
+		}
+		fmt.Fprintf(&transcript, "%s: %s\n\n", msg.Role, msg.Content)
+	}
+	text := transcript.String()
+	if runes := []rune(text); len(runes) > maxHistorySummaryChars {
+		text = string(runes[:maxHistorySummaryChars])
+	}
+
+	response, err := client.Chat(ctx, []ChatMessage{
+		{Role: "system", Content: historySummaryPrompt},
+		{Role: "user", Content: text},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	summary := strings.TrimSpace(response.Content)
+	if summary == "" {
+		return "", fmt.Errorf("empty summary")
+	}
+	return summary, nil
+}
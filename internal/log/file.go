@@ -0,0 +1,114 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// MaxFileSize is the size at which the log file is rotated
+	MaxFileSize = 5 << 20
+
+	// FileBackups is how many rotated log files are kept, as chatgo.log.1 (the newest) and up
+	FileBackups = 3
+)
+
+// SetFile mirrors new entries to the file at path, rotating it once it reaches MaxFileSize.
+// An empty path stops mirroring.
+func SetFile(path string) error {
+	var file *rotatingFile
+	if path != "" {
+		var err error
+		if file, err = openRotatingFile(path, MaxFileSize, FileBackups); err != nil {
+			return err
+		}
+	}
+
+	std.mu.Lock()
+	previous := std.file
+	std.file = file
+	std.mu.Unlock()
+
+	if previous != nil {
+		previous.close()
+	}
+	return nil
+}
+
+// rotatingFile appends to a file, moving it aside once it would grow past maxSize
+type rotatingFile struct {
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+// openRotatingFile opens path for appending, creating it and its directory if needed
+func openRotatingFile(path string, maxSize int64, backups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	r := &rotatingFile{path: path, maxSize: maxSize, backups: backups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open opens the file at r.path for appending
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to read log file: %w", err)
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// write appends line, rotating first when it would take the file past maxSize
+func (r *rotatingFile) write(line string) error {
+	if r.file == nil {
+		// Reopening failed after the last rotation; try again
+		if err := r.open(); err != nil {
+			return err
+		}
+	}
+	if r.size > 0 && r.size+int64(len(line)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := r.file.WriteString(line)
+	r.size += int64(n)
+	return err
+}
+
+// rotate shifts path.1 … path.N-1 up by one, dropping the oldest, moves the file to path.1
+// and starts a new one
+func (r *rotatingFile) rotate() error {
+	r.close()
+	os.Remove(fmt.Sprintf("%s.%d", r.path, r.backups))
+	for i := r.backups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	return r.open()
+}
+
+// close closes the file; safe to call when it isn't open
+func (r *rotatingFile) close() {
+	if r.file != nil {
+		r.file.Close()
+		r.file = nil
+	}
+}
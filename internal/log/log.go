@@ -0,0 +1,195 @@
+// Package log records what ChatGo does for troubleshooting. Entries are printed to stdout,
+// kept in an in-memory ring buffer shown by the Logs tab in Settings and, when enabled,
+// mirrored to a rotating file.
+package log
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is how important an entry is
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// Levels lists the levels from least to most important
+var Levels = []Level{LevelDebug, LevelInfo, LevelWarn, LevelError}
+
+// String returns the level's name, such as "INFO"
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// Field is a key and value attached to an entry
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Entry is one logged event
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Source  string // Component that logged it, such as "mcp" or "llm"
+	Message string
+	Fields  []Field
+}
+
+// String formats the entry on one line, e.g.
+// `2026-10-16 21:13:33.120 INFO  [mcp] Server initialized server=files tools=12`
+func (e Entry) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s [%s] %s", e.Time.Format("2006-01-02 15:04:05.000"), e.Level, e.Source, e.Message)
+	for _, f := range e.Fields {
+		value := fmt.Sprint(f.Value)
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&b, " %s=%s", f.Key, value)
+	}
+	return b.String()
+}
+
+// Capacity is how many of the latest entries the ring buffer keeps
+const Capacity = 2000
+
+// std is the process-wide log every Logger writes to
+var std = &store{entries: make([]Entry, Capacity), listeners: make(map[int]func(Entry))}
+
+// store keeps the latest entries and passes new ones to listeners and the log file
+type store struct {
+	mu        sync.Mutex
+	entries   []Entry // Ring buffer; next is the oldest once full
+	next      int
+	full      bool
+	file      *rotatingFile // nil unless mirroring to a file
+	listeners map[int]func(Entry)
+	nextID    int
+}
+
+// add records an entry and returns the listeners to pass it to
+func (s *store) add(e Entry) []func(Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[s.next] = e
+	s.next = (s.next + 1) % len(s.entries)
+	s.full = s.full || s.next == 0
+
+	if s.file != nil {
+		if err := s.file.write(e.String() + "\n"); err != nil {
+			fmt.Fprintf(os.Stderr, "[Log] Failed to write log file: %v\n", err)
+		}
+	}
+
+	listeners := make([]func(Entry), 0, len(s.listeners))
+	for _, listener := range s.listeners {
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+// Entries returns the entries in the ring buffer, oldest first
+func Entries() []Entry {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+
+	if !std.full {
+		return append([]Entry(nil), std.entries[:std.next]...)
+	}
+	return append(append([]Entry(nil), std.entries[std.next:]...), std.entries[:std.next]...)
+}
+
+// Subscribe registers a listener called with each new entry, from the goroutine that logged
+// it, and returns a function that removes it
+func Subscribe(listener func(Entry)) (unsubscribe func()) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+
+	id := std.nextID
+	std.nextID++
+	std.listeners[id] = listener
+	return func() {
+		std.mu.Lock()
+		defer std.mu.Unlock()
+		delete(std.listeners, id)
+	}
+}
+
+// Logger logs entries for one component
+type Logger struct {
+	source string
+}
+
+// New returns a logger whose entries name source as their component
+func New(source string) *Logger {
+	return &Logger{source: source}
+}
+
+// Debug logs details only useful when tracking down a problem. keyValues alternate keys and
+// values, as in Debug("Sending request", "model", name).
+func (l *Logger) Debug(msg string, keyValues ...any) {
+	l.log(LevelDebug, msg, keyValues)
+}
+
+// Info logs a normal event
+func (l *Logger) Info(msg string, keyValues ...any) {
+	l.log(LevelInfo, msg, keyValues)
+}
+
+// Warn logs a problem ChatGo worked around
+func (l *Logger) Warn(msg string, keyValues ...any) {
+	l.log(LevelWarn, msg, keyValues)
+}
+
+// Error logs a failure
+func (l *Logger) Error(msg string, keyValues ...any) {
+	l.log(LevelError, msg, keyValues)
+}
+
+// log records an entry, prints it and passes it to the listeners
+func (l *Logger) log(level Level, msg string, keyValues []any) {
+	e := Entry{Time: time.Now(), Level: level, Source: l.source, Message: msg, Fields: fields(keyValues)}
+	fmt.Println(e.String())
+	for _, listener := range std.add(e) {
+		listener(e)
+	}
+}
+
+// fields pairs up alternating keys and values; a value without a key is kept under "!value"
+func fields(keyValues []any) []Field {
+	if len(keyValues) == 0 {
+		return nil
+	}
+	result := make([]Field, 0, (len(keyValues)+1)/2)
+	for i := 0; i < len(keyValues); i += 2 {
+		key, ok := keyValues[i].(string)
+		if !ok || i+1 == len(keyValues) {
+			result = append(result, Field{Key: "!value", Value: keyValues[i]})
+			i--
+			continue
+		}
+		result = append(result, Field{Key: key, Value: keyValues[i+1]})
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+package mcp
+
+import (
+	"chatgo/internal/config"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+)
+
+const (
+	// DefaultHealthCheckInterval is how often initialized servers are pinged
+	DefaultHealthCheckInterval = 30 * time.Second
+
+	// healthCheckTimeout is how long a server may take to answer a ping
+	healthCheckTimeout = 10 * time.Second
+
+	// reconnectBaseDelay and reconnectMaxDelay bound the backoff between reconnection attempts
+	reconnectBaseDelay = 5 * time.Second
+	reconnectMaxDelay  = 5 * time.Minute
+)
+
+// reconnectState tracks reconnection attempts for a server that dropped
+type reconnectState struct {
+	attempts int
+	next     time.Time
+}
+
+// reconnectDelay returns the wait after the given number of failed attempts, doubling from reconnectBaseDelay
+func reconnectDelay(failures int) time.Duration {
+	delay := reconnectBaseDelay
+	for i := 0; i < failures && delay < reconnectMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, reconnectMaxDelay)
+}
+
+// StartHealthChecks pings every initialized server each interval. A server that doesn't
+// answer is closed and marked "disconnected", and is reinitialized with capped backoff
+// while it is enabled in the configuration returned by servers. Calling it again
+// restarts the checker with the new settings.
+func (m *Manager) StartHealthChecks(interval time.Duration, servers func() []config.MCPServer) {
+	m.StopHealthChecks()
+
+	stop := make(chan struct{})
+	m.healthMu.Lock()
+	m.stopHealth = stop
+	m.healthMu.Unlock()
+
+	go func() {
+		healthTicker := time.NewTicker(interval)
+		defer healthTicker.Stop()
+		// Reconnections are due more often than health checks early in the backoff
+		reconnectTicker := time.NewTicker(reconnectBaseDelay)
+		defer reconnectTicker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-healthTicker.C:
+				m.checkHealth()
+			case <-reconnectTicker.C:
+				m.reconnectDropped(servers)
+			}
+		}
+	}()
+}
+
+// StopHealthChecks stops the health checker, if running
+func (m *Manager) StopHealthChecks() {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	if m.stopHealth != nil {
+		close(m.stopHealth)
+		m.stopHealth = nil
+	}
+}
+
+// checkHealth pings all initialized servers concurrently
+func (m *Manager) checkHealth() {
+	m.mu.RLock()
+	clients := make(map[string]*client.Client)
+	for name, status := range m.servers {
+		if status.Status == "initialized" && status.Client != nil {
+			clients[name] = status.Client
+		}
+	}
+	m.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for name, c := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+			defer cancel()
+			if err := c.Ping(ctx); err != nil {
+				m.markDisconnected(name, c, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// markDisconnected closes a server whose health check failed and schedules a reconnection.
+// It does nothing if the server was reinitialized or disconnected in the meantime.
+func (m *Manager) markDisconnected(name string, c *client.Client, cause error) {
+	m.mu.Lock()
+	current, ok := m.servers[name]
+	if !ok || current.Client != c || current.Status != "initialized" {
+		m.mu.Unlock()
+		return
+	}
+	status := &MCPServerStatus{
+		Name:   name,
+		Type:   current.Type,
+		Status: "disconnected",
+		Error:  fmt.Errorf("health check failed: %w", cause),
+	}
+	m.servers[name] = status
+	m.mu.Unlock()
+
+	logger.Warn("Server failed health check", "server", name, "error", cause)
+	_ = c.Close()
+
+	m.healthMu.Lock()
+	m.reconnects[name] = &reconnectState{next: time.Now().Add(reconnectDelay(0))}
+	m.healthMu.Unlock()
+
+	m.notify(name, status)
+}
+
+// reconnectDropped reinitializes dropped servers that are still enabled and due for an attempt
+func (m *Manager) reconnectDropped(servers func() []config.MCPServer) {
+	m.healthMu.Lock()
+	pending := len(m.reconnects)
+	m.healthMu.Unlock()
+	if pending == 0 {
+		return
+	}
+
+	configs := make(map[string]config.MCPServer)
+	for _, server := range servers() {
+		configs[server.Name] = server
+	}
+
+	now := time.Now()
+	var due []config.MCPServer
+	m.healthMu.Lock()
+	for name, state := range m.reconnects {
+		cfg, ok := configs[name]
+		if !ok {
+			// Removed from the configuration
+			delete(m.reconnects, name)
+			continue
+		}
+		if cfg.Enabled && !now.Before(state.next) {
+			due = append(due, cfg)
+		}
+	}
+	m.healthMu.Unlock()
+
+	for _, cfg := range due {
+		logger.Info("Reconnecting server", "server", cfg.Name)
+		if _, err := m.InitializeServer(cfg); err != nil {
+			m.healthMu.Lock()
+			if state, ok := m.reconnects[cfg.Name]; ok {
+				state.attempts++
+				delay := reconnectDelay(state.attempts)
+				state.next = time.Now().Add(delay)
+				logger.Warn("Reconnecting failed", "server", cfg.Name, "attempt", state.attempts, "retry_in", delay)
+			}
+			m.healthMu.Unlock()
+		}
+	}
+}
+
+// forgetReconnect stops automatic reconnection of a server
+func (m *Manager) forgetReconnect(name string) {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	delete(m.reconnects, name)
+}
+
+// ForgetDisabled stops automatic reconnection of the servers that are disabled or missing in
+// servers, so a server disabled or removed in the settings isn't reconnected once it is
+// enabled or added again
+func (m *Manager) ForgetDisabled(servers []config.MCPServer) {
+	enabled := make(map[string]bool)
+	for _, server := range servers {
+		enabled[server.Name] = server.Enabled
+	}
+
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	for name := range m.reconnects {
+		if !enabled[name] {
+			delete(m.reconnects, name)
+		}
+	}
+}
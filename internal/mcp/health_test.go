@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"chatgo/internal/config"
+	"chatgo/internal/mcp/mcptest"
+)
+
+// dropServer marks an initialized server as having failed its health check
+func dropServer(t *testing.T, m *Manager, name string) {
+	t.Helper()
+	status, ok := m.GetServerStatus(name)
+	if !ok || status.Client == nil {
+		t.Fatalf("server %s isn't connected", name)
+	}
+	m.markDisconnected(name, status.Client, errors.New("dropped"))
+	if _, pending := m.reconnects[name]; !pending {
+		t.Fatalf("server %s wasn't scheduled for reconnection", name)
+	}
+}
+
+func TestForgetDisabled(t *testing.T) {
+	srv := mcptest.NewServer()
+	defer srv.Close()
+
+	m := NewManager()
+	defer m.DisconnectAll()
+	servers := []config.MCPServer{srv.Config("kept"), srv.Config("disabled"), srv.Config("removed")}
+	for _, server := range servers {
+		if _, err := m.InitializeServer(server); err != nil {
+			t.Fatalf("InitializeServer %s: %v", server.Name, err)
+		}
+		dropServer(t, m, server.Name)
+	}
+
+	// In the settings, one server is disabled and another removed
+	servers[1].Enabled = false
+	m.ForgetDisabled(servers[:2])
+
+	if _, pending := m.reconnects["kept"]; !pending {
+		t.Error("the enabled server is no longer reconnected")
+	}
+	for _, name := range []string{"disabled", "removed"} {
+		if _, pending := m.reconnects[name]; pending {
+			t.Errorf("server %s is still reconnected", name)
+		}
+	}
+}
+
+// makeReconnectDue lets the next reconnectDropped try a dropped server without waiting out its backoff
+func makeReconnectDue(t *testing.T, m *Manager, name string) {
+	t.Helper()
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	state, pending := m.reconnects[name]
+	if !pending {
+		t.Fatalf("server %s isn't scheduled for reconnection", name)
+	}
+	state.next = time.Now()
+}
+
+func TestReconnectAfterServerReturns(t *testing.T) {
+	m, srv := connect(t)
+	servers := func() []config.MCPServer { return []config.MCPServer{srv.Config("test")} }
+
+	statuses := make(chan string, 10)
+	unsubscribe := m.Subscribe(func(name string, status *MCPServerStatus) { statuses <- status.Status })
+	defer unsubscribe()
+
+	// The running checker notices the server dropping
+	m.StartHealthChecks(20*time.Millisecond, servers)
+	srv.SetDown(true)
+	select {
+	case status := <-statuses:
+		if status != "disconnected" {
+			t.Fatalf("status after the server dropped = %q, want disconnected", status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the health checker didn't notice the server dropping")
+	}
+	m.StopHealthChecks()
+
+	if _, err := m.CallTool(context.Background(), "test", mcptest.EchoTool, map[string]any{"text": "hello"}); !errors.Is(err, ErrServerNotInitialized) {
+		t.Errorf("CallTool on a dropped server = %v, want ErrServerNotInitialized", err)
+	}
+
+	// While the server is still down, attempts back off
+	makeReconnectDue(t, m, "test")
+	m.reconnectDropped(servers)
+	m.healthMu.Lock()
+	state := *m.reconnects["test"]
+	m.healthMu.Unlock()
+	if state.attempts != 1 || time.Until(state.next) <= reconnectBaseDelay {
+		t.Errorf("after a failed attempt: %d attempts, next in %s; want 1 attempt and more than %s",
+			state.attempts, time.Until(state.next).Round(time.Second), reconnectBaseDelay)
+	}
+
+	// Once it is back, the next attempt reconnects it
+	srv.SetDown(false)
+	makeReconnectDue(t, m, "test")
+	m.reconnectDropped(servers)
+
+	status, ok := m.GetServerStatus("test")
+	if !ok || status.Status != "initialized" {
+		t.Fatalf("status after the server returned = %v, want initialized", status)
+	}
+	if _, pending := m.reconnects["test"]; pending {
+		t.Error("a reconnected server is still scheduled for reconnection")
+	}
+	result, err := m.CallTool(context.Background(), "test", mcptest.EchoTool, map[string]any{"text": "hello"})
+	if err != nil {
+		t.Fatalf("CallTool after reconnecting: %v", err)
+	}
+	if got := resultText(t, result); got != "hello" {
+		t.Errorf("echo after reconnecting = %q, want %q", got, "hello")
+	}
+}
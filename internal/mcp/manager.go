@@ -3,22 +3,44 @@ package mcp
 
 import (
 	"chatgo/internal/config"
+	"chatgo/internal/log"
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"net/http"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// logger logs server connections and tool calls
+var logger = log.New("mcp")
+
+// sortedKeys returns the keys of m in order, for logging settings without their values
+func sortedKeys(m map[string]string) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// maxParallelInitializations is how many servers InitializeAll and InitializeAllAsync start at
+// once. Stdio servers are often launched through npx or uvx, which slow each other down when
+// many start together.
+const maxParallelInitializations = 4
+
 // MCPServerStatus represents the initialization status of an MCP server
 type MCPServerStatus struct {
-	Name     string
-	Type     config.MCPServerType
-	Status   string // "initialized", "error", "disconnected"
-	Error    error
-	Tools    []MCPTool
-	Client   *client.Client
+	Name   string
+	Type   config.MCPServerType
+	Status string // "initializing", "initialized", "error", "disconnected"
+	Error  error
+	Tools  []MCPTool
+	Client *client.Client
+
+	timeout time.Duration // Bounds each tool call made through ToolClient
 }
 
 // MCPTool represents a tool from an MCP server
@@ -28,31 +50,117 @@ type MCPTool struct {
 	InputSchema map[string]interface{}
 }
 
+// StatusListener is called from a background goroutine whenever a server's status changes
+type StatusListener func(name string, status *MCPServerStatus)
+
 // Manager manages MCP client connections and tools
 type Manager struct {
-	servers map[string]*MCPServerStatus
-	mu      sync.RWMutex
+	servers    map[string]*MCPServerStatus
+	httpClient *http.Client // Used by SSE and StreamableHTTP servers; nil uses the library default
+	mu         sync.RWMutex
+
+	listenersMu    sync.Mutex
+	listeners      map[int]StatusListener
+	nextListenerID int
+
+	healthMu   sync.Mutex
+	stopHealth chan struct{}              // Closed to stop the health checker; nil when not running
+	reconnects map[string]*reconnectState // Servers that dropped and should be reconnected
 }
 
 // NewManager creates a new MCP manager
 func NewManager() *Manager {
 	return &Manager{
-		servers: make(map[string]*MCPServerStatus),
+		servers:    make(map[string]*MCPServerStatus),
+		listeners:  make(map[int]StatusListener),
+		reconnects: make(map[string]*reconnectState),
+	}
+}
+
+// Subscribe registers a listener for status changes and returns a function that removes it
+func (m *Manager) Subscribe(listener StatusListener) (unsubscribe func()) {
+	m.listenersMu.Lock()
+	defer m.listenersMu.Unlock()
+
+	id := m.nextListenerID
+	m.nextListenerID++
+	m.listeners[id] = listener
+
+	return func() {
+		m.listenersMu.Lock()
+		defer m.listenersMu.Unlock()
+		delete(m.listeners, id)
 	}
 }
 
+// notify calls every listener with a server's new status. It must be called without m.mu held.
+func (m *Manager) notify(name string, status *MCPServerStatus) {
+	m.listenersMu.Lock()
+	listeners := make([]StatusListener, 0, len(m.listeners))
+	for _, listener := range m.listeners {
+		listeners = append(listeners, listener)
+	}
+	m.listenersMu.Unlock()
+
+	for _, listener := range listeners {
+		listener(name, status)
+	}
+}
+
+// SetHTTPClient sets the HTTP client used for SSE and StreamableHTTP servers initialized afterwards
+func (m *Manager) SetHTTPClient(httpClient *http.Client) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.httpClient = httpClient
+}
+
+// getHTTPClient returns the HTTP client for new SSE and StreamableHTTP connections
+func (m *Manager) getHTTPClient() *http.Client {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.httpClient
+}
+
 // InitializeServer initializes a single MCP server connection
 func (m *Manager) InitializeServer(cfg config.MCPServer) (*MCPServerStatus, error) {
-	fmt.Printf("[MCP] Initializing server '%s' (type: %s)\n", cfg.Name, cfg.Type)
+	if existing, ok, err := m.beginInitialize(cfg); !ok {
+		return existing, err
+	}
+	return m.initializeServer(cfg)
+}
 
-	// First check if already initialized (with read lock to avoid blocking)
-	m.mu.RLock()
-	if existing, ok := m.servers[cfg.Name]; ok && existing.Status == "initialized" {
-		m.mu.RUnlock()
-		fmt.Printf("[MCP] Server '%s' already initialized\n", cfg.Name)
-		return existing, nil
+// beginInitialize marks a server as initializing. It reports false, with the current
+// status, when the server is already initialized or another initialization is in progress.
+func (m *Manager) beginInitialize(cfg config.MCPServer) (*MCPServerStatus, bool, error) {
+	m.mu.Lock()
+	if existing, ok := m.servers[cfg.Name]; ok {
+		switch existing.Status {
+		case "initialized":
+			m.mu.Unlock()
+			logger.Debug("Server already initialized", "server", cfg.Name)
+			return existing, false, nil
+		case "initializing":
+			m.mu.Unlock()
+			return existing, false, fmt.Errorf("server '%s' is already initializing", cfg.Name)
+		}
 	}
-	m.mu.RUnlock()
+
+	status := &MCPServerStatus{
+		Name:   cfg.Name,
+		Type:   cfg.Type,
+		Status: "initializing",
+	}
+	m.servers[cfg.Name] = status
+	m.mu.Unlock()
+
+	m.notify(cfg.Name, status)
+	return nil, true, nil
+}
+
+// initializeServer connects to a server marked as initializing by beginInitialize
+func (m *Manager) initializeServer(cfg config.MCPServer) (*MCPServerStatus, error) {
+	logger.Info("Initializing server", "server", cfg.Name, "type", cfg.Type)
+	started := time.Now()
 
 	status := &MCPServerStatus{
 		Name:   cfg.Name,
@@ -66,15 +174,16 @@ func (m *Manager) InitializeServer(cfg config.MCPServer) (*MCPServerStatus, erro
 	// Create client (outside of lock to avoid blocking other operations)
 	switch cfg.Type {
 	case config.MCPServerTypeStdIO:
-		argsStr := ""
-		if len(cfg.Args) > 0 {
-			argsStr = " " + fmt.Sprintf("%v", cfg.Args)
-		}
-		fmt.Printf("[MCP] Type: StdIO\n")
-		fmt.Printf("[MCP]   Command: %s%s\n", cfg.Command, argsStr)
-		if len(cfg.Env) > 0 {
-			fmt.Printf("[MCP]   Env: %v\n", cfg.Env)
+		// Arguments and the working directory may use ~ and environment variables, as in a shell
+		args := make([]string, len(cfg.Args))
+		for i, arg := range cfg.Args {
+			args[i] = config.ExpandPath(arg)
 		}
+		dir := config.ExpandPath(cfg.WorkingDir)
+
+		// Only the names of environment variables are logged, as their values are often keys
+		logger.Debug("Starting stdio server", "server", cfg.Name, "command", cfg.Command, "args", args,
+			"dir", dir, "env", sortedKeys(cfg.Env))
 
 		// Convert env map to []string
 		env := []string{}
@@ -82,109 +191,93 @@ func (m *Manager) InitializeServer(cfg config.MCPServer) (*MCPServerStatus, erro
 			env = append(env, fmt.Sprintf("%s=%s", k, v))
 		}
 
-		// Initialize stdio client
-		// Note: NewStdioMCPClient automatically starts the connection internally
-		mcpClient, err = client.NewStdioMCPClient(cfg.Command, env, cfg.Args...)
-		if err != nil {
-			fmt.Printf("[MCP] Failed to create stdio client: %v\n", err)
-			status.Status = "error"
-			status.Error = fmt.Errorf("failed to create stdio client: %w", err)
-			m.setStatus(cfg.Name, status)
-			return status, status.Error
-		}
-		fmt.Printf("[MCP] Stdio client created successfully\n")
+		// Initialize stdio client; the server process is started with the client below
+		mcpClient = client.NewClient(newStdioTransport(cfg.Command, env, args, dir))
 
 	case config.MCPServerTypeSSE:
-		fmt.Printf("[MCP] Type: SSE\n")
-		fmt.Printf("[MCP]   URL: %s\n", cfg.URL)
-		if len(cfg.Headers) > 0 {
-			fmt.Printf("[MCP]   Headers: %v\n", cfg.Headers)
-		}
+		logger.Debug("Connecting to SSE server", "server", cfg.Name, "url", cfg.URL, "headers", sortedKeys(cfg.Headers))
 
 		// Initialize SSE client
-		mcpClient, err = client.NewSSEMCPClient(cfg.URL)
+		sseOptions := []transport.ClientOption{transport.WithHeaders(cfg.Headers)}
+		if httpClient := m.getHTTPClient(); httpClient != nil {
+			sseOptions = append(sseOptions, transport.WithHTTPClient(httpClient))
+		}
+		mcpClient, err = client.NewSSEMCPClient(cfg.URL, sseOptions...)
 		if err != nil {
-			fmt.Printf("[MCP] Failed to create SSE client: %v\n", err)
 			status.Status = "error"
 			status.Error = fmt.Errorf("failed to create SSE client: %w", err)
+			logger.Error("Server initialization failed", "server", cfg.Name, "error", status.Error)
 			m.setStatus(cfg.Name, status)
 			return status, status.Error
 		}
-		fmt.Printf("[MCP] SSE client created successfully\n")
 
 	case config.MCPServerTypeStreamableHTTP:
-		fmt.Printf("[MCP] Type: StreamableHTTP\n")
-		fmt.Printf("[MCP]   URL: %s\n", cfg.URL)
-		if len(cfg.Headers) > 0 {
-			fmt.Printf("[MCP]   Headers: %v\n", cfg.Headers)
-		}
-		if cfg.TimeoutSeconds > 0 {
-			fmt.Printf("[MCP]   Timeout: %d seconds\n", cfg.TimeoutSeconds)
-		}
+		logger.Debug("Connecting to streamable HTTP server", "server", cfg.Name, "url", cfg.URL,
+			"headers", sortedKeys(cfg.Headers), "timeout", ServerTimeout(cfg))
 
 		// Initialize streamable HTTP client
-		mcpClient, err = client.NewStreamableHttpClient(cfg.URL)
+		httpOptions := []transport.StreamableHTTPCOption{transport.WithHTTPHeaders(cfg.Headers)}
+		if httpClient := m.getHTTPClient(); httpClient != nil {
+			httpOptions = append(httpOptions, transport.WithHTTPBasicClient(httpClient))
+		}
+		mcpClient, err = client.NewStreamableHttpClient(cfg.URL, httpOptions...)
 		if err != nil {
-			fmt.Printf("[MCP] Failed to create HTTP stream client: %v\n", err)
 			status.Status = "error"
 			status.Error = fmt.Errorf("failed to create HTTP stream client: %w", err)
+			logger.Error("Server initialization failed", "server", cfg.Name, "error", status.Error)
 			m.setStatus(cfg.Name, status)
 			return status, status.Error
 		}
-		fmt.Printf("[MCP] StreamableHTTP client created successfully\n")
 
 	default:
-		fmt.Printf("[MCP] Unsupported MCP server type: %s\n", cfg.Type)
 		status.Status = "error"
 		status.Error = fmt.Errorf("unsupported MCP server type: %s", cfg.Type)
+		logger.Error("Server initialization failed", "server", cfg.Name, "error", status.Error)
 		m.setStatus(cfg.Name, status)
 		return status, status.Error
 	}
 
 	status.Client = mcpClient
+	status.timeout = ServerTimeout(cfg)
 
-	// Start the client (only for SSE and StreamableHTTP types)
-	// Note: Stdio client is already started by NewStdioMCPClient
-	ctx := context.Background()
-	if cfg.Type != config.MCPServerTypeStdIO {
-		fmt.Printf("[MCP] Starting client connection...\n")
-		err = mcpClient.Start(ctx)
-		if err != nil {
-			fmt.Printf("[MCP] Failed to start client: %v\n", err)
-			status.Status = "error"
-			status.Error = fmt.Errorf("failed to start MCP client: %w", err)
-			m.setStatus(cfg.Name, status)
-			return status, status.Error
+	// The whole handshake must finish within the server's timeout, so a server that
+	// accepts the connection but never answers doesn't leave it initializing forever
+	ctx, cancel := context.WithTimeout(context.Background(), status.timeout)
+	defer cancel()
+
+	// fail records an initialization error, reporting a timeout in place of the cancellation it caused
+	fail := func(err error) (*MCPServerStatus, error) {
+		if timedOut(ctx, err) {
+			err = fmt.Errorf("initialization timed out after %s", status.timeout)
 		}
+		status.Status = "error"
+		status.Error = err
+		status.Client = nil
+		logger.Error("Server initialization failed", "server", cfg.Name, "error", err, "duration", time.Since(started))
+		m.setStatus(cfg.Name, status)
+		mcpClient.Close()
+		return status, status.Error
+	}
+
+	// Start the client, which starts the server process of stdio servers
+	if err := startClient(ctx, mcpClient); err != nil {
+		return fail(fmt.Errorf("failed to start MCP client: %w", err))
 	}
 
-	fmt.Printf("[MCP] Initializing MCP protocol handshake...\n")
 	// Initialize the connection (outside of lock - this is a slow operation)
 	initReq := mcp.InitializeRequest{}
 	_, err = mcpClient.Initialize(ctx, initReq)
 	if err != nil {
-		fmt.Printf("[MCP] Failed to initialize MCP connection: %v\n", err)
-		status.Status = "error"
-		status.Error = fmt.Errorf("failed to initialize MCP connection: %w", err)
-		m.setStatus(cfg.Name, status)
-		mcpClient.Close()
-		return status, status.Error
+		return fail(fmt.Errorf("failed to initialize MCP connection: %w", err))
 	}
-	fmt.Printf("[MCP] MCP protocol handshake successful\n")
+	logger.Debug("Handshake complete", "server", cfg.Name)
 
 	// Get tools from the server (outside of lock - this is a slow operation)
-	fmt.Printf("[MCP] Requesting tools list...\n")
 	toolsReq := mcp.ListToolsRequest{}
 	toolsResult, err := mcpClient.ListTools(ctx, toolsReq)
 	if err != nil {
-		fmt.Printf("[MCP] Failed to get tools: %v\n", err)
-		status.Status = "error"
-		status.Error = fmt.Errorf("failed to get tools: %w", err)
-		m.setStatus(cfg.Name, status)
-		mcpClient.Close()
-		return status, status.Error
+		return fail(fmt.Errorf("failed to get tools: %w", err))
 	}
-	fmt.Printf("[MCP] Received %d tools\n", len(toolsResult.Tools))
 
 	// Parse tools
 	status.Tools = make([]MCPTool, 0, len(toolsResult.Tools))
@@ -195,7 +288,7 @@ func (m *Manager) InitializeServer(cfg config.MCPServer) (*MCPServerStatus, erro
 			InputSchema: map[string]interface{}{"inputSchema": tool.InputSchema},
 		}
 		status.Tools = append(status.Tools, mcpTool)
-		fmt.Printf("[MCP]   - %s: %s\n", tool.Name, tool.Description)
+		logger.Debug("Tool listed", "server", cfg.Name, "tool", tool.Name, "description", tool.Description)
 	}
 
 	status.Status = "initialized"
@@ -204,40 +297,86 @@ func (m *Manager) InitializeServer(cfg config.MCPServer) (*MCPServerStatus, erro
 	// Store the final status (with minimal time holding the lock)
 	m.setStatus(cfg.Name, status)
 
-	fmt.Printf("[MCP] Server '%s' initialization complete\n", cfg.Name)
+	logger.Info("Server initialized", "server", cfg.Name, "tools", len(status.Tools), "duration", time.Since(started))
 	return status, nil
 }
 
 // setStatus stores the status of a server (helper to reduce lock holding time)
 func (m *Manager) setStatus(name string, status *MCPServerStatus) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 	m.servers[name] = status
+	m.mu.Unlock()
+
+	if status.Status == "initialized" {
+		m.forgetReconnect(name)
+	}
+	m.notify(name, status)
 }
 
-// InitializeAll initializes all enabled MCP servers
+// InitializeAll initializes all enabled MCP servers concurrently and waits for them to finish.
+// The result holds each server's status, including servers that failed to initialize.
 func (m *Manager) InitializeAll(servers []config.MCPServer) map[string]*MCPServerStatus {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	results := make(map[string]*MCPServerStatus)
+	var resultsMu sync.Mutex
+	var wg sync.WaitGroup
+	failed := 0
 
+	for _, server := range servers {
+		if server.Enabled {
+			wg.Add(1)
+		}
+	}
+	m.initializeEnabled(servers, func(name string, status *MCPServerStatus, err error) {
+		defer wg.Done()
+		resultsMu.Lock()
+		results[name] = status
+		if err != nil {
+			failed++
+		}
+		resultsMu.Unlock()
+	})
+	wg.Wait()
+
+	logger.Info("Servers initialized", "succeeded", len(results)-failed, "failed", failed)
+	return results
+}
+
+// InitializeAllAsync initializes all enabled MCP servers concurrently and returns immediately.
+// The servers are marked "initializing" before it returns, so status lookups can show progress.
+// callback, if non-nil, is called from a background goroutine as each server finishes.
+func (m *Manager) InitializeAllAsync(servers []config.MCPServer, callback func(name string, status *MCPServerStatus, err error)) {
+	m.initializeEnabled(servers, func(name string, status *MCPServerStatus, err error) {
+		if callback != nil {
+			callback(name, status, err)
+		}
+	})
+}
+
+// initializeEnabled marks the enabled servers as initializing and initializes them in the
+// background, at most maxParallelInitializations at a time. done is called from a background
+// goroutine as each server finishes, including servers already initialized or initializing.
+func (m *Manager) initializeEnabled(servers []config.MCPServer, done func(name string, status *MCPServerStatus, err error)) {
+	slots := make(chan struct{}, maxParallelInitializations)
 	for _, server := range servers {
 		// Skip disabled servers
 		if !server.Enabled {
 			continue
 		}
 
-		status, err := m.InitializeServer(server)
-		if err != nil {
-			// Keep the error status but add to results
-			results[server.Name] = status
-		} else {
-			results[server.Name] = status
+		existing, ok, err := m.beginInitialize(server)
+		if !ok {
+			go done(server.Name, existing, err)
+			continue
 		}
-	}
 
-	return results
+		// initializeServer locks m.mu only around status updates, so servers launch in parallel
+		go func(srv config.MCPServer) {
+			slots <- struct{}{}
+			status, err := m.initializeServer(srv)
+			<-slots
+			done(srv.Name, status, err)
+		}(server)
+	}
 }
 
 // GetServerStatus returns the status of a specific server
@@ -273,6 +412,43 @@ func (m *Manager) GetServerClient(name string) (*client.Client, bool) {
 	return nil, false
 }
 
+// ToolClient returns a server's client with each tool call bounded by the server's timeout
+func (m *Manager) ToolClient(name string) (client.MCPClient, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if status, ok := m.servers[name]; ok && status.Status == "initialized" {
+		return &timeoutClient{Client: status.Client, timeout: status.timeout}, true
+	}
+	return nil, false
+}
+
+// ErrServerNotInitialized is returned for tool calls to a server that isn't connected
+var ErrServerNotInitialized = errors.New("MCP server is not initialized")
+
+// CallTool calls a tool of an initialized server with the given arguments, within the server's
+// timeout. It fails with ErrServerNotInitialized when the server is not connected. A tool that
+// ran but failed is reported in the result's IsError and content, not as an error.
+func (m *Manager) CallTool(ctx context.Context, serverName, toolName string, args map[string]any) (*mcp.CallToolResult, error) {
+	toolClient, ok := m.ToolClient(serverName)
+	if !ok {
+		state := "not configured"
+		if status, found := m.GetServerStatus(serverName); found {
+			state = status.Status
+		}
+		return nil, fmt.Errorf("%w: '%s' is %s", ErrServerNotInitialized, serverName, state)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = toolName
+	request.Params.Arguments = args
+	result, err := toolClient.CallTool(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call tool %s of MCP server '%s': %w", toolName, serverName, err)
+	}
+	return result, nil
+}
+
 // GetServerTools returns the tools for a specific server
 func (m *Manager) GetServerTools(name string) ([]MCPTool, bool) {
 	m.mu.RLock()
@@ -298,38 +474,62 @@ func (m *Manager) GetAllTools() map[string][]MCPTool {
 	return result
 }
 
-// DisconnectServer disconnects a specific server
+// DisconnectServer disconnects a specific server. A disconnected server isn't reconnected automatically.
 func (m *Manager) DisconnectServer(name string) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.forgetReconnect(name)
 
-	if status, ok := m.servers[name]; ok {
-		if status.Client != nil {
-			err := status.Client.Close()
-			status.Status = "disconnected"
-			status.Client = nil
-			status.Tools = nil
-			status.Error = fmt.Errorf("disconnected")
-			return err
-		}
+	m.mu.Lock()
+	current, ok := m.servers[name]
+	if !ok || current.Client == nil {
+		m.mu.Unlock()
+		return fmt.Errorf("server not found")
 	}
-	return fmt.Errorf("server not found")
+	// Statuses handed out stay as they were; the disconnected server gets a new one
+	status := &MCPServerStatus{
+		Name:   name,
+		Type:   current.Type,
+		Status: "disconnected",
+		Error:  fmt.Errorf("disconnected"),
+	}
+	m.servers[name] = status
+	m.mu.Unlock()
+
+	// Closing may wait for a stdio server to exit, so it happens without m.mu held
+	err := current.Client.Close()
+	m.notify(name, status)
+	return err
 }
 
 // DisconnectAll disconnects all servers
 func (m *Manager) DisconnectAll() {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.healthMu.Lock()
+	m.reconnects = make(map[string]*reconnectState)
+	m.healthMu.Unlock()
 
-	for _, status := range m.servers {
-		if status.Client != nil {
-			_ = status.Client.Close()
-			status.Status = "disconnected"
-			status.Client = nil
-			status.Tools = nil
-			status.Error = fmt.Errorf("disconnected")
+	m.mu.Lock()
+	disconnected := make(map[string]*MCPServerStatus)
+	var clients []*client.Client
+	for name, current := range m.servers {
+		if current.Client != nil {
+			clients = append(clients, current.Client)
+			status := &MCPServerStatus{
+				Name:   name,
+				Type:   current.Type,
+				Status: "disconnected",
+				Error:  fmt.Errorf("disconnected"),
+			}
+			m.servers[name] = status
+			disconnected[name] = status
 		}
 	}
+	m.mu.Unlock()
+
+	for _, c := range clients {
+		_ = c.Close()
+	}
+	for name, status := range disconnected {
+		m.notify(name, status)
+	}
 }
 
 // ReinitializeServer reinitializes a server (disconnects first if needed)
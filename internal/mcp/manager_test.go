@@ -0,0 +1,271 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"chatgo/internal/config"
+	"chatgo/internal/mcp/mcptest"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// connect starts a scripted server and initializes it in a new manager under the name "test"
+func connect(t *testing.T) (*Manager, *mcptest.Server) {
+	t.Helper()
+	srv := mcptest.NewServer()
+	t.Cleanup(srv.Close)
+
+	m := NewManager()
+	t.Cleanup(m.DisconnectAll)
+	status, err := m.InitializeServer(srv.Config("test"))
+	if err != nil {
+		t.Fatalf("InitializeServer: %v", err)
+	}
+	if status.Status != "initialized" {
+		t.Fatalf("status = %q, want initialized", status.Status)
+	}
+	return m, srv
+}
+
+// resultText returns the text content of a tool result
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	if len(result.Content) != 1 {
+		t.Fatalf("result has %d contents, want 1", len(result.Content))
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatalf("result content is %T, want text", result.Content[0])
+	}
+	return text.Text
+}
+
+func TestConnectListsTools(t *testing.T) {
+	m, _ := connect(t)
+
+	tools, ok := m.GetServerTools("test")
+	if !ok {
+		t.Fatal("GetServerTools found no initialized server")
+	}
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	want := []string{mcptest.AddTool, mcptest.EchoTool, mcptest.FailTool}
+	if !slices.Equal(names, want) {
+		t.Errorf("tools = %v, want %v", names, want)
+	}
+	if _, ok := m.GetServerClient("test"); !ok {
+		t.Error("GetServerClient found no client")
+	}
+}
+
+func TestCallTool(t *testing.T) {
+	m, srv := connect(t)
+	ctx := context.Background()
+
+	result, err := m.CallTool(ctx, "test", mcptest.EchoTool, map[string]any{"text": "hello"})
+	if err != nil {
+		t.Fatalf("CallTool echo: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("echo failed: %s", resultText(t, result))
+	}
+	if got := resultText(t, result); got != "hello" {
+		t.Errorf("echo = %q, want %q", got, "hello")
+	}
+
+	result, err = m.CallTool(ctx, "test", mcptest.AddTool, map[string]any{"a": 2, "b": 3})
+	if err != nil {
+		t.Fatalf("CallTool add: %v", err)
+	}
+	if got := resultText(t, result); got != "5" {
+		t.Errorf("add = %q, want %q", got, "5")
+	}
+
+	if calls := srv.Calls(); calls != 2 {
+		t.Errorf("server answered %d calls, want 2", calls)
+	}
+}
+
+func TestCallToolError(t *testing.T) {
+	m, _ := connect(t)
+
+	// A tool that ran and failed is reported in the result, not as an error
+	result, err := m.CallTool(context.Background(), "test", mcptest.FailTool, nil)
+	if err != nil {
+		t.Fatalf("CallTool fail: %v", err)
+	}
+	if !result.IsError {
+		t.Error("fail tool result isn't marked as an error")
+	}
+	if got := resultText(t, result); got != "scripted failure" {
+		t.Errorf("fail = %q, want %q", got, "scripted failure")
+	}
+}
+
+func TestInitializeSendsHeaders(t *testing.T) {
+	for _, newServer := range []func() *mcptest.Server{mcptest.NewServer, mcptest.NewSSEServer} {
+		srv := newServer()
+		defer srv.Close()
+
+		cfg := srv.Config("test")
+		cfg.Headers = map[string]string{"Authorization": "Bearer secret", "X-Team": "chatgo"}
+		m := NewManager()
+		defer m.DisconnectAll()
+		if _, err := m.InitializeServer(cfg); err != nil {
+			t.Fatalf("InitializeServer over %s: %v", cfg.Type, err)
+		}
+		if _, err := m.CallTool(context.Background(), "test", mcptest.EchoTool, map[string]any{"text": "hello"}); err != nil {
+			t.Fatalf("CallTool over %s: %v", cfg.Type, err)
+		}
+
+		header := srv.Header()
+		for key, want := range cfg.Headers {
+			if got := header.Get(key); got != want {
+				t.Errorf("%s server got %s %q, want %q", cfg.Type, key, got, want)
+			}
+		}
+	}
+}
+
+func TestCallToolServerDown(t *testing.T) {
+	m, srv := connect(t)
+	srv.SetDown(true)
+
+	_, err := m.CallTool(context.Background(), "test", mcptest.EchoTool, map[string]any{"text": "hello"})
+	if err == nil {
+		t.Fatal("CallTool succeeded against a server that is down")
+	}
+	if errors.Is(err, ErrServerNotInitialized) {
+		t.Errorf("CallTool error = %v, want a transport error, as the server is still initialized", err)
+	}
+}
+
+func TestInitializeTimeout(t *testing.T) {
+	// A server that accepts connections but never answers
+	release := make(chan struct{})
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer hanging.Close()
+	defer close(release)
+
+	m := NewManager()
+	cfg := config.MCPServer{
+		Name:           "hanging",
+		Type:           config.MCPServerTypeStreamableHTTP,
+		Enabled:        true,
+		URL:            hanging.URL + "/mcp",
+		TimeoutSeconds: 1,
+	}
+
+	started := time.Now()
+	status, err := m.InitializeServer(cfg)
+	if err == nil {
+		t.Fatal("InitializeServer succeeded against a server that never answers")
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("InitializeServer took %s, want about the 1s timeout", elapsed)
+	}
+	if status.Status != "error" || status.Client != nil {
+		t.Errorf("status = %q with client %v, want error without a client", status.Status, status.Client)
+	}
+}
+
+func TestDisconnectServer(t *testing.T) {
+	m, _ := connect(t)
+	before, _ := m.GetServerStatus("test")
+
+	var notified []string
+	unsubscribe := m.Subscribe(func(name string, status *MCPServerStatus) {
+		notified = append(notified, status.Status)
+	})
+	defer unsubscribe()
+
+	if err := m.DisconnectServer("test"); err != nil {
+		t.Fatalf("DisconnectServer: %v", err)
+	}
+	status, ok := m.GetServerStatus("test")
+	if !ok || status.Status != "disconnected" {
+		t.Errorf("status after disconnecting = %v, want disconnected", status)
+	}
+	if before.Status != "initialized" || before.Client == nil {
+		t.Errorf("status handed out before disconnecting was changed to %q", before.Status)
+	}
+	if !slices.Equal(notified, []string{"disconnected"}) {
+		t.Errorf("listeners were notified of %v, want [disconnected]", notified)
+	}
+	if _, ok := m.GetServerTools("test"); ok {
+		t.Error("a disconnected server still lists tools")
+	}
+
+	_, err := m.CallTool(context.Background(), "test", mcptest.EchoTool, map[string]any{"text": "hello"})
+	if !errors.Is(err, ErrServerNotInitialized) {
+		t.Errorf("CallTool after disconnecting = %v, want ErrServerNotInitialized", err)
+	}
+	if err := m.DisconnectServer("test"); err == nil {
+		t.Error("disconnecting twice succeeded")
+	}
+}
+
+func TestInitializeAllDoesNotDeadlock(t *testing.T) {
+	first, second := mcptest.NewServer(), mcptest.NewServer()
+	defer first.Close()
+	defer second.Close()
+	disabled := first.Config("disabled")
+	disabled.Enabled = false
+
+	m := NewManager()
+	defer m.DisconnectAll()
+
+	done := make(chan map[string]*MCPServerStatus, 1)
+	go func() {
+		done <- m.InitializeAll([]config.MCPServer{first.Config("first"), second.Config("second"), disabled})
+	}()
+
+	select {
+	case results := <-done:
+		if len(results) != 2 {
+			t.Errorf("InitializeAll returned %d statuses, want 2 for the enabled servers", len(results))
+		}
+		for _, name := range []string{"first", "second"} {
+			if status := results[name]; status == nil || status.Status != "initialized" {
+				t.Errorf("status of %s = %v, want initialized", name, status)
+			}
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("InitializeAll didn't return; it deadlocked or hung")
+	}
+}
+
+func TestCallToolUninitializedServer(t *testing.T) {
+	m := NewManager()
+
+	// Servers that aren't connected, with a client that would hang if it were used
+	silent := newSilentTransport(false)
+	for _, state := range []string{"initializing", "error", "disconnected"} {
+		m.servers[state] = &MCPServerStatus{Name: state, Status: state, Client: client.NewClient(silent), timeout: time.Minute}
+	}
+
+	for _, name := range []string{"initializing", "error", "disconnected", "unconfigured"} {
+		_, err := m.CallTool(context.Background(), name, mcptest.EchoTool, nil)
+		if !errors.Is(err, ErrServerNotInitialized) {
+			t.Errorf("CallTool on %s server = %v, want ErrServerNotInitialized", name, err)
+		}
+	}
+	if n := silent.requests.Load(); n != 0 {
+		t.Errorf("%d requests were sent to servers that aren't initialized", n)
+	}
+}
@@ -0,0 +1,138 @@
+// Package mcptest runs a scripted MCP server in-process, for exercising the MCP client path
+// without network access or npx. Its tools answer deterministically.
+package mcptest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"chatgo/internal/config"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Names of the tools the server offers
+const (
+	EchoTool = "echo" // Returns its "text" argument unchanged
+	AddTool  = "add"  // Returns the sum of its "a" and "b" arguments
+	FailTool = "fail" // Always returns a tool error
+)
+
+// Server is a scripted MCP server reached over streamable HTTP or SSE on a local port
+type Server struct {
+	http       *httptest.Server
+	serverType config.MCPServerType
+	endpoint   string       // Path of the MCP endpoint
+	down       atomic.Bool  // Set while the server refuses requests, as a dropped server would
+	calls      atomic.Int64 // Tool calls answered
+
+	mu      sync.Mutex
+	headers http.Header // Headers of the last request
+}
+
+// NewServer starts a scripted MCP server reached over streamable HTTP; stop it with Close
+func NewServer() *Server {
+	return newServer(config.MCPServerTypeStreamableHTTP, "/mcp", func(mcpServer *server.MCPServer) http.Handler {
+		return server.NewStreamableHTTPServer(mcpServer)
+	})
+}
+
+// NewSSEServer starts a scripted MCP server reached over SSE; stop it with Close
+func NewSSEServer() *Server {
+	return newServer(config.MCPServerTypeSSE, "/sse", func(mcpServer *server.MCPServer) http.Handler {
+		return server.NewSSEServer(mcpServer)
+	})
+}
+
+// newServer starts a scripted MCP server served by the handler transport returns
+func newServer(serverType config.MCPServerType, endpoint string, transport func(*server.MCPServer) http.Handler) *Server {
+	mcpServer := server.NewMCPServer("chatgo-mcptest", "1.0.0", server.WithToolCapabilities(false))
+	s := &Server{serverType: serverType, endpoint: endpoint}
+
+	mcpServer.AddTool(mcp.NewTool(EchoTool,
+		mcp.WithDescription("Echoes the given text"),
+		mcp.WithString("text", mcp.Required(), mcp.Description("Text to echo")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.calls.Add(1)
+		text, err := req.RequireString("text")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+	mcpServer.AddTool(mcp.NewTool(AddTool,
+		mcp.WithDescription("Adds two numbers"),
+		mcp.WithNumber("a", mcp.Required()),
+		mcp.WithNumber("b", mcp.Required()),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.calls.Add(1)
+		a, errA := req.RequireFloat("a")
+		b, errB := req.RequireFloat("b")
+		if errA != nil || errB != nil {
+			return mcp.NewToolResultError("a and b must be numbers"), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprint(a + b)), nil
+	})
+	mcpServer.AddTool(mcp.NewTool(FailTool,
+		mcp.WithDescription("Always fails"),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.calls.Add(1)
+		return mcp.NewToolResultError("scripted failure"), nil
+	})
+
+	handler := transport(mcpServer)
+	s.http = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.headers = r.Header.Clone()
+		s.mu.Unlock()
+		if s.down.Load() {
+			http.Error(w, "server is down", http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	return s
+}
+
+// URL returns the server's MCP endpoint
+func (s *Server) URL() string {
+	return strings.TrimSuffix(s.http.URL, "/") + s.endpoint
+}
+
+// Config returns the configuration connecting to the server under name
+func (s *Server) Config(name string) config.MCPServer {
+	return config.MCPServer{
+		Name:    name,
+		Type:    s.serverType,
+		Enabled: true,
+		URL:     s.URL(),
+	}
+}
+
+// SetDown makes the server refuse every request, like a server that dropped, or serve again
+func (s *Server) SetDown(down bool) {
+	s.down.Store(down)
+}
+
+// Header returns the headers of the last request the server received
+func (s *Server) Header() http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.headers.Clone()
+}
+
+// Calls returns how many tool calls the server has answered
+func (s *Server) Calls() int64 {
+	return s.calls.Load()
+}
+
+// Close stops the server
+func (s *Server) Close() {
+	s.http.Close()
+}
@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+)
+
+// stopGracePeriod is how long a stdio server has to exit after its input is closed before it is killed
+const stopGracePeriod = 5 * time.Second
+
+// stdioTransport runs a stdio server's process with the user's environment plus the server's
+// configured variables. Closing it closes the server's input, as the library's transport does,
+// and kills the server if it is still running after stopGracePeriod, so a server that ignores
+// the end of its input neither keeps running nor blocks disconnecting.
+type stdioTransport struct {
+	*transport.Stdio
+	command string
+	kill    context.CancelFunc
+}
+
+// newStdioTransport creates the transport of a stdio server, whose process is started with the
+// client in dir, or in the app's working directory when dir is empty
+func newStdioTransport(command string, env []string, args []string, dir string) *stdioTransport {
+	// The process outlives the context the client is started with, so it gets its own, which
+	// only Close cancels
+	ctx, kill := context.WithCancel(context.Background())
+	t := &stdioTransport{command: command, kill: kill}
+	t.Stdio = transport.NewStdioWithOptions(command, env, args, transport.WithCommandFunc(
+		func(_ context.Context, command string, env []string, args []string) (*exec.Cmd, error) {
+			cmd := exec.CommandContext(ctx, command, args...)
+			cmd.Env = append(os.Environ(), env...)
+			cmd.Dir = dir
+			return cmd, nil
+		}))
+	return t
+}
+
+// Close closes the server's input and waits for it to exit, killing it after stopGracePeriod
+func (t *stdioTransport) Close() error {
+	timer := time.AfterFunc(stopGracePeriod, func() {
+		logger.Warn("Killing server process still running after it was stopped", "command", t.command, "grace_period", stopGracePeriod)
+		t.kill()
+	})
+	err := t.Stdio.Close()
+	stopped := timer.Stop()
+	t.kill()
+
+	if !stopped {
+		// Being killed is how the process was stopped, not an error
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"chatgo/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultServerTimeout bounds initialization and each tool call when a server has no timeout configured
+const DefaultServerTimeout = 30 * time.Second
+
+// ServerTimeout returns the initialization and tool call timeout configured for a server
+func ServerTimeout(cfg config.MCPServer) time.Duration {
+	if cfg.TimeoutSeconds > 0 {
+		return time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	return DefaultServerTimeout
+}
+
+// startClient starts a client's transport within the deadline of ctx. The transport keeps
+// the context it is started with for the lifetime of the connection, so it is started with
+// a background context and closed if the deadline passes first.
+func startClient(ctx context.Context, c *client.Client) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Start(context.Background())
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// Closing the transport releases a Start blocked on an unresponsive server
+		_ = c.Close()
+		return ctx.Err()
+	}
+}
+
+// timedOut reports whether err was caused by ctx reaching its deadline
+func timedOut(ctx context.Context, err error) bool {
+	return err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+// timeoutClient bounds the requests made through a server's client by its configured timeout
+type timeoutClient struct {
+	*client.Client
+	timeout time.Duration
+}
+
+// ListTools lists the server's tools within the timeout
+func (c *timeoutClient) ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	result, err := c.Client.ListTools(ctx, request)
+	if timedOut(ctx, err) {
+		return nil, fmt.Errorf("listing tools timed out after %s", c.timeout)
+	}
+	return result, err
+}
+
+// CallTool calls a tool within the timeout
+func (c *timeoutClient) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	started := time.Now()
+	result, err := c.Client.CallTool(ctx, request)
+	if timedOut(ctx, err) {
+		err = fmt.Errorf("tool call %s timed out after %s", request.Params.Name, c.timeout)
+		logger.Error("Tool call failed", "tool", request.Params.Name, "error", err, "duration", time.Since(started))
+		return nil, err
+	}
+	if err != nil {
+		logger.Error("Tool call failed", "tool", request.Params.Name, "error", err, "duration", time.Since(started))
+	} else {
+		logger.Debug("Tool called", "tool", request.Params.Name, "duration", time.Since(started))
+	}
+	return result, err
+}
@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// silentTransport is a server that accepts the connection but never answers. It completes
+// the handshake unless hangOnStart is set, so requests made after it can be tested.
+type silentTransport struct {
+	hangOnStart bool
+	closed      chan struct{}
+	closeOnce   sync.Once
+	requests    atomic.Int32 // Requests sent, the handshake included
+}
+
+func newSilentTransport(hangOnStart bool) *silentTransport {
+	return &silentTransport{hangOnStart: hangOnStart, closed: make(chan struct{})}
+}
+
+func (t *silentTransport) Start(ctx context.Context) error {
+	if t.hangOnStart {
+		<-t.closed
+		return errors.New("transport closed")
+	}
+	return nil
+}
+
+func (t *silentTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	t.requests.Add(1)
+	if request.Method == "initialize" {
+		result, _ := json.Marshal(mcp.InitializeResult{ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION})
+		return &transport.JSONRPCResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: request.ID, Result: result}, nil
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-t.closed:
+		return nil, errors.New("transport closed")
+	}
+}
+
+func (t *silentTransport) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	return nil
+}
+
+func (t *silentTransport) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
+}
+
+func (t *silentTransport) Close() error {
+	t.closeOnce.Do(func() { close(t.closed) })
+	return nil
+}
+
+func (t *silentTransport) GetSessionId() string {
+	return ""
+}
+
+// isClosed reports whether the transport was closed
+func (t *silentTransport) isClosed() bool {
+	select {
+	case <-t.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestStartClientTimesOut(t *testing.T) {
+	silent := newSilentTransport(true)
+	c := client.NewClient(silent)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	err := startClient(ctx, c)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("startClient = %v, want context.DeadlineExceeded", err)
+	}
+	if !timedOut(ctx, err) {
+		t.Error("timedOut doesn't report the start as timed out")
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("startClient returned after %s, want about 50ms", elapsed)
+	}
+	if !silent.isClosed() {
+		t.Error("the transport of a client that didn't start in time wasn't closed")
+	}
+}
+
+// initializedSilentClient returns a client that completed the handshake with a server that
+// then never answers
+func initializedSilentClient(t *testing.T) *client.Client {
+	t.Helper()
+	silent := newSilentTransport(false)
+	c := client.NewClient(silent)
+	t.Cleanup(func() { c.Close() })
+
+	if err := startClient(context.Background(), c); err != nil {
+		t.Fatalf("startClient: %v", err)
+	}
+	if _, err := c.Initialize(context.Background(), mcp.InitializeRequest{}); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	return c
+}
+
+func TestTimeoutClientCallToolTimesOut(t *testing.T) {
+	tc := &timeoutClient{Client: initializedSilentClient(t), timeout: 50 * time.Millisecond}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "echo"
+
+	started := time.Now()
+	_, err := tc.CallTool(context.Background(), request)
+	if err == nil || !strings.Contains(err.Error(), "tool call echo timed out after 50ms") {
+		t.Errorf("CallTool = %v, want a timeout error", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("CallTool returned after %s, want about 50ms", elapsed)
+	}
+}
+
+func TestTimeoutClientListToolsTimesOut(t *testing.T) {
+	tc := &timeoutClient{Client: initializedSilentClient(t), timeout: 50 * time.Millisecond}
+
+	_, err := tc.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err == nil || !strings.Contains(err.Error(), "listing tools timed out after 50ms") {
+		t.Errorf("ListTools = %v, want a timeout error", err)
+	}
+}
+
+func TestTimeoutClientKeepsCancellation(t *testing.T) {
+	tc := &timeoutClient{Client: initializedSilentClient(t), timeout: time.Minute}
+
+	// A call cancelled by its caller isn't reported as timing out
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "echo"
+	_, err := tc.CallTool(ctx, request)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("CallTool = %v, want context.Canceled", err)
+	}
+}
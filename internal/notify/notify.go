@@ -0,0 +1,96 @@
+// Package notify queues notifications posted by background work until the UI can show them
+package notify
+
+import "sync"
+
+// Level is the severity of a notification
+type Level int
+
+const (
+	Info Level = iota
+	Warning
+	Error
+)
+
+// Action is a button offered alongside a notification's close button
+type Action struct {
+	Label string
+	Run   func() // Called on the UI goroutine
+}
+
+// Notification is a message for the user from background work
+type Notification struct {
+	Level   Level
+	Title   string
+	Message string
+	Action  *Action // Optional
+}
+
+// NeedsDialog reports whether the notification must be acknowledged: errors and
+// notifications offering an action are shown as dialogs, the rest as toasts
+func (n Notification) NeedsDialog() bool {
+	return n.Level == Error || n.Action != nil
+}
+
+// Queue holds notifications in the order they were posted. Any goroutine may post;
+// a single consumer takes them one at a time with Next.
+type Queue struct {
+	mu      sync.Mutex
+	pending []Notification
+	wake    chan struct{} // Signalled when a notification is posted or the queue is closed
+	closed  bool
+}
+
+// NewQueue creates an empty queue
+func NewQueue() *Queue {
+	return &Queue{wake: make(chan struct{}, 1)}
+}
+
+// Post adds a notification without blocking. Notifications posted after Close are dropped.
+func (q *Queue) Post(n Notification) {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.pending = append(q.pending, n)
+	q.mu.Unlock()
+	q.signal()
+}
+
+// Next waits for the oldest notification and removes it from the queue.
+// It returns false once the queue has been closed.
+func (q *Queue) Next() (Notification, bool) {
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return Notification{}, false
+		}
+		if len(q.pending) > 0 {
+			n := q.pending[0]
+			q.pending = q.pending[1:]
+			q.mu.Unlock()
+			return n, true
+		}
+		q.mu.Unlock()
+		<-q.wake
+	}
+}
+
+// Close discards pending notifications and ends Next
+func (q *Queue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.pending = nil
+	q.mu.Unlock()
+	q.signal()
+}
+
+// signal wakes the consumer; a wake-up already pending covers this one
+func (q *Queue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// next takes the next notification, failing the test if none comes within a second
+func next(t *testing.T, q *Queue) Notification {
+	t.Helper()
+	got := make(chan Notification, 1)
+	go func() {
+		if n, ok := q.Next(); ok {
+			got <- n
+		}
+	}()
+	select {
+	case n := <-got:
+		return n
+	case <-time.After(time.Second):
+		t.Fatal("Next didn't return a notification")
+		return Notification{}
+	}
+}
+
+func TestQueueKeepsOrder(t *testing.T) {
+	q := NewQueue()
+	defer q.Close()
+
+	for _, title := range []string{"first", "second", "third"} {
+		q.Post(Notification{Title: title})
+	}
+	for _, want := range []string{"first", "second", "third"} {
+		if got := next(t, q).Title; got != want {
+			t.Errorf("Next = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestQueueKeepsOrderOfEachPoster(t *testing.T) {
+	q := NewQueue()
+	defer q.Close()
+
+	const posters, each = 8, 50
+	var wg sync.WaitGroup
+	for p := 0; p < posters; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				q.Post(Notification{Title: fmt.Sprint(p), Message: fmt.Sprint(i)})
+			}
+		}()
+	}
+
+	// The consumer takes them while they are posted
+	seen := make(map[string]int)
+	for n := 0; n < posters*each; n++ {
+		got := next(t, q)
+		var i int
+		fmt.Sscan(got.Message, &i)
+		if i != seen[got.Title] {
+			t.Fatalf("poster %s's notification %d came after %d others of its own, want in the order posted", got.Title, i, seen[got.Title])
+		}
+		seen[got.Title]++
+	}
+	wg.Wait()
+}
+
+func TestNextWaitsForPost(t *testing.T) {
+	q := NewQueue()
+	defer q.Close()
+
+	got := make(chan Notification, 1)
+	go func() {
+		n, _ := q.Next()
+		got <- n
+	}()
+
+	select {
+	case n := <-got:
+		t.Fatalf("Next returned %+v from an empty queue", n)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	q.Post(Notification{Title: "posted"})
+	select {
+	case n := <-got:
+		if n.Title != "posted" {
+			t.Errorf("Next = %q, want %q", n.Title, "posted")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Next didn't return once a notification was posted")
+	}
+}
+
+func TestCloseEndsNext(t *testing.T) {
+	q := NewQueue()
+	q.Post(Notification{Title: "pending"})
+	q.Close()
+
+	if n, ok := q.Next(); ok {
+		t.Errorf("Next after Close = %+v, want the pending notification discarded", n)
+	}
+	q.Post(Notification{Title: "late"})
+	if n, ok := q.Next(); ok {
+		t.Errorf("Next = %+v, want a notification posted after Close dropped", n)
+	}
+
+	// A consumer already waiting is released too
+	q = NewQueue()
+	done := make(chan bool, 1)
+	go func() {
+		_, ok := q.Next()
+		done <- ok
+	}()
+	time.Sleep(20 * time.Millisecond)
+	q.Close()
+	select {
+	case ok := <-done:
+		if ok {
+			t.Error("Next returned a notification from a closed queue")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close didn't release a waiting Next")
+	}
+}
+
+func TestNeedsDialog(t *testing.T) {
+	tests := []struct {
+		n    Notification
+		want bool
+	}{
+		{Notification{Level: Info}, false},
+		{Notification{Level: Warning}, false},
+		{Notification{Level: Error}, true},
+		{Notification{Level: Info, Action: &Action{Label: "Open", Run: func() {}}}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.n.NeedsDialog(); got != tt.want {
+			t.Errorf("NeedsDialog of level %d with action %v = %v, want %v", tt.n.Level, tt.n.Action != nil, got, tt.want)
+		}
+	}
+}
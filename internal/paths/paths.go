@@ -0,0 +1,172 @@
+// Package paths resolves where ChatGo stores its configuration and data.
+//
+// In the default mode configuration lives in the user config directory and data in
+// ~/.chatgo. In portable mode, enabled by a portable.flag file next to the executable
+// or the --portable command line flag, everything is kept beside the executable.
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// PortableFlagFile enables portable mode when present next to the executable
+	PortableFlagFile = "portable.flag"
+
+	// PortableArg enables portable mode when passed on the command line
+	PortableArg = "--portable"
+)
+
+// Layout describes the directories in use
+type Layout struct {
+	Portable         bool
+	ConfigDir        string
+	DataDir          string
+	ConversationsDir string
+	LogsDir          string
+	AttachmentsDir   string
+	BackupsDir       string
+}
+
+var (
+	once    sync.Once
+	current Layout
+	initErr error
+)
+
+// Current returns the directory layout, detecting the mode on first use
+func Current() (Layout, error) {
+	once.Do(func() {
+		current, initErr = detect(os.Args[1:])
+	})
+	return current, initErr
+}
+
+// detect chooses portable or user directories
+func detect(args []string) (Layout, error) {
+	if exeDir, err := executableDir(); err == nil && isPortable(exeDir, args) {
+		return portableLayout(exeDir), nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return Layout{}, fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return Layout{}, fmt.Errorf("failed to locate home directory: %w", err)
+	}
+
+	dataDir := filepath.Join(homeDir, ".chatgo")
+	return Layout{
+		ConfigDir:        filepath.Join(configDir, "chatgo"),
+		DataDir:          dataDir,
+		ConversationsDir: filepath.Join(dataDir, "conversations"),
+		LogsDir:          filepath.Join(dataDir, "logs"),
+		AttachmentsDir:   filepath.Join(dataDir, "attachments"),
+		BackupsDir:       filepath.Join(dataDir, "backups"),
+	}, nil
+}
+
+// portableLayout keeps everything in a data directory next to the executable
+func portableLayout(exeDir string) Layout {
+	dataDir := filepath.Join(exeDir, "data")
+	return Layout{
+		Portable:         true,
+		ConfigDir:        dataDir,
+		DataDir:          dataDir,
+		ConversationsDir: filepath.Join(dataDir, "conversations"),
+		LogsDir:          filepath.Join(dataDir, "logs"),
+		AttachmentsDir:   filepath.Join(dataDir, "attachments"),
+		BackupsDir:       filepath.Join(dataDir, "backups"),
+	}
+}
+
+// isPortable reports whether portable mode was requested
+func isPortable(exeDir string, args []string) bool {
+	for _, arg := range args {
+		if arg == PortableArg {
+			return true
+		}
+	}
+	_, err := os.Stat(filepath.Join(exeDir, PortableFlagFile))
+	return err == nil
+}
+
+// executableDir returns the directory of the running executable with symlinks resolved
+func executableDir() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return filepath.Dir(exe), nil
+}
+
+// ConfigFile returns the path of config.yaml
+func ConfigFile() (string, error) {
+	layout, err := Current()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(layout.ConfigDir, "config.yaml"), nil
+}
+
+// ConversationsDir returns the directory conversations are stored in
+func ConversationsDir() (string, error) {
+	layout, err := Current()
+	if err != nil {
+		return "", err
+	}
+	return layout.ConversationsDir, nil
+}
+
+// BackupsDir returns the directory daily conversation backups are kept in
+func BackupsDir() (string, error) {
+	layout, err := Current()
+	if err != nil {
+		return "", err
+	}
+	return layout.BackupsDir, nil
+}
+
+// ModelCatalogFile returns the path of the optional model_catalog.json that updates the built-in model catalog
+func ModelCatalogFile() (string, error) {
+	layout, err := Current()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(layout.ConfigDir, "model_catalog.json"), nil
+}
+
+// QuotaFile returns the path of quota.json, which keeps the latest free-tier quota reported by each provider
+func QuotaFile() (string, error) {
+	layout, err := Current()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(layout.DataDir, "quota.json"), nil
+}
+
+// UIStateFile returns the path of ui_state.json, which remembers window sizes between runs
+func UIStateFile() (string, error) {
+	layout, err := Current()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(layout.DataDir, "ui_state.json"), nil
+}
+
+// LogFile returns the path of chatgo.log, which mirrors the log when logging to a file is enabled
+func LogFile() (string, error) {
+	layout, err := Current()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(layout.LogsDir, "chatgo.log"), nil
+}
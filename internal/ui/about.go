@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"chatgo/internal/paths"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showAbout displays the storage mode and the directories in use
+func (cw *ChatWindow) showAbout() {
+	layout, err := paths.Current()
+	if err != nil {
+		dialog.ShowError(err, cw.window)
+		return
+	}
+
+	mode := Translate("about.mode_standard")
+	if layout.Portable {
+		mode = Translatef("about.mode_portable", paths.PortableFlagFile, paths.PortableArg)
+	}
+
+	modeLabel := widget.NewLabel(Translatef("about.mode", mode))
+	modeLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	dirs := container.NewGridWithColumns(2,
+		widget.NewLabel(Translate("about.config")), widget.NewLabel(layout.ConfigDir),
+		widget.NewLabel(Translate("about.conversations")), widget.NewLabel(layout.ConversationsDir),
+		widget.NewLabel(Translate("about.logs")), widget.NewLabel(layout.LogsDir),
+		widget.NewLabel(Translate("about.attachments")), widget.NewLabel(layout.AttachmentsDir),
+		widget.NewLabel(Translate("about.backups")), widget.NewLabel(layout.BackupsDir),
+	)
+
+	content := container.NewVBox(
+		widget.NewLabel(Translate("app.title")),
+		modeLabel,
+		widget.NewSeparator(),
+		dirs,
+	)
+
+	dialog.ShowCustom(Translate("about.title"), Translate("common.close"), content, cw.window)
+}
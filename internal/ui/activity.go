@@ -0,0 +1,330 @@
+package ui
+
+import (
+	"chatgo/pkg/models"
+	"fmt"
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	heatmapWeeks    = 53 // Columns, enough to cover a year starting on any weekday
+	heatmapCellSize = 11
+	heatmapCellGap  = 3
+	heatmapLevels   = 4 // Shades for days with activity
+	dayKeyFormat    = "2006-01-02"
+)
+
+// localDay returns midnight of t's calendar day in the local time zone
+func localDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// dayKey identifies t's calendar day in the local time zone
+func dayKey(t time.Time) string {
+	return t.In(time.Local).Format(dayKeyFormat)
+}
+
+// messageCountsByDay counts the messages sent on each local calendar day
+func messageCountsByDay(conversations []models.Conversation) map[string]int {
+	counts := make(map[string]int)
+	for _, conv := range conversations {
+		for _, msg := range conv.Messages {
+			if msg.Timestamp.IsZero() {
+				continue
+			}
+			counts[dayKey(msg.Timestamp)]++
+		}
+	}
+	return counts
+}
+
+// activeOn reports whether a conversation was updated or had messages on the local day of day
+func activeOn(conv models.Conversation, day time.Time) bool {
+	key := dayKey(day)
+	if dayKey(conv.UpdatedAt) == key {
+		return true
+	}
+	for _, msg := range conv.Messages {
+		if !msg.Timestamp.IsZero() && dayKey(msg.Timestamp) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// activityStreaks returns the number of consecutive active days ending today, or yesterday
+// when nothing was sent today yet, and the longest run of active days from first to today
+func activityStreaks(counts map[string]int, first, today time.Time) (current, longest int) {
+	run := 0
+	// Days are stepped with AddDate rather than 24h so daylight saving changes don't skip or repeat a day
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		if counts[dayKey(day)] > 0 {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+
+	day := today
+	if counts[dayKey(day)] == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for !day.Before(first) && counts[dayKey(day)] > 0 {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+	return current, longest
+}
+
+// activityHeatmap is a GitHub-style grid of messages per day over the last year.
+// Columns are weeks starting on Sunday, rows are weekdays, and the last column ends today.
+type activityHeatmap struct {
+	widget.BaseWidget
+
+	counts map[string]int // Messages per day, keyed by dayKey
+	first  time.Time      // Sunday of the first column
+	today  time.Time
+	max    int
+
+	// OnHover is called with the day under the pointer; ok is false when the pointer leaves the grid
+	OnHover func(day time.Time, count int, ok bool)
+	// OnTapped is called with the day that was clicked
+	OnTapped func(day time.Time)
+}
+
+// newActivityHeatmap creates a heatmap of counts ending today
+func newActivityHeatmap(counts map[string]int) *activityHeatmap {
+	today := localDay(time.Now())
+	// Go back a year, then to that week's Sunday
+	first := today.AddDate(0, 0, -7*(heatmapWeeks-1))
+	first = first.AddDate(0, 0, -int(first.Weekday()))
+
+	h := &activityHeatmap{counts: counts, first: first, today: today}
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		h.max = max(h.max, counts[dayKey(day)])
+	}
+	h.ExtendBaseWidget(h)
+	return h
+}
+
+// dayAt returns the day of the cell at column and row, and whether it is within the heatmap
+func (h *activityHeatmap) dayAt(col, row int) (time.Time, bool) {
+	if col < 0 || col >= heatmapWeeks || row < 0 || row >= 7 {
+		return time.Time{}, false
+	}
+	day := h.first.AddDate(0, 0, col*7+row)
+	return day, !day.After(h.today)
+}
+
+// dayAtPosition returns the day of the cell under pos
+func (h *activityHeatmap) dayAtPosition(pos fyne.Position) (time.Time, bool) {
+	step := float32(heatmapCellSize + heatmapCellGap)
+	if pos.X < 0 || pos.Y < 0 {
+		return time.Time{}, false
+	}
+	col, row := int(pos.X/step), int(pos.Y/step)
+	// Ignore the gaps between cells
+	if pos.X-float32(col)*step > heatmapCellSize || pos.Y-float32(row)*step > heatmapCellSize {
+		return time.Time{}, false
+	}
+	return h.dayAt(col, row)
+}
+
+// level maps a count to a shade from 0 (no activity) to heatmapLevels relative to the busiest day
+func (h *activityHeatmap) level(count int) int {
+	if count <= 0 || h.max <= 0 {
+		return 0
+	}
+	return (count*heatmapLevels + h.max - 1) / h.max
+}
+
+// Tapped reports the clicked day
+func (h *activityHeatmap) Tapped(ev *fyne.PointEvent) {
+	if day, ok := h.dayAtPosition(ev.Position); ok && h.OnTapped != nil {
+		h.OnTapped(day)
+	}
+}
+
+// MouseIn implements desktop.Hoverable
+func (h *activityHeatmap) MouseIn(ev *desktop.MouseEvent) {
+	h.MouseMoved(ev)
+}
+
+// MouseMoved reports the day under the pointer
+func (h *activityHeatmap) MouseMoved(ev *desktop.MouseEvent) {
+	if h.OnHover == nil {
+		return
+	}
+	day, ok := h.dayAtPosition(ev.Position)
+	h.OnHover(day, h.counts[dayKey(day)], ok)
+}
+
+// MouseOut implements desktop.Hoverable
+func (h *activityHeatmap) MouseOut() {
+	if h.OnHover != nil {
+		h.OnHover(time.Time{}, 0, false)
+	}
+}
+
+// CreateRenderer implements fyne.Widget
+func (h *activityHeatmap) CreateRenderer() fyne.WidgetRenderer {
+	r := &heatmapRenderer{heatmap: h}
+	for col := 0; col < heatmapWeeks; col++ {
+		for row := 0; row < 7; row++ {
+			if _, ok := h.dayAt(col, row); !ok {
+				continue
+			}
+			cell := canvas.NewRectangle(color.Transparent)
+			cell.CornerRadius = 2
+			r.cells = append(r.cells, cell)
+			r.positions = append(r.positions, [2]int{col, row})
+		}
+	}
+	r.Refresh()
+	return r
+}
+
+// heatmapRenderer draws one rectangle per day up to today
+type heatmapRenderer struct {
+	heatmap   *activityHeatmap
+	cells     []*canvas.Rectangle
+	positions [][2]int // Column and row of each cell
+}
+
+func (r *heatmapRenderer) Layout(fyne.Size) {
+	step := float32(heatmapCellSize + heatmapCellGap)
+	for i, cell := range r.cells {
+		cell.Resize(fyne.NewSquareSize(heatmapCellSize))
+		cell.Move(fyne.NewPos(float32(r.positions[i][0])*step, float32(r.positions[i][1])*step))
+	}
+}
+
+func (r *heatmapRenderer) MinSize() fyne.Size {
+	step := float32(heatmapCellSize + heatmapCellGap)
+	return fyne.NewSize(heatmapWeeks*step-heatmapCellGap, 7*step-heatmapCellGap)
+}
+
+// Refresh recolors the cells from the current theme
+func (r *heatmapRenderer) Refresh() {
+	for i, cell := range r.cells {
+		day, _ := r.heatmap.dayAt(r.positions[i][0], r.positions[i][1])
+		cell.FillColor = heatmapColor(r.heatmap.level(r.heatmap.counts[dayKey(day)]))
+		cell.Refresh()
+	}
+}
+
+func (r *heatmapRenderer) Objects() []fyne.CanvasObject {
+	objects := make([]fyne.CanvasObject, len(r.cells))
+	for i, cell := range r.cells {
+		objects[i] = cell
+	}
+	return objects
+}
+
+func (r *heatmapRenderer) Destroy() {}
+
+// heatmapColor returns the shade for a level: the input background for no activity, then
+// the theme's primary color at increasing opacity
+func heatmapColor(level int) color.Color {
+	if level <= 0 {
+		return theme.Color(theme.ColorNameInputBackground)
+	}
+	primary := color.NRGBAModel.Convert(theme.Color(theme.ColorNamePrimary)).(color.NRGBA)
+	primary.A = uint8(255 * (level + 1) / (heatmapLevels + 1))
+	return primary
+}
+
+// showActivity displays the messages sent per day over the last year with the current and
+// longest streaks. Clicking a day narrows the sidebar to conversations active that day.
+func (cw *ChatWindow) showActivity() {
+	conversations, err := cw.convManager.ListConversations(models.ListOptions{IncludeArchived: true})
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to load conversations: %w", err), cw.window)
+		return
+	}
+
+	counts := messageCountsByDay(conversations)
+	heatmap := newActivityHeatmap(counts)
+
+	total := 0
+	for day := heatmap.first; !day.After(heatmap.today); day = day.AddDate(0, 0, 1) {
+		total += counts[dayKey(day)]
+	}
+	current, longest := activityStreaks(counts, heatmap.first, heatmap.today)
+	summary := widget.NewLabel(Translatef("activity.summary", total, current, longest))
+
+	hoverHint := Translate("activity.hover_hint")
+	hoverLabel := widget.NewLabel(hoverHint)
+	hoverLabel.Importance = widget.LowImportance
+	heatmap.OnHover = func(day time.Time, count int, ok bool) {
+		if !ok {
+			hoverLabel.SetText(hoverHint)
+			return
+		}
+		hoverLabel.SetText(Translatef("activity.day_messages", day.Format("Mon, Jan 2 2006"), count))
+	}
+
+	legend := container.NewHBox(widget.NewLabel(Translate("activity.less")))
+	for level := 0; level <= heatmapLevels; level++ {
+		swatch := canvas.NewRectangle(heatmapColor(level))
+		swatch.CornerRadius = 2
+		swatch.SetMinSize(fyne.NewSquareSize(heatmapCellSize))
+		legend.Add(container.NewCenter(swatch))
+	}
+	legend.Add(widget.NewLabel(Translate("activity.more")))
+
+	content := container.NewVBox(
+		summary,
+		container.NewHScroll(heatmap),
+		container.NewBorder(nil, nil, nil, legend, hoverLabel),
+	)
+
+	d := dialog.NewCustom(Translate("activity.title"), Translate("common.close"), content, cw.window)
+	heatmap.OnTapped = func(day time.Time) {
+		d.Hide()
+		cw.filterByDay(day)
+	}
+	d.Show()
+}
+
+// newDayFilterBar creates the sidebar bar shown while the list is narrowed to a day
+func (cw *ChatWindow) newDayFilterBar() fyne.CanvasObject {
+	cw.dayFilterLabel = widget.NewLabel("")
+	clearBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+		cw.filterByDay(time.Time{})
+	})
+	clearBtn.Importance = widget.LowImportance
+	cw.dayFilterBar = container.NewBorder(nil, nil, nil, clearBtn, cw.dayFilterLabel)
+	cw.dayFilterBar.Hide()
+	return cw.dayFilterBar
+}
+
+// filterByDay narrows the sidebar to conversations active on day; the zero time clears the filter
+func (cw *ChatWindow) filterByDay(day time.Time) {
+	if day.IsZero() {
+		cw.selectedDay = time.Time{}
+	} else {
+		cw.selectedDay = localDay(day)
+	}
+
+	if cw.dayFilterBar != nil {
+		if cw.selectedDay.IsZero() {
+			cw.dayFilterBar.Hide()
+		} else {
+			cw.dayFilterLabel.SetText(Translatef("activity.active_on", cw.selectedDay.Format("Mon, Jan 2 2006")))
+			cw.dayFilterBar.Show()
+		}
+	}
+	cw.filterConversations()
+}
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"fmt"
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// appTheme is the default theme with the variant and accent color chosen in Settings
+type appTheme struct {
+	fyne.Theme
+	variant *fyne.ThemeVariant // nil follows the system
+	accent  *color.NRGBA       // nil keeps the default primary color
+	scale   float32            // Size of chat message text relative to the theme's
+}
+
+// Text sizes of chat messages, which follow the message text scale rather than the theme alone
+const (
+	messageTextSize       fyne.ThemeSizeName = "chatgo.messageText"
+	messageHeadingSize    fyne.ThemeSizeName = "chatgo.messageHeadingText"
+	messageSubHeadingSize fyne.ThemeSizeName = "chatgo.messageSubHeadingText"
+	messageCaptionSize    fyne.ThemeSizeName = "chatgo.messageCaptionText"
+)
+
+// messageSizes maps the text sizes of the theme to those of chat messages
+var messageSizes = map[fyne.ThemeSizeName]fyne.ThemeSizeName{
+	"":                           messageTextSize,
+	theme.SizeNameText:           messageTextSize,
+	theme.SizeNameHeadingText:    messageHeadingSize,
+	theme.SizeNameSubHeadingText: messageSubHeadingSize,
+	theme.SizeNameCaptionText:    messageCaptionSize,
+}
+
+// newAppTheme creates the theme for the appearance settings in cfg. An accent color that
+// cannot be parsed is ignored.
+func newAppTheme(cfg *config.Config) *appTheme {
+	t := &appTheme{Theme: theme.DefaultTheme(), scale: float32(cfg.MessageScale())}
+	switch cfg.Theme {
+	case config.ThemeLight:
+		variant := theme.VariantLight
+		t.variant = &variant
+	case config.ThemeDark:
+		variant := theme.VariantDark
+		t.variant = &variant
+	}
+	if accent, err := parseHexColor(cfg.AccentColor); err == nil {
+		t.accent = &accent
+	}
+	return t
+}
+
+// Color returns the named color for the chosen variant, using the accent color for the
+// primary color and the colors derived from it
+func (t *appTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if t.variant != nil {
+		variant = *t.variant
+	}
+	if t.accent != nil {
+		accent := *t.accent
+		switch name {
+		case theme.ColorNamePrimary, theme.ColorNameHyperlink:
+			return accent
+		case theme.ColorNameFocus:
+			accent.A = 0x7f
+			return accent
+		case theme.ColorNameSelection:
+			accent.A = 0x40
+			return accent
+		case theme.ColorNameForegroundOnPrimary:
+			// Dark text on light accents so labels on primary buttons stay readable
+			if luminance(accent) > 0.6 {
+				return color.Black
+			}
+			return color.White
+		}
+	}
+	return t.Theme.Color(name, variant)
+}
+
+// Size returns the named size, scaling the text sizes of chat messages
+func (t *appTheme) Size(name fyne.ThemeSizeName) float32 {
+	switch name {
+	case messageTextSize:
+		return t.Theme.Size(theme.SizeNameText) * t.scale
+	case messageHeadingSize:
+		return t.Theme.Size(theme.SizeNameHeadingText) * t.scale
+	case messageSubHeadingSize:
+		return t.Theme.Size(theme.SizeNameSubHeadingText) * t.scale
+	case messageCaptionSize:
+		return t.Theme.Size(theme.SizeNameCaptionText) * t.scale
+	}
+	return t.Theme.Size(name)
+}
+
+// applyTheme shows the window in the theme, accent color and message text size set in Settings
+func (cw *ChatWindow) applyTheme() {
+	cw.app.Settings().SetTheme(newAppTheme(cw.config))
+}
+
+// parseHexColor parses a color written as #rrggbb
+func parseHexColor(s string) (color.NRGBA, error) {
+	c := color.NRGBA{A: 0xff}
+	if len(s) != 7 || !strings.HasPrefix(s, "#") {
+		return c, fmt.Errorf("invalid color %q, expected #rrggbb", s)
+	}
+	if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return c, fmt.Errorf("invalid color %q, expected #rrggbb", s)
+	}
+	return c, nil
+}
+
+// hexColor formats c as #rrggbb, ignoring its alpha
+func hexColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+}
+
+// luminance returns the perceived brightness of c from 0 to 1
+func luminance(c color.NRGBA) float64 {
+	return (0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)) / 255
+}
@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"chatgo/internal/llm"
+	"chatgo/internal/notify"
+	"chatgo/pkg/models"
+	"fmt"
+	"os"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// imageAttachmentMaxBytes is the largest image that can be attached, Anthropic's per-image
+// limit and the smallest among the multimodal providers
+const imageAttachmentMaxBytes = 5 * 1024 * 1024
+
+// thumbnailSize is the size of the image thumbnails in messages and the attachment bar
+var thumbnailSize = fyne.NewSize(120, 90)
+
+// showAttachFileDialog lets the user pick a text file to attach to the next message
+func (cw *ChatWindow) showAttachFileDialog() {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, cw.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		attachment, err := models.ReadTextAttachment(reader.URI().Name(), reader, cw.config.AttachmentMaxBytes())
+		if err != nil {
+			dialog.ShowError(err, cw.window)
+			return
+		}
+		cw.pendingAttachments = append(cw.pendingAttachments, attachment)
+		cw.refreshPendingAttachments()
+	}, cw.window)
+	open.Show()
+}
+
+// showAttachImageDialog lets the user pick an image to attach to the next message
+func (cw *ChatWindow) showAttachImageDialog() {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, cw.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		attachment, err := models.ReadImageAttachment(reader.URI().Path(), reader, imageAttachmentMaxBytes)
+		if err != nil {
+			dialog.ShowError(err, cw.window)
+			return
+		}
+		if !cw.acceptsImages() {
+			provider, _ := cw.currentProvider()
+			cw.notifications.Post(notify.Notification{
+				Level:   notify.Warning,
+				Title:   Translate("attachments.no_vision_title"),
+				Message: Translatef("attachments.no_vision", provider.Model),
+			})
+		}
+		cw.pendingAttachments = append(cw.pendingAttachments, attachment)
+		cw.refreshPendingAttachments()
+	}, cw.window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg", ".gif", ".webp"}))
+	open.Show()
+}
+
+// acceptsImages reports whether the current provider's model accepts images. Models the
+// catalog doesn't know are given the benefit of the doubt.
+func (cw *ChatWindow) acceptsImages() bool {
+	provider, ok := cw.currentProvider()
+	if !ok {
+		return false
+	}
+	info, known := llm.ModelCatalog.ForProvider(provider)
+	return !known || info.Vision
+}
+
+// chatImages converts image attachments for sending to the model
+func chatImages(attachments []models.Attachment) []llm.ChatImage {
+	if len(attachments) == 0 {
+		return nil
+	}
+	images := make([]llm.ChatImage, len(attachments))
+	for i, attachment := range attachments {
+		images[i] = llm.ChatImage{MimeType: attachment.MimeType, Data: attachment.Data}
+	}
+	return images
+}
+
+// refreshPendingAttachments shows a removable chip for each file attached to the next message
+func (cw *ChatWindow) refreshPendingAttachments() {
+	cw.attachmentBar.RemoveAll()
+	for i, attachment := range cw.pendingAttachments {
+		index := i
+		removeBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+			cw.pendingAttachments = append(cw.pendingAttachments[:index], cw.pendingAttachments[index+1:]...)
+			cw.refreshPendingAttachments()
+		})
+		removeBtn.Importance = widget.LowImportance
+		cw.attachmentBar.Add(container.NewHBox(cw.newAttachmentChip(attachment), removeBtn))
+	}
+
+	if len(cw.pendingAttachments) == 0 {
+		cw.attachmentBar.Hide()
+	} else {
+		cw.attachmentBar.Show()
+	}
+}
+
+// newAttachmentChip shows an attachment's file name, or a thumbnail of an image; tapping it
+// previews the content
+func (cw *ChatWindow) newAttachmentChip(attachment models.Attachment) fyne.CanvasObject {
+	if attachment.IsImage() {
+		return cw.newImageThumbnail(attachment)
+	}
+	chip := widget.NewButtonWithIcon(attachment.Name, theme.FileTextIcon(), func() {
+		cw.showAttachmentPreview(attachment)
+	})
+	chip.Importance = widget.LowImportance
+	return chip
+}
+
+// newAttachmentChips lays out the chips of a message's attachments
+func (cw *ChatWindow) newAttachmentChips(attachments []models.Attachment) fyne.CanvasObject {
+	chips := container.NewHBox()
+	for _, attachment := range attachments {
+		chips.Add(cw.newAttachmentChip(attachment))
+	}
+	return chips
+}
+
+// newImageThumbnail shows a small copy of an attached image that opens the full image when tapped
+func (cw *ChatWindow) newImageThumbnail(attachment models.Attachment) fyne.CanvasObject {
+	image := newAttachmentImage(attachment)
+	image.SetMinSize(thumbnailSize)
+
+	// A flat button over the image makes it tappable
+	open := widget.NewButton("", func() {
+		cw.showAttachmentPreview(attachment)
+	})
+	open.Importance = widget.LowImportance
+	return container.NewStack(image, open)
+}
+
+// newAttachmentImage creates an image from the file it was attached from, or from the copy
+// saved with the message when that file has since been moved or deleted
+func newAttachmentImage(attachment models.Attachment) *canvas.Image {
+	var image *canvas.Image
+	if _, err := os.Stat(attachment.Path); attachment.Path != "" && err == nil {
+		image = canvas.NewImageFromFile(attachment.Path)
+	} else {
+		data, _ := attachment.ImageBytes()
+		image = canvas.NewImageFromResource(fyne.NewStaticResource(attachment.Name, data))
+	}
+	image.FillMode = canvas.ImageFillContain
+	return image
+}
+
+// showAttachmentPreview displays an attachment's content as it is sent to the model
+func (cw *ChatWindow) showAttachmentPreview(attachment models.Attachment) {
+	title := fmt.Sprintf("%s (%s)", attachment.Name, attachment.MimeType)
+	if attachment.IsImage() {
+		image := newAttachmentImage(attachment)
+		image.SetMinSize(fyne.NewSize(600, 400))
+		dialog.ShowCustom(title, "Close", image, cw.window)
+		return
+	}
+
+	content := CreateMarkdownRichText(attachment.Fenced(), DefaultRichTextConfig())
+	scroll := container.NewScroll(content)
+	scroll.SetMinSize(fyne.NewSize(600, 400))
+	dialog.ShowCustom(title, "Close", scroll, cw.window)
+}
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"chatgo/pkg/models"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// backupTimeFormat is how the time a backup was made is shown
+const backupTimeFormat = "2006-01-02 15:04"
+
+// startDailyBackup backs up the conversations in the background unless that was done today
+func (cw *ChatWindow) startDailyBackup() {
+	keep := cw.config.BackupsKept()
+	go func() {
+		due, err := cw.convManager.BackupDue()
+		if err != nil {
+			logger.Warn("Failed to list conversation backups", "error", err)
+		}
+		if !due {
+			return
+		}
+		if _, err := cw.convManager.BackUp(keep); err != nil {
+			logger.Warn("Failed to back up conversations", "error", err)
+		}
+	}()
+}
+
+// newAutoBackupRows creates the settings for the daily conversation backups: how many are kept,
+// when the last was made and a button restoring from one
+func (cw *ChatWindow) newAutoBackupRows(parentWindow fyne.Window) fyne.CanvasObject {
+	keepEntry := widget.NewEntry()
+	if cw.config.BackupKeep > 0 {
+		keepEntry.SetText(strconv.Itoa(cw.config.BackupKeep))
+	}
+	keepEntry.SetPlaceHolder(strconv.Itoa(config.DefaultBackupKeep))
+	keepSaveBtn := widget.NewButton(Translate("common.save"), func() {
+		keep := 0
+		if text := strings.TrimSpace(keepEntry.Text); text != "" {
+			var err error
+			keep, err = strconv.Atoi(text)
+			if err != nil || keep <= 0 {
+				dialog.ShowError(fmt.Errorf("backups kept must be a positive number"), parentWindow)
+				return
+			}
+		}
+		cw.config.BackupKeep = keep
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+		}
+	})
+
+	lastLabel := widget.NewLabel(Translate("backup.last_none"))
+	lastLabel.Importance = widget.LowImportance
+	if backups, err := cw.convManager.ListBackups(); err == nil && len(backups) > 0 {
+		lastLabel.SetText(Translatef("backup.last", backups[0].Time.Format(backupTimeFormat)))
+	}
+
+	restoreBtn := widget.NewButton(Translate("backup.restore_from"), func() {
+		cw.showRestoreBackupDialog(parentWindow)
+	})
+
+	return container.NewVBox(
+		newFormGrid(newFormLabel(Translate("backup.keep")), container.NewBorder(nil, nil, nil, keepSaveBtn, keepEntry)),
+		container.NewBorder(nil, nil, nil, restoreBtn, lastLabel),
+	)
+}
+
+// showRestoreBackupDialog lists the backups with their dates and sizes and restores every
+// conversation in the chosen one, or a single conversation picked from it
+func (cw *ChatWindow) showRestoreBackupDialog(parentWindow fyne.Window) {
+	backups, err := cw.convManager.ListBackups()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to list backups: %w", err), parentWindow)
+		return
+	}
+	if len(backups) == 0 {
+		dialog.ShowInformation(Translate("backup.restore_title"), Translate("backup.none"), parentWindow)
+		return
+	}
+
+	// Conversations in the selected backup, offered after the option restoring all of them
+	var conversations []models.Conversation
+	selected := -1
+	conversationSelect := widget.NewSelect(nil, nil)
+	conversationSelect.Disable()
+
+	backupList := widget.NewList(
+		func() int { return len(backups) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(fmt.Sprintf("%s    %s", backups[id].Time.Format(backupTimeFormat), formatByteSize(backups[id].Size)))
+		},
+	)
+	backupList.OnSelected = func(id widget.ListItemID) {
+		found, err := cw.convManager.BackupConversations(backups[id].Path)
+		if err != nil {
+			dialog.ShowError(err, parentWindow)
+			return
+		}
+		selected, conversations = id, found
+		sortConversations(conversations)
+		options := []string{Translatef("backup.restore_all", len(conversations))}
+		for _, conv := range conversations {
+			options = append(options, conversationListTitle(conv))
+		}
+		conversationSelect.SetOptions(options)
+		conversationSelect.SetSelectedIndex(0)
+		conversationSelect.Enable()
+	}
+
+	hint := widget.NewLabel(Translate("backup.restore_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	content := container.NewBorder(
+		hint,
+		newFormGrid(newFormLabel(Translate("backup.restore_what")), conversationSelect),
+		nil, nil,
+		backupList,
+	)
+	d := dialog.NewCustomConfirm(Translate("backup.restore_title"), Translate("backup.restore"), Translate("common.cancel"), content, func(restore bool) {
+		if !restore || selected < 0 {
+			return
+		}
+		id := ""
+		if i := conversationSelect.SelectedIndex(); i > 0 {
+			id = conversations[i-1].ID
+		}
+		cw.restoreBackup(parentWindow, backups[selected], id)
+	}, parentWindow)
+	d.Resize(fyne.NewSize(560, 440))
+	d.Show()
+}
+
+// restoreBackup restores the conversation with ID id from a backup, or every conversation in
+// it when id is empty, once the user confirms replacing the current versions
+func (cw *ChatWindow) restoreBackup(parentWindow fyne.Window, backup models.Backup, id string) {
+	dialog.ShowConfirm(Translate("backup.restore_title"), Translatef("backup.restore_confirm", backup.Time.Format(backupTimeFormat)), func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		restored, err := cw.convManager.RestoreBackup(backup.Path, id)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to restore backup: %w", err), parentWindow)
+			if restored == 0 {
+				return
+			}
+		}
+
+		// Show the restored version of the open conversation, so saving it doesn't undo the restore
+		if current := cw.currentConversation; current != nil && (id == "" || id == current.ID) {
+			cw.loadConversation(current.ID)
+		}
+		if err == nil {
+			dialog.ShowInformation(Translate("common.success"), Translatef("backup.restored", restored), parentWindow)
+		}
+	}, parentWindow)
+}
@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"image/color"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// bubbleGutter is the empty margin on the side of a message bubble away from its avatar,
+// which sets user and assistant messages apart
+const bubbleGutter = 64
+
+// avatarSize is the diameter of a message's role avatar
+const avatarSize = 28
+
+// roleColors returns the theme colors of a role's bubble, avatar and avatar initial
+func roleColors(role string) (bubble, avatar, initial fyne.ThemeColorName) {
+	switch role {
+	case "user":
+		return theme.ColorNameSelection, theme.ColorNamePrimary, theme.ColorNameForegroundOnPrimary
+	case "assistant":
+		return theme.ColorNameInputBackground, theme.ColorNameSuccess, theme.ColorNameForegroundOnSuccess
+	default:
+		return theme.ColorNameInputBackground, theme.ColorNameWarning, theme.ColorNameForegroundOnWarning
+	}
+}
+
+// themedRectangle is a filled rectangle that follows a theme color, so it changes with the
+// light or dark variant
+type themedRectangle struct {
+	widget.BaseWidget
+	fill   fyne.ThemeColorName
+	square bool // Square corners instead of rounded ones
+}
+
+// newThemedRectangle creates a rounded rectangle filled with the named theme color
+func newThemedRectangle(fill fyne.ThemeColorName) *themedRectangle {
+	r := &themedRectangle{fill: fill}
+	r.ExtendBaseWidget(r)
+	return r
+}
+
+// CreateRenderer draws the rectangle
+func (r *themedRectangle) CreateRenderer() fyne.WidgetRenderer {
+	rect := canvas.NewRectangle(color.Transparent)
+	return newThemedRenderer(&themedRenderer{objects: []fyne.CanvasObject{rect}, refresh: func() {
+		rect.FillColor = theme.Color(r.fill)
+		if !r.square {
+			rect.CornerRadius = theme.InputRadiusSize() * 2
+		}
+	}})
+}
+
+// messageAvatar is a circle with the initial of a message's role
+type messageAvatar struct {
+	widget.BaseWidget
+	role string
+}
+
+// newMessageAvatar creates the avatar of a role, such as "U" on the user color for "user"
+func newMessageAvatar(role string) *messageAvatar {
+	a := &messageAvatar{role: role}
+	a.ExtendBaseWidget(a)
+	return a
+}
+
+// CreateRenderer draws the circle and the initial centered on it
+func (a *messageAvatar) CreateRenderer() fyne.WidgetRenderer {
+	circle := canvas.NewCircle(color.Transparent)
+	initial := canvas.NewText(strings.ToUpper(a.role[:min(1, len(a.role))]), color.Transparent)
+	initial.TextStyle = fyne.TextStyle{Bold: true}
+	initial.Alignment = fyne.TextAlignCenter
+
+	return newThemedRenderer(&themedRenderer{
+		objects: []fyne.CanvasObject{circle, initial},
+		minSize: fyne.NewSquareSize(avatarSize),
+		refresh: func() {
+			_, fill, text := roleColors(a.role)
+			circle.FillColor = theme.Color(fill)
+			initial.Color = theme.Color(text)
+		},
+		layout: func(size fyne.Size) {
+			circle.Resize(size)
+			textSize := initial.MinSize()
+			initial.Move(fyne.NewPos(0, (size.Height-textSize.Height)/2))
+			initial.Resize(fyne.NewSize(size.Width, textSize.Height))
+		},
+	})
+}
+
+// themedRenderer renders canvas objects whose colors are read from the theme on every refresh
+type themedRenderer struct {
+	objects []fyne.CanvasObject
+	minSize fyne.Size
+	refresh func()          // Applies the theme colors
+	layout  func(fyne.Size) // nil sizes every object to fill the widget
+}
+
+// newThemedRenderer applies the theme colors to a renderer before its first draw
+func newThemedRenderer(r *themedRenderer) *themedRenderer {
+	r.refresh()
+	return r
+}
+
+// Layout positions the objects
+func (r *themedRenderer) Layout(size fyne.Size) {
+	if r.layout != nil {
+		r.layout(size)
+		return
+	}
+	for _, o := range r.objects {
+		o.Resize(size)
+	}
+}
+
+// MinSize returns the fixed minimum size, if any
+func (r *themedRenderer) MinSize() fyne.Size {
+	return r.minSize
+}
+
+// Refresh reapplies the theme colors and redraws
+func (r *themedRenderer) Refresh() {
+	r.refresh()
+	for _, o := range r.objects {
+		o.Refresh()
+	}
+}
+
+// Objects returns the canvas objects drawn
+func (r *themedRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+// Destroy does nothing
+func (r *themedRenderer) Destroy() {
+}
+
+// newBubbleHeader creates the line at the top of a bubble with the time the message was
+// sent and its actions. The day is shown by the date separators, so only the time is
+// shown here; hovering it shows the full timestamp.
+func (cw *ChatWindow) newBubbleHeader(timestamp time.Time, actions fyne.CanvasObject) fyne.CanvasObject {
+	timestamp = timestamp.Local()
+	timeLabel := widget.NewRichText(&widget.TextSegment{
+		Text:  timestamp.Format("15:04"),
+		Style: widget.RichTextStyle{Inline: true, SizeName: theme.SizeNameCaptionText},
+	})
+	return container.NewBorder(nil, nil, cw.newTooltipArea(timeLabel, timestamp.Format(time.RFC3339)), actions)
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// chatEntry is a multi-line entry that sends on Enter and inserts a newline on Shift+Enter.
+// The behaviour is swapped when sendOnEnter reports false. Ctrl+Enter (Cmd+Enter on macOS)
+// always sends.
+type chatEntry struct {
+	widget.Entry
+
+	// OnSend is called when the key combination for sending is pressed
+	OnSend func()
+
+	// OnPaste is called with the clipboard text before pasting; returning true
+	// means the paste was handled and the text is not inserted
+	OnPaste func(text string) bool
+
+	// OnShortcut is offered every other shortcut first; returning true means it was handled,
+	// e.g. as one of the window's shortcuts, which the focused entry would otherwise swallow
+	OnShortcut func(shortcut fyne.Shortcut) bool
+
+	sendOnEnter func() bool
+	shiftDown   bool
+}
+
+// newChatEntry creates a multi-line chat input. sendOnEnter is consulted on every
+// key press so configuration changes apply immediately.
+func newChatEntry(sendOnEnter func() bool) *chatEntry {
+	e := &chatEntry{sendOnEnter: sendOnEnter}
+	e.MultiLine = true
+	e.Wrapping = fyne.TextWrapWord
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// KeyDown tracks the shift modifier before passing the event to the entry
+func (e *chatEntry) KeyDown(key *fyne.KeyEvent) {
+	if key.Name == desktop.KeyShiftLeft || key.Name == desktop.KeyShiftRight {
+		e.shiftDown = true
+	}
+	e.Entry.KeyDown(key)
+}
+
+// KeyUp tracks the shift modifier before passing the event to the entry
+func (e *chatEntry) KeyUp(key *fyne.KeyEvent) {
+	if key.Name == desktop.KeyShiftLeft || key.Name == desktop.KeyShiftRight {
+		e.shiftDown = false
+	}
+	e.Entry.KeyUp(key)
+}
+
+// TypedKey sends or inserts a newline on Enter depending on the shift state
+func (e *chatEntry) TypedKey(key *fyne.KeyEvent) {
+	if key.Name != fyne.KeyReturn && key.Name != fyne.KeyEnter {
+		e.Entry.TypedKey(key)
+		return
+	}
+
+	send := e.shiftDown
+	if e.sendOnEnter == nil || e.sendOnEnter() {
+		send = !e.shiftDown
+	}
+
+	if send {
+		if e.OnSend != nil && !e.Disabled() {
+			e.OnSend()
+		}
+		return
+	}
+
+	// OnSubmitted is never set on a chatEntry, so the base entry always inserts a newline here
+	e.Entry.TypedKey(key)
+}
+
+// TypedShortcut sends on Ctrl+Enter, and gives OnShortcut and OnPaste a chance to handle
+// the other shortcuts
+func (e *chatEntry) TypedShortcut(shortcut fyne.Shortcut) {
+	if s, ok := shortcut.(*desktop.CustomShortcut); ok && s.Modifier == sendShortcut.Modifier &&
+		(s.KeyName == fyne.KeyReturn || s.KeyName == fyne.KeyEnter) {
+		if e.OnSend != nil && !e.Disabled() {
+			e.OnSend()
+		}
+		return
+	}
+	if e.OnShortcut != nil && e.OnShortcut(shortcut) {
+		return
+	}
+	if paste, ok := shortcut.(*fyne.ShortcutPaste); ok && e.OnPaste != nil && paste.Clipboard != nil {
+		if e.OnPaste(paste.Clipboard.Content()) {
+			return
+		}
+	}
+	e.Entry.TypedShortcut(shortcut)
+}
+
+// PasteText inserts text at the cursor as a regular paste, bypassing OnPaste
+func (e *chatEntry) PasteText(text string) {
+	e.Entry.TypedShortcut(&fyne.ShortcutPaste{Clipboard: &staticClipboard{content: text}})
+}
+
+// staticClipboard is a fyne.Clipboard holding fixed content
+type staticClipboard struct {
+	content string
+}
+
+// Content returns the clipboard content
+func (c *staticClipboard) Content() string {
+	return c.content
+}
+
+// SetContent replaces the clipboard content
+func (c *staticClipboard) SetContent(content string) {
+	c.content = content
+}
@@ -4,12 +4,19 @@
 package ui
 
 import (
+	"chatgo/internal/autotitle"
 	"chatgo/internal/config"
+	"chatgo/internal/httpclient"
 	"chatgo/internal/llm"
+	"chatgo/internal/log"
 	"chatgo/internal/mcp"
+	"chatgo/internal/notify"
 	"chatgo/pkg/models"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -24,9 +31,17 @@ import (
 
 	einomcp "github.com/cloudwego/eino-ext/components/tool/mcp"
 	"github.com/cloudwego/eino/components/tool"
-	"github.com/cloudwego/eino/schema"
 )
 
+// logger logs what the window does with providers, tools and MCP servers
+var logger = log.New("ui")
+
+// streamFlushInterval is how often accumulated stream chunks are rendered, at most ten times a second
+const streamFlushInterval = 100 * time.Millisecond
+
+// waitingHintDelay is how long a stream may go without a chunk before a waiting hint is shown
+const waitingHintDelay = 3 * time.Second
+
 // ChatWindow represents the main chat window of the application.
 // It manages two modes: home page (simple centered input) and chat interface (full conversation view).
 // The chat interface supports streaming messages, multiple LLM providers, and conversation persistence.
@@ -36,38 +51,107 @@ type ChatWindow struct {
 	config              *config.Config
 	convManager         *models.ConversationManager
 	mcpManager          *MCPManagerWrapper
+	mcpStatusLabel      *widget.Label
+	mcpActivity         *widget.Activity // Spins beside mcpStatusLabel while servers initialize
+	mcpStatus           *fyne.Container  // mcpActivity and mcpStatusLabel, shown on both pages
+	titleQueue          *autotitle.Queue
+	titleProgress       autotitle.Progress
+	titleProgressBox    *fyne.Container
+	titleProgressLabel  *widget.Label
+	titlePauseBtn       *widget.Button
 	toolSelectionMgr    *ToolSelectionManager
 	currentConversation *models.Conversation
 	llmClient           *llm.Client
 	reactClient         *llm.ReactClient
+	agentTools          string // toolSelectionKey of the tools reactClient was built with
 
 	// UI components
+	split             *container.Split // Sidebar and chat area; nil in home mode
+	splitOffset       float64          // Position the sidebar divider starts at
 	convList          *widget.List
 	chatArea          *container.Scroll
-	messageEntry      *widget.Entry
+	messageEntry      *chatEntry
+	draftStatsLabel   *widget.Label // Size and cost of the draft, under the message entry
 	sendButton        *widget.Button
-	providerSelect    *widget.Select
+	attachButton      *widget.Button
+	attachImageButton *widget.Button
+	attachmentBar     *fyne.Container // Chips of the files and images attached to the next message
+	providerSelect    *tooltipSelect
 	toolSelectBtn     *widget.Button
-	convListData      []models.Conversation
+	convListData      []models.Conversation // Conversations shown in the sidebar, after filtering
+	allConversations  []models.Conversation // Every stored conversation, archived ones included, in sidebar order
+	syncingSelection  bool                  // Set while the sidebar highlights the open conversation's moved row
+	searchEntry       *shortcutEntry
+	searchQuery       string            // Text the sidebar's conversation titles are narrowed to
+	tagFilter         *fyne.Container   // Tag chips
+	tagFilterBar      *container.Scroll // Scrolls the tag chips; hidden until a conversation is tagged
+	selectedTags      []string          // Tags the sidebar is narrowed to, showing conversations with any of them; empty shows all
+	selectedDay       time.Time         // Day the sidebar is narrowed to from the activity heatmap; zero shows all
+	showArchived      bool              // The sidebar lists archived conversations instead of the others
+	dayFilterBar      *fyne.Container
+	dayFilterLabel    *widget.Label
 	messagesContainer *fyne.Container
+	earlierMessages   *fyne.Container // Row above the shown messages that loads earlier ones
+	earlierBtn        *widget.Button
+	firstShownMessage int               // Index of the first message of the current conversation in the chat
+	emptyState        fyne.CanvasObject // Hints shown in place of the messages of an empty conversation, if any
+	readOnlyBanner    *fyne.Container
+	readOnlyLabel     *widget.Label
+	finishEditBtn     *widget.Button
+
+	// Conversation currently open in an external editor, if any
+	externalEdit *externalEditSession
+
+	// Files attached to the next message
+	pendingAttachments []models.Attachment
+
+	// Earlier conversation the current one continues, shown above the input
+	contextBar     *fyne.Container
+	pendingContext *pendingContext // Summary being generated for a conversation's context, if any
+
+	// Context strategy in the chat bar, kept in step with the one chosen in Settings
+	contextStrategySelect *widget.Select
+
+	// Tools the user allowed to run without asking for the rest of the session
+	toolApprovals *toolApprovals
+
+	// Settings window while it is open
+	settingsWindow  fyne.Window
+	settingsTabs    *container.AppTabs
+	unsubscribeLogs func() // Stops the Logs tab following the log; nil when it isn't shown
+
+	// Notifications posted by background work, shown as toasts over the window or as dialogs
+	notifications *notify.Queue
+	toastLayer    fyne.CanvasObject
+	toastBox      *fyne.Container // Holds the toast currently shown, if any
+	tooltipLayer  *fyne.Container // Holds the tooltip shown over the content, if any
 
 	// Home page components
-	homeContainer    *fyne.Container
-	homeMessageEntry *widget.Entry
-	isHomeMode       bool
+	homeContainer       *fyne.Container
+	homeMessageEntry    *chatEntry
+	homeProviderSelect  *tooltipSelect // Provider a conversation started from the home page uses
+	recentList          *widget.List
+	recentConversations []models.Conversation // Most recently updated conversations, listed on the home page
+	isHomeMode          bool
+
+	// Keyboard shortcuts of the window, in the order the shortcuts dialog lists them
+	shortcuts []appShortcut
 }
 
 // NewChatWindow creates a new chat window instance with the given app and configuration.
 // It initializes the conversation manager, sets up the home page UI, and loads existing conversations.
-// The window starts in home mode, displaying a centered input box for quick message entry.
+// The window reopens the conversation that was open when it was last closed; otherwise it starts
+// in home mode, displaying a centered input box for quick message entry.
 func NewChatWindow(app fyne.App, cfg *config.Config) (*ChatWindow, error) {
 	convManager, err := models.NewConversationManager()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create conversation manager: %w", err)
 	}
 
-	window := app.NewWindow("ChatGo - AI Chatbot")
-	window.Resize(fyne.NewSize(1000, 700))
+	SetLanguage(cfg.Language)
+	window := app.NewWindow(Translate("app.title"))
+	state := loadUIState()
+	window.Resize(state.windowSize())
 
 	mcpManager := NewMCPManagerWrapper()
 
@@ -79,16 +163,86 @@ func NewChatWindow(app fyne.App, cfg *config.Config) (*ChatWindow, error) {
 		mcpManager:  mcpManager,
 		isHomeMode:  true,
 	}
+	cw.applyTheme()
+	cw.toolApprovals = &toolApprovals{allowed: make(map[string]bool)}
+	cw.notifications = notify.NewQueue()
+	cw.toastLayer = cw.newToastLayer()
+	cw.tooltipLayer = newTooltipLayer()
+	cw.startNotifications()
+
+	// Startup MCP initialization progress, shown on both the home page and the chat UI
+	cw.mcpStatusLabel = widget.NewLabel("")
+	cw.mcpStatusLabel.Importance = widget.LowImportance
+	cw.mcpStatusLabel.Hide()
+	cw.mcpActivity = widget.NewActivity()
+	cw.mcpActivity.Hide()
+	cw.mcpStatus = container.NewHBox(cw.mcpActivity, cw.mcpStatusLabel)
+
+	// Close MCP connections (and stop stdio server processes) with the window
+	// An incognito conversation is only discarded with the window once the user agrees
+	window.SetCloseIntercept(func() {
+		cw.confirmDiscardIncognito(window.Close)
+	})
+	window.SetOnClosed(func() {
+		cw.saveWindowState()
+		cw.mcpManager.StopHealthChecks()
+		cw.mcpManager.DisconnectAll()
+		cw.titleQueue.Stop()
+		cw.notifications.Close()
+	})
+
+	// Route MCP HTTP connections through the configured proxy
+	cw.applyProxy()
+
+	if err := applyLogFile(cfg.LogToFile); err != nil {
+		logger.Warn("Not logging to a file", "error", err)
+	}
+
+	// User model metadata takes precedence over the built-in catalog
+	llm.ModelCatalog.SetOverrides(cfg.ModelOverrides)
 
 	// Initialize tool selection manager
 	cw.toolSelectionMgr = NewToolSelectionManager(cfg, mcpManager, window)
+	cw.toolSelectionMgr.SetToolLimit(cw.toolLimit)
+	cw.toolSelectionMgr.SetOnChanged(cw.refreshEmptyState)
+
+	// Conversations deleted long enough ago leave the trash for good
+	go func() {
+		if _, err := convManager.PurgeTrash(models.TrashRetention); err != nil {
+			logger.Warn("Failed to empty the conversation trash", "error", err)
+		}
+	}()
+	cw.startDailyBackup()
 
 	cw.setupHomeUI()
 	cw.loadConversations()
+	cw.registerShortcuts()
+
+	// Saved and deleted conversations update only their rows in the sidebar
+	convManager.Subscribe(func(event models.ConversationEvent) {
+		fyne.Do(func() {
+			cw.applyConversationEvent(event)
+		})
+	})
+
+	// The home page's recent list is reread when the user comes back to the app, as
+	// conversations may have been changed outside it meanwhile
+	app.Lifecycle().SetOnEnteredForeground(func() {
+		if cw.isHomeMode {
+			cw.loadConversations()
+		}
+	})
 
 	// Auto-initialize MCP servers
 	cw.initializeMCPServers()
 
+	// Fill in missing titles and summaries in the background
+	cw.startTitleQueue()
+
+	// Pick up where the last run left off
+	cw.splitOffset = state.splitOffset()
+	cw.restoreConversation(state.LastConversationID)
+
 	return cw, nil
 }
 
@@ -108,60 +262,149 @@ func (cw *ChatWindow) setupUI() {
 			editBtn := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() {})
 			editBtn.Importance = widget.LowImportance
 
+			// Pin toggle button
+			pinBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {})
+			pinBtn.Importance = widget.LowImportance
+
 			// Delete icon button
 			deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {})
 			deleteBtn.Importance = widget.LowImportance
 
-			return container.NewHBox(label, layout.NewSpacer(), editBtn, deleteBtn)
+			// Maintenance actions menu button
+			moreBtn := widget.NewButtonWithIcon("", theme.MoreVerticalIcon(), func() {})
+			moreBtn.Importance = widget.LowImportance
+
+			// Chips of the conversation's tags
+			tags := container.NewHBox()
+
+			return container.NewHBox(label, tags, layout.NewSpacer(), pinBtn, editBtn, deleteBtn, moreBtn)
 		},
 		func(id widget.ListItemID, obj fyne.CanvasObject) {
 			container := obj.(*fyne.Container)
 			objects := container.Objects
 
 			label := objects[0].(*widget.Label)
-			editBtn := objects[2].(*widget.Button)
-			deleteBtn := objects[3].(*widget.Button)
+			tags := objects[1].(*fyne.Container)
+			pinBtn := objects[3].(*widget.Button)
+			editBtn := objects[4].(*widget.Button)
+			deleteBtn := objects[5].(*widget.Button)
+			moreBtn := objects[6].(*widget.Button)
 
 			if id < len(cw.convListData) {
 				// Format title as Chat-YYYYMMDDHHMMSS
 				conv := cw.convListData[id]
 				label.SetText(conv.Title)
+				cw.setRowTagChips(tags, conv.Tags)
+
+				// Set up pin button, highlighted while pinned; redrawn only when that changes
+				pinImportance := widget.LowImportance
+				if conv.Pinned {
+					pinImportance = widget.HighImportance
+				}
+				if pinBtn.Importance != pinImportance {
+					pinBtn.Importance = pinImportance
+					pinBtn.Refresh()
+				}
+				pinBtn.OnTapped = func() {
+					cw.togglePinned(id)
+				}
 
 				// Set up edit button
 				editBtn.OnTapped = func() {
-					cw.editConversationTitle(id)
+					cw.editConversationMetadata(id)
 				}
 
 				// Set up delete button
 				deleteBtn.OnTapped = func() {
 					cw.deleteConversation(id)
 				}
+
+				// Set up maintenance actions menu
+				moreBtn.OnTapped = func() {
+					cw.showConversationMenu(id, moreBtn)
+				}
 			}
 		},
 	)
 	cw.convList.OnSelected = func(id widget.ListItemID) {
+		if cw.syncingSelection {
+			return
+		}
 		if id < len(cw.convListData) {
-			cw.loadConversation(cw.convListData[id].ID)
+			convID := cw.convListData[id].ID
+			cw.confirmDiscardIncognito(func() {
+				cw.loadConversation(convID)
+			})
 		}
 	}
 
-	// New conversation button
-	newConvBtn := widget.NewButton("New Chat", func() {
-		cw.createNewConversation()
+	// New conversation button, and one for a new conversation that continues an earlier one
+	newConvBtn := widget.NewButton(Translate("sidebar.new_chat"), func() {
+		cw.confirmDiscardIncognito(cw.createNewConversation)
+	})
+
+	// Home button, back to the home page
+	homeBtn := widget.NewButtonWithIcon("", theme.HomeIcon(), func() {
+		cw.confirmDiscardIncognito(cw.switchToHome)
 	})
+	continueBtn := widget.NewButtonWithIcon("", theme.MailReplyIcon(), func() {
+		cw.showNewChatDialog()
+	})
+
+	// Tag filter narrowing the conversation list, hidden until a conversation is tagged
+	cw.tagFilter = container.NewHBox()
+	cw.tagFilterBar = container.NewHScroll(cw.tagFilter)
+	cw.updateTagFilter(models.CollectTags(cw.allConversations))
+
+	// Search box narrowing the conversation list by title
+	cw.searchEntry = newShortcutEntry(cw.runShortcut)
+	cw.searchEntry.SetPlaceHolder(Translate("sidebar.search"))
+	cw.searchEntry.SetText(cw.searchQuery)
+	cw.searchEntry.OnChanged = func(query string) {
+		cw.searchQuery = query
+		cw.filterConversations()
+	}
 
 	// Settings button
-	settingsBtn := widget.NewButton("Settings", func() {
+	settingsBtn := widget.NewButton(Translate("sidebar.settings"), func() {
 		cw.showSettings()
 	})
 
+	// About button, next to Settings
+	aboutBtn := widget.NewButtonWithIcon("", theme.InfoIcon(), func() {
+		cw.showAbout()
+	})
+
+	// Activity heatmap button, next to About
+	activityBtn := widget.NewButtonWithIcon("", theme.HistoryIcon(), func() {
+		cw.showActivity()
+	})
+
+	// Keyboard shortcuts button, next to About
+	shortcutsBtn := widget.NewButtonWithIcon("", theme.HelpIcon(), func() {
+		cw.showShortcuts()
+	})
+
 	// Conversation list with scroll
 	convListScroll := container.NewScroll(cw.convList)
 
-	// Sidebar layout: New Chat on top, Settings on bottom, list fills remaining space
+	// Switches the list to archived conversations, where they can be unarchived or deleted
+	showArchivedCheck := widget.NewCheck(Translate("sidebar.show_archived"), func(checked bool) {
+		cw.showArchived = checked
+		cw.filterConversations()
+	})
+	showArchivedCheck.Checked = cw.showArchived
+
+	// Sidebar layout: Home, New Chat, search and the filters on top, title progress and Settings on bottom, list fills remaining space
+	sidebarFooter := container.NewVBox(
+		cw.newTitleProgressFooter(),
+		showArchivedCheck,
+		container.NewBorder(nil, nil, nil, container.NewHBox(activityBtn, shortcutsBtn, aboutBtn), settingsBtn),
+	)
+	sidebarHeader := container.NewVBox(container.NewBorder(nil, nil, homeBtn, continueBtn, newConvBtn), cw.searchEntry, cw.tagFilterBar, cw.newDayFilterBar())
 	sidebar := container.NewBorder(
-		newConvBtn,     // Top
-		settingsBtn,    // Bottom
+		sidebarHeader,  // Top
+		sidebarFooter,  // Bottom
 		nil,            // Left
 		nil,            // Right
 		convListScroll, // Center (fills remaining space)
@@ -169,96 +412,147 @@ func (cw *ChatWindow) setupUI() {
 
 	// Chat area
 	cw.messagesContainer = container.NewVBox()
+	cw.earlierBtn = widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+		cw.loadEarlierMessages()
+	})
+	cw.earlierBtn.Importance = widget.LowImportance
+	cw.earlierMessages = container.NewCenter(cw.earlierBtn)
 	cw.chatArea = container.NewScroll(cw.messagesContainer)
 	cw.chatArea.SetMinSize(fyne.NewSize(600, 400))
 	// Disable horizontal scrolling
 	cw.chatArea.Direction = container.ScrollVerticalOnly
 
-	// Provider selector (placed above input area)
-	providerNames := make([]string, len(cw.config.Providers))
-	for i, p := range cw.config.Providers {
-		providerNames[i] = p.Name
-	}
-	cw.providerSelect = widget.NewSelect(providerNames, func(selected string) {
+	// Provider selector (placed above input area); disabled providers are not offered.
+	// Hovering shows the provider's remaining free-tier quota, when it reports one.
+	cw.providerSelect = cw.newTooltipSelect(cw.enabledProviderNames(), func(selected string) {
 		cw.switchProvider(selected)
+	}, func() string {
+		return providerQuotaText(cw.providerSelect.Selected)
 	})
-	cw.providerSelect.SetSelected(cw.config.CurrentProvider)
+	cw.selectProvider(cw.config.CurrentProvider)
 
 	// Initialize tool selection manager
 	toolCheckGroup := cw.toolSelectionMgr.LoadToolCheckGroup()
 	cw.toolSelectionMgr.SetCheckGroup(toolCheckGroup)
 
 	// Tool selection button
-	cw.toolSelectBtn = widget.NewButton("选择工具 (0)", func() {
+	cw.toolSelectBtn = widget.NewButton(Translatef("tools.select_count", 0), func() {
 		cw.toolSelectionMgr.ShowToolSelectionDialog()
 	})
 	cw.toolSelectionMgr.SetButton(cw.toolSelectBtn)
 
 	// Message entry
-	cw.messageEntry = widget.NewMultiLineEntry()
-	cw.messageEntry.SetPlaceHolder("Type your message here...")
-	cw.messageEntry.OnSubmitted = func(text string) {
+	cw.messageEntry = newChatEntry(func() bool { return cw.config.SendOnEnter })
+	cw.messageEntry.SetPlaceHolder(Translate("chat.message_placeholder"))
+	cw.messageEntry.OnSend = func() {
 		cw.sendMessage()
 	}
+	cw.messageEntry.OnPaste = cw.transcriptPasteHandler(cw.messageEntry)
+	cw.messageEntry.OnShortcut = cw.runShortcut
+	cw.messageEntry.OnChanged = func(string) {
+		cw.updateDraftStats()
+	}
 
 	// Send button
-	cw.sendButton = widget.NewButton("Send", func() {
+	cw.sendButton = widget.NewButton(Translate("chat.send"), func() {
 		cw.sendMessage()
 	})
 
+	// Attach button, for sending text files as context with the next message
+	cw.attachButton = widget.NewButtonWithIcon("", theme.MailAttachmentIcon(), func() {
+		cw.showAttachFileDialog()
+	})
+	// Attach image button, for models that accept images
+	cw.attachImageButton = widget.NewButtonWithIcon("", theme.FileImageIcon(), func() {
+		cw.showAttachImageDialog()
+	})
+	cw.attachmentBar = container.NewHBox()
+	cw.attachmentBar.Hide()
+
+	cw.contextStrategySelect = cw.newContextStrategySelect()
+
 	// Provider and tool bar (above input)
 	providerToolBar := container.NewHBox(
-		widget.NewLabel("Model:"),
+		widget.NewLabel(Translate("chat.model")),
 		cw.providerSelect,
 		widget.NewSeparator(),
-		widget.NewLabel("Tools:"),
+		widget.NewLabel(Translate("chat.tools")),
 		cw.toolSelectBtn,
+		widget.NewSeparator(),
+		widget.NewLabel(Translate("chat.context")),
+		cw.contextStrategySelect,
+		layout.NewSpacer(),
+		cw.mcpStatus,
 	)
 
 	// Input area
-	inputArea := container.NewBorder(nil, nil, nil, cw.sendButton, cw.messageEntry)
+	inputArea := container.NewBorder(nil, nil, container.NewHBox(cw.attachButton, cw.attachImageButton), cw.sendButton, cw.messageEntry)
 	inputAreaContainer := container.NewVBox(
 		widget.NewSeparator(),
 		providerToolBar,
+		cw.newContextBar(),
+		cw.attachmentBar,
 		inputArea,
+		cw.newDraftStatsLabel(),
 	)
 
+	// Read-only banner, shown for conversations saved by a newer version
+	// or currently open in an external editor
+	cw.readOnlyLabel = widget.NewLabel("")
+	cw.readOnlyLabel.Importance = widget.WarningImportance
+	cw.readOnlyLabel.Wrapping = fyne.TextWrapWord
+	schemaInfoBtn := widget.NewButtonWithIcon("", theme.InfoIcon(), func() {
+		cw.showSchemaChangelog()
+	})
+	schemaInfoBtn.Importance = widget.LowImportance
+	cw.finishEditBtn = widget.NewButton(Translate("chat.finish_external_edit"), func() {
+		cw.finishExternalEdit()
+	})
+	cw.readOnlyBanner = container.NewBorder(nil, widget.NewSeparator(), nil,
+		container.NewHBox(cw.finishEditBtn, schemaInfoBtn), cw.readOnlyLabel)
+	cw.readOnlyBanner.Hide()
+
 	// Main layout
 	mainContent := container.NewBorder(
-		nil,
+		cw.readOnlyBanner,
 		inputAreaContainer,
 		nil,
 		nil,
 		cw.chatArea,
 	)
 
-	split := container.NewHSplit(
+	cw.split = container.NewHSplit(
 		sidebar,
 		mainContent,
 	)
-	split.SetOffset(0.25)
+	cw.split.SetOffset(cw.splitOffset)
 
-	cw.window.SetContent(split)
+	cw.setWindowContent(cw.split)
 }
 
-// loadConversations loads all conversations from the database and refreshes the UI list.
+// loadConversations loads all conversations, archived ones included, from the database and
+// refreshes the sidebar and the home page's recent conversations.
 // Safe to call in home mode as it checks if convList is initialized.
-// For home mode, only shows the 5 most recent conversations.
 func (cw *ChatWindow) loadConversations() {
-	conversations, err := cw.convManager.ListConversations()
+	conversations, err := cw.convManager.ListConversations(models.ListOptions{IncludeArchived: true})
 	if err != nil {
 		return
 	}
 
-	// Sort conversations by last message time (most recent first)
-	// We need to sort based on the last message timestamp
-	for i := 0; i < len(conversations); i++ {
-		for j := i + 1; j < len(conversations); j++ {
-			timeI := getConversationLastTime(conversations[i])
-			timeJ := getConversationLastTime(conversations[j])
-			if timeI.Before(timeJ) {
-				conversations[i], conversations[j] = conversations[j], conversations[i]
-			}
+	sortConversations(conversations)
+	cw.allConversations = conversations
+	cw.updateRecentConversations()
+	cw.filterConversations()
+}
+
+// filterConversations narrows the sidebar to the search, selected tag and day and refreshes it
+func (cw *ChatWindow) filterConversations() {
+	cw.updateTagFilter(models.CollectTags(cw.allConversations))
+
+	conversations := make([]models.Conversation, 0, len(cw.allConversations))
+	for _, conv := range cw.allConversations {
+		if cw.conversationVisible(conv) {
+			conversations = append(conversations, conv)
 		}
 	}
 
@@ -266,7 +560,163 @@ func (cw *ChatWindow) loadConversations() {
 	// Only refresh if convList is initialized (not in home mode)
 	if cw.convList != nil {
 		cw.convList.Refresh()
+		cw.syncConversationSelection()
+	}
+}
+
+// conversationVisible reports whether a conversation passes the sidebar's search, tag and day
+// filters and is archived exactly when archived conversations are shown
+func (cw *ChatWindow) conversationVisible(conv models.Conversation) bool {
+	if conv.Archived != cw.showArchived {
+		return false
+	}
+	// Titles are matched ignoring case
+	if query := strings.TrimSpace(cw.searchQuery); query != "" && !strings.Contains(strings.ToLower(conv.Title), strings.ToLower(query)) {
+		return false
+	}
+	if len(cw.selectedTags) > 0 && !conv.HasAnyTag(cw.selectedTags) {
+		return false
+	}
+	// The day picked in the activity heatmap
+	if !cw.selectedDay.IsZero() && !activeOn(conv, cw.selectedDay) {
+		return false
+	}
+	return true
+}
+
+// sortConversations orders pinned conversations first, then the most recently updated
+func sortConversations(conversations []models.Conversation) {
+	sort.SliceStable(conversations, func(i, j int) bool {
+		return conversationBefore(conversations[i], conversations[j])
+	})
+}
+
+// conversationBefore reports whether a is listed above b: pinned first, then the most recently updated
+func conversationBefore(a, b models.Conversation) bool {
+	if a.Pinned != b.Pinned {
+		return a.Pinned
+	}
+	return a.UpdatedAt.After(b.UpdatedAt)
+}
+
+// updateTagFilter shows a chip for each of the given tags in the sidebar's tag filter. Selected
+// tags no longer used by any conversation are dropped from the selection.
+func (cw *ChatWindow) updateTagFilter(tags []string) {
+	selected := cw.selectedTags[:0]
+	for _, tag := range cw.selectedTags {
+		if slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			selected = append(selected, tag)
+		}
+	}
+	cw.selectedTags = selected
+
+	if cw.tagFilter == nil {
+		return
+	}
+	cw.tagFilter.RemoveAll()
+	if len(tags) == 0 {
+		cw.tagFilterBar.Hide()
+		return
+	}
+
+	allBtn := widget.NewButton(Translate("sidebar.all_tags"), func() {
+		cw.selectedTags = nil
+		cw.filterConversations()
+	})
+	allBtn.Importance = tagChipImportance(len(cw.selectedTags) == 0)
+	cw.tagFilter.Add(allBtn)
+	for _, tag := range tags {
+		chip := widget.NewButton("#"+tag, func() {
+			cw.toggleTagFilter(tag)
+		})
+		chip.Importance = tagChipImportance(slices.ContainsFunc(cw.selectedTags, func(t string) bool { return strings.EqualFold(t, tag) }))
+		cw.tagFilter.Add(chip)
+	}
+	cw.tagFilterBar.Show()
+}
+
+// tagChipImportance highlights a selected tag chip
+func tagChipImportance(selected bool) widget.Importance {
+	if selected {
+		return widget.HighImportance
+	}
+	return widget.LowImportance
+}
+
+// toggleTagFilter adds tag to the tags the sidebar is narrowed to, or removes it if selected
+func (cw *ChatWindow) toggleTagFilter(tag string) {
+	i := slices.IndexFunc(cw.selectedTags, func(t string) bool { return strings.EqualFold(t, tag) })
+	if i >= 0 {
+		cw.selectedTags = slices.Delete(slices.Clone(cw.selectedTags), i, i+1)
+	} else {
+		cw.selectedTags = append(slices.Clone(cw.selectedTags), tag)
 	}
+	cw.filterConversations()
+}
+
+// maxRowTagChips is how many tag chips a sidebar row shows before summing up the rest
+const maxRowTagChips = 2
+
+// setRowTagChips shows a conversation's tags as chips in its sidebar row. Tapping one narrows
+// the sidebar to the tag, or widens it again, like the chips of the tag filter. A recycled row's
+// chips are reused, as creating new ones costs more than the rest of the row.
+func (cw *ChatWindow) setRowTagChips(row *fyne.Container, tags []string) {
+	shown := tags[:min(len(tags), maxRowTagChips)]
+	chips := make([]fyne.CanvasObject, 0, len(shown)+1)
+	var more *widget.Label
+	buttons := 0
+	for _, object := range row.Objects {
+		switch object := object.(type) {
+		case *widget.Button:
+			buttons++
+			if len(chips) < len(shown) {
+				chips = append(chips, object)
+			}
+		case *widget.Label:
+			more = object
+		}
+	}
+	changed := buttons != len(shown)
+	for len(chips) < len(shown) {
+		chips = append(chips, widget.NewButton("", nil))
+	}
+
+	for i, tag := range shown {
+		chip := chips[i].(*widget.Button)
+		chip.OnTapped = func() {
+			cw.toggleTagFilter(tag)
+		}
+		importance := tagChipImportance(slices.ContainsFunc(cw.selectedTags, func(t string) bool { return strings.EqualFold(t, tag) }))
+		if chip.Text != "#"+tag || chip.Importance != importance {
+			chip.Text = "#" + tag
+			chip.Importance = importance
+			chip.Refresh()
+		}
+	}
+	if rest := len(tags) - maxRowTagChips; rest > 0 {
+		if more == nil {
+			more = widget.NewLabel("")
+			more.Importance = widget.LowImportance
+			changed = true
+		}
+		more.SetText(fmt.Sprintf("+%d", rest))
+		chips = append(chips, more)
+	} else {
+		changed = changed || more != nil
+	}
+
+	if changed {
+		row.Objects = chips
+		row.Refresh()
+	}
+}
+
+// conversationListTitle is a conversation's title followed by its tags
+func conversationListTitle(conv models.Conversation) string {
+	if len(conv.Tags) == 0 {
+		return conv.Title
+	}
+	return conv.Title + "  #" + strings.Join(conv.Tags, " #")
 }
 
 // loadConversation loads a specific conversation by ID and displays its messages.
@@ -275,36 +725,124 @@ func (cw *ChatWindow) loadConversation(id string) {
 	if err != nil {
 		return
 	}
+	cw.openConversation(conv)
+}
+
+// closeConversation empties the chat area, leaving no conversation open
+func (cw *ChatWindow) closeConversation() {
+	cw.currentConversation = nil
+	cw.titleQueue.SetActiveConversation("")
+	cw.emptyState = nil
+	cw.messagesContainer.Objects = nil
+	cw.messagesContainer.Refresh()
+	cw.updateReadOnlyState()
+	cw.updateContextBar()
+}
 
+// openConversation shows a loaded conversation in the chat area
+func (cw *ChatWindow) openConversation(conv *models.Conversation) {
 	cw.currentConversation = conv
+	cw.titleQueue.SetActiveConversation(conv.ID)
 	cw.setupCurrentProvider()
+	cw.updateReadOnlyState()
+	cw.updateContextBar()
 
-	// Clear messages
-	cw.messagesContainer.Objects = nil
+	// Show the latest messages; earlier ones are rendered on request
+	cw.showConversationMessages()
 
-	// Load messages
-	for _, msg := range conv.Messages {
-		cw.addMessageToUI(msg)
+	cw.chatArea.ScrollToBottom()
+}
+
+// updateReadOnlyState shows the read-only banner and locks the input when the current
+// conversation was written by a newer version of the app or is open in an external editor
+func (cw *ChatWindow) updateReadOnlyState() {
+	if cw.readOnlyBanner == nil {
+		return
 	}
 
-	cw.chatArea.ScrollToBottom()
+	readOnly := false
+	if cw.currentConversation != nil {
+		if cw.currentConversation.ReadOnly() {
+			readOnly = true
+			cw.readOnlyLabel.SetText(Translate("chat.read_only_newer"))
+			cw.finishEditBtn.Hide()
+		} else if cw.externalEdit != nil && cw.externalEdit.convID == cw.currentConversation.ID {
+			readOnly = true
+			cw.readOnlyLabel.SetText(Translate("chat.read_only_external"))
+			cw.finishEditBtn.Show()
+		}
+	}
+
+	if readOnly {
+		cw.readOnlyBanner.Show()
+		cw.messageEntry.Disable()
+		cw.sendButton.Disable()
+		cw.attachButton.Disable()
+		cw.attachImageButton.Disable()
+	} else {
+		cw.readOnlyBanner.Hide()
+		cw.messageEntry.Enable()
+		cw.sendButton.Enable()
+		cw.attachButton.Enable()
+		cw.attachImageButton.Enable()
+	}
+}
+
+// showSchemaChangelog displays the conversation file format versions known to this build
+func (cw *ChatWindow) showSchemaChangelog() {
+	lines := make([]string, 0, len(models.SchemaChangelog)+2)
+	for _, change := range models.SchemaChangelog {
+		lines = append(lines, fmt.Sprintf("v%d: %s", change.Version, change.Description))
+	}
+	if cw.currentConversation != nil {
+		lines = append(lines, "", Translatef("schema.versions_in_use",
+			models.CurrentSchemaVersion, cw.currentConversation.SchemaVersion))
+	}
+
+	dialog.ShowInformation(Translate("schema.title"), strings.Join(lines, "\n"), cw.window)
+}
+
+// clientOptions returns the LLM client options derived from the current configuration.
+// An invalid proxy is reported and ignored so chats still work without it.
+func (cw *ChatWindow) clientOptions() llm.ClientOptions {
+	httpClient, err := httpclient.New(cw.config.Proxy)
+	if err != nil {
+		logger.Warn("Ignoring proxy configuration", "error", err)
+		return llm.ClientOptions{}
+	}
+	return llm.ClientOptions{HTTPClient: httpClient}
+}
+
+// applyProxy updates the MCP manager to use the configured proxy for new connections
+func (cw *ChatWindow) applyProxy() {
+	cw.mcpManager.SetHTTPClient(cw.clientOptions().HTTPClient)
 }
 
 func (cw *ChatWindow) setupCurrentProvider() {
 	if cw.currentConversation == nil {
 		return
 	}
+	// The tool limit shown on the tools button, the draft's cost and the empty state depend on
+	// the provider
+	defer cw.toolSelectionMgr.RefreshButton()
+	defer cw.updateDraftStats()
 
 	// Find provider
 	for _, p := range cw.config.Providers {
 		if p.Name == cw.currentConversation.Provider {
-			// Check if React Agent is enabled
-			if cw.config.UseReactAgent {
+			// Check if React Agent is enabled. Models the catalog knows can't call tools use the
+			// regular client; unknown models are given the benefit of the doubt.
+			useAgent := cw.config.UseReactAgent
+			if info, ok := llm.ModelCatalog.ForProvider(p); useAgent && ok && !info.ToolCalling {
+				logger.Debug("Model doesn't support tool calling; using the regular client", "provider", p.Name, "model", p.Model)
+				useAgent = false
+			}
+			if useAgent {
 				err := cw.setupReactAgent(p)
 				if err != nil {
-					fmt.Printf("Failed to setup React Agent: %v\n", err)
+					logger.Warn("Failed to set up the agent; using the regular client", "provider", p.Name, "error", err)
 					// Fallback to regular client
-					client, err := llm.NewClient(p)
+					client, err := llm.NewClient(p, cw.clientOptions())
 					if err != nil {
 						return
 					}
@@ -313,7 +851,7 @@ func (cw *ChatWindow) setupCurrentProvider() {
 				}
 			} else {
 				// Use regular client
-				client, err := llm.NewClient(p)
+				client, err := llm.NewClient(p, cw.clientOptions())
 				if err != nil {
 					return
 				}
@@ -329,15 +867,10 @@ func (cw *ChatWindow) setupCurrentProvider() {
 func (cw *ChatWindow) setupReactAgent(provider config.Provider) error {
 	ctx := context.Background()
 
-	fmt.Printf("[React Agent] ============================================\n")
-	fmt.Printf("[React Agent] Setting up React Agent for provider: %s\n", provider.Name)
-
 	// Get selected tools
 	selectedTools := cw.toolSelectionMgr.GetSelectedTools()
-	fmt.Printf("[React Agent] Selected tools: %d\n", len(selectedTools))
-	for i, tool := range selectedTools {
-		fmt.Printf("[React Agent]   [%d] %s\n", i+1, tool)
-	}
+	cw.agentTools = toolSelectionKey(selectedTools)
+	logger.Debug("Setting up agent", "provider", provider.Name, "tools", selectedTools)
 
 	// Collect all Eino tools (both builtin and MCP)
 	einoTools := make([]tool.BaseTool, 0)
@@ -349,18 +882,15 @@ func (cw *ChatWindow) setupReactAgent(provider config.Provider) error {
 
 	for _, toolID := range selectedTools {
 		if strings.HasPrefix(toolID, "builtin:") {
-			// Handle builtin tools - create custom tool definitions
+			// Handle builtin tools - build the Eino tool for the tool's type
 			toolName := strings.TrimPrefix(toolID, "builtin:")
-			def, err := cw.createBuiltinToolDefinition(toolName)
+			builtinTool, err := cw.buildBuiltinTool(toolName)
 			if err != nil {
-				fmt.Printf("[React Agent] Warning: failed to create tool definition for %s: %v\n", toolName, err)
+				logger.Warn("Failed to create built-in tool", "tool", toolName, "error", err)
 				continue
 			}
-			// Wrap as Eino tool
-			wrappedTool := newBuiltinToolWrapper(def)
-			einoTools = append(einoTools, wrappedTool)
+			einoTools = append(einoTools, builtinTool)
 			builtinCount++
-			fmt.Printf("[React Agent] Added builtin tool: %s - %s\n", toolName, def.Description)
 
 		} else if strings.HasPrefix(toolID, "mcp:") {
 			// Collect MCP tool names for batch processing
@@ -375,21 +905,21 @@ func (cw *ChatWindow) setupReactAgent(provider config.Provider) error {
 
 	// Get MCP tools using Eino's mcp.GetTools() for each server
 	for serverName, toolNames := range mcpToolsByServer {
-		status, ok := cw.mcpManager.GetServerStatus(serverName)
-		if !ok || status.Status != "initialized" {
-			fmt.Printf("[React Agent] Warning: MCP server %s not initialized, skipping %d tools\n",
-				serverName, len(toolNames))
+		// The tool client bounds each call by the server's configured timeout
+		toolClient, ok := cw.mcpManager.ToolClient(serverName)
+		if !ok {
+			logger.Warn("MCP server not initialized; skipping its tools", "server", serverName, "tools", len(toolNames))
 			continue
 		}
 
 		// Use Eino's mcp.GetTools() to get properly formatted tools
 		mcpTools, err := einomcp.GetTools(ctx, &einomcp.Config{
-			Cli:          status.Client,
+			Cli:          toolClient,
 			ToolNameList: toolNames,
 		})
 
 		if err != nil {
-			fmt.Printf("[React Agent] Warning: failed to get MCP tools from %s: %v\n", serverName, err)
+			logger.Warn("Failed to get MCP tools", "server", serverName, "error", err)
 			continue
 		}
 
@@ -397,26 +927,19 @@ func (cw *ChatWindow) setupReactAgent(provider config.Provider) error {
 		for _, mcpTool := range mcpTools {
 			einoTools = append(einoTools, mcpTool)
 			mcpCount++
-			info, _ := mcpTool.Info(ctx)
-			fmt.Printf("[React Agent] Added MCP tool: %s:%s - %s\n", serverName, info.Name, info.Desc)
 		}
 	}
 
-	fmt.Printf("[React Agent] Successfully loaded %d builtin tools and %d MCP tools (total: %d)\n",
-		builtinCount, mcpCount, len(einoTools))
-
-	if len(einoTools) == 0 {
-		fmt.Println("[React Agent] Warning: No tools loaded. Agent will run without tools.")
-	}
+	logger.Debug("Agent tools loaded", "builtin", builtinCount, "mcp", mcpCount)
 
 	// Create React Agent config
 	agentConfig := &llm.ReactAgentConfig{
-		MaxStep:      cw.config.ReactAgentMaxStep,
+		MaxStep:      cw.config.ReactAgentSteps(),
 		SystemPrompt: "You are a helpful AI assistant with access to various tools. Use tools when appropriate to help answer questions. When you use a tool, carefully consider the required parameters and provide accurate values.",
 	}
 
 	// Create React Client with Eino tools directly
-	reactClient, err := llm.NewReactClientWithEinoTools(provider, einoTools, agentConfig)
+	reactClient, err := llm.NewReactClientWithEinoTools(provider, cw.clientOptions(), einoTools, agentConfig)
 	if err != nil {
 		return fmt.Errorf("failed to create React client: %w", err)
 	}
@@ -424,95 +947,44 @@ func (cw *ChatWindow) setupReactAgent(provider config.Provider) error {
 	cw.reactClient = reactClient
 	cw.llmClient = nil
 
-	fmt.Printf("[React Agent] Successfully initialized React Agent with max_step=%d\n", cw.config.ReactAgentMaxStep)
+	logger.Debug("Agent set up", "provider", provider.Name, "max_step", cw.config.ReactAgentSteps())
 	return nil
 }
 
-// createBuiltinToolDefinition creates a tool definition for a builtin tool
-func (cw *ChatWindow) createBuiltinToolDefinition(toolName string) (llm.ToolDefinition, error) {
-	// Find the tool in config
-	var builtinTool *config.BuiltinTool
-	for i, t := range cw.config.BuiltinTools {
+// buildBuiltinTool creates the Eino tool for an enabled builtin tool by its configured name
+func (cw *ChatWindow) buildBuiltinTool(toolName string) (tool.BaseTool, error) {
+	for _, t := range cw.config.BuiltinTools {
 		if t.Name == toolName && t.Enabled {
-			builtinTool = &cw.config.BuiltinTools[i]
-			break
+			return llm.BuildEinoTool(t, cw.clientOptions())
 		}
 	}
+	return nil, fmt.Errorf("builtin tool %s not found or not enabled", toolName)
+}
 
-	if builtinTool == nil {
-		return llm.ToolDefinition{}, fmt.Errorf("builtin tool %s not found or not enabled", toolName)
-	}
-
-	def := llm.ToolDefinition{
-		Name:        builtinTool.Name,
-		Description: config.GetBuiltinToolDescription(builtinTool.Type),
-		Parameters:  make(map[string]*schema.ParameterInfo),
-	}
-
-	// Build parameter info based on tool type
-	requiredFields := config.GetRequiredConfigFields(builtinTool.Type)
-	configFields := config.GetBuiltinToolConfigFields(builtinTool.Type)
-
-	for _, field := range configFields {
-		required := false
-		for _, reqField := range requiredFields {
-			if field == reqField {
-				required = true
-				break
-			}
-		}
-
-		def.Parameters[field] = &schema.ParameterInfo{
-			Type:     schema.String,
-			Desc:     fmt.Sprintf("%s parameter for %s tool", field, builtinTool.Name),
-			Required: required,
+// enabledProviderNames returns the names of the providers that can be chosen for a conversation
+func (cw *ChatWindow) enabledProviderNames() []string {
+	var names []string
+	for _, p := range cw.config.Providers {
+		if p.Enabled {
+			names = append(names, p.Name)
 		}
 	}
-
-	// Implement actual tool handler for builtin tools
-	// For now, return a placeholder handler
-	def.Handler = func(ctx context.Context, arguments string) (string, error) {
-		fmt.Printf("[Tool Execution] Executing builtin tool: %s with args: %s\n", toolName, arguments)
-
-		// TODO: Implement actual tool execution logic
-		// For now, return a simulated response
-		return fmt.Sprintf("Tool %s executed successfully with args: %s\n\n(Note: Actual tool execution not yet implemented)", toolName, arguments), nil
-	}
-
-	return def, nil
+	return names
 }
 
-// newBuiltinToolWrapper creates an Eino tool wrapper for builtin tools
-func newBuiltinToolWrapper(def llm.ToolDefinition) tool.BaseTool {
-	return &builtinToolWrapper{
-		info: &schema.ToolInfo{
-			Name:        def.Name,
-			Desc:        def.Description,
-			ParamsOneOf: schema.NewParamsOneOfByParams(def.Parameters),
-		},
-		handler: def.Handler,
+// selectProvider selects the named provider in the provider selector, or the first enabled
+// provider when it is disabled or no longer configured
+func (cw *ChatWindow) selectProvider(name string) {
+	options := cw.providerSelect.Options
+	if !slices.Contains(options, name) {
+		if len(options) == 0 {
+			// Nothing can be chosen; clear the selection without switching provider
+			cw.providerSelect.Selected = ""
+			return
+		}
+		name = options[0]
 	}
-}
-
-// builtinToolWrapper wraps a builtin tool as an Eino InvokableTool
-type builtinToolWrapper struct {
-	info    *schema.ToolInfo
-	handler func(ctx context.Context, arguments string) (string, error)
-}
-
-// Info returns the tool information
-func (w *builtinToolWrapper) Info(ctx context.Context) (*schema.ToolInfo, error) {
-	return w.info, nil
-}
-
-// InvokableRun executes the tool (required by InvokableTool interface)
-func (w *builtinToolWrapper) InvokableRun(ctx context.Context, arguments string) (string, error) {
-	return w.handler(ctx, arguments)
-}
-
-// StreamableRun executes the tool with streaming (optional, returns not supported)
-func (w *builtinToolWrapper) StreamableRun(ctx context.Context, arguments string) (*schema.StreamReader[string], error) {
-	return nil, fmt.Errorf("streaming not supported for this tool")
+	cw.providerSelect.SetSelected(name)
 }
 
 func (cw *ChatWindow) switchProvider(providerName string) {
@@ -525,7 +997,7 @@ func (cw *ChatWindow) switchProvider(providerName string) {
 		for _, p := range cw.config.Providers {
 			if p.Name == providerName {
 				cw.currentConversation.Model = p.Model
-				client, err := llm.NewClient(p)
+				client, err := llm.NewClient(p, cw.clientOptions())
 				if err == nil {
 					cw.llmClient = client
 				}
@@ -533,13 +1005,19 @@ func (cw *ChatWindow) switchProvider(providerName string) {
 			}
 		}
 
-		cw.convManager.SaveConversation(cw.currentConversation)
+		cw.saveConversation(cw.currentConversation)
 	}
 
 	config.SaveConfig(cw.config)
 }
 
 func (cw *ChatWindow) createNewConversation() {
+	cw.newConversation(false)
+}
+
+// newConversation opens a new conversation with the selected provider. An incognito one is
+// kept in memory only and discarded when closed.
+func (cw *ChatWindow) newConversation(incognito bool) {
 	providerName := cw.providerSelect.Selected
 	model := ""
 
@@ -553,70 +1031,133 @@ func (cw *ChatWindow) createNewConversation() {
 	// Format: Chat-YYYYMMDDHHMMSS
 	title := fmt.Sprintf("Chat-%s", time.Now().Format("20060102150405"))
 
-	conv, err := cw.convManager.CreateConversation(
-		title,
-		providerName,
-		model,
-	)
-	if err != nil {
-		return
+	var conv *models.Conversation
+	if incognito {
+		conv = cw.convManager.NewIncognitoConversation(title, providerName, model)
+	} else {
+		var err error
+		conv, err = cw.convManager.CreateConversation(
+			title,
+			providerName,
+			model,
+		)
+		if err != nil {
+			return
+		}
 	}
 
 	cw.currentConversation = conv
+	cw.titleQueue.SetActiveConversation(conv.ID)
 	cw.setupCurrentProvider()
-	cw.loadConversations()
+	cw.updateReadOnlyState()
+	cw.updateContextBar()
 
 	// Clear messages
-	cw.messagesContainer.Objects = nil
-	cw.messagesContainer.Refresh()
+	cw.showConversationMessages()
 }
 
-func (cw *ChatWindow) editConversationTitle(id widget.ListItemID) {
+// editConversationMetadata edits the title and tags of a conversation in the sidebar
+func (cw *ChatWindow) editConversationMetadata(id widget.ListItemID) {
 	if id < 0 || id >= len(cw.convListData) {
 		return
 	}
 
+	// Edit the open conversation itself when it is the one in the list, so a later
+	// save of the open conversation doesn't undo the change
 	conv := &cw.convListData[id]
+	if cw.currentConversation != nil && cw.currentConversation.ID == conv.ID {
+		conv = cw.currentConversation
+	}
 
 	// Create entry for editing title
 	entry := widget.NewEntry()
 	entry.SetText(conv.Title)
-	entry.SetPlaceHolder("Enter new title")
+	entry.SetPlaceHolder(Translate("conversation.title_placeholder"))
+
+	// Tags as a comma separated list
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetText(strings.Join(conv.Tags, ", "))
+	tagsEntry.SetPlaceHolder(Translate("conversation.tags_placeholder"))
 
 	// Create form
 	form := container.NewVBox(
-		widget.NewLabel("Edit Conversation Title"),
+		widget.NewLabel(Translate("conversation.edit")),
 		widget.NewSeparator(),
+		widget.NewLabel(Translate("conversation.title")),
 		entry,
+		widget.NewLabel(Translate("conversation.tags")),
+		tagsEntry,
 	)
 
 	// Show dialog
-	d := dialog.NewCustomConfirm("Edit Title", "Save", "Cancel", form, func(save bool) {
-		if save && entry.Text != "" {
-			// Update title
-			conv.Title = entry.Text
+	d := dialog.NewCustomConfirm(Translate("conversation.edit"), Translate("common.save"), Translate("common.cancel"), form, func(save bool) {
+		if !save || entry.Text == "" {
+			return
+		}
 
-			// Save to database
-			err := cw.convManager.SaveConversation(conv)
-			if err != nil {
-				dialog.ShowError(fmt.Errorf("failed to save title: %w", err), cw.window)
-				return
-			}
+		oldTitle, oldTags := conv.Title, conv.Tags
+		conv.Title = entry.Text
+		conv.Tags = models.NormalizeTags(strings.Split(tagsEntry.Text, ","))
 
-			// Refresh list
-			cw.convList.Refresh()
+		// Save to database
+		if err := cw.convManager.SaveConversation(conv); err != nil {
+			conv.Title, conv.Tags = oldTitle, oldTags
+			dialog.ShowError(fmt.Errorf("failed to save conversation: %w", err), cw.window)
+			return
+		}
 
-			// If this is the current conversation, update window title
-			if cw.currentConversation != nil && cw.currentConversation.ID == conv.ID {
-				cw.window.SetTitle(fmt.Sprintf("ChatGo - %s", conv.Title))
-			}
+		// If this is the current conversation, update window title
+		if cw.currentConversation != nil && cw.currentConversation.ID == conv.ID {
+			cw.window.SetTitle(fmt.Sprintf("ChatGo - %s", conv.Title))
 		}
 	}, cw.window)
 
-	d.Resize(fyne.NewSize(400, 200))
+	d.Resize(fyne.NewSize(400, 280))
 	d.Show()
 }
 
+// togglePinned pins or unpins a conversation and re-sorts the sidebar
+func (cw *ChatWindow) togglePinned(id widget.ListItemID) {
+	if id < 0 || id >= len(cw.convListData) {
+		return
+	}
+
+	// Save through the open conversation when it is the one being pinned, so a later
+	// save of the open conversation doesn't undo the change
+	conv := &cw.convListData[id]
+	if cw.currentConversation != nil && cw.currentConversation.ID == conv.ID {
+		conv = cw.currentConversation
+	}
+	conv.Pinned = !conv.Pinned
+
+	if err := cw.convManager.SaveConversation(conv); err != nil {
+		conv.Pinned = !conv.Pinned
+		dialog.ShowError(fmt.Errorf("failed to save conversation: %w", err), cw.window)
+	}
+}
+
+// toggleArchived archives a conversation, hiding it from the sidebar unless archived
+// conversations are shown, or unarchives it
+func (cw *ChatWindow) toggleArchived(id widget.ListItemID) {
+	if id < 0 || id >= len(cw.convListData) {
+		return
+	}
+
+	// Save through the open conversation when it is the one being archived, so a later
+	// save of the open conversation doesn't undo the change
+	conv := &cw.convListData[id]
+	if cw.currentConversation != nil && cw.currentConversation.ID == conv.ID {
+		conv = cw.currentConversation
+	}
+	conv.Archived = !conv.Archived
+
+	if err := cw.convManager.SaveConversation(conv); err != nil {
+		conv.Archived = !conv.Archived
+		dialog.ShowError(fmt.Errorf("failed to save conversation: %w", err), cw.window)
+	}
+}
+
+// deleteConversation moves a conversation to the trash after confirmation
 func (cw *ChatWindow) deleteConversation(id widget.ListItemID) {
 	if id < 0 || id >= len(cw.convListData) {
 		return
@@ -626,8 +1167,8 @@ func (cw *ChatWindow) deleteConversation(id widget.ListItemID) {
 
 	// Show confirmation dialog
 	dialog.ShowConfirm(
-		"Delete Conversation",
-		fmt.Sprintf("Are you sure you want to delete '%s'?", conv.Title),
+		Translate("conversation.delete"),
+		Translatef("conversation.delete_confirm", conv.Title),
 		func(confirmed bool) {
 			if confirmed {
 				// Delete from database
@@ -639,13 +1180,8 @@ func (cw *ChatWindow) deleteConversation(id widget.ListItemID) {
 
 				// If this is the current conversation, clear it
 				if cw.currentConversation != nil && cw.currentConversation.ID == conv.ID {
-					cw.currentConversation = nil
-					cw.messagesContainer.Objects = nil
-					cw.messagesContainer.Refresh()
+					cw.closeConversation()
 				}
-
-				// Reload list
-				cw.loadConversations()
 			}
 		},
 		cw.window,
@@ -657,210 +1193,490 @@ func (cw *ChatWindow) deleteConversation(id widget.ListItemID) {
 // Streaming updates are sent through a channel to update the UI in real-time.
 func (cw *ChatWindow) sendMessage() {
 	text := cw.messageEntry.Text
-	if text == "" || cw.currentConversation == nil {
+	if (text == "" && len(cw.pendingAttachments) == 0) || cw.currentConversation == nil || cw.currentConversation.ReadOnly() {
+		return
+	}
+	if cw.convManager.SavingPaused(cw.currentConversation.ID) {
+		return
+	}
+	if cw.runContextCommand(text) {
 		return
 	}
 
-	// Debug: Log which client is being used
-	if cw.reactClient != nil {
-		fmt.Printf("[DEBUG] Using React Client (Agent mode)\n")
-	} else if cw.llmClient != nil {
-		fmt.Printf("[DEBUG] Using Regular LLM Client\n")
-	} else {
-		fmt.Printf("[DEBUG] ERROR: No valid client available!\n")
+	if cw.reactClient == nil && cw.llmClient == nil {
+		logger.Warn("No client for the conversation's provider", "provider", cw.currentConversation.Provider)
 		return
 	}
 
+	// Offer to trim the tools first when the agent would send more than the provider accepts
+	if cw.reactClient != nil && cw.exceedsToolLimit() {
+		cw.showToolLimitDialog(text)
+		return
+	}
+
+	cw.submitMessage(text)
+}
+
+// submitMessage adds text to the current conversation as a user message and requests a reply
+func (cw *ChatWindow) submitMessage(text string) {
+	// Rebuild the agent if the tool selection changed since it was set up
+	if cw.reactClient != nil && cw.agentTools != toolSelectionKey(cw.toolSelectionMgr.GetSelectedTools()) {
+		cw.setupCurrentProvider()
+	}
+
 	// Clear input
 	cw.messageEntry.SetText("")
 
-	// Create user message
+	// Create user message with the pending attachments
 	userMsg := models.Message{
-		ID:        fmt.Sprintf("%d", time.Now().UnixNano()),
-		Role:      "user",
-		Content:   text,
-		Timestamp: time.Now(),
+		ID:          models.NewMessageID(),
+		Role:        "user",
+		Content:     text,
+		Timestamp:   time.Now(),
+		Attachments: cw.pendingAttachments,
 	}
+	cw.pendingAttachments = nil
+	cw.refreshPendingAttachments()
 
 	cw.currentConversation.Messages = append(cw.currentConversation.Messages, userMsg)
 	cw.addMessageToUI(userMsg)
-	cw.convManager.SaveConversation(cw.currentConversation)
+	cw.saveConversation(cw.currentConversation)
+
+	cw.requestAssistantResponse()
+}
 
+// requestAssistantResponse streams an assistant reply to the messages already in the
+// current conversation into a new message row.
+func (cw *ChatWindow) requestAssistantResponse() {
+	conv := cw.currentConversation
+	cw.hideEmptyState()
+
+	// Prepare messages; a retry re-sends exactly these
+	history := make([]llm.ChatMessage, len(conv.Messages))
+	acceptsImages := cw.acceptsImages()
+	for i, msg := range conv.Messages {
+		history[i] = llm.ChatMessage{
+			Role:      msg.Role,
+			Content:   msg.PromptContent(),
+			ToolCalls: recordsFromToolCalls(msg.ToolCalls),
+		}
+		if acceptsImages {
+			history[i].Images = chatImages(msg.Images())
+		}
+	}
+
+	// The reply is timestamped now, which may be a day after the last message
+	if messages := conv.Messages; len(messages) == 0 || !sameDay(messages[len(messages)-1].Timestamp, time.Now()) {
+		cw.messagesContainer.Add(newDateSeparator(time.Now()))
+	}
+
+	reserved := llm.EstimateTokens(cw.config.SystemPrompt)
+	if link := conv.ContextFrom; link != nil {
+		reserved += llm.EstimateTokens(link.ContextPrompt())
+	}
+	trim := cw.trimToContext(history, reserved)
+	var notice *widget.Label
+	if trim.left > 0 {
+		notice = newTrimNotice(trim.left)
+		cw.messagesContainer.Add(notice)
+	}
+	row := container.NewVBox()
+	cw.messagesContainer.Add(row)
+
+	cw.summarizeTrimmed(conv, history, trim, notice, func(summary *models.TrimmedSummary) {
+		// The global prompt comes first, then the summary of the conversation this one
+		// continues, then the summary of its own messages left out to fit the context
+		messages := trim.messages
+		if summary != nil {
+			messages = llm.WithSystemPrompt(summary.ContextPrompt(), messages)
+		}
+		if link := conv.ContextFrom; link != nil {
+			messages = llm.WithSystemPrompt(link.ContextPrompt(), messages)
+		}
+		messages = llm.WithSystemPrompt(cw.config.SystemPrompt, messages)
+		cw.streamAssistantResponse(conv, messages, row)
+	})
+}
+
+// streamAssistantResponse streams the reply to messages into row. A failed request is
+// shown in the row with a retry button instead of being saved; retrying re-sends the
+// same messages and fills the same row, so the user message isn't added again. The returned
+// channel is closed once the reply or the failure is shown.
+func (cw *ChatWindow) streamAssistantResponse(conv *models.Conversation, messages []llm.ChatMessage, row *fyne.Container) <-chan struct{} {
 	// Create assistant message placeholder
 	assistantMsg := models.Message{
-		ID:        fmt.Sprintf("%d", time.Now().UnixNano()+1),
+		ID:        models.NewMessageID(),
 		Role:      "assistant",
 		Content:   "",
 		Timestamp: time.Now(),
 	}
 
-	// Add placeholder for streaming
-	msgLabel := cw.addStreamingMessageToUI(assistantMsg)
+	// Show placeholder for streaming
+	streamMsg := cw.showStreamingMessage(row, &assistantMsg)
 
-	// Prepare messages
-	messages := make([]llm.ChatMessage, len(cw.currentConversation.Messages))
-	for i, msg := range cw.currentConversation.Messages {
-		messages[i] = llm.ChatMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
-	}
+	// Capture state used by the background goroutines so they never read ChatWindow fields
+	reactClient := cw.reactClient
+	llmClient := cw.llmClient
+	timeout := cw.requestTimeout(conv.Provider)
+	provider, _ := cw.providerNamed(conv.Provider)
+	// What the request needs from the model, to offer models that have it if it is refused
+	requirements := llm.RequirementsOf(messages, reactClient != nil)
 
-	// Channel for streaming updates
-	chunkChan := make(chan string)
+	// Channel for streaming updates; closed by the sender once the response is complete
+	chunkChan := make(chan string, 64)
 	doneChan := make(chan struct{})
+	shown := make(chan struct{})
 
-	// Goroutine to handle streaming updates
+	// Goroutine batching streamed chunks into periodic UI updates.
+	// Widgets are only touched through fyne.Do.
 	go func() {
+		defer close(doneChan)
+
+		ticker := time.NewTicker(streamFlushInterval)
+		defer ticker.Stop()
+
+		var streamed strings.Builder
+		dirty := false
+		renderer := newStreamRenderer(streamMsg.body, streamMsg.content)
+		// Set while a render is queued on the UI goroutine. When rendering a long reply takes
+		// longer than streamFlushInterval, ticks are skipped rather than piling up renders of
+		// text that is already out of date.
+		var rendering atomic.Bool
+
+		// The waiting hint is shown whenever no chunk has arrived for waitingHintDelay,
+		// both before the first chunk and while the model pauses mid-stream
+		lastChunk := time.Now()
+		waiting := false
+
 		for {
 			select {
-			case chunk := <-chunkChan:
-				assistantMsg.Content += chunk
-				// Update UI using goroutine-safe method
-				cw.messageEntry.Refresh() // Force refresh to trigger UI update
-				msgLabel.ParseMarkdown(assistantMsg.Content)
-				cw.messagesContainer.Refresh()
-				cw.chatArea.ScrollToBottom()
-			case <-doneChan:
-				return
+			case chunk, ok := <-chunkChan:
+				if !ok {
+					// The final content is rendered by the sender
+					return
+				}
+				streamed.WriteString(chunk)
+				dirty = true
+				lastChunk = time.Now()
+			case <-ticker.C:
+				if idle := time.Since(lastChunk) >= waitingHintDelay; idle != waiting {
+					waiting = idle
+					fyne.Do(func() {
+						streamMsg.setWaiting(idle)
+					})
+				}
+				if !dirty || rendering.Load() {
+					continue
+				}
+				dirty = false
+				rendering.Store(true)
+				text := streamed.String()
+				fyne.Do(func() {
+					defer rendering.Store(false)
+					// Follow the reply only if the user hasn't scrolled up to read
+					follow := cw.chatAtBottom()
+					streamMsg.stopIndicator()
+					renderer.render(text)
+					if follow {
+						cw.chatArea.ScrollToBottom()
+					}
+				})
 			}
 		}
 	}()
 
 	// Send to LLM asynchronously in goroutine
 	go func() {
-		defer close(doneChan)
-
-		ctx := context.Background()
+		// Fail stalled requests: the timeout covers the wait for the first chunk,
+		// then each gap between chunks is allowed a longer inactivity timeout
+		ctx, streamTimeout := llm.NewStreamTimeout(context.Background(), timeout)
+		started := time.Now()
 		var response *llm.ChatResponse
 		var err error
 
-		// Use React Client if available, otherwise use regular client
-		if cw.reactClient != nil {
-			response, err = cw.reactClient.Chat(ctx, messages, func(chunk string) {
-				chunkChan <- chunk
-			})
-		} else if cw.llmClient != nil {
-			response, err = cw.llmClient.Chat(ctx, messages, func(chunk string) {
-				chunkChan <- chunk
+		onChunk := func(chunk string) {
+			streamTimeout.Touch()
+			chunkChan <- chunk
+		}
+
+		// Tool calls are shown as the agent makes them; a running tool counts as activity
+		onToolCall := func(record llm.ToolCallRecord) {
+			streamTimeout.Touch()
+			fyne.Do(func() {
+				follow := cw.chatAtBottom()
+				streamMsg.stopIndicator()
+				streamMsg.showToolCall(record)
+				if follow {
+					cw.chatArea.ScrollToBottom()
+				}
 			})
-		} else {
-			err = fmt.Errorf("no valid client available")
 		}
 
-		if err != nil {
-			assistantMsg.Content = fmt.Sprintf("Error: %v", err)
+		// Dangerous tools wait for the user's approval, which does not count against the timeout
+		ctx = llm.WithToolApprover(ctx, func(ctx context.Context, name, arguments string) (bool, error) {
+			streamTimeout.Pause()
+			defer streamTimeout.Touch()
+			return cw.approveToolCall(ctx, name, arguments)
+		})
+
+		// Use React Client if available, otherwise use regular client
+		if reactClient != nil {
+			response, err = reactClient.ChatWithToolCalls(ctx, messages, onChunk, onToolCall)
+		} else if llmClient != nil {
+			response, err = llmClient.Chat(ctx, messages, onChunk)
 		} else {
-			assistantMsg.Content = response.Content
+			err = fmt.Errorf("no valid client available")
+		}
+		err = streamTimeout.Err(err)
+		streamTimeout.Stop()
+
+		if errors.Is(err, llm.ErrRequestTimeout) {
+			err = errors.New(Translatef("chat.request_timeout", timeout))
+		} else if err != nil {
+			// A refusal for lack of quota shows when the quota resets
+			if quota, ok := llm.Quotas.ExhaustedSince(conv.Provider, started); ok {
+				err = &llm.QuotaError{Provider: conv.Provider, Quota: quota, Err: err}
+			} else if provider.Type == "ollama" && llm.IsOllamaModelNotFound(err) {
+				// A model that isn't pulled yet can be pulled and the request retried
+				err = &llm.OllamaModelMissingError{BaseURL: provider.BaseURL, Model: provider.Model, Err: err}
+			} else if capErr := llm.AsCapabilityError(err, requirements); capErr != nil {
+				// A model lacking a capability the request needs can be swapped for one that has it
+				err = capErr
+			}
 		}
 
-		// Final update with complete content
-		msgLabel.ParseMarkdown(assistantMsg.Content)
-		cw.currentConversation.Messages = append(cw.currentConversation.Messages, assistantMsg)
-		cw.convManager.SaveConversation(cw.currentConversation)
-		cw.chatArea.ScrollToBottom()
+		close(chunkChan)
+		<-doneChan
+
+		// Final update with complete content; queued after any pending flush
+		fyne.Do(func() {
+			defer close(shown)
+			follow := cw.chatAtBottom()
+			streamMsg.stopIndicator()
+			streamMsg.setWaiting(false)
+			if err != nil {
+				cw.showRequestError(conv, row, err, func() {
+					cw.streamAssistantResponse(conv, messages, row)
+				})
+				return
+			}
+
+			assistantMsg.Content = response.Content
+			assistantMsg.ToolCalls = toolCallsFromRecords(response.ToolCalls)
+			for _, record := range response.ToolCalls {
+				streamMsg.showToolCall(record)
+			}
+			streamMsg.showContent(assistantMsg.Content)
+			if response.NotStreamed {
+				streamMsg.badge.Show()
+			}
+			streamMsg.actions.Refresh()
+			streamMsg.actions.SetEnabled(true)
+			conv.Messages = append(conv.Messages, assistantMsg)
+			cw.saveConversation(conv)
+			if follow {
+				cw.chatArea.ScrollToBottom()
+			}
+		})
 	}()
+	return shown
 }
 
+// addMessageToUI appends a message to the end of the chat
 func (cw *ChatWindow) addMessageToUI(msg models.Message) {
-	roleLabel := widget.NewLabel(msg.Role)
-	roleLabel.TextStyle = fyne.TextStyle{Bold: true}
+	cw.hideEmptyState()
+
+	// msg has just been appended to the conversation, so the one before it is the last shown
+	if messages := cw.currentConversation.Messages; len(messages) < 2 || !sameDay(messages[len(messages)-2].Timestamp, msg.Timestamp) {
+		cw.messagesContainer.Add(newDateSeparator(msg.Timestamp))
+	}
+	cw.messagesContainer.Add(cw.newMessageRow(msg))
+	cw.messagesContainer.Refresh()
+}
+
+// newMessageRow creates the widgets showing a saved message
+func (cw *ChatWindow) newMessageRow(msg models.Message) fyne.CanvasObject {
+	content := msg.Content
+	actions := cw.newMessageActions(func() string { return content })
 
 	// Build message container parts
 	parts := []fyne.CanvasObject{
-		container.NewHBox(roleLabel, widget.NewLabel(msg.Timestamp.Format("15:04"))),
+		cw.newBubbleHeader(msg.Timestamp, actions.box),
+	}
+
+	// Attached files are shown as chips and images as thumbnails rather than inline
+	if len(msg.Attachments) > 0 {
+		parts = append(parts, cw.newAttachmentChips(msg.Attachments))
 	}
 
 	// Add tool call information if present
 	if len(msg.ToolCalls) > 0 {
-		for i, toolCall := range msg.ToolCalls {
-			toolIcon := "🔧"
-			statusIcon := "✅"
-			if toolCall.Error != "" {
-				statusIcon = "❌"
-			}
+		parts = append(parts, newToolCallsView(msg.ToolCalls))
+	}
 
-			toolLabel := widget.NewLabel(fmt.Sprintf("%s 工具调用 #%d: %s", toolIcon, i+1, toolCall.Name))
-			toolLabel.TextStyle = fyne.TextStyle{Bold: true}
+	// Add message content
+	contentLabel := CreateMarkdownRichText(msg.Content, DefaultRichTextConfig())
 
-			// Create tool call details container
-			toolDetails := container.NewVBox()
+	parts = append(parts, contentLabel)
 
-			// Add arguments if present
-			if toolCall.Arguments != "" {
-				argsLabel := widget.NewLabel(fmt.Sprintf("参数: %s", toolCall.Arguments))
-				argsLabel.Wrapping = fyne.TextWrapWord
-				argsLabel.TextStyle = fyne.TextStyle{Italic: true}
-				toolDetails.Add(argsLabel)
-			}
+	return CreateMessageBubble(msg.Role, parts...)
+}
 
-			// Add result if present
-			if toolCall.Result != "" {
-				resultLabel := widget.NewLabel(fmt.Sprintf("结果: %s", toolCall.Result))
-				resultLabel.Wrapping = fyne.TextWrapWord
-				toolDetails.Add(resultLabel)
-			}
+// providerNamed returns the configuration of the named provider
+func (cw *ChatWindow) providerNamed(providerName string) (config.Provider, bool) {
+	for _, p := range cw.config.Providers {
+		if p.Name == providerName {
+			return p, true
+		}
+	}
+	return config.Provider{}, false
+}
 
-			// Add error if present
-			if toolCall.Error != "" {
-				errorLabel := widget.NewLabel(fmt.Sprintf("错误: %s", toolCall.Error))
-				errorLabel.Wrapping = fyne.TextWrapWord
-				errorLabel.Importance = widget.DangerImportance
-				toolDetails.Add(errorLabel)
-			}
+// requestTimeout returns the time-to-first-chunk timeout of the named provider
+func (cw *ChatWindow) requestTimeout(providerName string) time.Duration {
+	if p, ok := cw.providerNamed(providerName); ok {
+		return llm.RequestTimeout(p)
+	}
+	return llm.DefaultRequestTimeout
+}
 
-			// Create expandable tool call container
-			toolContainer := container.NewVBox(
-				container.NewHBox(toolLabel, widget.NewLabel(statusIcon)),
-				container.NewPadded(toolDetails),
-				widget.NewSeparator(),
-			)
+// showRequestError replaces the contents of a message row with a failed request, which
+// is not part of the conversation. The retry button disables itself and calls retry.
+func (cw *ChatWindow) showRequestError(conv *models.Conversation, row *fyne.Container, err error, retry func()) {
+	roleLabel := widget.NewLabel(Translate("chat.error_role"))
+	roleLabel.TextStyle = fyne.TextStyle{Bold: true}
+	roleLabel.Importance = widget.DangerImportance
 
-			// Add a card-like border for tool calls
-			toolCard := container.NewBorder(
-				widget.NewSeparator(),
-				nil,
-				nil,
-				nil,
-				toolContainer,
-			)
+	errorLabel := widget.NewLabel(err.Error())
+	errorLabel.Wrapping = fyne.TextWrapWord
+	errorLabel.Importance = widget.DangerImportance
 
-			parts = append(parts, toolCard)
+	// Retries once, whether from the button or when a scheduled retry comes due
+	var retryBtn *widget.Button
+	retryOnce := func() {
+		if retryBtn.Disabled() {
+			return
 		}
+		retryBtn.Disable()
+		retry()
 	}
+	retryBtn = widget.NewButtonWithIcon(Translate("chat.retry"), theme.ViewRefreshIcon(), retryOnce)
 
-	// Add message content
-	contentLabel := widget.NewRichTextFromMarkdown(msg.Content)
-	// Enable text wrapping for RichText
-	contentLabel.Wrapping = fyne.TextWrapWord
+	row.Objects = []fyne.CanvasObject{
+		container.NewHBox(roleLabel, widget.NewLabel(time.Now().Format("15:04")), layout.NewSpacer(), retryBtn),
+		errorLabel,
+	}
+	var quotaErr *llm.QuotaError
+	if errors.As(err, &quotaErr) {
+		row.Add(newQuotaRetry(quotaErr.Quota, retryOnce))
+	}
+	var missingErr *llm.OllamaModelMissingError
+	if errors.As(err, &missingErr) {
+		row.Add(cw.newModelPullOffer(missingErr, retryOnce))
+	}
+	var capErr *llm.CapabilityError
+	if errors.As(err, &capErr) {
+		row.Add(cw.newHandoffOffer(conv, capErr, retryOnce))
+	}
+	row.Add(widget.NewSeparator())
+	row.Refresh()
+	cw.messagesContainer.Refresh()
+}
 
-	parts = append(parts, contentLabel, widget.NewSeparator())
+// streamingMessage holds the widgets of an assistant message that is still being streamed
+type streamingMessage struct {
+	row       *fyne.Container
+	toolCalls *fyne.Container
+	liveCalls *liveToolCalls  // Created with the first tool call
+	body      *fyne.Container // Holds content, after the finished blocks while streaming
+	content   *widget.RichText
+	actions   *messageActions
+	indicator *widget.ProgressBarInfinite
+	hint      *widget.Label
+	badge     *widget.Label // Shown when the reply wasn't streamed
+}
 
-	container := container.NewVBox(parts...)
+// stopIndicator removes the waiting indicator; safe to call more than once
+func (m *streamingMessage) stopIndicator() {
+	if m.indicator.Visible() {
+		m.indicator.Stop()
+		m.indicator.Hide()
+	}
+}
 
-	cw.messagesContainer.Add(container)
-	cw.messagesContainer.Refresh()
+// showToolCall adds a tool call to the message or updates the one with the same ID
+func (m *streamingMessage) showToolCall(record llm.ToolCallRecord) {
+	if m.liveCalls == nil {
+		m.liveCalls = newLiveToolCalls()
+		m.toolCalls.Add(m.liveCalls.accordion)
+	}
+	m.liveCalls.update(record)
 }
 
-func (cw *ChatWindow) addStreamingMessageToUI(msg models.Message) *widget.RichText {
-	roleLabel := widget.NewLabel(msg.Role)
-	roleLabel.TextStyle = fyne.TextStyle{Bold: true}
+// showContent renders the complete reply, replacing the blocks rendered while it streamed
+func (m *streamingMessage) showContent(markdown string) {
+	m.body.Objects = []fyne.CanvasObject{m.content}
+	SetMarkdown(m.content, markdown, DefaultRichTextConfig())
+	m.body.Refresh()
+}
+
+// setWaiting shows or hides the hint that the model hasn't sent anything for a while
+func (m *streamingMessage) setWaiting(waiting bool) {
+	if waiting {
+		m.hint.Show()
+	} else {
+		m.hint.Hide()
+	}
+}
 
+// showStreamingMessage lays out an empty message in row, replacing anything it showed
+// before, to be filled in as chunks arrive. A progress indicator is shown until the first
+// chunk, and the copy actions stay disabled until the caller enables them on completion.
+// A hint that the model is still being waited for is hidden until setWaiting shows it.
+func (cw *ChatWindow) showStreamingMessage(row *fyne.Container, msg *models.Message) *streamingMessage {
 	contentLabel := widget.NewRichTextFromMarkdown("")
 	// Enable text wrapping for RichText
 	contentLabel.Wrapping = fyne.TextWrapWord
+	// The streamed blocks are split across RichTexts without adding space between them
+	body := container.New(layout.NewCustomPaddedVBoxLayout(0), contentLabel)
 
-	container := container.NewVBox(
-		container.NewHBox(roleLabel, widget.NewLabel(msg.Timestamp.Format("15:04"))),
-		contentLabel,
-		widget.NewSeparator(),
-	)
+	actions := cw.newMessageActions(func() string { return msg.Content })
+	actions.SetEnabled(false)
+
+	// Waiting indicator, shown until the first chunk arrives
+	indicator := widget.NewProgressBarInfinite()
+
+	hint := widget.NewLabel(Translate("chat.waiting_for_model"))
+	hint.TextStyle = fyne.TextStyle{Italic: true}
+	hint.Importance = widget.LowImportance
+	hint.Hide()
 
-	cw.messagesContainer.Add(container)
+	badge := widget.NewLabel(Translate("chat.not_streamed"))
+	badge.TextStyle = fyne.TextStyle{Italic: true}
+	badge.Importance = widget.LowImportance
+	badge.Hide()
+
+	// Filled in with the agent's tool calls as it makes them
+	toolCalls := container.NewVBox()
+
+	// The same bubble as a saved message, so the reply keeps its look once complete
+	row.Objects = []fyne.CanvasObject{
+		CreateMessageBubble(msg.Role, cw.newBubbleHeader(msg.Timestamp, actions.box), indicator, toolCalls, body, hint, badge),
+	}
+	row.Refresh()
 	cw.messagesContainer.Refresh()
 	cw.chatArea.ScrollToBottom()
 
-	return contentLabel
+	return &streamingMessage{
+		row:       row,
+		toolCalls: toolCalls,
+		body:      body,
+		content:   contentLabel,
+		actions:   actions,
+		indicator: indicator,
+		hint:      hint,
+		badge:     badge,
+	}
 }
 
 // Show displays the chat window
@@ -870,100 +1686,158 @@ func (cw *ChatWindow) Show() {
 
 // MCPManagerWrapper wraps the MCP manager for UI use
 type MCPManagerWrapper struct {
-	manager *mcp.Manager
+	*mcp.Manager
+
+	mu             sync.Mutex
+	statusListener func()
 }
 
+// NewMCPManagerWrapper creates a wrapper around a new MCP manager
 func NewMCPManagerWrapper() *MCPManagerWrapper {
-	return &MCPManagerWrapper{
-		manager: mcp.NewManager(),
+	w := &MCPManagerWrapper{
+		Manager: mcp.NewManager(),
 	}
+	w.Subscribe(func(string, *mcp.MCPServerStatus) {
+		w.notifyStatusChanged()
+	})
+	return w
 }
 
-// InitializeAllServers initializes all configured MCP servers
-func (m *MCPManagerWrapper) InitializeAllServers(servers []config.MCPServer) map[string]*mcp.MCPServerStatus {
-	return m.manager.InitializeAll(servers)
-}
-
-// GetServerStatus returns the status of a specific server
-func (m *MCPManagerWrapper) GetServerStatus(name string) (*mcp.MCPServerStatus, bool) {
-	return m.manager.GetServerStatus(name)
-}
-
-// GetAllStatus returns all server statuses
-func (m *MCPManagerWrapper) GetAllStatus() map[string]*mcp.MCPServerStatus {
-	return m.manager.GetAllStatus()
+// SetStatusListener sets a function called from a background goroutine whenever
+// a server's status changes. Pass nil to remove it.
+func (m *MCPManagerWrapper) SetStatusListener(listener func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statusListener = listener
 }
 
-// GetServerTools returns the tools for a specific server
-func (m *MCPManagerWrapper) GetServerTools(name string) ([]mcp.MCPTool, bool) {
-	return m.manager.GetServerTools(name)
+// notifyStatusChanged calls the status listener, if any
+func (m *MCPManagerWrapper) notifyStatusChanged() {
+	m.mu.Lock()
+	listener := m.statusListener
+	m.mu.Unlock()
+	if listener != nil {
+		listener()
+	}
 }
 
-// GetAllTools returns all tools from all initialized servers
-func (m *MCPManagerWrapper) GetAllTools() map[string][]mcp.MCPTool {
-	return m.manager.GetAllTools()
-}
+// initializeMCPServers initializes all enabled MCP servers on startup and starts health checks.
+// This runs asynchronously; progress is shown in mcpStatus.
+func (cw *ChatWindow) initializeMCPServers() {
+	// Keep the status label and tool list current as servers connect, drop and reconnect
+	cw.mcpManager.Subscribe(func(name string, _ *mcp.MCPServerStatus) {
+		fyne.Do(func() {
+			cw.updateMCPStatusLabel()
+			cw.toolSelectionMgr.RefreshToolCheckGroup()
+			// An agent set up before the server connected was built without its tools, and one set
+			// up before it dropped still calls them; either is rebuilt on the next send
+			if cw.reactClient != nil && strings.Contains(cw.agentTools, "mcp:"+name+":") {
+				cw.agentTools = ""
+			}
+		})
+	})
+	cw.mcpManager.StartHealthChecks(mcp.DefaultHealthCheckInterval, cw.mcpServersSnapshot)
 
-// ReinitializeServer reinitializes a server
-func (m *MCPManagerWrapper) ReinitializeServer(cfg config.MCPServer) (*mcp.MCPServerStatus, error) {
-	return m.manager.ReinitializeServer(cfg)
-}
+	// Directories passed to stdio servers may have moved since they were configured
+	for _, server := range cw.config.MCPServers {
+		missing := server.MissingPathArgs()
+		for _, i := range missing {
+			logger.Warn("MCP server argument is not an existing path", "server", server.Name, "argument", i+1, "path", server.Args[i])
+		}
+		if server.Enabled && len(missing) > 0 {
+			cw.notifications.Post(notify.Notification{
+				Level:   notify.Warning,
+				Title:   Translate("mcp.path_invalid"),
+				Message: Translatef("mcp.path_invalid_message", server.Name, server.Args[missing[0]]),
+				Action:  &notify.Action{Label: Translate("common.open_settings"), Run: cw.showSettings},
+			})
+		}
+	}
 
-// DisconnectServer disconnects a specific server
-func (m *MCPManagerWrapper) DisconnectServer(name string) error {
-	return m.manager.DisconnectServer(name)
-}
+	enabledCount := 0
+	for _, server := range cw.config.MCPServers {
+		if server.Enabled {
+			enabledCount++
+		} else {
+			logger.Debug("Skipping disabled MCP server", "server", server.Name)
+		}
+	}
 
-// initializeMCPServers initializes all configured MCP servers on startup
-// This runs asynchronously to avoid blocking the UI
-func (cw *ChatWindow) initializeMCPServers() {
-	if len(cw.config.MCPServers) == 0 {
-		fmt.Println("No MCP servers configured")
+	if enabledCount == 0 {
+		logger.Debug("No MCP servers enabled")
+		cw.updateMCPStatusLabel()
 		return
 	}
 
-	fmt.Printf("Initializing %d MCP server(s)...\n", len(cw.config.MCPServers))
+	logger.Debug("Initializing MCP servers", "enabled", enabledCount)
 
-	// Use a WaitGroup to track when all servers have been initialized
-	var wg sync.WaitGroup
-	successCount := int64(0)
+	var finishedCount, successCount int64
+	cw.mcpManager.InitializeAllAsync(cw.config.MCPServers, func(name string, _ *mcp.MCPServerStatus, err error) {
+		if err != nil {
+			cw.notifications.Post(notify.Notification{
+				Level:   notify.Warning,
+				Title:   Translate("mcp.init_failed"),
+				Message: fmt.Sprintf("'%s': %v", name, err),
+			})
+		} else {
+			atomic.AddInt64(&successCount, 1)
+		}
+
+		if atomic.AddInt64(&finishedCount, 1) == int64(enabledCount) {
+			logger.Debug("MCP servers initialized", "succeeded", atomic.LoadInt64(&successCount), "enabled", enabledCount)
+		}
+	})
+	cw.updateMCPStatusLabel()
+}
 
-	// Initialize each server in its own goroutine for parallel execution
+// updateMCPStatusLabel shows how many enabled MCP servers are connected, or initialization progress
+func (cw *ChatWindow) updateMCPStatusLabel() {
+	enabled, initializing, connected := 0, 0, 0
 	for _, server := range cw.config.MCPServers {
-		// Skip disabled servers
 		if !server.Enabled {
-			fmt.Printf("  ⊘ Skipping disabled MCP server '%s'\n", server.Name)
 			continue
 		}
-
-		wg.Add(1)
-		go func(srv config.MCPServer) {
-			defer wg.Done()
-			fmt.Printf("  Initializing MCP server '%s' (%s)...\n", srv.Name, srv.Type)
-			status, err := cw.mcpManager.manager.InitializeServer(srv)
-			if err != nil {
-				fmt.Printf("  ✗ Failed to initialize '%s': %v\n", srv.Name, err)
-			} else {
-				toolCount := len(status.Tools)
-				fmt.Printf("  ✓ Successfully initialized '%s' (%d tool%s)\n",
-					srv.Name, toolCount, map[bool]string{true: "s", false: ""}[toolCount != 1])
-				atomic.AddInt64(&successCount, 1)
+		enabled++
+		if status, ok := cw.mcpManager.GetServerStatus(server.Name); ok {
+			switch status.Status {
+			case "initializing":
+				initializing++
+			case "initialized":
+				connected++
 			}
-		}(server)
+		}
 	}
 
-	// Count enabled servers for final message
-	enabledCount := 0
-	for _, server := range cw.config.MCPServers {
-		if server.Enabled {
-			enabledCount++
-		}
+	if enabled == 0 {
+		cw.mcpStatusLabel.Hide()
+		cw.stopMCPActivity()
+		return
+	}
+	if initializing > 0 {
+		cw.mcpStatusLabel.SetText(Translatef("mcp.status_initializing", enabled-initializing, enabled))
+		cw.mcpActivity.Show()
+		cw.mcpActivity.Start()
+	} else {
+		cw.mcpStatusLabel.SetText(Translatef("mcp.status_connected", connected, enabled))
+		cw.stopMCPActivity()
+	}
+	cw.mcpStatusLabel.Show()
+}
+
+// stopMCPActivity hides the initialization spinner; safe to call when it isn't running
+func (cw *ChatWindow) stopMCPActivity() {
+	if cw.mcpActivity.Visible() {
+		cw.mcpActivity.Stop()
+		cw.mcpActivity.Hide()
 	}
+}
 
-	// Wait for all servers to finish initialization in a separate goroutine
-	go func() {
-		wg.Wait()
-		fmt.Printf("MCP server initialization complete: %d/%d successful\n",
-			atomic.LoadInt64(&successCount), enabledCount)
-	}()
+// mcpServersSnapshot returns a copy of the MCP server configuration.
+// It is called from the health checker, so configuration is read on the UI goroutine.
+func (cw *ChatWindow) mcpServersSnapshot() []config.MCPServer {
+	var servers []config.MCPServer
+	fyne.DoAndWait(func() {
+		servers = append(servers, cw.config.MCPServers...)
+	})
+	return servers
 }
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// copiedIconDuration is how long a code block's copy button shows a check mark after copying
+const copiedIconDuration = 1500 * time.Millisecond
+
+// codeBlockSegment shows a fenced code block inside a RichText under a small header with the
+// block's language and a button copying its code
+type codeBlockSegment struct {
+	code     string
+	lang     string
+	segments []widget.RichTextSegment // The code, colorized when highlighting applies
+}
+
+// Inline returns false as a code block takes its own block
+func (s *codeBlockSegment) Inline() bool {
+	return false
+}
+
+// Textual returns the code
+func (s *codeBlockSegment) Textual() string {
+	return s.code
+}
+
+// Visual creates the header and the code
+func (s *codeBlockSegment) Visual() fyne.CanvasObject {
+	lang := widget.NewRichText()
+	copyBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), nil)
+	copyBtn.Importance = widget.LowImportance
+	code := widget.NewRichText()
+	code.Wrapping = fyne.TextWrapBreak
+
+	o := container.NewVBox(container.NewBorder(nil, nil, lang, copyBtn), code)
+	s.Update(o)
+	return o
+}
+
+// Update shows this block's language and code in an existing visual
+func (s *codeBlockSegment) Update(o fyne.CanvasObject) {
+	box := o.(*fyne.Container)
+	header := box.Objects[0].(*fyne.Container)
+	code := box.Objects[1].(*widget.RichText)
+
+	for _, object := range header.Objects {
+		switch object := object.(type) {
+		case *widget.RichText:
+			object.Segments = []widget.RichTextSegment{&widget.TextSegment{
+				Text:  s.lang,
+				Style: widget.RichTextStyle{Inline: true, SizeName: messageCaptionSize, ColorName: theme.ColorNamePlaceHolder},
+			}}
+			object.Refresh()
+		case *widget.Button:
+			s.bindCopy(object)
+		}
+	}
+
+	code.Segments = s.segments
+	code.Refresh()
+}
+
+// bindCopy makes btn copy this block's code, showing a check mark for a moment after
+func (s *codeBlockSegment) bindCopy(btn *widget.Button) {
+	code := s.code
+	btn.SetIcon(theme.ContentCopyIcon())
+	btn.OnTapped = func() {
+		fyne.CurrentApp().Clipboard().SetContent(code)
+		btn.SetIcon(theme.ConfirmIcon())
+		time.AfterFunc(copiedIconDuration, func() {
+			fyne.Do(func() {
+				btn.SetIcon(theme.ContentCopyIcon())
+			})
+		})
+	}
+}
+
+// Select does nothing; the code is copied with the button
+func (s *codeBlockSegment) Select(_, _ fyne.Position) {
+}
+
+// SelectedText returns nothing as the code is copied with the button
+func (s *codeBlockSegment) SelectedText() string {
+	return ""
+}
+
+// Unselect does nothing; the code is copied with the button
+func (s *codeBlockSegment) Unselect() {
+}
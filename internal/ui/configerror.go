@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// configErrorContextLines is how many lines are shown on each side of a config error
+const configErrorContextLines = 3
+
+// ShowConfigError is shown at startup instead of the chat window when config.yaml can't be
+// read. It points at the problem and offers to open the file, reload it once fixed, restore
+// the backup or reset to the defaults. onLoaded is called with the configuration once one
+// has been loaded, to open the chat window.
+func ShowConfigError(app fyne.App, parseErr *config.ParseError, onLoaded func(*config.Config)) {
+	window := app.NewWindow("ChatGo - Configuration Error")
+	window.Resize(fyne.NewSize(720, 420))
+
+	message := widget.NewLabel("")
+	message.Wrapping = fyne.TextWrapWord
+	snippet := widget.NewTextGrid()
+	snippetScroll := container.NewScroll(snippet)
+
+	show := func(parseErr *config.ParseError) {
+		message.SetText(fmt.Sprintf("%s could not be read:\n%s", filepath.Base(parseErr.Path), parseErr.Error()))
+		snippet.SetText(parseErr.Snippet(configErrorContextLines))
+		if parseErr.Line == 0 {
+			snippetScroll.Hide()
+		} else {
+			snippetScroll.Show()
+		}
+	}
+	show(parseErr)
+
+	loaded := func(cfg *config.Config, err error) {
+		var nextErr *config.ParseError
+		switch {
+		case errors.As(err, &nextErr):
+			show(nextErr)
+		case err != nil:
+			dialog.ShowError(err, window)
+		default:
+			// The chat window is opened before this one closes so the app keeps running
+			onLoaded(cfg)
+			window.Close()
+		}
+	}
+
+	openBtn := widget.NewButton(Translate("config_error.open_file"), func() {
+		if err := app.OpenURL(&url.URL{Scheme: "file", Path: filepath.ToSlash(parseErr.Path)}); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to open %s: %w", parseErr.Path, err), window)
+		}
+	})
+	reloadBtn := widget.NewButton(Translate("config_error.reload"), func() {
+		loaded(config.LoadConfig())
+	})
+	reloadBtn.Importance = widget.HighImportance
+	restoreBtn := widget.NewButton(Translate("config_error.restore"), func() {
+		dialog.ShowConfirm(Translate("config_error.restore"), Translate("config_error.restore_confirm"), func(ok bool) {
+			if ok {
+				loaded(config.RestoreBackup())
+			}
+		}, window)
+	})
+	if !config.HasBackup() {
+		restoreBtn.Disable()
+	}
+	resetBtn := widget.NewButton(Translate("config_error.reset"), func() {
+		dialog.ShowConfirm(Translate("config_error.reset"), Translate("config_error.reset_confirm"), func(ok bool) {
+			if ok {
+				loaded(config.ResetConfig())
+			}
+		}, window)
+	})
+	resetBtn.Importance = widget.DangerImportance
+
+	hint := widget.NewLabel(Translate("config_error.hint"))
+	hint.Importance = widget.LowImportance
+
+	window.SetContent(container.NewBorder(
+		message,
+		container.NewVBox(hint, container.NewHBox(openBtn, layout.NewSpacer(), restoreBtn, resetBtn, reloadBtn)),
+		nil, nil,
+		snippetScroll,
+	))
+	window.Show()
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// configFileFilter limits the export and import file dialogs to YAML files
+var configFileFilter = storage.NewExtensionFileFilter([]string{".yaml", ".yml"})
+
+// newBackupSection creates the General tab's section for exporting and importing the whole
+// configuration and all conversations, e.g. to move them to another machine, and for the
+// daily conversation backups
+func (cw *ChatWindow) newBackupSection(parentWindow fyne.Window) fyne.CanvasObject {
+	redactCheck := widget.NewCheck(Translate("settings.export_redact_keys"), nil)
+	redactCheck.SetChecked(true)
+	exportBtn := widget.NewButton(Translate("settings.export"), func() {
+		cw.exportConfig(parentWindow, redactCheck.Checked)
+	})
+	importBtn := widget.NewButton(Translate("settings.import"), func() {
+		cw.importConfig(parentWindow)
+	})
+
+	hint := widget.NewLabel(Translate("settings.backup_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	return settingsSection(Translate("settings.backup"),
+		redactCheck,
+		hint,
+		container.NewHBox(exportBtn, importBtn),
+		cw.newConversationBackupRow(parentWindow),
+		cw.newAutoBackupRows(parentWindow),
+	)
+}
+
+// exportConfig asks where to save the configuration and exports it there
+func (cw *ChatWindow) exportConfig(parentWindow fyne.Window, redactKeys bool) {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, parentWindow)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		// ExportConfig writes the file itself, with permissions that keep API keys private
+		path := writer.URI().Path()
+		writer.Close()
+
+		if err := config.ExportConfig(cw.config, path, redactKeys); err != nil {
+			dialog.ShowError(err, parentWindow)
+			return
+		}
+		dialog.ShowInformation(Translate("common.success"), Translatef("settings.export_done", path), parentWindow)
+	}, parentWindow)
+	save.SetFileName("chatgo-config.yaml")
+	save.SetFilter(configFileFilter)
+	save.Show()
+}
+
+// importConfig asks for an exported configuration and whether to merge it with the current
+// one or replace it, then applies it
+func (cw *ChatWindow) importConfig(parentWindow fyne.Window) {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, parentWindow)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		uri := reader.URI()
+		reader.Close()
+
+		imported, err := config.ImportConfig(uri.Path())
+		if err != nil {
+			dialog.ShowError(err, parentWindow)
+			return
+		}
+
+		mergeOption, replaceOption := Translate("settings.import_merge"), Translate("settings.import_replace")
+		mode := widget.NewRadioGroup([]string{mergeOption, replaceOption}, nil)
+		mode.Required = true
+		mode.SetSelected(mergeOption)
+		keysHint := widget.NewLabel(Translate("settings.import_keys_kept"))
+		keysHint.Wrapping = fyne.TextWrapWord
+		keysHint.Importance = widget.LowImportance
+
+		content := container.NewVBox(
+			widget.NewLabel(Translatef("settings.import_summary", uri.Name(), len(imported.Providers), len(imported.MCPServers))),
+			mode,
+			keysHint,
+		)
+		confirm := dialog.NewCustomConfirm(Translate("settings.import_title"), Translate("settings.import_confirm"), Translate("common.cancel"), content, func(ok bool) {
+			if ok {
+				cw.applyImportedConfig(imported, mode.Selected == replaceOption, parentWindow)
+			}
+		}, parentWindow)
+		confirm.Resize(fyne.NewSize(480, confirm.MinSize().Height))
+		confirm.Show()
+	}, parentWindow)
+	open.SetFilter(configFileFilter)
+	open.Show()
+}
+
+// applyImportedConfig merges or replaces the configuration with an imported one, saves it, and
+// refreshes everything that shows or uses it. MCP servers that were removed or changed are
+// disconnected, so they are initialized again with their new settings.
+func (cw *ChatWindow) applyImportedConfig(imported *config.Config, replace bool, parentWindow fyne.Window) {
+	previousServers := slices.Clone(cw.config.MCPServers)
+	if replace {
+		cw.config.Replace(imported)
+	} else {
+		cw.config.Merge(imported)
+	}
+	if err := config.SaveConfig(cw.config); err != nil {
+		dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+	}
+
+	for _, previous := range previousServers {
+		i := slices.IndexFunc(cw.config.MCPServers, func(s config.MCPServer) bool { return s.Name == previous.Name })
+		if i < 0 || !reflect.DeepEqual(cw.config.MCPServers[i], previous) {
+			_ = cw.mcpManager.DisconnectServer(previous.Name)
+		}
+	}
+	cw.mcpManager.ForgetDisabled(cw.config.MCPServers)
+
+	cw.applyTheme()
+	cw.applyProxy()
+	cw.titleQueue.SetRate(cw.config.TitleRatePerMinute)
+	cw.updateProviderSelector()
+	cw.setupCurrentProvider()
+	cw.toolSelectionMgr.RefreshToolCheckGroup()
+	cw.refreshSettings()
+}
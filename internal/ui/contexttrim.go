@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"chatgo/internal/llm"
+	"chatgo/pkg/models"
+	"context"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// contextLimits returns the most messages and estimated tokens of history sent to p per
+// request; 0 is no limit
+func contextLimits(p config.Provider) (maxMessages, maxTokens int) {
+	maxTokens = p.MaxContextTokens
+	if maxTokens <= 0 {
+		if info, ok := llm.ModelCatalog.ForProvider(p); ok && info.ContextWindow > 0 {
+			// The rest is left for the reply, tool definitions and the estimate being off
+			maxTokens = info.ContextWindow * 3 / 4
+		}
+	}
+	return p.MaxContextMessages, maxTokens
+}
+
+// contextTrim is the history of a request after trimming it to the provider's context limits
+type contextTrim struct {
+	messages []llm.ChatMessage // To send
+	dropped  int               // Leading messages considered; the non-system ones are left out
+	left     int               // Messages left out
+	summary  bool              // Send a summary of the messages left out
+}
+
+// trimToContext trims the oldest messages of history, the current conversation's messages as
+// sent to the model, to the context strategy and the current provider's context limits, leaving
+// reserved tokens for the system prompts
+func (cw *ChatWindow) trimToContext(history []llm.ChatMessage, reserved int) contextTrim {
+	p, _ := cw.currentProvider()
+	maxMessages, maxTokens := contextLimits(p)
+	if maxTokens > 0 {
+		maxTokens = max(maxTokens-reserved, 1)
+	}
+	if n := cw.config.ContextMessages(); n > 0 && (maxMessages <= 0 || n < maxMessages) {
+		maxMessages = n
+	}
+
+	trim := contextTrim{summary: p.SummarizeTrimmed || cw.config.ContextMode() == config.ContextSummarize}
+	trim.messages, trim.dropped = llm.TrimHistory(history, maxMessages, maxTokens)
+	for _, msg := range history[:trim.dropped] {
+		if msg.Role != "system" {
+			trim.left++
+		}
+	}
+	return trim
+}
+
+// contextStrategyName returns the name a context strategy is shown with
+func contextStrategyName(strategy string) string {
+	return Translate("context_strategy." + strategy)
+}
+
+// newContextStrategySelect creates the select in the chat bar showing the active context
+// strategy, which switches it when changed
+func (cw *ChatWindow) newContextStrategySelect() *widget.Select {
+	names := make([]string, len(config.ContextStrategies))
+	for i, strategy := range config.ContextStrategies {
+		names[i] = contextStrategyName(strategy)
+	}
+	strategySelect := widget.NewSelect(names, nil)
+	strategySelect.SetSelected(contextStrategyName(cw.config.ContextMode()))
+	strategySelect.OnChanged = func(string) {
+		strategy := config.ContextStrategies[strategySelect.SelectedIndex()]
+		if strategy == cw.config.ContextMode() {
+			return
+		}
+		cw.config.ContextStrategy = strategy
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), cw.window)
+		}
+	}
+	return strategySelect
+}
+
+// newTrimNotice creates the line in the chat saying messages were left out of a request
+func newTrimNotice(left int) *widget.Label {
+	notice := widget.NewLabel(fmt.Sprintf("%d earlier messages were left out to fit the model's context", left))
+	notice.Importance = widget.LowImportance
+	notice.Alignment = fyne.TextAlignCenter
+	notice.Wrapping = fyne.TextWrapWord
+	return notice
+}
+
+// summarizeTrimmed calls send with the summary of the messages trim leaves out of conv's
+// history, when the provider is set to summarize them, and otherwise with nil. A stored summary
+// of the same messages is reused, and one of fewer is extended with the rest; a new summary is
+// generated in the background and stored on conv. notice tells the user what is sent.
+func (cw *ChatWindow) summarizeTrimmed(conv *models.Conversation, history []llm.ChatMessage, trim contextTrim, notice *widget.Label, send func(*models.TrimmedSummary)) {
+	if !trim.summary || trim.left == 0 {
+		send(nil)
+		return
+	}
+	sentAsSummary := fmt.Sprintf("%d earlier messages were sent as a summary to fit the model's context", trim.left)
+	if conv.TrimmedSummary.Covers(conv, trim.dropped) {
+		notice.SetText(sentAsSummary)
+		send(conv.TrimmedSummary)
+		return
+	}
+
+	previous, from := "", 0
+	if s := conv.TrimmedSummary; s != nil && s.Through < trim.dropped && s.Covers(conv, s.Through) {
+		previous, from = s.Summary, s.Through
+	}
+	notice.SetText(fmt.Sprintf("Summarizing %d earlier messages to fit the model's context…", trim.left))
+	go func() {
+		summary, err := cw.generateTrimmedSummary(previous, history[from:trim.dropped])
+		fyne.Do(func() {
+			if err != nil {
+				notice.SetText(fmt.Sprintf("%d earlier messages were left out to fit the model's context; summarizing them failed: %v", trim.left, err))
+				send(nil)
+				return
+			}
+			conv.TrimmedSummary = &models.TrimmedSummary{
+				Through:       trim.dropped,
+				LastMessageID: conv.Messages[trim.dropped-1].ID,
+				Summary:       summary,
+			}
+			cw.saveConversation(conv)
+			notice.SetText(sentAsSummary)
+			send(conv.TrimmedSummary)
+		})
+	}()
+}
+
+// generateTrimmedSummary asks the title provider for a summary of messages left out of the
+// context, folding in the previous summary. It is called from a background goroutine.
+func (cw *ChatWindow) generateTrimmedSummary(previous string, messages []llm.ChatMessage) (string, error) {
+	client, err := cw.newTitleClient()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), llm.DefaultRequestTimeout)
+	defer cancel()
+	return llm.SummarizeHistory(ctx, client, previous, messages)
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// archiveFileFilter limits the conversation export and import file dialogs to zip archives
+var archiveFileFilter = storage.NewExtensionFileFilter([]string{".zip"})
+
+// newConversationBackupRow creates the buttons that export every conversation to an archive and
+// import one, e.g. to move them to another machine
+func (cw *ChatWindow) newConversationBackupRow(parentWindow fyne.Window) fyne.CanvasObject {
+	exportBtn := widget.NewButton(Translate("archive.export"), func() {
+		cw.exportConversations(parentWindow)
+	})
+	importBtn := widget.NewButton(Translate("archive.import"), func() {
+		cw.importConversations(parentWindow)
+	})
+	return container.NewHBox(exportBtn, importBtn)
+}
+
+// exportConversations asks where to save the archive and writes every conversation to it
+func (cw *ChatWindow) exportConversations(parentWindow fyne.Window) {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, parentWindow)
+			return
+		}
+		if writer == nil {
+			return
+		}
+
+		count, err := cw.convManager.ExportAll(writer)
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to export conversations: %w", err), parentWindow)
+			return
+		}
+		dialog.ShowInformation(Translate("common.success"), Translatef("archive.export_done", count, writer.URI().Path()), parentWindow)
+	}, parentWindow)
+	save.SetFileName(fmt.Sprintf("chatgo-conversations-%s.zip", time.Now().Format("20060102")))
+	save.SetFilter(archiveFileFilter)
+	save.Show()
+}
+
+// importConversations asks for an archive written by exportConversations and adds its
+// conversations
+func (cw *ChatWindow) importConversations(parentWindow fyne.Window) {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, parentWindow)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		result, err := cw.convManager.ImportAll(reader)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to import conversations: %w", err), parentWindow)
+			return
+		}
+		message := Translatef("archive.import_done", result.Imported, result.Skipped)
+		if result.Renamed > 0 {
+			message += "\n" + Translatef("archive.import_renamed", result.Renamed)
+		}
+		dialog.ShowInformation(Translate("common.success"), message, parentWindow)
+	}, parentWindow)
+	open.SetFilter(archiveFileFilter)
+	open.Show()
+}
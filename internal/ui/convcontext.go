@@ -0,0 +1,300 @@
+package ui
+
+import (
+	"chatgo/internal/autotitle"
+	"chatgo/internal/llm"
+	"chatgo/internal/notify"
+	"chatgo/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// contextCommand links an earlier conversation as context, e.g. "/context 周报"
+const contextCommand = "/context"
+
+// pendingContext is an earlier conversation being summarized to link as context
+type pendingContext struct {
+	convID      string // Conversation the summary is for
+	sourceTitle string
+}
+
+// newContextBar creates the bar above the input that shows the conversation the current one continues
+func (cw *ChatWindow) newContextBar() fyne.CanvasObject {
+	cw.contextBar = container.NewHBox()
+	cw.contextBar.Hide()
+	return cw.contextBar
+}
+
+// updateContextBar shows a chip for the current conversation's context with its estimated
+// token count, or the summary still being generated for it
+func (cw *ChatWindow) updateContextBar() {
+	if cw.contextBar == nil {
+		return
+	}
+	cw.contextBar.RemoveAll()
+	conv := cw.currentConversation
+	if conv != nil && conv.Incognito() {
+		cw.contextBar.Add(cw.newIncognitoChip(conv))
+	}
+
+	switch {
+	case conv == nil:
+	case cw.pendingContext != nil && cw.pendingContext.convID == conv.ID:
+		label := widget.NewLabel(Translatef("context.summarizing", cw.pendingContext.sourceTitle))
+		label.Importance = widget.LowImportance
+		cw.contextBar.Add(container.NewHBox(widget.NewActivity(), label))
+	case conv.ContextFrom != nil:
+		link := conv.ContextFrom
+		tokens := llm.EstimateTokens(link.ContextPrompt())
+		chip := widget.NewButtonWithIcon(Translatef("context.chip", link.Title, tokens), theme.MailReplyIcon(), func() {
+			cw.showContextSummary(link)
+		})
+		chip.Importance = widget.LowImportance
+
+		removeBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+			conv.ContextFrom = nil
+			cw.saveConversation(conv)
+			cw.updateContextBar()
+		})
+		removeBtn.Importance = widget.LowImportance
+		if conv.ReadOnly() {
+			removeBtn.Disable()
+		}
+		cw.contextBar.Add(container.NewHBox(chip, removeBtn))
+	}
+
+	if len(cw.contextBar.Objects) == 0 {
+		cw.contextBar.Hide()
+	} else {
+		cw.contextBar.Show()
+	}
+}
+
+// showContextSummary displays the summary sent to the model as context
+func (cw *ChatWindow) showContextSummary(link *models.ConversationLink) {
+	summary := widget.NewLabel(link.Summary)
+	summary.Wrapping = fyne.TextWrapWord
+	scroll := container.NewVScroll(summary)
+	scroll.SetMinSize(fyne.NewSize(480, 160))
+	dialog.ShowCustom(Translatef("context.summary_title", link.Title), Translate("common.close"), scroll, cw.window)
+}
+
+// showNewChatDialog starts a new conversation, optionally continuing an earlier one by
+// sending its summary as context
+func (cw *ChatWindow) showNewChatDialog() {
+	var candidates []models.Conversation
+	var titles []string
+	for _, conv := range cw.allConversations {
+		candidates = append(candidates, conv)
+		titles = append(titles, conversationListTitle(conv))
+	}
+
+	sourceSelect := widget.NewSelect(titles, nil)
+	if previous := cw.previousConversationIndex(candidates); previous >= 0 {
+		sourceSelect.SetSelectedIndex(previous)
+	}
+	contextCheck := widget.NewCheck(Translate("context.include_summary"), func(checked bool) {
+		if checked {
+			sourceSelect.Enable()
+		} else {
+			sourceSelect.Disable()
+		}
+	})
+	contextCheck.SetChecked(len(candidates) > 0)
+	if len(candidates) == 0 {
+		contextCheck.Disable()
+		sourceSelect.Disable()
+	}
+
+	// An incognito conversation is kept in memory only, never written to disk
+	incognitoCheck := widget.NewCheck(Translate("incognito.new"), nil)
+
+	content := widget.NewForm(
+		widget.NewFormItem("", contextCheck),
+		widget.NewFormItem(Translate("context.source"), sourceSelect),
+		widget.NewFormItem("", incognitoCheck),
+	)
+	dialog.ShowCustomConfirm(Translate("context.new_chat_title"), Translate("context.new_chat_confirm"), Translate("common.cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		cw.confirmDiscardIncognito(func() {
+			cw.newConversation(incognitoCheck.Checked)
+			index := sourceSelect.SelectedIndex()
+			if contextCheck.Checked && index >= 0 && cw.currentConversation != nil {
+				cw.linkConversationContext(cw.currentConversation, candidates[index].ID)
+			}
+		})
+	}, cw.window)
+}
+
+// previousConversationIndex returns the position of the conversation the user was last in:
+// the open one, or else the most recently updated. It returns -1 when there is none.
+func (cw *ChatWindow) previousConversationIndex(conversations []models.Conversation) int {
+	if cw.currentConversation != nil {
+		if i := conversationIndex(conversations, cw.currentConversation.ID); i >= 0 {
+			return i
+		}
+	}
+	latest := -1
+	for i, conv := range conversations {
+		if latest < 0 || conv.UpdatedAt.After(conversations[latest].UpdatedAt) {
+			latest = i
+		}
+	}
+	return latest
+}
+
+// runContextCommand handles "/context <conversation>" typed in the message entry. It reports
+// false when text is not the command, so it is sent as a message.
+func (cw *ChatWindow) runContextCommand(text string) bool {
+	text = strings.TrimSpace(text)
+	if text != contextCommand && !strings.HasPrefix(text, contextCommand+" ") {
+		return false
+	}
+	query := strings.TrimSpace(strings.TrimPrefix(text, contextCommand))
+
+	source, err := cw.findContextConversation(query)
+	if err != nil {
+		dialog.ShowError(err, cw.window)
+		return true
+	}
+	cw.messageEntry.SetText("")
+	cw.linkConversationContext(cw.currentConversation, source.ID)
+	return true
+}
+
+// findContextConversation finds the conversation named by a /context argument: an ID, a title,
+// or a unique part of a title. An empty query means the most recently updated other conversation.
+func (cw *ChatWindow) findContextConversation(query string) (models.Conversation, error) {
+	var others []models.Conversation
+	for _, conv := range cw.allConversations {
+		if conv.ID != cw.currentConversation.ID {
+			others = append(others, conv)
+		}
+	}
+
+	if query == "" {
+		if latest := cw.previousConversationIndex(others); latest >= 0 {
+			return others[latest], nil
+		}
+		return models.Conversation{}, errors.New(Translate("context.no_source"))
+	}
+
+	var matches []models.Conversation
+	for _, conv := range others {
+		if conv.ID == query || strings.EqualFold(conv.Title, query) {
+			return conv, nil
+		}
+		if strings.Contains(strings.ToLower(conv.Title), strings.ToLower(query)) {
+			matches = append(matches, conv)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return models.Conversation{}, errors.New(Translatef("context.no_match", query))
+	case 1:
+		return matches[0], nil
+	default:
+		return models.Conversation{}, errors.New(Translatef("context.many_matches", len(matches), query))
+	}
+}
+
+// linkConversationContext makes conv continue the conversation sourceID by sending its summary
+// as context. A stored summary is reused; otherwise one is generated with the title provider
+// and saved on the source conversation too.
+func (cw *ChatWindow) linkConversationContext(conv *models.Conversation, sourceID string) {
+	if conv.ReadOnly() {
+		return
+	}
+	source, err := cw.convManager.LoadConversation(sourceID)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to load conversation: %w", err), cw.window)
+		return
+	}
+
+	if strings.TrimSpace(source.Summary) != "" {
+		cw.setConversationContext(conv.ID, source)
+		return
+	}
+	if len(source.Messages) == 0 {
+		dialog.ShowError(errors.New(Translatef("context.no_messages", source.Title)), cw.window)
+		return
+	}
+
+	cw.pendingContext = &pendingContext{convID: conv.ID, sourceTitle: source.Title}
+	cw.updateContextBar()
+
+	go func() {
+		title, summary, err := cw.summarizeConversation(source)
+		fyne.Do(func() {
+			if cw.pendingContext != nil && cw.pendingContext.convID == conv.ID {
+				cw.pendingContext = nil
+			}
+			if err != nil {
+				cw.updateContextBar()
+				cw.notifications.Post(notify.Notification{
+					Level:   notify.Error,
+					Title:   Translate("context.summary_failed_title"),
+					Message: Translatef("context.summary_failed", source.Title, err),
+				})
+				return
+			}
+
+			// Store the summary on the source so it is reused next time, reloading it so
+			// edits made while the request was running aren't lost
+			if latest, err := cw.convManager.LoadConversation(source.ID); err == nil {
+				source = latest
+			}
+			if strings.TrimSpace(source.Summary) == "" {
+				source.Summary = summary
+			}
+			if autotitle.NeedsTitle(source) {
+				source.Title = title
+			}
+			cw.convManager.SaveConversation(source)
+			cw.setConversationContext(conv.ID, source)
+		})
+	}()
+}
+
+// summarizeConversation asks the title provider for a title and summary of conv. It is
+// called from a background goroutine.
+func (cw *ChatWindow) summarizeConversation(conv *models.Conversation) (title, summary string, err error) {
+	client, err := cw.newTitleClient()
+	if err != nil {
+		return "", "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), llm.DefaultRequestTimeout)
+	defer cancel()
+	return autotitle.Generate(ctx, client, conv)
+}
+
+// setConversationContext links source as the context of the conversation convID and saves it.
+// The conversation is reloaded unless it is open, so a stale copy doesn't overwrite newer messages.
+func (cw *ChatWindow) setConversationContext(convID string, source *models.Conversation) {
+	conv := cw.currentConversation
+	if conv == nil || conv.ID != convID {
+		var err error
+		if conv, err = cw.convManager.LoadConversation(convID); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to load conversation: %w", err), cw.window)
+			return
+		}
+	}
+
+	conv.ContextFrom = &models.ConversationLink{
+		ID:      source.ID,
+		Title:   source.Title,
+		Summary: source.Summary,
+	}
+	cw.saveConversation(conv)
+	cw.updateContextBar()
+}
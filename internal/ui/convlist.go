@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"chatgo/pkg/models"
+	"slices"
+	"sort"
+)
+
+// applyConversationEvent updates the sidebar for a saved or deleted conversation without
+// reloading the others. A conversation whose position is unchanged only redraws its row.
+func (cw *ChatWindow) applyConversationEvent(event models.ConversationEvent) {
+	tagsChanged := true
+	if event.Kind == models.ConversationRemoved {
+		cw.allConversations = removeConversation(cw.allConversations, event.ID)
+	} else {
+		if i := conversationIndex(cw.allConversations, event.ID); i >= 0 {
+			tagsChanged = !slices.Equal(cw.allConversations[i].Tags, event.Conversation.Tags)
+		}
+		cw.allConversations, _, _ = upsertConversation(cw.allConversations, event.Conversation)
+	}
+	// Switching to the home page reloads the recent list
+	if cw.isHomeMode {
+		cw.updateRecentConversations()
+	}
+
+	// Dropping the last conversation with a selected tag removes it from the tag filter
+	if tagsChanged {
+		selectedTags := len(cw.selectedTags)
+		cw.updateTagFilter(models.CollectTags(cw.allConversations))
+		if len(cw.selectedTags) != selectedTags {
+			cw.filterConversations()
+			return
+		}
+	}
+
+	shown := conversationIndex(cw.convListData, event.ID) >= 0
+	selected := cw.currentConversationIndex()
+	switch {
+	case event.Kind != models.ConversationRemoved && cw.conversationVisible(event.Conversation):
+		var from, to int
+		cw.convListData, from, to = upsertConversation(cw.convListData, event.Conversation)
+		if from == to {
+			if cw.convList != nil {
+				cw.convList.RefreshItem(to)
+			}
+			return
+		}
+	case shown:
+		cw.convListData = removeConversation(cw.convListData, event.ID)
+	default:
+		return
+	}
+
+	// Rows between the old and new position have shifted. Selecting the open conversation's
+	// row where it has moved to redraws them too.
+	if cw.convList != nil {
+		if selected < 0 || cw.currentConversationIndex() == selected {
+			cw.convList.Refresh()
+		}
+		cw.syncConversationSelection()
+	}
+}
+
+// currentConversationIndex returns the sidebar row of the open conversation, or -1
+func (cw *ChatWindow) currentConversationIndex() int {
+	if cw.currentConversation == nil {
+		return -1
+	}
+	return conversationIndex(cw.convListData, cw.currentConversation.ID)
+}
+
+// syncConversationSelection highlights the open conversation's row after rows have moved,
+// without reloading the conversation
+func (cw *ChatWindow) syncConversationSelection() {
+	if cw.currentConversation == nil {
+		return
+	}
+	index := cw.currentConversationIndex()
+	if index < 0 {
+		cw.convList.UnselectAll()
+		return
+	}
+
+	cw.syncingSelection = true
+	cw.convList.Select(index)
+	cw.syncingSelection = false
+}
+
+// conversationIndex returns the position of the conversation with the given ID, or -1
+func conversationIndex(conversations []models.Conversation, id string) int {
+	for i := range conversations {
+		if conversations[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// upsertConversation places conv at its sorted position, replacing any earlier copy. It returns
+// the updated slice with conv's previous position, -1 if it is new, and its new position.
+func upsertConversation(conversations []models.Conversation, conv models.Conversation) ([]models.Conversation, int, int) {
+	from := conversationIndex(conversations, conv.ID)
+	if from >= 0 {
+		// Most saves keep the conversation where it is
+		if (from == 0 || !conversationBefore(conv, conversations[from-1])) &&
+			(from == len(conversations)-1 || !conversationBefore(conversations[from+1], conv)) {
+			conversations[from] = conv
+			return conversations, from, from
+		}
+		conversations = append(conversations[:from], conversations[from+1:]...)
+	}
+
+	to := sort.Search(len(conversations), func(i int) bool {
+		return !conversationBefore(conversations[i], conv)
+	})
+	conversations = append(conversations, models.Conversation{})
+	copy(conversations[to+1:], conversations[to:])
+	conversations[to] = conv
+	return conversations, from, to
+}
+
+// removeConversation drops the conversation with the given ID, if present
+func removeConversation(conversations []models.Conversation, id string) []models.Conversation {
+	if i := conversationIndex(conversations, id); i >= 0 {
+		return append(conversations[:i], conversations[i+1:]...)
+	}
+	return conversations
+}
@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"chatgo/pkg/models"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// sidebarSize is how many conversations the sidebar benchmarks list
+const sidebarSize = 5000
+
+// sidebarWithConversations returns a chat window whose sidebar lists n conversations, updated
+// a minute apart, with one of them open. The window's background work updates it through
+// fyne.Do, so the caller changes it only within the driver's do.
+func sidebarWithConversations(b testing.TB, n int) (*ChatWindow, *uiThreadDriver, time.Time) {
+	b.Helper()
+	app, driver := newUIThreadApp(b)
+	driver.mu.Lock()
+	defer driver.mu.Unlock()
+	cw, err := NewChatWindow(app, &config.Config{})
+	if err != nil {
+		b.Fatalf("NewChatWindow: %v", err)
+	}
+	b.Cleanup(func() { driver.do(cw.window.Close) })
+	cw.window.Resize(fyne.NewSize(1200, 800))
+	cw.switchToChatUI()
+
+	latest := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	conversations := make([]models.Conversation, n)
+	for i := range conversations {
+		updated := latest.Add(-time.Duration(i) * time.Minute)
+		conversations[i] = models.Conversation{
+			ID:        fmt.Sprintf("%s-%06d", updated.Format("20060102150405"), i),
+			Title:     fmt.Sprintf("Conversation %d", i),
+			Tags:      []string{fmt.Sprintf("tag%d", i%20)},
+			CreatedAt: updated,
+			UpdatedAt: updated,
+		}
+	}
+	cw.allConversations = conversations
+	cw.convListData = slices.Clone(conversations)
+	cw.convList.Refresh()
+	current := conversations[n/2]
+	cw.currentConversation = &current
+	cw.syncConversationSelection()
+	return cw, driver, latest
+}
+
+func TestApplyConversationEvent(t *testing.T) {
+	cw, driver, latest := sidebarWithConversations(t, 10)
+	driver.mu.Lock()
+	defer driver.mu.Unlock()
+	ids := func(conversations []models.Conversation) []string {
+		var ids []string
+		for _, conv := range conversations {
+			ids = append(ids, conv.ID)
+		}
+		return ids
+	}
+	apply := func(kind models.ConversationEventKind, conv models.Conversation) {
+		t.Helper()
+		cw.applyConversationEvent(models.ConversationEvent{Kind: kind, ID: conv.ID, Conversation: conv})
+		for i := 1; i < len(cw.allConversations); i++ {
+			if conversationBefore(cw.allConversations[i], cw.allConversations[i-1]) {
+				t.Fatalf("conversations out of order after updating %s: %q", conv.Title, ids(cw.allConversations))
+			}
+		}
+		if !slices.Equal(ids(cw.convListData), ids(cw.allConversations)) {
+			t.Fatalf("sidebar lists %q, want %q", ids(cw.convListData), ids(cw.allConversations))
+		}
+	}
+	before := ids(cw.convListData)
+
+	renamed := cw.convListData[3]
+	renamed.Title = "Renamed"
+	apply(models.ConversationUpdated, renamed)
+	if !slices.Equal(ids(cw.convListData), before) || cw.convListData[3].Title != "Renamed" {
+		t.Errorf("renaming moved rows or wasn't shown: %q", ids(cw.convListData))
+	}
+
+	moved := cw.convListData[9]
+	moved.UpdatedAt = latest.Add(time.Second)
+	apply(models.ConversationUpdated, moved)
+	if cw.convListData[0].ID != moved.ID {
+		t.Errorf("updated conversation isn't at the top: %q", ids(cw.convListData))
+	}
+
+	pinned := cw.convListData[5]
+	pinned.Pinned = true
+	pinned.UpdatedAt = latest.Add(-time.Hour)
+	apply(models.ConversationUpdated, pinned)
+	if cw.convListData[0].ID != pinned.ID {
+		t.Errorf("pinned conversation isn't at the top: %q", ids(cw.convListData))
+	}
+
+	tagged := cw.convListData[2]
+	tagged.Tags = []string{"fresh"}
+	apply(models.ConversationUpdated, tagged)
+	if !slices.Contains(models.CollectTags(cw.allConversations), "fresh") || !slices.ContainsFunc(cw.tagFilter.Objects, func(o fyne.CanvasObject) bool {
+		button, ok := o.(*widget.Button)
+		return ok && button.Text == "#fresh"
+	}) {
+		t.Error("the tag filter doesn't offer a newly added tag")
+	}
+
+	apply(models.ConversationRemoved, moved)
+	if slices.Contains(ids(cw.convListData), moved.ID) || len(cw.convListData) != 9 {
+		t.Errorf("removed conversation is still listed: %q", ids(cw.convListData))
+	}
+}
+
+func TestSetRowTagChips(t *testing.T) {
+	cw := newTestChatWindow(t)
+	row := container.NewHBox()
+	shown := func() []string {
+		var texts []string
+		for _, object := range row.Objects {
+			switch object := object.(type) {
+			case *widget.Button:
+				texts = append(texts, object.Text)
+			case *widget.Label:
+				texts = append(texts, object.Text)
+			}
+		}
+		return texts
+	}
+
+	// A recycled row goes from one conversation's tags to another's
+	for _, tt := range []struct {
+		tags []string
+		want []string
+	}{
+		{[]string{"a", "b", "c"}, []string{"#a", "#b", "+1"}},
+		{[]string{"x"}, []string{"#x"}},
+		{[]string{"x", "y", "z", "w"}, []string{"#x", "#y", "+2"}},
+		{nil, nil},
+		{[]string{"a", "b"}, []string{"#a", "#b"}},
+	} {
+		cw.setRowTagChips(row, tt.tags)
+		if got := shown(); !slices.Equal(got, tt.want) {
+			t.Errorf("chips for %q = %q, want %q", tt.tags, got, tt.want)
+		}
+	}
+}
+
+// BenchmarkSidebarRename measures updating the sidebar for a conversation saved without
+// moving, e.g. renamed, which redraws only its row
+func BenchmarkSidebarRename(b *testing.B) {
+	cw, driver, _ := sidebarWithConversations(b, sidebarSize)
+	driver.mu.Lock()
+	defer driver.mu.Unlock()
+	conv := cw.convListData[sidebarSize/3]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conv.Title = fmt.Sprintf("Renamed %d", i)
+		cw.applyConversationEvent(models.ConversationEvent{Kind: models.ConversationUpdated, ID: conv.ID, Conversation: conv})
+	}
+}
+
+// BenchmarkSidebarMoveToTop measures updating the sidebar for a conversation that received a
+// message, moving it from the bottom of the list to the top
+func BenchmarkSidebarMoveToTop(b *testing.B) {
+	cw, driver, latest := sidebarWithConversations(b, sidebarSize)
+	driver.mu.Lock()
+	defer driver.mu.Unlock()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conv := cw.convListData[len(cw.convListData)-1]
+		conv.UpdatedAt = latest.Add(time.Duration(i+1) * time.Second)
+		cw.applyConversationEvent(models.ConversationEvent{Kind: models.ConversationUpdated, ID: conv.ID, Conversation: conv})
+	}
+}
+
+// BenchmarkSidebarReload measures rebuilding the sidebar from every conversation, which the
+// incremental updates avoid
+func BenchmarkSidebarReload(b *testing.B) {
+	cw, driver, _ := sidebarWithConversations(b, sidebarSize)
+	driver.mu.Lock()
+	defer driver.mu.Unlock()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cw.filterConversations()
+	}
+}
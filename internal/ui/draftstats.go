@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"chatgo/internal/llm"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+// newDraftStatsLabel creates the label under the message entry that sizes up the draft
+func (cw *ChatWindow) newDraftStatsLabel() *widget.Label {
+	cw.draftStatsLabel = widget.NewLabel("")
+	cw.draftStatsLabel.Importance = widget.LowImportance
+	cw.draftStatsLabel.Hide()
+	return cw.draftStatsLabel
+}
+
+// updateDraftStats shows the word, character and estimated token counts of the draft, and
+// what its tokens would cost as input to the current model when the catalog has its price.
+// The label is hidden while the draft is empty.
+func (cw *ChatWindow) updateDraftStats() {
+	if cw.draftStatsLabel == nil {
+		return
+	}
+	text := cw.messageEntry.Text
+	if strings.TrimSpace(text) == "" {
+		cw.draftStatsLabel.Hide()
+		return
+	}
+
+	tokens := llm.EstimateTokens(text)
+	stats := fmt.Sprintf("%d words · %d characters · ~%d tokens",
+		len(strings.Fields(text)), utf8.RuneCountInString(text), tokens)
+	if p, ok := cw.currentProvider(); ok {
+		info, _ := llm.ModelCatalog.ForProvider(p)
+		if cost, ok := info.EstimateCost(llm.TokenUsage{PromptTokens: tokens}); ok {
+			stats += " · " + formatDraftCost(cost) + " as input"
+		}
+	}
+	cw.draftStatsLabel.SetText(stats)
+	cw.draftStatsLabel.Show()
+}
+
+// formatDraftCost formats a USD amount, keeping a few significant digits of small amounts
+func formatDraftCost(cost float64) string {
+	switch {
+	case cost == 0:
+		return "$0"
+	case cost < 0.0001:
+		return "<$0.0001"
+	case cost < 0.01:
+		return fmt.Sprintf("~$%.4f", cost)
+	default:
+		return fmt.Sprintf("~$%.2f", cost)
+	}
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// emptyStatePrompts are the keys of the example prompts offered in an empty conversation
+var emptyStatePrompts = []string{"empty.prompt_explain", "empty.prompt_summarize", "empty.prompt_write"}
+
+// maxEmptyStateTools is how many selected tools the empty state names before summarizing the rest
+const maxEmptyStateTools = 6
+
+// showEmptyState fills the chat of a conversation without messages with hints on how to start:
+// the model that will answer, example prompts, how to send, and the tools in use
+func (cw *ChatWindow) showEmptyState() {
+	cw.emptyState = cw.newEmptyState()
+	cw.messagesContainer.Objects = []fyne.CanvasObject{cw.emptyState}
+	cw.messagesContainer.Refresh()
+}
+
+// hideEmptyState removes the empty state before the first message is added to the chat
+func (cw *ChatWindow) hideEmptyState() {
+	if cw.emptyState == nil {
+		return
+	}
+	cw.messagesContainer.Remove(cw.emptyState)
+	cw.emptyState = nil
+}
+
+// refreshEmptyState rebuilds the empty state, if shown, after the provider, agent mode or tool
+// selection changed
+func (cw *ChatWindow) refreshEmptyState() {
+	if cw.emptyState == nil || cw.currentConversation == nil {
+		return
+	}
+	cw.showEmptyState()
+}
+
+// newEmptyState creates the empty state for the current conversation
+func (cw *ChatWindow) newEmptyState() fyne.CanvasObject {
+	title := widget.NewLabelWithStyle(Translate("empty.title"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	providerName, model := cw.currentConversation.Provider, cw.currentConversation.Model
+	if provider, ok := cw.currentProvider(); ok {
+		model = provider.Model
+	}
+	modelLabel := widget.NewLabelWithStyle(Translatef("empty.model", providerName, model), fyne.TextAlignCenter, fyne.TextStyle{})
+
+	// Clicking an example puts it in the input to be edited or sent
+	prompts := container.NewVBox()
+	for _, key := range emptyStatePrompts {
+		text := Translate(key)
+		btn := widget.NewButton(text, func() {
+			cw.messageEntry.SetText(text)
+			cw.window.Canvas().Focus(cw.messageEntry)
+		})
+		btn.Importance = widget.LowImportance
+		if cw.messageEntry.Disabled() {
+			btn.Disable()
+		}
+		prompts.Add(btn)
+	}
+
+	sendKey := shortcutText(sendShortcut)
+	sendHint := Translatef("empty.send_hint", sendKey)
+	if cw.config.SendOnEnter {
+		sendHint = Translatef("empty.send_hint_enter", sendKey)
+	}
+	hint := widget.NewLabelWithStyle(sendHint, fyne.TextAlignCenter, fyne.TextStyle{})
+	hint.Importance = widget.LowImportance
+
+	parts := []fyne.CanvasObject{title, modelLabel, container.NewCenter(prompts), hint}
+	if cw.config.UseReactAgent {
+		tools := widget.NewLabelWithStyle(cw.emptyStateTools(), fyne.TextAlignCenter, fyne.TextStyle{})
+		tools.Wrapping = fyne.TextWrapWord
+		tools.Importance = widget.LowImportance
+		parts = append(parts, tools)
+	}
+	return container.NewPadded(container.NewVBox(parts...))
+}
+
+// emptyStateTools describes the tools the agent may use in the conversation
+func (cw *ChatWindow) emptyStateTools() string {
+	selected := cw.toolSelectionMgr.GetSelectedTools()
+	if len(selected) == 0 {
+		return Translate("empty.no_tools")
+	}
+
+	names := make([]string, 0, min(len(selected), maxEmptyStateTools))
+	for _, id := range selected[:min(len(selected), maxEmptyStateTools)] {
+		names = append(names, toolName(id))
+	}
+	list := strings.Join(names, ", ")
+	if more := len(selected) - len(names); more > 0 {
+		list = Translatef("empty.more_tools", list, more)
+	}
+	return Translatef("empty.tools", len(selected), list)
+}
+
+// toolName returns the name of a tool from its selection ID, such as "builtin:calculator" or
+// "mcp:server:tool"
+func toolName(id string) string {
+	if name, ok := strings.CutPrefix(id, "builtin:"); ok {
+		return name
+	}
+	if rest, ok := strings.CutPrefix(id, "mcp:"); ok {
+		if _, name, ok := strings.Cut(rest, ":"); ok {
+			return name
+		}
+	}
+	return id
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"chatgo/pkg/models"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// exportUserMessages saves the user's messages of a conversation to a markdown file,
+// e.g. to refine them into a reusable prompt
+func (cw *ChatWindow) exportUserMessages(convID string) {
+	conv, err := cw.convManager.LoadConversation(convID)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to load conversation: %w", err), cw.window)
+		return
+	}
+
+	count := 0
+	for _, msg := range conv.Messages {
+		if msg.Role == "user" && strings.TrimSpace(msg.Content) != "" {
+			count++
+		}
+	}
+	if count == 0 {
+		dialog.ShowInformation(Translate("export.my_messages"), Translate("export.no_messages"), cw.window)
+		return
+	}
+
+	numberedCheck := widget.NewCheck(Translate("export.numbered"), nil)
+	content := widget.NewForm(
+		widget.NewFormItem("", widget.NewLabel(Translatef("export.count", count))),
+		widget.NewFormItem("", numberedCheck),
+	)
+
+	dialog.ShowCustomConfirm(Translate("export.my_messages"), Translate("export.confirm"), Translate("common.cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		markdown := models.UserMessagesMarkdown(conv, numberedCheck.Checked)
+
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, cw.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+			if _, err := writer.Write([]byte(markdown)); err != nil {
+				dialog.ShowError(fmt.Errorf("failed to write %s: %w", writer.URI().Name(), err), cw.window)
+			}
+		}, cw.window)
+		save.SetFileName(exportFileName(conv.Title) + ".md")
+		save.SetFilter(storage.NewExtensionFileFilter([]string{".md"}))
+		save.Show()
+	}, cw.window)
+}
+
+// exportFileName turns a conversation title into a file name, replacing characters that
+// are not allowed in file names on common platforms
+func exportFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(title))
+	if name == "" {
+		return "conversation"
+	}
+	return name
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// externalEditPollInterval is how often an externally edited conversation file is checked for changes
+const externalEditPollInterval = time.Second
+
+// externalEditSession tracks a conversation file that is open in an external editor
+type externalEditSession struct {
+	convID  string
+	path    string
+	modTime time.Time
+	stop    chan struct{}
+}
+
+// showConversationMenu shows the archive, maintenance and export actions for a conversation next to its list row
+func (cw *ChatWindow) showConversationMenu(id widget.ListItemID, anchor fyne.CanvasObject) {
+	if id < 0 || id >= len(cw.convListData) {
+		return
+	}
+	convID := cw.convListData[id].ID
+
+	archiveLabel := Translate("conversation.archive")
+	if cw.convListData[id].Archived {
+		archiveLabel = Translate("conversation.unarchive")
+	}
+
+	menu := fyne.NewMenu("",
+		fyne.NewMenuItem(archiveLabel, func() {
+			cw.toggleArchived(id)
+		}),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(Translate("conversation.reveal_file"), func() {
+			cw.revealConversationFile(convID)
+		}),
+		fyne.NewMenuItem(Translate("conversation.open_in_editor"), func() {
+			cw.openConversationInEditor(convID)
+		}),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(Translate("export.my_messages"), func() {
+			cw.exportUserMessages(convID)
+		}),
+	)
+
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(anchor)
+	widget.ShowPopUpMenuAtPosition(menu, cw.window.Canvas(), pos.Add(fyne.NewPos(0, anchor.Size().Height)))
+}
+
+// revealConversationFile opens the folder containing a conversation file with the OS opener
+func (cw *ChatWindow) revealConversationFile(convID string) {
+	dir := filepath.Dir(cw.convManager.ConversationPath(convID))
+	if err := cw.app.OpenURL(&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}); err != nil {
+		dialog.ShowError(fmt.Errorf("failed to open folder: %w", err), cw.window)
+	}
+}
+
+// openConversationInEditor opens a conversation file in the configured external editor.
+// Saving is paused for the conversation and the file is watched until the session is finished.
+func (cw *ChatWindow) openConversationInEditor(convID string) {
+	path := cw.convManager.ConversationPath(convID)
+	info, err := os.Stat(path)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to open conversation file: %w", err), cw.window)
+		return
+	}
+
+	if err := cw.launchEditor(path); err != nil {
+		dialog.ShowError(fmt.Errorf("failed to start external editor: %w", err), cw.window)
+		return
+	}
+
+	// Only one external editing session at a time
+	cw.finishExternalEdit()
+
+	session := &externalEditSession{
+		convID:  convID,
+		path:    path,
+		modTime: info.ModTime(),
+		stop:    make(chan struct{}),
+	}
+	cw.externalEdit = session
+	cw.convManager.PauseSaving(convID)
+	cw.updateReadOnlyState()
+
+	go cw.watchExternalEdit(session)
+}
+
+// launchEditor starts the configured editor command, or the OS default application when none is set
+func (cw *ChatWindow) launchEditor(path string) error {
+	command := strings.Fields(cw.config.ExternalEditor)
+	if len(command) == 0 {
+		return cw.app.OpenURL(&url.URL{Scheme: "file", Path: filepath.ToSlash(path)})
+	}
+
+	cmd := exec.Command(command[0], append(command[1:], path)...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the process; the session itself is finished explicitly by the user
+	go cmd.Wait()
+	return nil
+}
+
+// watchExternalEdit polls the conversation file and reloads it whenever it changes on disk
+func (cw *ChatWindow) watchExternalEdit(session *externalEditSession) {
+	ticker := time.NewTicker(externalEditPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-session.stop:
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(session.path)
+		if err != nil || info.ModTime().Equal(session.modTime) {
+			continue
+		}
+		session.modTime = info.ModTime()
+
+		// Validate before touching the UI so a half-written file never replaces the conversation
+		conv, err := cw.convManager.LoadConversation(session.convID)
+		if err == nil && conv.ID != session.convID {
+			err = fmt.Errorf("conversation id changed from %s to %s", session.convID, conv.ID)
+		}
+
+		fyne.Do(func() {
+			if cw.externalEdit != session {
+				return
+			}
+			if err != nil {
+				cw.readOnlyLabel.SetText(fmt.Sprintf("External changes were not loaded: %v", err))
+				return
+			}
+			cw.updateReadOnlyState()
+			cw.loadConversations()
+			if cw.currentConversation != nil && cw.currentConversation.ID == session.convID {
+				cw.loadConversation(session.convID)
+			}
+		})
+	}
+}
+
+// finishExternalEdit ends the current external editing session and resumes saving
+func (cw *ChatWindow) finishExternalEdit() {
+	session := cw.externalEdit
+	if session == nil {
+		return
+	}
+
+	close(session.stop)
+	cw.externalEdit = nil
+	cw.convManager.ResumeSaving(session.convID)
+
+	// Pick up the final version of the file so later saves don't overwrite it
+	if cw.currentConversation != nil && cw.currentConversation.ID == session.convID {
+		cw.loadConversation(session.convID)
+	} else {
+		cw.updateReadOnlyState()
+	}
+	cw.loadConversations()
+}
@@ -0,0 +1,775 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"chatgo/internal/llm"
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// providerTypes lists the provider types selectable in the provider form
+var providerTypes = []string{"openai", "azure", "anthropic", "claude", "ollama", "custom", "openrouter", "qwen", "deepseek", "gemini"}
+
+// mcpServerTypes lists the MCP server types selectable in the MCP server form
+var mcpServerTypes = []string{"stdio", "sse", "streamable_http"}
+
+// ProviderForm is the shared editor for a provider configuration,
+// used by both the Providers tab and the add/edit provider dialog
+type ProviderForm struct {
+	NameEntry      *widget.Entry
+	TypeSelect     *widget.Select
+	APIKeyEntry    *widget.Entry
+	BaseURLEntry   *widget.Entry
+	ModelEntry     *widget.SelectEntry
+	TimeoutEntry   *widget.Entry
+	RateLimitEntry *widget.Entry
+	EnabledCheck   *widget.Check
+	FetchModelsBtn *widget.Button
+	ModelDetail    *widget.Label
+
+	// NoStreamCheck requests whole replies, for gateways that reject streaming
+	NoStreamCheck *widget.Check
+
+	// Context window limits for the history sent with each request
+	MaxMessagesEntry *widget.Entry
+	MaxTokensEntry   *widget.Entry
+	SummarizeCheck   *widget.Check
+
+	// Azure OpenAI fields, shown only for the azure type
+	DeploymentEntry *widget.Entry
+	APIVersionEntry *widget.Entry
+	azureFields     []fyne.CanvasObject
+
+	// Options only some provider types read, as KEY=VALUE lines, with the known options of the
+	// selected type offered and described below
+	ExtrasEntry   *widget.Entry
+	extrasSelect  *widget.Select
+	extrasHint    *widget.Label
+	extrasWarning *widget.Label
+
+	// fetchedModels are the models listed by the provider; the catalog is offered until fetched
+	fetchedModels []string
+
+	// defaultBaseURL is the base URL prefilled for the selected type, if any
+	defaultBaseURL string
+
+	// Content is the form layout to embed in a tab or dialog
+	Content fyne.CanvasObject
+}
+
+// NewProviderForm creates an empty provider form. Errors from model fetching are shown on parent
+// and clientOptions supplies the HTTP settings used to reach the models endpoint.
+func NewProviderForm(parent fyne.Window, clientOptions func() llm.ClientOptions) *ProviderForm {
+	f := &ProviderForm{
+		NameEntry:      widget.NewEntry(),
+		TypeSelect:     widget.NewSelect(providerTypes, nil),
+		APIKeyEntry:    widget.NewEntry(),
+		BaseURLEntry:   widget.NewEntry(),
+		ModelEntry:     widget.NewSelectEntry(nil),
+		TimeoutEntry:   widget.NewEntry(),
+		RateLimitEntry: widget.NewEntry(),
+		EnabledCheck:   widget.NewCheck(Translate("settings.enabled"), nil),
+		ModelDetail:    widget.NewLabel(""),
+
+		DeploymentEntry: widget.NewEntry(),
+		APIVersionEntry: widget.NewEntry(),
+
+		MaxMessagesEntry: widget.NewEntry(),
+		MaxTokensEntry:   widget.NewEntry(),
+		SummarizeCheck:   widget.NewCheck(Translate("provider.summarize_left_out"), nil),
+
+		NoStreamCheck: widget.NewCheck(Translate("provider.no_stream"), nil),
+
+		ExtrasEntry:   widget.NewMultiLineEntry(),
+		extrasHint:    widget.NewLabel(""),
+		extrasWarning: newKeyValueWarning(),
+	}
+	f.APIKeyEntry.Password = true
+	f.ModelDetail.Wrapping = fyne.TextWrapWord
+	f.ModelEntry.SetPlaceHolder("Model name")
+	f.TimeoutEntry.SetPlaceHolder(fmt.Sprintf("%d", int(llm.DefaultRequestTimeout.Seconds())))
+	f.RateLimitEntry.SetPlaceHolder("Unlimited")
+	f.DeploymentEntry.SetPlaceHolder("Deployment name in Azure AI Foundry")
+	f.APIVersionEntry.SetPlaceHolder(llm.AzureDefaultAPIVersion)
+	f.MaxMessagesEntry.SetPlaceHolder("All that fit")
+	f.MaxTokensEntry.SetPlaceHolder("3/4 of the model's context window")
+	f.ExtrasEntry.SetPlaceHolder("KEY=VALUE, one per line")
+	f.ExtrasEntry.SetMinRowsVisible(2)
+	f.ExtrasEntry.OnChanged = func(string) {
+		f.updateExtrasWarning()
+	}
+	f.extrasHint.Wrapping = fyne.TextWrapWord
+	f.extrasHint.Importance = widget.LowImportance
+
+	// Picking a known option adds a line for it
+	f.extrasSelect = widget.NewSelect(nil, func(name string) {
+		if name == "" {
+			return
+		}
+		text := strings.TrimRight(f.ExtrasEntry.Text, "\n")
+		if text != "" {
+			text += "\n"
+		}
+		f.ExtrasEntry.SetText(text + name + "=")
+		f.extrasSelect.ClearSelected()
+	})
+	f.extrasSelect.PlaceHolder = Translate("provider.add_option")
+
+	// Fetch available models from the provider's models endpoint
+	f.FetchModelsBtn = widget.NewButtonWithIcon("Fetch Models", theme.ViewRefreshIcon(), func() {
+		provider := config.Provider{
+			Type:    f.TypeSelect.Selected,
+			APIKey:  f.APIKeyEntry.Text,
+			BaseURL: f.BaseURLEntry.Text,
+			Extras:  parseKeyValueLines(f.ExtrasEntry.Text),
+		}
+		f.FetchModelsBtn.Disable()
+		go func() {
+			models, err := llm.ListModels(provider, clientOptions())
+			fyne.Do(func() {
+				f.FetchModelsBtn.Enable()
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("failed to fetch models: %w", err), parent)
+					return
+				}
+				f.fetchedModels = models
+				if f.ModelEntry.Text == "" && len(models) > 0 {
+					f.ModelEntry.SetText(models[0])
+				}
+				f.updateModelOptions()
+			})
+		}()
+	})
+	f.FetchModelsBtn.Disable()
+
+	// Model listing is only available for some provider types; others keep a free-text entry
+	f.TypeSelect.OnChanged = func(providerType string) {
+		f.updateBaseURL(providerType)
+		f.showTypeFields(providerType)
+		f.updateExtrasWarning()
+		f.fetchedModels = nil
+		f.updateModelOptions()
+		f.updateModelDetail()
+		if llm.SupportsModelListing(providerType) {
+			f.FetchModelsBtn.Enable()
+		} else {
+			f.FetchModelsBtn.Disable()
+		}
+	}
+
+	// Typing narrows the dropdown and shows what the catalog knows about the model
+	f.ModelEntry.OnChanged = func(string) {
+		f.updateModelOptions()
+		f.updateModelDetail()
+	}
+
+	// The form skips rows whose label and entry are both hidden
+	deploymentLabel := newFormLabel("Deployment:")
+	apiVersionLabel := newFormLabel("API Version:")
+	f.azureFields = []fyne.CanvasObject{deploymentLabel, f.DeploymentEntry, apiVersionLabel, f.APIVersionEntry}
+	f.showTypeFields("")
+
+	f.Content = newFormGrid(
+		newFormLabel("Name:"), f.NameEntry,
+		newFormLabel("Type:"), f.TypeSelect,
+		newFormLabel("API Key:"), f.APIKeyEntry,
+		newFormLabel("Base URL:"), f.BaseURLEntry,
+		deploymentLabel, f.DeploymentEntry,
+		apiVersionLabel, f.APIVersionEntry,
+		newFormLabel("Model:"), container.NewBorder(nil, nil, nil, f.FetchModelsBtn, f.ModelEntry),
+		newFormLabel(""), f.ModelDetail,
+		newFormLabel("Timeout (seconds):"), f.TimeoutEntry,
+		newFormLabel("Requests per minute:"), f.RateLimitEntry,
+		newFormLabel("Context messages:"), f.MaxMessagesEntry,
+		newFormLabel("Context tokens:"), f.MaxTokensEntry,
+		newFormLabel(""), f.SummarizeCheck,
+		newFormLabel(""), f.NoStreamCheck,
+		newFormLabel("Options:"), container.NewVBox(f.ExtrasEntry, f.extrasWarning, container.NewBorder(nil, nil, nil, f.extrasSelect, f.extrasHint)),
+		newFormLabel(""), f.EnabledCheck,
+	)
+
+	return f
+}
+
+// updateBaseURL prefills the default base URL of the selected provider type. A default left
+// in the entry from the previous type is cleared; a URL the user entered is kept.
+func (f *ProviderForm) updateBaseURL(providerType string) {
+	current := strings.TrimSpace(f.BaseURLEntry.Text)
+	if current != "" && current != f.defaultBaseURL {
+		f.defaultBaseURL = llm.DefaultBaseURL(providerType)
+		return
+	}
+	f.defaultBaseURL = llm.DefaultBaseURL(providerType)
+	f.BaseURLEntry.SetText(f.defaultBaseURL)
+}
+
+// showTypeFields shows the fields only the selected provider type uses
+func (f *ProviderForm) showTypeFields(providerType string) {
+	for _, field := range f.azureFields {
+		if providerType == "azure" {
+			field.Show()
+		} else {
+			field.Hide()
+		}
+	}
+	if providerType == "azure" {
+		f.BaseURLEntry.SetPlaceHolder("https://<resource>.openai.azure.com")
+	} else {
+		f.BaseURLEntry.SetPlaceHolder("")
+	}
+
+	// The known options of the type
+	var names, descriptions []string
+	for _, key := range config.KnownExtras[providerType] {
+		names = append(names, key.Name)
+		descriptions = append(descriptions, describeExtra(key))
+	}
+	f.extrasSelect.SetOptions(names)
+	if len(names) == 0 {
+		f.extrasSelect.Hide()
+		f.extrasHint.SetText("")
+	} else {
+		f.extrasSelect.Show()
+		f.extrasHint.SetText(strings.Join(descriptions, "\n"))
+	}
+}
+
+// describeExtra describes a known provider option for the form, e.g.
+// "num_ctx (number): Context window in tokens"
+func describeExtra(key config.ExtraKey) string {
+	var values string
+	switch {
+	case len(key.Values) > 0:
+		values = strings.Join(key.Values, ", ")
+	case key.Kind == config.ExtraInt:
+		values = "number"
+	case key.Kind == config.ExtraBool:
+		values = "true or false"
+	}
+	if values == "" {
+		return fmt.Sprintf("%s: %s", key.Name, key.Description)
+	}
+	return fmt.Sprintf("%s (%s): %s", key.Name, values, key.Description)
+}
+
+// updateExtrasWarning flags option lines that are not KEY=VALUE, values the selected type can't
+// use, and keys it doesn't read
+func (f *ProviderForm) updateExtrasWarning() {
+	updateKeyValueWarning(f.extrasWarning, f.ExtrasEntry.Text)
+	if f.extrasWarning.Visible() {
+		return
+	}
+
+	providerType := f.TypeSelect.Selected
+	extras := parseKeyValueLines(f.ExtrasEntry.Text)
+	if err := config.ValidateExtras(providerType, extras); err != nil {
+		f.extrasWarning.SetText(err.Error())
+		f.extrasWarning.Show()
+		return
+	}
+	var unknown []string
+	for key := range extras {
+		if _, ok := config.KnownExtra(providerType, key); !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 && providerType != "" {
+		sort.Strings(unknown)
+		f.extrasWarning.SetText(Translatef("settings.unknown_extras", providerType, strings.Join(unknown, ", ")))
+		f.extrasWarning.Show()
+		return
+	}
+	f.extrasWarning.Hide()
+}
+
+// updateModelOptions offers the fetched models, or the catalog models for the selected type,
+// that match the model text. Everything is offered when nothing matches.
+func (f *ProviderForm) updateModelOptions() {
+	var options, matches []string
+	if len(f.fetchedModels) > 0 {
+		options = f.fetchedModels
+		query := strings.ToLower(strings.TrimSpace(f.ModelEntry.Text))
+		for _, option := range options {
+			if strings.Contains(strings.ToLower(option), query) {
+				matches = append(matches, option)
+			}
+		}
+	} else {
+		for _, model := range llm.ModelCatalog.Models(f.TypeSelect.Selected) {
+			options = append(options, model.ID)
+		}
+		for _, model := range llm.ModelCatalog.Search(f.TypeSelect.Selected, f.ModelEntry.Text) {
+			matches = append(matches, model.ID)
+		}
+	}
+
+	if len(matches) == 0 {
+		matches = options
+	}
+	f.ModelEntry.SetOptions(matches)
+}
+
+// updateModelDetail shows the catalog metadata of the entered model
+func (f *ProviderForm) updateModelDetail() {
+	model := strings.TrimSpace(f.ModelEntry.Text)
+	if model == "" {
+		f.ModelDetail.SetText("")
+		return
+	}
+	info, ok := llm.ModelCatalog.Lookup(f.TypeSelect.Selected, model)
+	if detail := info.Describe(); ok && detail != "" {
+		f.ModelDetail.SetText(detail)
+	} else {
+		f.ModelDetail.SetText(Translate("provider.no_model_metadata"))
+	}
+}
+
+// Bind populates the form from a provider, or clears it when provider is nil.
+// A cleared form defaults to enabled when enabledByDefault is set.
+func (f *ProviderForm) Bind(provider *config.Provider, enabledByDefault bool) {
+	f.fetchedModels = nil
+	defer func() {
+		f.updateModelOptions()
+		f.updateModelDetail()
+	}()
+
+	if provider == nil {
+		f.NameEntry.SetText("")
+		f.TypeSelect.SetSelected("")
+		f.showTypeFields("")
+		f.APIKeyEntry.SetText("")
+		f.BaseURLEntry.SetText("")
+		f.ModelEntry.SetText("")
+		f.DeploymentEntry.SetText("")
+		f.APIVersionEntry.SetText("")
+		f.TimeoutEntry.SetText("")
+		f.RateLimitEntry.SetText("")
+		f.MaxMessagesEntry.SetText("")
+		f.MaxTokensEntry.SetText("")
+		f.SummarizeCheck.SetChecked(false)
+		f.NoStreamCheck.SetChecked(false)
+		f.ExtrasEntry.SetText("")
+		f.EnabledCheck.SetChecked(enabledByDefault)
+		return
+	}
+
+	f.NameEntry.SetText(provider.Name)
+	f.TypeSelect.SetSelected(provider.Type)
+	f.showTypeFields(provider.Type)
+	f.APIKeyEntry.SetText(provider.APIKey)
+	f.BaseURLEntry.SetText(provider.BaseURL)
+	f.ModelEntry.SetText(provider.Model)
+	f.DeploymentEntry.SetText(provider.DeploymentName)
+	f.APIVersionEntry.SetText(provider.APIVersion)
+	if provider.RequestTimeoutSeconds > 0 {
+		f.TimeoutEntry.SetText(fmt.Sprintf("%d", provider.RequestTimeoutSeconds))
+	} else {
+		f.TimeoutEntry.SetText("")
+	}
+	if provider.RequestsPerMinute > 0 {
+		f.RateLimitEntry.SetText(fmt.Sprintf("%d", provider.RequestsPerMinute))
+	} else {
+		f.RateLimitEntry.SetText("")
+	}
+	f.MaxMessagesEntry.SetText(optionalCount(provider.MaxContextMessages))
+	f.MaxTokensEntry.SetText(optionalCount(provider.MaxContextTokens))
+	f.SummarizeCheck.SetChecked(provider.SummarizeTrimmed)
+	f.NoStreamCheck.SetChecked(provider.DisableStreaming)
+	f.ExtrasEntry.SetText(formatKeyValueLines(provider.Extras))
+	f.EnabledCheck.SetChecked(provider.Enabled)
+}
+
+// optionalCount formats a limit for an entry, leaving it empty when unset
+func optionalCount(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// Read validates the form and returns the provider it describes
+func (f *ProviderForm) Read() (config.Provider, error) {
+	timeout := 0
+	if text := strings.TrimSpace(f.TimeoutEntry.Text); text != "" {
+		if _, err := fmt.Sscanf(text, "%d", &timeout); err != nil || timeout <= 0 {
+			return config.Provider{}, fmt.Errorf("Timeout must be a positive number of seconds")
+		}
+	}
+	rpm := 0
+	if text := strings.TrimSpace(f.RateLimitEntry.Text); text != "" {
+		if _, err := fmt.Sscanf(text, "%d", &rpm); err != nil || rpm <= 0 {
+			return config.Provider{}, fmt.Errorf("Requests per minute must be a positive number")
+		}
+	}
+	maxMessages := 0
+	if text := strings.TrimSpace(f.MaxMessagesEntry.Text); text != "" {
+		if _, err := fmt.Sscanf(text, "%d", &maxMessages); err != nil || maxMessages <= 0 {
+			return config.Provider{}, fmt.Errorf("Context messages must be a positive number")
+		}
+	}
+	maxTokens := 0
+	if text := strings.TrimSpace(f.MaxTokensEntry.Text); text != "" {
+		if _, err := fmt.Sscanf(text, "%d", &maxTokens); err != nil || maxTokens <= 0 {
+			return config.Provider{}, fmt.Errorf("Context tokens must be a positive number")
+		}
+	}
+	if lines := invalidKeyValueLines(f.ExtrasEntry.Text); len(lines) > 0 {
+		return config.Provider{}, fmt.Errorf("Options line(s) %s are not KEY=VALUE", joinLineNumbers(lines))
+	}
+
+	provider := config.Provider{
+		Name:                  f.NameEntry.Text,
+		Type:                  f.TypeSelect.Selected,
+		APIKey:                f.APIKeyEntry.Text,
+		BaseURL:               f.BaseURLEntry.Text,
+		Model:                 f.ModelEntry.Text,
+		Enabled:               f.EnabledCheck.Checked,
+		RequestTimeoutSeconds: timeout,
+		RequestsPerMinute:     rpm,
+		MaxContextMessages:    maxMessages,
+		MaxContextTokens:      maxTokens,
+		SummarizeTrimmed:      f.SummarizeCheck.Checked,
+		DisableStreaming:      f.NoStreamCheck.Checked,
+		Extras:                parseKeyValueLines(f.ExtrasEntry.Text),
+	}
+	if provider.Type == "azure" {
+		provider.DeploymentName = strings.TrimSpace(f.DeploymentEntry.Text)
+		provider.APIVersion = strings.TrimSpace(f.APIVersionEntry.Text)
+	}
+	if err := config.ValidateProvider(provider); err != nil {
+		return config.Provider{}, err
+	}
+	return provider, nil
+}
+
+// MCPServerForm is the shared editor for an MCP server configuration,
+// used by both the MCP Servers tab and the add/edit server dialog
+type MCPServerForm struct {
+	NameEntry    *widget.Entry
+	TypeSelect   *widget.Select
+	EnabledCheck *widget.Check
+
+	// StdIO fields
+	CommandEntry    *widget.Entry
+	ArgsEntry       *widget.Entry
+	EnvEntry        *widget.Entry
+	WorkingDirEntry *widget.Entry
+
+	// SSE and StreamableHTTP fields
+	URLEntry     *widget.Entry
+	HeadersEntry *widget.Entry
+	TimeoutEntry *widget.Entry
+
+	// Warnings under the env and headers fields about lines that are not KEY=VALUE
+	envWarning     *widget.Label
+	headersWarning *widget.Label
+
+	// saveButton is disabled while the shown key-value fields have invalid lines
+	saveButton *widget.Button
+
+	stdioContainer *fyne.Container
+	httpContainer  *fyne.Container
+
+	// Content is the form layout to embed in a tab or dialog
+	Content *fyne.Container
+}
+
+// NewMCPServerForm creates an empty MCP server form showing the StdIO fields
+func NewMCPServerForm() *MCPServerForm {
+	f := &MCPServerForm{
+		NameEntry:       widget.NewEntry(),
+		TypeSelect:      widget.NewSelect(mcpServerTypes, nil),
+		EnabledCheck:    widget.NewCheck("Enabled", nil),
+		CommandEntry:    widget.NewEntry(),
+		ArgsEntry:       widget.NewMultiLineEntry(),
+		EnvEntry:        widget.NewMultiLineEntry(),
+		WorkingDirEntry: widget.NewEntry(),
+		URLEntry:        widget.NewEntry(),
+		HeadersEntry:    widget.NewMultiLineEntry(),
+		TimeoutEntry:    widget.NewEntry(),
+		envWarning:      newKeyValueWarning(),
+		headersWarning:  newKeyValueWarning(),
+		stdioContainer:  container.NewVBox(),
+		httpContainer:   container.NewVBox(),
+	}
+
+	f.ArgsEntry.SetPlaceHolder("Enter arguments separated by new lines\ne.g.:\n-y\n@modelcontextprotocol/server-filesystem\n/path/to/files")
+	f.EnvEntry.SetPlaceHolder("Enter environment variables as KEY=VALUE, one per line\ne.g.:\nPATH=/usr/local/bin\nNODE_ENV=production")
+	f.WorkingDirEntry.SetPlaceHolder("Optional; ~ and $VAR are expanded, e.g. ~/projects")
+	f.HeadersEntry.SetPlaceHolder("Enter HTTP headers as KEY=VALUE, one per line\ne.g.:\nAuthorization=Bearer token\nContent-Type=application/json")
+	f.TimeoutEntry.SetPlaceHolder("30")
+	f.TimeoutEntry.SetText("30")
+
+	// Multi-line entries show a few lines and scroll the rest themselves
+	f.ArgsEntry.SetMinRowsVisible(3)
+	f.EnvEntry.SetMinRowsVisible(3)
+	f.HeadersEntry.SetMinRowsVisible(3)
+
+	f.TypeSelect.OnChanged = f.showTypeFields
+	f.EnvEntry.OnChanged = func(text string) {
+		updateKeyValueWarning(f.envWarning, text)
+		f.updateSaveButton()
+	}
+	f.HeadersEntry.OnChanged = func(text string) {
+		updateKeyValueWarning(f.headersWarning, text)
+		f.updateSaveButton()
+	}
+
+	f.Content = container.NewVBox(
+		newFormGrid(
+			newFormLabel("Name:"), f.NameEntry,
+			newFormLabel("Type:"), f.TypeSelect,
+			newFormLabel(""), f.EnabledCheck,
+		),
+		f.stdioContainer,
+		f.httpContainer,
+	)
+	f.showTypeFields("stdio")
+
+	return f
+}
+
+// showTypeFields shows only the fields relevant to the given server type
+func (f *MCPServerForm) showTypeFields(serverType string) {
+	if serverType == "stdio" {
+		f.stdioContainer.Objects = []fyne.CanvasObject{
+			widget.NewSeparator(),
+			widget.NewLabelWithStyle("StdIO Configuration:", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			newFormGrid(
+				newFormLabel("Command:"), f.CommandEntry,
+				newFormLabel("Args:"), f.ArgsEntry,
+				newFormLabel("Env:"), container.NewVBox(f.EnvEntry, f.envWarning),
+				newFormLabel("Working dir:"), f.WorkingDirEntry,
+			),
+		}
+		f.httpContainer.Objects = nil
+	} else {
+		f.stdioContainer.Objects = nil
+		f.httpContainer.Objects = []fyne.CanvasObject{
+			widget.NewSeparator(),
+			widget.NewLabelWithStyle(serverType+" Configuration:", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			newFormGrid(
+				newFormLabel("URL:"), f.URLEntry,
+				newFormLabel("Headers:"), container.NewVBox(f.HeadersEntry, f.headersWarning),
+				newFormLabel("Timeout (seconds):"), f.TimeoutEntry,
+			),
+		}
+	}
+	f.stdioContainer.Refresh()
+	f.httpContainer.Refresh()
+	f.updateSaveButton()
+}
+
+// SetSaveButton sets the button that saves the form, which is disabled while the env or headers
+// field of the selected type has lines that are not KEY=VALUE
+func (f *MCPServerForm) SetSaveButton(btn *widget.Button) {
+	f.saveButton = btn
+	f.updateSaveButton()
+}
+
+// updateSaveButton enables the save button when the form's key-value fields are valid
+func (f *MCPServerForm) updateSaveButton() {
+	if f.saveButton == nil {
+		return
+	}
+	if f.keyValueError() == nil {
+		f.saveButton.Enable()
+	} else {
+		f.saveButton.Disable()
+	}
+}
+
+// keyValueError reports the invalid lines of the env or headers field, whichever the selected type uses
+func (f *MCPServerForm) keyValueError() error {
+	// The StdIO fields are shown until a type is selected
+	field, text := "Env", f.EnvEntry.Text
+	if selected := f.TypeSelect.Selected; selected != "" && selected != "stdio" {
+		field, text = "Headers", f.HeadersEntry.Text
+	}
+	if lines := invalidKeyValueLines(text); len(lines) > 0 {
+		return fmt.Errorf("%s line(s) %s are not KEY=VALUE", field, joinLineNumbers(lines))
+	}
+	return nil
+}
+
+// Bind populates the form from a server, or clears it when server is nil.
+// A cleared form selects StdIO and defaults to enabled when enabledByDefault is set.
+func (f *MCPServerForm) Bind(server *config.MCPServer, enabledByDefault bool) {
+	if server == nil {
+		f.NameEntry.SetText("")
+		f.TypeSelect.SetSelected("stdio")
+		f.EnabledCheck.SetChecked(enabledByDefault)
+		f.CommandEntry.SetText("")
+		f.ArgsEntry.SetText("")
+		f.EnvEntry.SetText("")
+		f.WorkingDirEntry.SetText("")
+		f.URLEntry.SetText("")
+		f.HeadersEntry.SetText("")
+		f.TimeoutEntry.SetText("30")
+		return
+	}
+
+	serverType := string(server.Type)
+	if serverType == "" {
+		serverType = "stdio"
+	}
+
+	f.NameEntry.SetText(server.Name)
+	f.TypeSelect.SetSelected(serverType)
+	f.EnabledCheck.SetChecked(server.Enabled)
+
+	// Populate StdIO fields
+	f.CommandEntry.SetText(server.Command)
+	f.ArgsEntry.SetText(strings.Join(server.Args, "\n"))
+	f.EnvEntry.SetText(formatKeyValueLines(server.Env))
+	f.WorkingDirEntry.SetText(server.WorkingDir)
+
+	// Populate HTTP fields
+	f.URLEntry.SetText(server.URL)
+	f.HeadersEntry.SetText(formatKeyValueLines(server.Headers))
+	if server.TimeoutSeconds > 0 {
+		f.TimeoutEntry.SetText(fmt.Sprintf("%d", server.TimeoutSeconds))
+	} else {
+		f.TimeoutEntry.SetText("30")
+	}
+}
+
+// Read validates the form and returns the server it describes
+func (f *MCPServerForm) Read() (config.MCPServer, error) {
+	if f.NameEntry.Text == "" {
+		return config.MCPServer{}, fmt.Errorf("Server name cannot be empty")
+	}
+	if f.TypeSelect.Selected == "" {
+		return config.MCPServer{}, fmt.Errorf("Server type must be selected")
+	}
+	if err := f.keyValueError(); err != nil {
+		return config.MCPServer{}, err
+	}
+
+	server := config.MCPServer{
+		Name:    f.NameEntry.Text,
+		Type:    config.MCPServerType(f.TypeSelect.Selected),
+		Enabled: f.EnabledCheck.Checked,
+	}
+
+	// Set type-specific fields
+	if f.TypeSelect.Selected == "stdio" {
+		if f.CommandEntry.Text == "" {
+			return config.MCPServer{}, fmt.Errorf("Command cannot be empty for StdIO type")
+		}
+		server.Command = f.CommandEntry.Text
+
+		if strings.TrimSpace(f.ArgsEntry.Text) != "" {
+			server.Args = strings.Split(strings.TrimSpace(f.ArgsEntry.Text), "\n")
+		}
+		server.Env = parseKeyValueLines(f.EnvEntry.Text)
+		server.WorkingDir = strings.TrimSpace(f.WorkingDirEntry.Text)
+	} else {
+		if f.URLEntry.Text == "" {
+			return config.MCPServer{}, fmt.Errorf("URL cannot be empty for %s type", f.TypeSelect.Selected)
+		}
+		server.URL = f.URLEntry.Text
+		server.Headers = parseKeyValueLines(f.HeadersEntry.Text)
+
+		if strings.TrimSpace(f.TimeoutEntry.Text) != "" {
+			var timeout int
+			if _, err := fmt.Sscanf(f.TimeoutEntry.Text, "%d", &timeout); err == nil {
+				server.TimeoutSeconds = timeout
+			}
+		}
+	}
+
+	return server, nil
+}
+
+// parseKeyValueLines parses KEY=VALUE lines into a map, returning nil for empty input
+func parseKeyValueLines(text string) map[string]string {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	values := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 {
+			values[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	return values
+}
+
+// invalidKeyValueLines returns the 1-based numbers of the lines parseKeyValueLines would drop:
+// lines without '=' or with nothing before it. Blank lines are allowed.
+func invalidKeyValueLines(text string) []int {
+	var invalid []int
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, _, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(key) == "" {
+			invalid = append(invalid, i+1)
+		}
+	}
+	return invalid
+}
+
+// joinLineNumbers lists line numbers for a message, e.g. "2, 5"
+func joinLineNumbers(lines []int) string {
+	numbers := make([]string, len(lines))
+	for i, line := range lines {
+		numbers[i] = fmt.Sprintf("%d", line)
+	}
+	return strings.Join(numbers, ", ")
+}
+
+// newKeyValueWarning creates the hidden label shown under a key-value field with invalid lines
+func newKeyValueWarning() *widget.Label {
+	label := widget.NewLabel("")
+	label.Importance = widget.DangerImportance
+	label.Wrapping = fyne.TextWrapWord
+	label.Hide()
+	return label
+}
+
+// updateKeyValueWarning shows which lines of a key-value field are not KEY=VALUE, or hides the
+// warning when all are
+func updateKeyValueWarning(warning *widget.Label, text string) {
+	lines := invalidKeyValueLines(text)
+	if len(lines) == 0 {
+		warning.Hide()
+		return
+	}
+	warning.SetText(Translatef("mcp.invalid_key_value_lines", len(lines), joinLineNumbers(lines)))
+	warning.Show()
+}
+
+// formatKeyValueLines formats a map as sorted KEY=VALUE lines
+func formatKeyValueLines(values map[string]string) string {
+	lines := make([]string, 0, len(values))
+	for k, v := range values {
+		lines = append(lines, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// newFormGrid lays out label and field pairs the way widget.Form does: the labels in a column
+// as wide as the widest and the fields taking the rest of the width. Unlike widget.Form, a row
+// whose label and field are both hidden takes no space.
+func newFormGrid(objects ...fyne.CanvasObject) *fyne.Container {
+	return container.New(layout.NewFormLayout(), objects...)
+}
+
+// newFormLabel creates a label for newFormGrid, styled like widget.Form's labels
+func newFormLabel(text string) *widget.Label {
+	return widget.NewLabelWithStyle(text, fyne.TextAlignTrailing, fyne.TextStyle{Bold: true})
+}
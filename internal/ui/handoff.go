@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"chatgo/internal/llm"
+	"chatgo/pkg/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxHandoffs is how many providers a request refused for lack of a capability offers to switch to
+const maxHandoffs = 4
+
+// handoffProviders returns the enabled providers other than refused whose model the catalog
+// knows can take a request refused with capErr. For a request too long for its model, the
+// model must be known to have a larger context window.
+func (cw *ChatWindow) handoffProviders(refused string, capErr *llm.CapabilityError) []config.Provider {
+	current, _ := cw.providerNamed(refused)
+	currentInfo, currentKnown := llm.ModelCatalog.ForProvider(current)
+
+	var providers []config.Provider
+	for _, p := range cw.config.Providers {
+		if !p.Enabled || p.Name == refused {
+			continue
+		}
+		info, ok := llm.ModelCatalog.ForProvider(p)
+		if !ok || !capErr.Requirements.MetBy(info) {
+			continue
+		}
+		if capErr.Missing == llm.CapabilityContext && (info.ContextWindow == 0 || currentKnown && info.ContextWindow <= currentInfo.ContextWindow) {
+			continue
+		}
+		providers = append(providers, p)
+		if len(providers) == maxHandoffs {
+			break
+		}
+	}
+	return providers
+}
+
+// newHandoffOffer creates the part of an error bubble that says which capability the model
+// lacks, with a button for each configured provider whose model has it. Choosing one switches
+// the conversation to that provider and calls retry to send the request again.
+func (cw *ChatWindow) newHandoffOffer(conv *models.Conversation, capErr *llm.CapabilityError, retry func()) fyne.CanvasObject {
+	refused := conv.Provider
+	model := refused
+	if p, ok := cw.providerNamed(refused); ok && p.Model != "" {
+		model = p.Model
+	}
+	label := widget.NewLabel(Translatef("handoff.missing_"+string(capErr.Missing), model))
+	label.Importance = widget.WarningImportance
+	label.Wrapping = fyne.TextWrapWord
+
+	providers := cw.handoffProviders(refused, capErr)
+	if len(providers) == 0 {
+		hint := widget.NewLabel(Translate("handoff.none"))
+		hint.Importance = widget.LowImportance
+		hint.Wrapping = fyne.TextWrapWord
+		return container.NewVBox(label, hint)
+	}
+
+	buttons := container.NewHBox()
+	for _, p := range providers {
+		name := p.Name
+		btn := widget.NewButtonWithIcon(Translatef("handoff.switch", p.Name, p.Model), theme.MediaReplayIcon(), nil)
+		btn.OnTapped = func() {
+			// The provider selector switches the open conversation, which must be the one refused
+			if cw.currentConversation == nil || cw.currentConversation.ID != conv.ID {
+				return
+			}
+			for _, b := range buttons.Objects {
+				b.(*widget.Button).Disable()
+			}
+			cw.selectProvider(name)
+			cw.setupCurrentProvider()
+			retry()
+		}
+		buttons.Add(btn)
+	}
+	return container.NewVBox(label, container.NewHScroll(buttons))
+}
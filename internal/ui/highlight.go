@@ -0,0 +1,340 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// tokenKind classifies a piece of highlighted code
+type tokenKind int
+
+const (
+	tokenPlain tokenKind = iota
+	tokenKeyword
+	tokenString
+	tokenComment
+	tokenNumber
+)
+
+// tokenColors maps token kinds to theme colors so highlighting follows light and dark themes
+var tokenColors = map[tokenKind]fyne.ThemeColorName{
+	tokenPlain:   theme.ColorNameForeground,
+	tokenKeyword: theme.ColorNamePrimary,
+	tokenString:  theme.ColorNameSuccess,
+	tokenComment: theme.ColorNamePlaceHolder,
+	tokenNumber:  theme.ColorNameWarning,
+}
+
+// language describes just enough syntax to colorize keywords, strings, comments and numbers
+type language struct {
+	keywords          map[string]bool
+	lineComment       string
+	blockComment      [2]string // Opening and closing markers; empty when unsupported
+	quotes            string    // Characters that start a string
+	tripleQuotes      bool      // Python-style """ and ''' strings
+	commentNeedsSpace bool      // Shell: # only starts a comment at a word boundary
+}
+
+func keywordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+var (
+	goLanguage = &language{
+		keywords: keywordSet(`break case chan const continue default defer else fallthrough for func go goto if
+			import interface map package range return select struct switch type var
+			true false nil iota`),
+		lineComment:  "//",
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	}
+	pythonLanguage = &language{
+		keywords: keywordSet(`and as assert async await break class continue def del elif else except finally
+			for from global if import in is lambda nonlocal not or pass raise return try while with yield
+			True False None self`),
+		lineComment:  "#",
+		quotes:       `"'`,
+		tripleQuotes: true,
+	}
+	javaScriptLanguage = &language{
+		keywords: keywordSet(`async await break case catch class const continue default delete do else export
+			extends finally for from function if import in instanceof let new of return static super switch
+			this throw try typeof var void while yield true false null undefined
+			interface type enum implements readonly`),
+		lineComment:  "//",
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	}
+	jsonLanguage = &language{
+		keywords: keywordSet(`true false null`),
+		quotes:   `"`,
+	}
+	shellLanguage = &language{
+		keywords: keywordSet(`if then else elif fi for in do done case esac while until function return
+			export local readonly source exit echo cd`),
+		lineComment:       "#",
+		quotes:            `"'`,
+		commentNeedsSpace: true,
+	}
+)
+
+// languages maps fence language hints to their syntax
+var languages = map[string]*language{
+	"go":         goLanguage,
+	"golang":     goLanguage,
+	"python":     pythonLanguage,
+	"py":         pythonLanguage,
+	"js":         javaScriptLanguage,
+	"javascript": javaScriptLanguage,
+	"jsx":        javaScriptLanguage,
+	"ts":         javaScriptLanguage,
+	"typescript": javaScriptLanguage,
+	"tsx":        javaScriptLanguage,
+	"json":       jsonLanguage,
+	"sh":         shellLanguage,
+	"bash":       shellLanguage,
+	"zsh":        shellLanguage,
+	"shell":      shellLanguage,
+	"console":    shellLanguage,
+}
+
+// token is a run of code with a single kind
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits code into tokens for lang, merging adjacent tokens of the same kind
+func tokenize(code string, lang *language) []token {
+	var tokens []token
+	emit := func(kind tokenKind, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(tokens); n > 0 && tokens[n-1].kind == kind {
+			tokens[n-1].text += text
+			return
+		}
+		tokens = append(tokens, token{kind: kind, text: text})
+	}
+
+	runes := []rune(code)
+	hasPrefix := func(i int, prefix string) bool {
+		p := []rune(prefix)
+		if len(p) == 0 || i+len(p) > len(runes) {
+			return false
+		}
+		for j := range p {
+			if runes[i+j] != p[j] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case lang.lineComment != "" && hasPrefix(i, lang.lineComment) &&
+			(!lang.commentNeedsSpace || i == 0 || unicode.IsSpace(runes[i-1])):
+			end := i
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+			emit(tokenComment, string(runes[i:end]))
+			i = end
+
+		case lang.blockComment[0] != "" && hasPrefix(i, lang.blockComment[0]):
+			end := i + len([]rune(lang.blockComment[0]))
+			for end < len(runes) && !hasPrefix(end, lang.blockComment[1]) {
+				end++
+			}
+			end = min(len(runes), end+len([]rune(lang.blockComment[1])))
+			emit(tokenComment, string(runes[i:end]))
+			i = end
+
+		case strings.ContainsRune(lang.quotes, r):
+			end := scanString(runes, i, lang.tripleQuotes)
+			emit(tokenString, string(runes[i:end]))
+			i = end
+
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || unicode.IsLetter(runes[end]) || runes[end] == '.' || runes[end] == '_') {
+				end++
+			}
+			emit(tokenNumber, string(runes[i:end]))
+			i = end
+
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			word := string(runes[i:end])
+			if lang.keywords[word] {
+				emit(tokenKeyword, word)
+			} else {
+				emit(tokenPlain, word)
+			}
+			i = end
+
+		default:
+			emit(tokenPlain, string(r))
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// scanString returns the index just past the string starting at start
+func scanString(runes []rune, start int, tripleQuotes bool) int {
+	quote := runes[start]
+
+	if tripleQuotes && start+2 < len(runes) && runes[start+1] == quote && runes[start+2] == quote {
+		for i := start + 3; i+2 < len(runes); i++ {
+			if runes[i] == quote && runes[i+1] == quote && runes[i+2] == quote {
+				return i + 3
+			}
+		}
+		return len(runes)
+	}
+
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && quote != '`':
+			i++
+		case runes[i] == quote:
+			return i + 1
+		case runes[i] == '\n' && quote != '`':
+			// Unterminated string; stop at the end of the line
+			return i
+		}
+	}
+	return len(runes)
+}
+
+// highlightCode returns colorized code block segments, or nil when the language isn't supported
+func highlightCode(code, lang string) []widget.RichTextSegment {
+	syntax, ok := languages[strings.ToLower(lang)]
+	if !ok {
+		return nil
+	}
+
+	tokens := tokenize(code, syntax)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	segments := make([]widget.RichTextSegment, len(tokens))
+	for i, tok := range tokens {
+		style := widget.RichTextStyleCodeBlock
+		style.ColorName = tokenColors[tok.kind]
+		// Tokens flow inline; the last one ends the block like a plain code block segment
+		style.Inline = i < len(tokens)-1
+		segments[i] = &widget.TextSegment{Style: style, Text: tok.text}
+	}
+	return segments
+}
+
+// fencedBlock is a fenced code block with its language hint
+type fencedBlock struct {
+	lang string
+	code string
+}
+
+// extractFencedBlocks returns the fenced code blocks in markdown with their language hints
+func extractFencedBlocks(markdown string) []fencedBlock {
+	var blocks []fencedBlock
+	var current []string
+	var lang string
+	fence := ""
+
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence == "" {
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				fence = trimmed[:3]
+				lang = ""
+				if fields := strings.Fields(strings.TrimLeft(trimmed, fence[:1])); len(fields) > 0 {
+					lang = fields[0]
+				}
+				current = nil
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, fence) && strings.TrimLeft(trimmed, fence[:1]) == "" {
+			blocks = append(blocks, fencedBlock{lang: lang, code: strings.Join(current, "\n")})
+			fence = ""
+			continue
+		}
+		current = append(current, line)
+	}
+
+	return blocks
+}
+
+// renderCodeBlocks replaces code block segments that came from fenced blocks: with colorized
+// segments when highlight is set and the language hint is supported, and at the top level with
+// a block that has a copy button when copyButton is set. Other code blocks stay plain monospace.
+func renderCodeBlocks(segments []widget.RichTextSegment, markdown string, highlight, copyButton bool) []widget.RichTextSegment {
+	blocks := extractFencedBlocks(markdown)
+	if len(blocks) == 0 {
+		return segments
+	}
+
+	next := 0
+	var walk func(segments []widget.RichTextSegment, topLevel bool) []widget.RichTextSegment
+	walk = func(segments []widget.RichTextSegment, topLevel bool) []widget.RichTextSegment {
+		result := make([]widget.RichTextSegment, 0, len(segments))
+		for _, segment := range segments {
+			switch seg := segment.(type) {
+			case *widget.ListSegment:
+				// List items are laid out by the list, which only takes text
+				seg.Items = walk(seg.Items, false)
+			case *widget.TextSegment:
+				if seg.Style != widget.RichTextStyleCodeBlock {
+					break
+				}
+				// Match the segment to the next fenced block with the same content;
+				// indented code blocks have no fence and are left unmatched
+				for i := next; i < len(blocks); i++ {
+					if strings.TrimSuffix(blocks[i].code, "\n") != seg.Text {
+						continue
+					}
+					next = i + 1
+					code := []widget.RichTextSegment{seg}
+					if highlight {
+						if highlighted := highlightCode(seg.Text, blocks[i].lang); highlighted != nil {
+							code = highlighted
+						}
+					}
+					if copyButton && topLevel {
+						result = append(result, &codeBlockSegment{code: seg.Text, lang: blocks[i].lang, segments: code})
+					} else {
+						result = append(result, code...)
+					}
+					segment = nil
+					break
+				}
+			}
+			if segment != nil {
+				result = append(result, segment)
+			}
+		}
+		return result
+	}
+
+	return walk(segments, true)
+}
@@ -2,6 +2,8 @@ package ui
 
 import (
 	"chatgo/pkg/models"
+	"slices"
+	"sort"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -15,37 +17,42 @@ import (
 // When a message is submitted, it switches to the full chat interface.
 func (cw *ChatWindow) setupHomeUI() {
 	// Create centered input for home page
-	cw.homeMessageEntry = widget.NewMultiLineEntry()
-	cw.homeMessageEntry.SetPlaceHolder("输入消息开始聊天...")
+	cw.homeMessageEntry = newChatEntry(func() bool { return cw.config.SendOnEnter })
+	cw.homeMessageEntry.SetPlaceHolder(Translate("home.message_placeholder"))
 	cw.homeMessageEntry.SetMinRowsVisible(3)
 
-	cw.homeMessageEntry.OnSubmitted = func(text string) {
+	cw.homeMessageEntry.OnSend = func() {
 		cw.handleHomeMessageSubmit()
 	}
+	cw.homeMessageEntry.OnPaste = cw.transcriptPasteHandler(cw.homeMessageEntry)
+	cw.homeMessageEntry.OnShortcut = cw.runShortcut
 
 	// Create send button
-	sendBtn := widget.NewButton("发送", func() {
+	sendBtn := widget.NewButton(Translate("chat.send"), func() {
 		cw.handleHomeMessageSubmit()
 	})
 
+	// The provider the first message is sent to, the one last used unless another is picked
+	cw.homeProviderSelect = cw.newTooltipSelect(cw.enabledProviderNames(), nil, func() string {
+		return providerQuotaText(cw.homeProviderSelect.Selected)
+	})
+	cw.selectHomeProvider(cw.config.CurrentProvider)
+
 	// Wrap input and button in a container
 	inputContainer := container.NewVBox(
 		cw.homeMessageEntry,
 		sendBtn,
+		container.NewHBox(cw.homeProviderSelect, layout.NewSpacer(), cw.mcpStatus),
 	)
 
 	// Create recent conversations section
-	recentConvsLabel := widget.NewLabel("最近会话")
+	recentConvsLabel := widget.NewLabel(Translate("home.recent_conversations"))
 	recentConvsLabel.TextStyle = fyne.TextStyle{Bold: true}
 
-	// Create list for recent conversations (will be populated by updateRecentConversations)
-	recentConvsList := widget.NewList(
+	// Create list for recent conversations (populated by updateRecentConversations)
+	cw.recentList = widget.NewList(
 		func() int {
-			// Show only the 5 most recent conversations
-			if len(cw.convListData) > 5 {
-				return 5
-			}
-			return len(cw.convListData)
+			return len(cw.recentConversations)
 		},
 		func() fyne.CanvasObject {
 			// Title and time in the same row
@@ -59,8 +66,8 @@ func (cw *ChatWindow) setupHomeUI() {
 		},
 		func(id widget.ListItemID, obj fyne.CanvasObject) {
 			cont := obj.(*fyne.Container)
-			if id < len(cw.convListData) {
-				conv := cw.convListData[id]
+			if id < len(cw.recentConversations) {
+				conv := cw.recentConversations[id]
 				titleLabel := cont.Objects[0].(*widget.Label)
 				timeLabel := cont.Objects[2].(*widget.Label)
 
@@ -72,7 +79,7 @@ func (cw *ChatWindow) setupHomeUI() {
 					lastMsg := conv.Messages[len(conv.Messages)-1]
 					timeLabel.SetText(lastMsg.Timestamp.Format("2006-01-02 15:04"))
 				} else {
-					timeLabel.SetText("空会话")
+					timeLabel.SetText(Translate("home.empty_conversation"))
 				}
 				timeLabel.TextStyle = fyne.TextStyle{Italic: true}
 			}
@@ -80,16 +87,17 @@ func (cw *ChatWindow) setupHomeUI() {
 	)
 
 	// Make list items clickable
-	recentConvsList.OnSelected = func(id widget.ListItemID) {
-		if id < len(cw.convListData) {
+	cw.recentList.OnSelected = func(id widget.ListItemID) {
+		if id < len(cw.recentConversations) {
 			// Switch to chat UI and load the selected conversation
 			cw.switchToChatUI()
-			cw.loadConversation(cw.convListData[id].ID)
+			cw.loadConversation(cw.recentConversations[id].ID)
+			cw.syncConversationSelection()
 		}
 	}
 
 	// Set max height for recent conversations list (show up to 5 items)
-	recentConvsScroll := container.NewScroll(recentConvsList)
+	recentConvsScroll := container.NewScroll(cw.recentList)
 	recentConvsScroll.SetMinSize(fyne.NewSize(400, 150))
 
 	// Create recent conversations container
@@ -109,11 +117,26 @@ func (cw *ChatWindow) setupHomeUI() {
 	)
 
 	cw.homeContainer = container.NewPadded(homeContent)
-	cw.window.SetContent(cw.homeContainer)
+	cw.setWindowContent(cw.homeContainer)
+}
+
+// selectHomeProvider selects the named provider in the home page's provider selector, or the
+// first enabled provider when it is disabled or no longer configured
+func (cw *ChatWindow) selectHomeProvider(name string) {
+	options := cw.homeProviderSelect.Options
+	if !slices.Contains(options, name) {
+		if len(options) == 0 {
+			cw.homeProviderSelect.ClearSelected()
+			return
+		}
+		name = options[0]
+	}
+	cw.homeProviderSelect.SetSelected(name)
 }
 
 // handleHomeMessageSubmit handles message submission from the home page.
-// It switches to the chat UI, creates a new conversation, and sends the message.
+// It switches to the chat UI, creates a new conversation with the provider picked on the
+// home page, and sends the message.
 func (cw *ChatWindow) handleHomeMessageSubmit() {
 	text := cw.homeMessageEntry.Text
 	if text == "" {
@@ -123,7 +146,8 @@ func (cw *ChatWindow) handleHomeMessageSubmit() {
 	// Switch to chat UI
 	cw.switchToChatUI()
 
-	// Create new conversation with current provider
+	// Create new conversation with the chosen provider, which becomes the current one
+	cw.selectProvider(cw.homeProviderSelect.Selected)
 	cw.createNewConversation()
 
 	// Send the message
@@ -132,22 +156,67 @@ func (cw *ChatWindow) handleHomeMessageSubmit() {
 }
 
 // switchToChatUI switches from home page mode to full chat interface mode.
-// This is called when the user submits their first message from the home page.
+// The chat interface is built the first time and shown again as it was after that.
 func (cw *ChatWindow) switchToChatUI() {
 	if !cw.isHomeMode {
 		return
 	}
 
 	cw.isHomeMode = false
-	cw.setupUI()
+	if cw.split == nil {
+		cw.setupUI()
+	} else {
+		cw.setWindowContent(cw.split)
+	}
 	cw.setupCurrentProvider()
 }
 
-// getConversationLastTime returns the timestamp of the last message in a conversation
-// If the conversation has no messages, returns a zero time
-func getConversationLastTime(conv models.Conversation) time.Time {
+// switchToHome returns from the chat interface to the home page, closing the open conversation
+// and clearing the selections of both conversation lists
+func (cw *ChatWindow) switchToHome() {
+	if cw.isHomeMode {
+		return
+	}
+
+	cw.isHomeMode = true
+	cw.closeConversation()
+	// Reread the conversations so the recent list includes ones changed outside the app
+	cw.loadConversations()
+	cw.convList.UnselectAll()
+	cw.recentList.UnselectAll()
+	cw.selectHomeProvider(cw.config.CurrentProvider)
+	cw.setWindowContent(cw.homeContainer)
+	cw.window.Canvas().Focus(cw.homeMessageEntry)
+}
+
+// recentConversationCount is how many conversations the home page lists
+const recentConversationCount = 5
+
+// updateRecentConversations lists the conversations with the latest messages on the home page,
+// whatever the sidebar is filtered to and whether they are pinned. Archived ones are left out.
+func (cw *ChatWindow) updateRecentConversations() {
+	recent := slices.DeleteFunc(slices.Clone(cw.allConversations), func(conv models.Conversation) bool {
+		return conv.Archived
+	})
+	// Renaming, tagging or pinning a conversation updates it, but doesn't make it recent
+	sort.SliceStable(recent, func(i, j int) bool {
+		return conversationLastTime(recent[i]).After(conversationLastTime(recent[j]))
+	})
+	if len(recent) > recentConversationCount {
+		recent = recent[:recentConversationCount]
+	}
+
+	cw.recentConversations = recent
+	if cw.recentList != nil {
+		cw.recentList.Refresh()
+	}
+}
+
+// conversationLastTime returns when the last message of a conversation was sent, or when the
+// conversation was created if it has none
+func conversationLastTime(conv models.Conversation) time.Time {
 	if len(conv.Messages) == 0 {
-		return time.Time{}
+		return conv.CreatedAt
 	}
 	return conv.Messages[len(conv.Messages)-1].Timestamp
 }
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2/lang"
+)
+
+// fallbackLocale is the locale whose strings are used for keys missing from the current one
+const fallbackLocale = "en"
+
+// translations holds each locale's strings by key. Each locale registers its map from its own
+// file, so adding a locale only takes a new i18n_<locale>.go.
+var translations = map[string]map[string]string{}
+
+// currentLocale is the locale Translate looks keys up in first
+var currentLocale = fallbackLocale
+
+// registerLocale adds the strings of a locale, such as "en" or "zh-CN"
+func registerLocale(locale string, strings map[string]string) {
+	translations[locale] = strings
+}
+
+// SetLanguage selects the locale of the UI strings. An empty language follows the system
+// locale; a language without translations falls back to English.
+func SetLanguage(language string) {
+	if language == "" {
+		language = lang.SystemLocale().String()
+	}
+	currentLocale = matchLocale(language)
+}
+
+// Language returns the locale the UI strings are shown in
+func Language() string {
+	return currentLocale
+}
+
+// matchLocale finds the registered locale closest to language: the same locale, or else one
+// of the same language, e.g. "zh-CN" for "zh-Hans-SG". It returns the fallback when none match.
+func matchLocale(language string) string {
+	language = strings.ReplaceAll(language, "_", "-")
+	if i := strings.IndexByte(language, '.'); i >= 0 {
+		// POSIX locales such as "zh_CN.UTF-8"
+		language = language[:i]
+	}
+
+	base, _, _ := strings.Cut(language, "-")
+	match := ""
+	for _, locale := range Locales() {
+		if strings.EqualFold(locale, language) {
+			return locale
+		}
+		localeBase, _, _ := strings.Cut(locale, "-")
+		if match == "" && strings.EqualFold(localeBase, base) {
+			match = locale
+		}
+	}
+	if match == "" {
+		return fallbackLocale
+	}
+	return match
+}
+
+// Locales returns the registered locales, sorted
+func Locales() []string {
+	locales := make([]string, 0, len(translations))
+	for locale := range translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// LocaleName returns the name of a locale in its own language, e.g. "简体中文"
+func LocaleName(locale string) string {
+	if name, ok := translations[locale]["language.name"]; ok {
+		return name
+	}
+	return locale
+}
+
+// Translate returns the string for key in the current locale, or in English when the locale
+// has no translation for it. An unknown key is returned as is.
+func Translate(key string) string {
+	if s, ok := translations[currentLocale][key]; ok {
+		return s
+	}
+	if s, ok := translations[fallbackLocale][key]; ok {
+		return s
+	}
+	return key
+}
+
+// Translatef formats the string for key with args, as fmt.Sprintf does
+func Translatef(key string, args ...any) string {
+	return fmt.Sprintf(Translate(key), args...)
+}
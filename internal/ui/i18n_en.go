@@ -0,0 +1,357 @@
+package ui
+
+// English strings, which are used for any key another locale has no translation for
+func init() {
+	registerLocale("en", map[string]string{
+		"language.name": "English",
+
+		"app.title": "ChatGo - AI Chatbot",
+
+		"common.save":          "Save",
+		"common.cancel":        "Cancel",
+		"common.open_settings": "Open Settings",
+		"common.ok":            "OK",
+		"common.success":       "Success",
+		"common.delete":        "Delete",
+		"common.close":         "Close",
+
+		"home.message_placeholder":  "Type a message to start chatting...",
+		"home.recent_conversations": "Recent Conversations",
+		"home.empty_conversation":   "Empty",
+
+		"sidebar.new_chat": "New Chat",
+		"sidebar.settings": "Settings",
+		"sidebar.all_tags": "All tags",
+		"sidebar.search":   "Search conversations",
+
+		"sidebar.show_archived":  "Show archived",
+		"conversation.archive":   "Archive",
+		"conversation.unarchive": "Unarchive",
+
+		"chat.message_placeholder":  "Type your message here...",
+		"chat.send":                 "Send",
+		"chat.model":                "Model:",
+		"chat.tools":                "Tools:",
+		"chat.context":              "Context:",
+		"chat.finish_external_edit": "Finish External Edit",
+		"chat.read_only_newer":      "This conversation was saved by a newer version of ChatGo and is read-only.",
+		"chat.read_only_external":   "This conversation is open in an external editor. Changes on disk are reloaded automatically and saving is paused.",
+		"chat.error_role":           "error",
+		"chat.retry":                "Retry",
+		"chat.waiting_for_model":    "Waiting for model…",
+		"chat.not_streamed":         "Not streamed",
+		"chat.request_timeout":      "The request timed out: the model did not respond within %s. Try again, or adjust the timeout in the provider's settings.",
+
+		"conversation.title_placeholder": "Enter new title",
+		"conversation.tags_placeholder":  "e.g. work, personal",
+		"conversation.edit":              "Edit Conversation",
+		"conversation.title":             "Title:",
+		"conversation.tags":              "Tags (comma separated):",
+		"conversation.delete":            "Delete Conversation",
+		"conversation.delete_confirm":    "Are you sure you want to delete '%s'? It is kept in the trash for 30 days.",
+
+		"archive.export":         "Export Conversations...",
+		"archive.import":         "Import Conversations...",
+		"archive.export_done":    "%d conversations exported to %s",
+		"archive.import_done":    "%d conversations imported; %d already here were skipped.",
+		"archive.import_renamed": "%d of them were given a new ID, as theirs was taken by another conversation.",
+
+		"backup.keep":            "Daily backups kept:",
+		"backup.last":            "Last automatic backup: %s",
+		"backup.last_none":       "No automatic backup yet",
+		"backup.none":            "There are no backups yet. One is made on the first launch of each day.",
+		"backup.restore_from":    "Restore from Backup…",
+		"backup.restore_title":   "Restore from Backup",
+		"backup.restore_hint":    "Restored conversations replace their current versions. Conversations created since the backup are kept.",
+		"backup.restore_what":    "Restore:",
+		"backup.restore_all":     "All conversations (%d)",
+		"backup.restore":         "Restore",
+		"backup.restore_confirm": "Replace the current versions with those backed up on %s?",
+		"backup.restored":        "%d conversations restored",
+
+		"schema.versions_in_use": "This build understands up to v%d; this conversation uses v%d.",
+		"schema.title":           "Conversation Format Versions",
+
+		"tools.select_count":           "Select Tools (%d)",
+		"tools.uninitialized_server":   "(not initialized) %s",
+		"tools.initialize_in_settings": "Initialize this server in Settings first",
+		"tools.select_count_limit":     "Select Tools (%d/%d)",
+		"tools.agent_off":              "Agent mode is off; requests carry no tools",
+		"tools.send_count":             "Each request sends %d tools",
+		"tools.send_count_over_limit":  "Each request sends %d tools, over the current model's limit of %d",
+		"tools.send_count_limit":       "Each request sends %d tools (limit %d)",
+		"tools.builtin_group":          "Built-in",
+		"tools.choose":                 "Choose the tools to use:",
+		"tools.select":                 "Select Tools",
+		"tools.override":               "Name & Description",
+		"tools.override_title":         "Tool Name & Description",
+		"tools.override_tool":          "Tool",
+		"tools.override_name":          "Shown as",
+		"tools.override_description":   "Description",
+		"tools.override_hint":          "Only changes how the tool is shown in ChatGo; models are still sent its own name and description. Leave both empty to use the tool's own.",
+		"tools.more":                   "More",
+		"tools.detail_placeholder":     "Select a tool to see its full description and parameters.",
+		"tools.schema":                 "Parameters",
+		"tools.schema_no_parameters":   "This tool takes no parameters.",
+		"tools.schema_no_description":  "No description",
+		"tools.schema_values":          "One of: %s",
+		"tools.schema_more_properties": "%d more nested properties",
+		"tools.settings":               "Settings",
+
+		"mcp.path_invalid":            "Invalid MCP Server Path",
+		"mcp.path_invalid_message":    "An argument of MCP server '%s' is not an existing path: %s",
+		"mcp.init_failed":             "MCP Server Failed to Initialize",
+		"mcp.status_initializing":     "MCP: initializing (%d/%d)",
+		"mcp.status_connected":        "MCP: %d/%d connected",
+		"mcp.status_none_selected":    "Status: none selected",
+		"mcp.tools_none_selected":     "Tools: none selected",
+		"mcp.status_not_initialized":  "Status: not initialized",
+		"mcp.tools_not_initialized":   "Tools: not initialized",
+		"mcp.fix_path":                "Fix Path…",
+		"mcp.path_arg_empty":          "Argument %d should be a directory path but is empty",
+		"mcp.path_arg_missing":        "A path in the arguments does not exist: %s",
+		"mcp.status":                  "Status: %s",
+		"mcp.tools_count":             "Tools (%d):",
+		"mcp.tools_none":              "Tools: none available",
+		"mcp.initialize":              "Initialize",
+		"mcp.select_server_first":     "Please select a server first",
+		"mcp.initializing":            "Initializing",
+		"mcp.initializing_server":     "Initializing MCP server '%s'...",
+		"mcp.init_failed_title":       "Initialization Failed",
+		"mcp.init_failed_message":     "Initialization failed: %v",
+		"mcp.init_succeeded":          "Server '%s' initialized with %d tools",
+		"mcp.disconnect":              "Disconnect",
+		"mcp.disconnect_failed":       "Failed to disconnect",
+		"mcp.disconnected":            "Server '%s' disconnected",
+		"mcp.server_details":          "MCP Server Details",
+		"mcp.select_server_to_delete": "Please select a server to delete",
+		"mcp.delete_server":           "Delete MCP Server",
+		"mcp.delete_server_confirm":   "Are you sure you want to delete MCP server '%s'?",
+		"mcp.initialize_all":          "Initialize All",
+		"mcp.add_server":              "Add MCP Server",
+		"mcp.edit_server":             "Edit MCP Server",
+		"mcp.invalid_key_value_lines": "%d line(s) are not KEY=VALUE (line %s); fix them to save",
+
+		"logs.level":   "Level:",
+		"logs.copy":    "Copy",
+		"logs.to_file": "Also write the log to %s, keeping the last few files",
+
+		"handoff.missing_vision":  "The model %s can't read images.",
+		"handoff.missing_tools":   "The model %s can't call tools.",
+		"handoff.missing_context": "The conversation is too long for the model %s.",
+		"handoff.switch":          "Switch to %s (%s)",
+		"handoff.none":            "None of the other configured providers has a model known to support this. Add one in Settings or change the model.",
+
+		"incognito.new":             "Incognito (kept in memory only, never saved)",
+		"incognito.chip":            "Incognito · not saved",
+		"incognito.export":          "Export…",
+		"incognito.discard_title":   "Discard Incognito Conversation",
+		"incognito.discard_confirm": "This incognito conversation isn't saved anywhere and will be gone for good. Discard it?",
+
+		"ollama.model_missing": "The model %s isn't downloaded to Ollama yet.",
+		"ollama.pull":          "Pull Model",
+		"ollama.pull_title":    "Model Not Found",
+		"ollama.pull_confirm":  "Ollama doesn't have the model %s. Pull it now? The request is sent again once it's ready.",
+		"ollama.pulling":       "Pulling %s",
+		"ollama.pull_starting": "Starting…",
+
+		"quota.remaining":       "About %d requests left today",
+		"quota.remaining_reset": "About %d requests left today / resets at %s",
+		"quota.exhausted":       "Quota used up / resets at %s",
+		"quota.auto_retry":      "Retry automatically at %s",
+
+		"conversation.reveal_file":    "Show in File Manager",
+		"conversation.open_in_editor": "Open in External Editor",
+
+		"context.summarizing":          "Summarizing “%s”…",
+		"context.chip":                 "Continues “%s” · ~%d tokens",
+		"context.summary_title":        "Continues “%s”",
+		"context.include_summary":      "Include a summary of an earlier conversation",
+		"context.source":               "Conversation",
+		"context.new_chat_title":       "New Conversation",
+		"context.new_chat_confirm":     "Create",
+		"context.no_source":            "There is no conversation to continue",
+		"context.no_match":             "No conversation title contains “%s”",
+		"context.many_matches":         "%d conversation titles contain “%s”; enter more of the title",
+		"context.no_messages":          "“%s” has no messages to summarize yet",
+		"context.summary_failed_title": "Failed to Summarize",
+		"context.summary_failed":       "Couldn't summarize “%s”: %v",
+
+		"export.my_messages": "Export My Messages",
+		"export.no_messages": "You haven't sent any messages in this conversation yet.",
+		"export.numbered":    "Number the messages",
+		"export.count":       "Export %d messages, in order, to a Markdown file.",
+		"export.confirm":     "Export",
+
+		"attachments.no_vision_title": "Model Can't Read Images",
+		"attachments.no_vision":       "%s doesn't accept images; the image won't be sent to the model",
+
+		"titles.paused":     "Title generation paused (%d left)",
+		"titles.generating": "Generating titles… (%d left)",
+
+		"tool_approval.title":         "Run Tool?",
+		"tool_approval.message":       "The agent wants to run the tool %s. Allow it?",
+		"tool_approval.decline":       "Decline",
+		"tool_approval.allow_session": "Always Allow This Session",
+		"tool_approval.allow":         "Allow",
+
+		"tool_calls.arguments": "Arguments: %s",
+		"tool_calls.result":    "Result: %s",
+		"tool_calls.error":     "Error: %s",
+
+		"tool_limit.title":       "Too Many Tools",
+		"tool_limit.message":     "%d tools are selected, but %s (%s) accepts at most %d per request. The rest may be cut off, or the request may fail.",
+		"tool_limit.groups":      "Groups with the most tools:",
+		"tool_limit.group":       "%s: %d tools",
+		"tool_limit.deselect":    "Deselect",
+		"tool_limit.count":       "%d tools selected, limit %d",
+		"tool_limit.send_anyway": "Send Anyway",
+		"tool_limit.trim":        "Keep the %d Most Relevant and Send",
+
+		"transcript.title":         "Import Transcript",
+		"transcript.detected":      "Found %d messages. Change their roles or uncheck any you don't want, then import.",
+		"transcript.import":        "Import",
+		"transcript.paste_as_text": "Paste as Text",
+
+		"provider.summarize_left_out": "Summarize messages left out",
+		"provider.add_option":         "Add option…",
+		"provider.no_model_metadata":  "No metadata for this model",
+		"provider.no_stream":          "Don't stream replies",
+
+		"config_error.open_file":       "Open File",
+		"config_error.reload":          "Reload",
+		"config_error.restore":         "Restore Backup",
+		"config_error.restore_confirm": "Replace config.yaml with the copy saved before the last change?\nThe current file is kept as config.invalid.yaml.",
+		"config_error.reset":           "Reset to Defaults",
+		"config_error.reset_confirm":   "Start over with the default configuration?\nThe current file is kept as config.invalid.yaml.",
+		"config_error.hint":            "Fix the file and press Reload, or replace it.",
+
+		"about.title":         "About ChatGo",
+		"about.mode":          "Mode: %s",
+		"about.mode_standard": "Standard (per-user directories)",
+		"about.mode_portable": "Portable (enabled by %s or %s)",
+		"about.config":        "Config:",
+		"about.conversations": "Conversations:",
+		"about.logs":          "Logs:",
+		"about.attachments":   "Attachments:",
+		"about.backups":       "Backups:",
+
+		"activity.title":        "Activity",
+		"activity.summary":      "%d messages in the last year · current streak %d days · longest streak %d days",
+		"activity.hover_hint":   "Hover over a day to see its messages, click to show its conversations",
+		"activity.day_messages": "%s: %d messages",
+		"activity.less":         "Less",
+		"activity.more":         "More",
+		"activity.active_on":    "Active on %s",
+
+		"settings.general":                   "General",
+		"settings.providers":                 "Providers",
+		"settings.mcp_servers":               "MCP Servers",
+		"settings.builtin_tools":             "Built-in Tools",
+		"settings.agent":                     "Agent",
+		"settings.logs":                      "Logs",
+		"settings.title":                     "Settings",
+		"settings.theme_system":              "System",
+		"settings.theme_light":               "Light",
+		"settings.theme_dark":                "Dark",
+		"settings.language_system":           "System",
+		"settings.language_restart":          "Restart ChatGo to apply the new language everywhere.",
+		"settings.accent_default":            "Theme default",
+		"settings.accent_choose":             "Choose…",
+		"settings.accent_title":              "Accent Color",
+		"settings.accent_message":            "Used for buttons, selections and your messages",
+		"settings.reset":                     "Reset",
+		"settings.send_on_enter":             "Send with Enter (Shift+Enter inserts a newline)",
+		"settings.send_on_enter_hint":        "When unchecked, Shift+Enter sends and Enter inserts a newline. Ctrl+Enter (Cmd+Enter on macOS) always sends.",
+		"settings.system_prompt_placeholder": "e.g. You are a concise assistant. Answer in Chinese unless asked otherwise.",
+		"settings.system_prompt_hint":        "Sent before every conversation. A conversation's own system prompt follows it.",
+		"settings.editor_placeholder":        "e.g. code --wait (empty = system default)",
+		"settings.proxy_placeholder":         "http://host:port or socks5://host:port (empty = HTTP_PROXY/HTTPS_PROXY)",
+		"settings.current_provider":          "(current provider)",
+		"settings.appearance":                "Appearance",
+		"settings.language":                  "Language:",
+		"settings.language_section":          "Language",
+		"settings.theme":                     "Theme:",
+		"settings.accent_color":              "Accent color:",
+		"settings.message_text_size":         "Message text size:",
+		"settings.network":                   "Network",
+		"settings.proxy":                     "Proxy:",
+		"settings.input":                     "Input",
+		"settings.attachment_limit":          "Attachment limit (KB):",
+		"settings.system_prompt":             "System Prompt",
+		"settings.conversation_files":        "Conversation Files",
+		"settings.external_editor":           "External editor:",
+		"settings.background_titles":         "Background Titles and Summaries",
+		"settings.context_strategy":          "Long Conversations",
+		"settings.context_strategy_mode":     "Context",
+		"settings.context_last_n":            "Messages kept",
+		"settings.context_strategy_hint":     "Full history sends every message; Last messages sends only the latest ones; Summarize sends the latest ones with a summary of the rest. The oldest messages are still left out when they don't fit the model's context.",
+
+		"context_strategy.full":              "Full history",
+		"context_strategy.last_n":            "Last messages",
+		"context_strategy.summarize":         "Summarize",
+		"settings.provider":                  "Provider:",
+		"settings.requests_per_minute":       "Requests per minute:",
+		"settings.backup":                    "Backup",
+		"settings.export_redact_keys":        "Leave API keys out of exports",
+		"settings.export":                    "Export...",
+		"settings.import":                    "Import...",
+		"settings.backup_hint":               "An export holds every setting, to import on another machine. API keys written as ${VAR} are exported as references.",
+		"settings.export_done":               "Settings exported to %s",
+		"settings.import_title":              "Import Settings",
+		"settings.import_summary":            "%s has %d providers and %d MCP servers.",
+		"settings.import_merge":              "Merge: add them, replacing those of the same name",
+		"settings.import_replace":            "Replace all current settings",
+		"settings.import_keys_kept":          "Providers imported without an API key keep the key of the provider of the same name.",
+		"settings.import_confirm":            "Import",
+		"settings.enabled":                   "Enabled",
+		"settings.ask_before_run":            "Ask before each run",
+		"settings.tool_no_config":            "No additional configuration required for this tool type.",
+		"settings.status_enabled":            "enabled",
+		"settings.status_disabled":           "disabled",
+		"settings.tool_type":                 "Tool Type:",
+		"settings.tool_type_value":           "Tool Type: %s",
+		"settings.select_tool":               "(Select a tool from the list)",
+		"settings.builtin_tool_config":       "Built-in Tool Configuration",
+		"settings.tool_config":               "Tool Configuration:",
+		"settings.required_field":            "* = Required field",
+		"settings.save_config":               "Save Configuration",
+		"settings.select_tool_to_save":       "Please select a tool to save",
+		"settings.tool_config_saved":         "Configuration for '%s' has been saved.",
+		"settings.agent_enable":              "Enable React Agent mode (requests carry the selected tools)",
+		"settings.agent_steps_hint":          "The most steps the agent takes per request (%d–%d). Each model call and each tool call counts as one step.",
+		"settings.agent_max_steps":           "Max steps:",
+		"settings.provider_details":          "Provider Details",
+		"settings.add_new":                   "Add New",
+		"settings.select_provider_to_delete": "Please select a provider to delete",
+		"settings.delete_provider":           "Delete Provider",
+		"settings.delete_provider_confirm":   "Are you sure you want to delete provider '%s'?",
+		"settings.delete_provider_in_use":    "Provider '%s' is used by %d conversations. They, and the provider new chats start with, are moved to another provider.",
+		"settings.delete_provider_move_to":   "Move to:",
+		"settings.rename_provider":           "Provider Renamed",
+		"settings.rename_provider_confirm":   "Provider '%s' is now named '%s'. Update the %d conversations using it, and the provider new chats start with, to the new name?",
+		"settings.add_provider":              "Add Provider",
+		"settings.edit_provider":             "Edit Provider",
+		"settings.unknown_extras":            "%s providers don't read: %s",
+
+		"shortcuts.title":         "Keyboard Shortcuts",
+		"shortcuts.send":          "Send the message",
+		"shortcuts.new_chat":      "New conversation",
+		"shortcuts.settings":      "Open settings",
+		"shortcuts.search":        "Search conversations",
+		"shortcuts.next_chat":     "Next conversation",
+		"shortcuts.previous_chat": "Previous conversation",
+		"shortcuts.help":          "Show keyboard shortcuts",
+
+		"empty.title":            "Start the conversation",
+		"empty.model":            "Replies come from %s · %s",
+		"empty.prompt_explain":   "Explain how a hash map works, with a short example",
+		"empty.prompt_summarize": "Summarize the key points of the text I paste next",
+		"empty.prompt_write":     "Help me write a polite email declining a meeting",
+		"empty.send_hint_enter":  "Press Enter or %s to send, Shift+Enter for a new line",
+		"empty.send_hint":        "Press %s or Shift+Enter to send, Enter for a new line",
+		"empty.tools":            "Agent mode is on with %d tools: %s",
+		"empty.more_tools":       "%s and %d more",
+		"empty.no_tools":         "Agent mode is on, but no tools are selected",
+	})
+}
@@ -0,0 +1,370 @@
+package ui
+
+// Simplified Chinese strings
+func init() {
+	registerLocale("zh-CN", map[string]string{
+		"language.name": "简体中文",
+
+		"app.title": "ChatGo - AI 聊天助手",
+
+		"common.save":          "保存",
+		"common.cancel":        "取消",
+		"common.open_settings": "打开设置",
+		"common.ok":            "确定",
+		"common.success":       "成功",
+		"common.delete":        "删除",
+		"common.close":         "关闭",
+
+		"home.message_placeholder":  "输入消息开始聊天...",
+		"home.recent_conversations": "最近会话",
+		"home.empty_conversation":   "空会话",
+
+		"sidebar.new_chat": "新建会话",
+		"sidebar.settings": "设置",
+		"sidebar.all_tags": "全部标签",
+		"sidebar.search":   "搜索会话",
+
+		"sidebar.show_archived":  "显示已归档",
+		"conversation.archive":   "归档",
+		"conversation.unarchive": "取消归档",
+
+		"chat.message_placeholder":  "在此输入消息...",
+		"chat.send":                 "发送",
+		"chat.model":                "模型:",
+		"chat.tools":                "工具:",
+		"chat.context":              "上下文:",
+		"chat.finish_external_edit": "完成外部编辑",
+		"chat.read_only_newer":      "此会话由更新版本的 ChatGo 保存，只能查看。",
+		"chat.read_only_external":   "此会话正在外部编辑器中打开。磁盘上的修改会自动重新加载，保存已暂停。",
+		"chat.error_role":           "错误",
+		"chat.retry":                "重试",
+		"chat.waiting_for_model":    "正在等待模型…",
+		"chat.not_streamed":         "非流式",
+		"chat.request_timeout":      "请求超时：模型在 %s 内没有响应，请重试或在提供商设置中调整超时时间",
+
+		"conversation.title_placeholder": "输入新标题",
+		"conversation.tags_placeholder":  "例如：工作, 个人",
+		"conversation.edit":              "编辑会话",
+		"conversation.title":             "标题:",
+		"conversation.tags":              "标签（用逗号分隔）:",
+		"conversation.delete":            "删除会话",
+		"conversation.delete_confirm":    "确定要删除「%s」吗？会话会在回收站中保留 30 天。",
+
+		"archive.export":         "导出会话...",
+		"archive.import":         "导入会话...",
+		"archive.export_done":    "已将 %d 个会话导出到 %s",
+		"archive.import_done":    "已导入 %d 个会话，跳过已存在的 %d 个。",
+		"archive.import_renamed": "其中 %d 个会话的 ID 与现有会话冲突，已分配新 ID。",
+
+		"backup.keep":            "保留的每日备份数:",
+		"backup.last":            "上次自动备份: %s",
+		"backup.last_none":       "尚无自动备份",
+		"backup.none":            "还没有备份。每天首次启动时会自动备份一次。",
+		"backup.restore_from":    "从备份恢复…",
+		"backup.restore_title":   "从备份恢复",
+		"backup.restore_hint":    "恢复的会话会替换当前版本，备份之后新建的会话会保留。",
+		"backup.restore_what":    "恢复:",
+		"backup.restore_all":     "全部会话（%d 个）",
+		"backup.restore":         "恢复",
+		"backup.restore_confirm": "要用 %s 的备份替换当前版本吗？",
+		"backup.restored":        "已恢复 %d 个会话",
+
+		"schema.versions_in_use": "当前版本最高支持 v%d，此会话使用 v%d。",
+		"schema.title":           "会话文件格式版本",
+
+		"tools.select_count":           "选择工具 (%d)",
+		"tools.uninitialized_server":   "(未初始化) %s",
+		"tools.initialize_in_settings": "请先在设置中初始化此服务器",
+		"tools.select_count_limit":     "选择工具 (%d/%d)",
+		"tools.agent_off":              "Agent 模式未开启，请求不会携带工具",
+		"tools.send_count":             "每次请求将发送 %d 个工具",
+		"tools.send_count_over_limit":  "每次请求将发送 %d 个工具，超出当前模型的上限 %d 个",
+		"tools.send_count_limit":       "每次请求将发送 %d 个工具（上限 %d 个）",
+		"tools.builtin_group":          "内置",
+		"tools.choose":                 "选择要使用的工具:",
+		"tools.select":                 "选择工具",
+		"tools.override":               "别名/说明",
+		"tools.override_title":         "工具别名/说明",
+		"tools.override_tool":          "工具",
+		"tools.override_name":          "别名",
+		"tools.override_description":   "说明",
+		"tools.override_hint":          "仅改变工具在 ChatGo 中的显示，发送给模型的仍是工具原本的名称和说明。两项都留空即恢复原样。",
+		"tools.more":                   "更多",
+		"tools.detail_placeholder":     "选择一个工具以查看完整说明和参数。",
+		"tools.schema":                 "参数",
+		"tools.schema_no_parameters":   "此工具没有参数。",
+		"tools.schema_no_description":  "无说明",
+		"tools.schema_values":          "可选值：%s",
+		"tools.schema_more_properties": "另有 %d 个嵌套属性",
+		"tools.settings":               "设置",
+
+		// Built-in tool descriptions; other languages use the English ones from the config package
+		"tool_description.bingsearch":         "Bing 搜索 - 使用 Bing 搜索引擎搜索网页",
+		"tool_description.googlesearch":       "Google 搜索 - 使用 Google 搜索引擎搜索网页",
+		"tool_description.wikipedia":          "维基百科 - 搜索并获取维基百科中的信息",
+		"tool_description.duckduckgosearch":   "DuckDuckGo 搜索 - 使用 DuckDuckGo 进行隐私搜索",
+		"tool_description.httprequest":        "HTTP 请求 - 向网络服务发送 HTTP 请求",
+		"tool_description.browseruse":         "浏览器操作 - 自动执行浏览器交互",
+		"tool_description.commandline":        "命令行 - 执行 Shell 命令（请谨慎使用）",
+		"tool_description.sequentialthinking": "顺序思考 - 链式思维推理工具",
+		"tool_description.calculator":         "计算器 - 大数精确运算、单位换算和日期计算",
+		"tool_description.currenttime":        "当前时间 - 任意时区的当前日期和时间",
+		"tool_description.weather":            "天气 - 通过 Open-Meteo 查询城市或坐标的当前天气（无需 API Key）",
+
+		"mcp.path_invalid":            "MCP 服务器路径无效",
+		"mcp.path_invalid_message":    "MCP 服务器 '%s' 的参数中有不存在的路径：%s",
+		"mcp.init_failed":             "MCP 服务器初始化失败",
+		"mcp.status_initializing":     "MCP: 正在初始化 (%d/%d)",
+		"mcp.status_connected":        "MCP: %d/%d 已连接",
+		"mcp.status_none_selected":    "状态: 未选择",
+		"mcp.tools_none_selected":     "工具列表: 未选择",
+		"mcp.status_not_initialized":  "状态: 未初始化",
+		"mcp.tools_not_initialized":   "工具列表: 未初始化",
+		"mcp.fix_path":                "修复路径…",
+		"mcp.path_arg_empty":          "第 %d 个参数应为目录路径，但为空",
+		"mcp.path_arg_missing":        "参数中的路径不存在: %s",
+		"mcp.status":                  "状态: %s",
+		"mcp.tools_count":             "工具列表 (%d 个工具):",
+		"mcp.tools_none":              "工具列表: 无可用工具",
+		"mcp.initialize":              "初始化",
+		"mcp.select_server_first":     "请先选择一个服务器",
+		"mcp.initializing":            "正在初始化",
+		"mcp.initializing_server":     "正在初始化 MCP 服务器 '%s'...",
+		"mcp.init_failed_title":       "初始化失败",
+		"mcp.init_failed_message":     "初始化失败: %v",
+		"mcp.init_succeeded":          "服务器 '%s' 初始化成功，获取到 %d 个工具",
+		"mcp.disconnect":              "断开连接",
+		"mcp.disconnect_failed":       "断开连接失败",
+		"mcp.disconnected":            "服务器 '%s' 已断开连接",
+		"mcp.server_details":          "MCP 服务器详情",
+		"mcp.select_server_to_delete": "请先选择要删除的服务器",
+		"mcp.delete_server":           "删除 MCP 服务器",
+		"mcp.delete_server_confirm":   "确定要删除 MCP 服务器「%s」吗？",
+		"mcp.initialize_all":          "全部初始化",
+		"mcp.add_server":              "添加 MCP 服务器",
+		"mcp.invalid_key_value_lines": "%d 行不是 KEY=VALUE 格式（第 %s 行），修正后才能保存",
+		"mcp.edit_server":             "编辑 MCP 服务器",
+
+		"logs.level":   "级别：",
+		"logs.copy":    "复制",
+		"logs.to_file": "同时将日志写入 %s，并保留最近几个文件",
+
+		"handoff.missing_vision":  "模型 %s 无法读取图片。",
+		"handoff.missing_tools":   "模型 %s 无法调用工具。",
+		"handoff.missing_context": "对话过长，超出了模型 %s 的上下文。",
+		"handoff.switch":          "切换到 %s (%s)",
+		"handoff.none":            "其他已配置的服务商中没有已知支持此功能的模型。请在设置中添加，或更换模型。",
+
+		"incognito.new":             "隐私会话（仅保存在内存中，不写入磁盘）",
+		"incognito.chip":            "隐私会话 · 不会保存",
+		"incognito.export":          "导出…",
+		"incognito.discard_title":   "丢弃隐私会话",
+		"incognito.discard_confirm": "隐私会话不会保存在任何地方，关闭后将无法找回。确定丢弃吗？",
+
+		"ollama.model_missing": "Ollama 尚未下载模型 %s。",
+		"ollama.pull":          "拉取模型",
+		"ollama.pull_title":    "未找到模型",
+		"ollama.pull_confirm":  "Ollama 中没有模型 %s，现在拉取吗？模型就绪后会自动重新发送请求。",
+		"ollama.pulling":       "正在拉取 %s",
+		"ollama.pull_starting": "正在开始…",
+
+		"quota.remaining":       "今日剩余约 %d 次",
+		"quota.remaining_reset": "今日剩余约 %d 次 / 重置于 %s",
+		"quota.exhausted":       "额度已用完 / 重置于 %s",
+		"quota.auto_retry":      "到 %s 时自动重试",
+
+		"conversation.reveal_file":    "在文件管理器中显示",
+		"conversation.open_in_editor": "用外部编辑器打开",
+
+		"context.summarizing":          "正在总结「%s」…",
+		"context.chip":                 "接续「%s」 · ~%d tokens",
+		"context.summary_title":        "接续「%s」",
+		"context.include_summary":      "带上上一个会话的摘要",
+		"context.source":               "会话",
+		"context.new_chat_title":       "新建会话",
+		"context.new_chat_confirm":     "新建",
+		"context.no_source":            "没有可以接续的会话",
+		"context.no_match":             "找不到标题包含「%s」的会话",
+		"context.many_matches":         "有 %d 个会话的标题包含「%s」，请输入更完整的标题",
+		"context.no_messages":          "「%s」还没有消息，无法总结",
+		"context.summary_failed_title": "生成摘要失败",
+		"context.summary_failed":       "无法总结「%s」: %v",
+
+		"export.my_messages": "导出我的消息",
+		"export.no_messages": "这个对话中还没有你发送的消息。",
+		"export.numbered":    "为消息编号",
+		"export.count":       "将 %d 条消息按顺序导出为 Markdown 文件。",
+		"export.confirm":     "导出",
+
+		"attachments.no_vision_title": "模型不支持图片",
+		"attachments.no_vision":       "%s 不接受图片输入，图片不会发送给模型",
+
+		"titles.paused":     "标题生成已暂停 (剩余 %d)",
+		"titles.generating": "正在生成标题… (剩余 %d)",
+
+		"tool_approval.title":         "确认运行工具",
+		"tool_approval.message":       "智能体请求运行工具 %s，是否允许？",
+		"tool_approval.decline":       "拒绝",
+		"tool_approval.allow_session": "本次会话始终允许",
+		"tool_approval.allow":         "允许",
+
+		"tool_calls.arguments": "参数: %s",
+		"tool_calls.result":    "结果: %s",
+		"tool_calls.error":     "错误: %s",
+
+		"tool_limit.title":       "工具数量超出上限",
+		"tool_limit.message":     "已选择 %d 个工具，但 %s (%s) 每次请求最多接受 %d 个。超出的工具可能被截断，或导致请求失败。",
+		"tool_limit.groups":      "工具最多的分组：",
+		"tool_limit.group":       "%s: %d 个工具",
+		"tool_limit.deselect":    "取消选择",
+		"tool_limit.count":       "当前选择 %d 个工具，上限 %d 个",
+		"tool_limit.send_anyway": "仍然发送",
+		"tool_limit.trim":        "按相关性保留 %d 个并发送",
+
+		"transcript.title":         "导入对话记录",
+		"transcript.detected":      "检测到 %d 条对话记录，可修改角色或取消勾选后导入。",
+		"transcript.import":        "导入",
+		"transcript.paste_as_text": "作为文本粘贴",
+
+		"provider.summarize_left_out": "总结未发送的消息",
+		"provider.add_option":         "添加选项…",
+		"provider.no_model_metadata":  "没有这个模型的元数据",
+		"provider.no_stream":          "不使用流式回复",
+
+		"config_error.open_file":       "打开文件",
+		"config_error.reload":          "重新加载",
+		"config_error.restore":         "恢复备份",
+		"config_error.restore_confirm": "用上次修改前保存的副本替换 config.yaml？\n当前文件会保留为 config.invalid.yaml。",
+		"config_error.reset":           "恢复默认设置",
+		"config_error.reset_confirm":   "使用默认配置重新开始？\n当前文件会保留为 config.invalid.yaml。",
+		"config_error.hint":            "修正文件后点击重新加载，或者替换它。",
+
+		"about.title":         "关于 ChatGo",
+		"about.mode":          "模式：%s",
+		"about.mode_standard": "标准（按用户的目录）",
+		"about.mode_portable": "便携（由 %s 或 %s 启用）",
+		"about.config":        "配置：",
+		"about.conversations": "会话：",
+		"about.logs":          "日志：",
+		"about.attachments":   "附件：",
+		"about.backups":       "备份：",
+
+		"activity.title":        "活跃度",
+		"activity.summary":      "过去一年共 %d 条消息 · 当前连续 %d 天 · 最长连续 %d 天",
+		"activity.hover_hint":   "将鼠标悬停在某一天上查看消息数，点击显示当天的会话",
+		"activity.day_messages": "%s：%d 条消息",
+		"activity.less":         "少",
+		"activity.more":         "多",
+		"activity.active_on":    "%s 有活动",
+
+		"settings.general":                   "通用",
+		"settings.providers":                 "服务商",
+		"settings.mcp_servers":               "MCP 服务器",
+		"settings.builtin_tools":             "内置工具",
+		"settings.agent":                     "Agent",
+		"settings.logs":                      "日志",
+		"settings.title":                     "设置",
+		"settings.theme_system":              "跟随系统",
+		"settings.theme_light":               "浅色",
+		"settings.theme_dark":                "深色",
+		"settings.language_system":           "跟随系统",
+		"settings.language_restart":          "重启 ChatGo 后新语言才会全部生效。",
+		"settings.accent_default":            "主题默认",
+		"settings.accent_choose":             "选择…",
+		"settings.accent_title":              "强调色",
+		"settings.accent_message":            "用于按钮、选中项和你的消息",
+		"settings.reset":                     "重置",
+		"settings.send_on_enter":             "按 Enter 发送（Shift+Enter 换行）",
+		"settings.send_on_enter_hint":        "取消勾选时，Shift+Enter 发送，Enter 换行。Ctrl+Enter（macOS 上为 Cmd+Enter）始终发送。",
+		"settings.system_prompt_placeholder": "例如：你是一个简洁的助手，除非另有要求，请用中文回答。",
+		"settings.system_prompt_hint":        "在每个会话之前发送，会话自己的系统提示词跟在它后面。",
+		"settings.editor_placeholder":        "例如 code --wait（留空则使用系统默认）",
+		"settings.proxy_placeholder":         "http://host:port 或 socks5://host:port（留空则使用 HTTP_PROXY/HTTPS_PROXY）",
+		"settings.current_provider":          "（当前服务商）",
+		"settings.appearance":                "外观",
+		"settings.language":                  "语言:",
+		"settings.language_section":          "语言",
+		"settings.theme":                     "主题:",
+		"settings.accent_color":              "强调色:",
+		"settings.message_text_size":         "消息字号:",
+		"settings.network":                   "网络",
+		"settings.proxy":                     "代理:",
+		"settings.input":                     "输入",
+		"settings.attachment_limit":          "附件大小上限 (KB):",
+		"settings.system_prompt":             "系统提示词",
+		"settings.conversation_files":        "会话文件",
+		"settings.external_editor":           "外部编辑器:",
+		"settings.background_titles":         "后台生成标题和摘要",
+		"settings.context_strategy":          "长对话",
+		"settings.context_strategy_mode":     "上下文",
+		"settings.context_last_n":            "保留消息数",
+		"settings.context_strategy_hint":     "完整历史会发送全部消息；最近消息只发送最新的若干条；自动摘要会发送最新的若干条并附上其余消息的摘要。超出模型上下文的最早消息仍会被省略。",
+
+		"context_strategy.full":              "完整历史",
+		"context_strategy.last_n":            "最近消息",
+		"context_strategy.summarize":         "自动摘要",
+		"settings.provider":                  "服务商:",
+		"settings.requests_per_minute":       "每分钟请求数:",
+		"settings.backup":                    "备份",
+		"settings.export_redact_keys":        "导出时不包含 API Key",
+		"settings.export":                    "导出...",
+		"settings.import":                    "导入...",
+		"settings.backup_hint":               "导出文件包含全部设置，可在另一台电脑上导入。写成 ${VAR} 的 API Key 会按引用导出。",
+		"settings.export_done":               "设置已导出到 %s",
+		"settings.import_title":              "导入设置",
+		"settings.import_summary":            "%s 包含 %d 个服务商和 %d 个 MCP 服务器。",
+		"settings.import_merge":              "合并：添加到现有设置，替换同名项",
+		"settings.import_replace":            "替换：用导入的设置替换全部现有设置",
+		"settings.import_keys_kept":          "没有 API Key 的服务商会沿用同名服务商已有的 Key。",
+		"settings.import_confirm":            "导入",
+		"settings.enabled":                   "启用",
+		"settings.ask_before_run":            "每次运行前询问",
+		"settings.tool_no_config":            "此类工具无需额外配置。",
+		"settings.status_enabled":            "已启用",
+		"settings.status_disabled":           "已停用",
+		"settings.tool_type":                 "工具类型:",
+		"settings.tool_type_value":           "工具类型: %s",
+		"settings.select_tool":               "（从列表中选择一个工具）",
+		"settings.builtin_tool_config":       "内置工具配置",
+		"settings.tool_config":               "工具配置:",
+		"settings.required_field":            "* = 必填项",
+		"settings.save_config":               "保存配置",
+		"settings.select_tool_to_save":       "请先选择要保存的工具",
+		"settings.tool_config_saved":         "「%s」的配置已保存。",
+		"settings.agent_enable":              "启用 React Agent 模式（请求会携带所选工具）",
+		"settings.agent_steps_hint":          "每次请求中 Agent 最多执行的步骤数（%d–%d），每次模型调用和工具调用各算一步。",
+		"settings.agent_max_steps":           "最大步数:",
+		"settings.provider_details":          "服务商详情",
+		"settings.add_new":                   "新增",
+		"settings.select_provider_to_delete": "请先选择要删除的服务商",
+		"settings.delete_provider":           "删除服务商",
+		"settings.delete_provider_confirm":   "确定要删除服务商「%s」吗？",
+		"settings.delete_provider_in_use":    "服务商「%s」正被 %d 个对话使用。这些对话以及新对话默认使用的服务商将改为另一个服务商。",
+		"settings.delete_provider_move_to":   "改为：",
+		"settings.rename_provider":           "服务商已重命名",
+		"settings.rename_provider_confirm":   "服务商「%s」已更名为「%s」。是否将使用它的 %d 个对话以及新对话默认使用的服务商更新为新名称？",
+		"settings.add_provider":              "添加服务商",
+		"settings.edit_provider":             "编辑服务商",
+		"settings.unknown_extras":            "%s 服务商不会读取：%s",
+
+		"shortcuts.title":         "键盘快捷键",
+		"shortcuts.send":          "发送消息",
+		"shortcuts.new_chat":      "新建会话",
+		"shortcuts.settings":      "打开设置",
+		"shortcuts.search":        "搜索会话",
+		"shortcuts.next_chat":     "下一个会话",
+		"shortcuts.previous_chat": "上一个会话",
+		"shortcuts.help":          "显示键盘快捷键",
+
+		"empty.title":            "开始对话",
+		"empty.model":            "由 %s · %s 回复",
+		"empty.prompt_explain":   "解释哈希表的工作原理，并给出一个简短的例子",
+		"empty.prompt_summarize": "总结我接下来粘贴的文本的要点",
+		"empty.prompt_write":     "帮我写一封礼貌地拒绝会议邀请的邮件",
+		"empty.send_hint_enter":  "按 Enter 或 %s 发送，Shift+Enter 换行",
+		"empty.send_hint":        "按 %s 或 Shift+Enter 发送，Enter 换行",
+		"empty.tools":            "Agent 模式已开启，可使用 %d 个工具：%s",
+		"empty.more_tools":       "%s 等另外 %d 个",
+		"empty.no_tools":         "Agent 模式已开启，但未选择任何工具",
+	})
+}
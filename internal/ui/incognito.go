@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"chatgo/pkg/models"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// saveConversation saves a conversation unless it is incognito, which lives in memory only
+func (cw *ChatWindow) saveConversation(conv *models.Conversation) error {
+	if conv.Incognito() {
+		return nil
+	}
+	return cw.convManager.SaveConversation(conv)
+}
+
+// confirmDiscardIncognito calls leave once the user agrees to discard the open incognito
+// conversation, or right away when the open conversation is saved or the incognito one is
+// still empty. When the user keeps it, the sidebar selection goes back to it.
+func (cw *ChatWindow) confirmDiscardIncognito(leave func()) {
+	conv := cw.currentConversation
+	if conv == nil || !conv.Incognito() || len(conv.Messages) == 0 {
+		leave()
+		return
+	}
+	dialog.ShowConfirm(Translate("incognito.discard_title"), Translate("incognito.discard_confirm"), func(discard bool) {
+		if discard {
+			leave()
+			return
+		}
+		cw.syncConversationSelection()
+	}, cw.window)
+}
+
+// newIncognitoChip creates the chip above the input marking an incognito conversation, with a
+// button exporting it, the only way to keep it
+func (cw *ChatWindow) newIncognitoChip(conv *models.Conversation) fyne.CanvasObject {
+	label := widget.NewLabelWithStyle(Translate("incognito.chip"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	label.Importance = widget.WarningImportance
+
+	exportBtn := widget.NewButtonWithIcon(Translate("incognito.export"), theme.DocumentSaveIcon(), func() {
+		cw.exportConversationMarkdown(conv)
+	})
+	exportBtn.Importance = widget.LowImportance
+
+	return container.NewHBox(widget.NewIcon(theme.VisibilityOffIcon()), label, exportBtn)
+}
+
+// exportConversationMarkdown saves a conversation to a markdown file the user picks
+func (cw *ChatWindow) exportConversationMarkdown(conv *models.Conversation) {
+	markdown := models.ConversationMarkdown(conv)
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, cw.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+		if _, err := writer.Write([]byte(markdown)); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to write %s: %w", writer.URI().Name(), err), cw.window)
+		}
+	}, cw.window)
+	save.SetFileName(exportFileName(conv.Title) + ".md")
+	save.SetFilter(storage.NewExtensionFileFilter([]string{".md"}))
+	save.Show()
+}
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"chatgo/internal/log"
+	"chatgo/internal/paths"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// applyLogFile mirrors the log to chatgo.log in the logs directory when enabled, or stops mirroring
+func applyLogFile(enabled bool) error {
+	path := ""
+	if enabled {
+		var err error
+		if path, err = paths.LogFile(); err != nil {
+			return err
+		}
+	}
+	return log.SetFile(path)
+}
+
+// levelImportance colors a log entry by its level
+func levelImportance(level log.Level) widget.Importance {
+	switch level {
+	case log.LevelDebug:
+		return widget.LowImportance
+	case log.LevelWarn:
+		return widget.WarningImportance
+	case log.LevelError:
+		return widget.DangerImportance
+	default:
+		return widget.MediumImportance
+	}
+}
+
+// createLogsTab creates the Logs settings tab, which shows the entries kept in memory from a chosen
+// level up and follows new ones while the tab exists
+func (cw *ChatWindow) createLogsTab(parentWindow fyne.Window) fyne.CanvasObject {
+	minLevel := log.LevelDebug
+	var shown []log.Entry
+	filter := func() {
+		shown = shown[:0]
+		for _, e := range log.Entries() {
+			if e.Level >= minLevel {
+				shown = append(shown, e)
+			}
+		}
+	}
+	filter()
+
+	list := widget.NewList(
+		func() int {
+			return len(shown)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			label.Importance = levelImportance(shown[id].Level)
+			label.SetText(shown[id].String())
+		},
+	)
+	// Selecting an entry shows it in full, as long lines are cut off in the list
+	list.OnSelected = func(id widget.ListItemID) {
+		list.Unselect(id)
+		text := widget.NewLabel(shown[id].String())
+		text.TextStyle = fyne.TextStyle{Monospace: true}
+		text.Wrapping = fyne.TextWrapBreak
+		d := dialog.NewCustom(shown[id].Level.String(), Translate("common.close"), container.NewVScroll(text), parentWindow)
+		d.Resize(fyne.NewSize(640, 320))
+		d.Show()
+	}
+	list.ScrollToBottom()
+
+	levelNames := make([]string, len(log.Levels))
+	for i, level := range log.Levels {
+		levelNames[i] = level.String()
+	}
+	levelSelect := widget.NewSelect(levelNames, nil)
+	levelSelect.SetSelectedIndex(int(minLevel))
+	levelSelect.OnChanged = func(string) {
+		minLevel = log.Levels[levelSelect.SelectedIndex()]
+		filter()
+		list.Refresh()
+		list.ScrollToBottom()
+	}
+
+	copyBtn := widget.NewButtonWithIcon(Translate("logs.copy"), theme.ContentCopyIcon(), func() {
+		lines := make([]string, len(shown))
+		for i, e := range shown {
+			lines[i] = e.String()
+		}
+		cw.app.Clipboard().SetContent(strings.Join(lines, "\n"))
+	})
+
+	logFile, _ := paths.LogFile()
+	fileCheck := widget.NewCheck(Translatef("logs.to_file", logFile), func(checked bool) {
+		cw.config.LogToFile = checked
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+		}
+		if err := applyLogFile(checked); err != nil {
+			dialog.ShowError(err, parentWindow)
+		}
+	})
+	fileCheck.Checked = cw.config.LogToFile
+
+	// Follow new entries until the tab is recreated or the settings window closes
+	cw.stopFollowingLogs()
+	cw.unsubscribeLogs = log.Subscribe(func(e log.Entry) {
+		fyne.Do(func() {
+			if e.Level < minLevel {
+				return
+			}
+			filter()
+			list.Refresh()
+			list.ScrollToBottom()
+		})
+	})
+
+	toolbar := container.NewHBox(widget.NewLabel(Translate("logs.level")), levelSelect, layout.NewSpacer(), copyBtn)
+	return container.NewBorder(
+		container.NewPadded(toolbar),
+		container.NewPadded(fileCheck),
+		nil, nil,
+		list,
+	)
+}
+
+// stopFollowingLogs stops the Logs tab from following new entries, if it was
+func (cw *ChatWindow) stopFollowingLogs() {
+	if cw.unsubscribeLogs != nil {
+		cw.unsubscribeLogs()
+		cw.unsubscribeLogs = nil
+	}
+}
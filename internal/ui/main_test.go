@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestMain keeps the tests' conversations, settings and quotas out of the user's directories
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "chatgo-ui-test")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("XDG_CONFIG_HOME", home+"/.config")
+
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"image/color"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -11,25 +12,30 @@ import (
 
 // RichTextConfig holds configuration for markdown rendering
 type RichTextConfig struct {
-	Wrapping   fyne.TextWrap
-	TextColor  color.Color
-	Inline     bool
-	Hyperlinks bool
+	Wrapping           fyne.TextWrap
+	TextColor          color.Color
+	Inline             bool
+	Hyperlinks         bool
+	SyntaxHighlighting bool // Colorize fenced code blocks that have a supported language hint
+	CodeCopyButtons    bool // Head fenced code blocks with their language and a button copying the code
+	Streaming          bool // The markdown is still arriving; a table is shown once its last row is complete
 }
 
 // DefaultRichTextConfig returns default configuration for markdown rendering
 func DefaultRichTextConfig() *RichTextConfig {
 	return &RichTextConfig{
-		Wrapping:   fyne.TextWrapWord,
-		TextColor:  nil, // Use default theme color
-		Inline:     false,
-		Hyperlinks: true,
+		Wrapping:           fyne.TextWrapWord,
+		TextColor:          nil, // Use default theme color
+		Inline:             false,
+		Hyperlinks:         true,
+		SyntaxHighlighting: true,
+		CodeCopyButtons:    true,
 	}
 }
 
 // CreateMarkdownRichText creates a RichText widget configured for markdown rendering
 func CreateMarkdownRichText(markdown string, config *RichTextConfig) *widget.RichText {
-	richText := widget.NewRichTextFromMarkdown(markdown)
+	richText := widget.NewRichText(markdownSegments(markdown, config)...)
 
 	if config != nil {
 		richText.Wrapping = config.Wrapping
@@ -44,22 +50,75 @@ func CreateMarkdownRichText(markdown string, config *RichTextConfig) *widget.Ric
 	return richText
 }
 
-// CreateMessageBubble creates a styled container for chat messages with markdown content
-func CreateMessageBubble(content string, isUser bool) *fyne.Container {
-	config := DefaultRichTextConfig()
-	richText := CreateMarkdownRichText(content, config)
+// SetMarkdown replaces the content of a RichText with rendered markdown, applying the
+// same highlighting and tables as CreateMarkdownRichText
+func SetMarkdown(richText *widget.RichText, markdown string, config *RichTextConfig) {
+	richText.Segments = markdownSegments(markdown, config)
+	richText.Refresh()
+}
 
-	// Create a border around the message
-	border := canvas.NewRectangle(color.NRGBA{R: 200, G: 200, B: 200, A: 255})
-	border.CornerRadius = 5
+// markdownSegments parses markdown into RichText segments. Tables, which the Fyne parser
+// shows as plain text, are rendered as tables, and code blocks are highlighted and given copy
+// buttons if configured.
+func markdownSegments(markdown string, config *RichTextConfig) []widget.RichTextSegment {
+	streaming := config != nil && config.Streaming
+	highlight := config != nil && config.SyntaxHighlighting
+	copyButtons := config != nil && config.CodeCopyButtons
 
-	contentContainer := container.NewPadded(richText)
+	var segments []widget.RichTextSegment
+	for _, part := range splitMarkdownTables(markdown, streaming) {
+		if part.table != nil {
+			segments = append(segments, &tableSegment{table: part.table})
+			continue
+		}
+		parsed := widget.NewRichTextFromMarkdown(part.text).Segments
+		if highlight || copyButtons {
+			parsed = renderCodeBlocks(parsed, part.text, highlight, copyButtons)
+		}
+		segments = append(segments, parsed...)
+	}
+	scaleMessageText(segments)
+	return segments
+}
 
-	// Add padding and styling
-	return container.NewStack(
-		border,
-		contentContainer,
+// scaleMessageText sets segments, and those nested in them, to the text sizes of chat
+// messages so they follow the message text size set in Settings
+func scaleMessageText(segments []widget.RichTextSegment) {
+	for _, segment := range segments {
+		switch seg := segment.(type) {
+		case *widget.TextSegment:
+			if size, ok := messageSizes[seg.Style.SizeName]; ok {
+				seg.Style.SizeName = size
+			}
+		case *widget.ParagraphSegment:
+			scaleMessageText(seg.Texts)
+		case *widget.ListSegment:
+			scaleMessageText(seg.Items)
+		case *codeBlockSegment:
+			scaleMessageText(seg.segments)
+		}
+	}
+}
+
+// CreateMessageBubble lays out the parts of a message in a bubble beside an avatar with the
+// initial of its role. User messages are on the right and others on the left, each with
+// its own theme color, and a margin on the far side keeps the two apart.
+func CreateMessageBubble(role string, parts ...fyne.CanvasObject) *fyne.Container {
+	bubbleColor, _, _ := roleColors(role)
+	bubble := container.NewStack(
+		newThemedRectangle(bubbleColor),
+		container.NewPadded(container.NewVBox(parts...)),
 	)
+
+	// The avatar stays level with the top of the bubble
+	avatar := container.NewVBox(newMessageAvatar(role))
+	gutter := canvas.NewRectangle(color.Transparent)
+	gutter.SetMinSize(fyne.NewSize(bubbleGutter, 0))
+
+	if role == "user" {
+		return container.NewBorder(nil, nil, gutter, avatar, bubble)
+	}
+	return container.NewBorder(nil, nil, avatar, gutter, bubble)
 }
 
 // ParseMarkdownToText parses markdown and returns plain text (for fallback)
@@ -70,3 +129,36 @@ func ParseMarkdownToText(markdown string) string {
 	// since RichTextFromMarkdown handles the parsing
 	return markdown
 }
+
+// ExtractCodeBlocks returns the contents of all fenced code blocks in the markdown,
+// without the fence lines themselves
+func ExtractCodeBlocks(markdown string) []string {
+	var blocks []string
+	var current []string
+	fence := ""
+
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence == "" {
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				fence = trimmed[:3]
+				current = nil
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, fence) && strings.TrimLeft(trimmed, fence[:1]) == "" {
+			blocks = append(blocks, strings.Join(current, "\n"))
+			fence = ""
+			continue
+		}
+		current = append(current, line)
+	}
+
+	// An unterminated fence still counts as code
+	if fence != "" && len(current) > 0 {
+		blocks = append(blocks, strings.Join(current, "\n"))
+	}
+
+	return blocks
+}
@@ -0,0 +1,287 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// markdownTable is a GitHub-style pipe table
+type markdownTable struct {
+	header []string
+	align  []fyne.TextAlign // One per column, from the delimiter row
+	rows   [][]string       // Padded or cut to the header's column count
+}
+
+// markdownPart is a run of markdown text, or a table lifted out of it
+type markdownPart struct {
+	text  string
+	table *markdownTable
+}
+
+// splitMarkdownTables separates the tables in markdown from the text around them. Tables inside
+// fenced code blocks are left alone. When streaming, a table that reaches the last line of the
+// markdown may still be growing and is left as text until the line after it is complete.
+func splitMarkdownTables(markdown string, streaming bool) []markdownPart {
+	lines := strings.Split(markdown, "\n")
+	var parts []markdownPart
+	var text []string
+	fence := ""
+
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.TrimLeft(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			text = append(text, lines[i])
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			text = append(text, lines[i])
+			continue
+		}
+
+		table, end := parseMarkdownTable(lines, i)
+		if table == nil || (streaming && end >= len(lines)-1) {
+			text = append(text, lines[i])
+			continue
+		}
+
+		if len(text) > 0 {
+			parts = append(parts, markdownPart{text: strings.Join(text, "\n")})
+			text = nil
+		}
+		parts = append(parts, markdownPart{table: table})
+		i = end - 1
+	}
+
+	if len(text) > 0 {
+		parts = append(parts, markdownPart{text: strings.Join(text, "\n")})
+	}
+	return parts
+}
+
+// parseMarkdownTable parses a table whose header row is lines[start]. It returns nil when
+// there is no table there, otherwise the table and the index of the line after it.
+func parseMarkdownTable(lines []string, start int) (*markdownTable, int) {
+	if start+1 >= len(lines) || !strings.Contains(lines[start], "|") {
+		return nil, start
+	}
+	header := splitTableRow(lines[start])
+	align, ok := parseTableDelimiter(lines[start+1])
+	if !ok || len(align) != len(header) {
+		return nil, start
+	}
+
+	table := &markdownTable{header: header, align: align}
+	end := start + 2
+	for ; end < len(lines); end++ {
+		line := strings.TrimSpace(lines[end])
+		if line == "" || !strings.Contains(line, "|") {
+			break
+		}
+		// Ragged rows are padded with empty cells; extra cells are dropped
+		cells := splitTableRow(line)
+		row := make([]string, len(header))
+		copy(row, cells)
+		table.rows = append(table.rows, row)
+	}
+	return table, end
+}
+
+// parseTableDelimiter reads the alignment of each column from a delimiter row such as
+// "| :--- | :---: | ---: |". It reports false when the line is not a delimiter row.
+func parseTableDelimiter(line string) ([]fyne.TextAlign, bool) {
+	if !strings.Contains(line, "-") || !strings.Contains(line, "|") {
+		return nil, false
+	}
+	cells := splitTableRow(line)
+	align := make([]fyne.TextAlign, len(cells))
+	for i, cell := range cells {
+		dashes := strings.Trim(cell, ":")
+		if dashes == "" || strings.Trim(dashes, "-") != "" {
+			return nil, false
+		}
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			align[i] = fyne.TextAlignCenter
+		case right:
+			align[i] = fyne.TextAlignTrailing
+		default:
+			align[i] = fyne.TextAlignLeading
+		}
+	}
+	return align, true
+}
+
+// splitTableRow splits a table row into trimmed cells. Leading and trailing pipes are
+// optional and escaped pipes (\|) stay in the cell.
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// tableSegment shows a markdown table inside a RichText, with a shaded header row and
+// columns as wide as their widest cell. Tables wider than the message scroll sideways.
+type tableSegment struct {
+	table *markdownTable
+}
+
+// Inline returns false as a table takes its own block
+func (s *tableSegment) Inline() bool {
+	return false
+}
+
+// Textual returns the table as tab-separated rows
+func (s *tableSegment) Textual() string {
+	lines := []string{strings.Join(s.table.header, "\t")}
+	for _, row := range s.table.rows {
+		lines = append(lines, strings.Join(row, "\t"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Visual creates the table's cells in a horizontal scroll
+func (s *tableSegment) Visual() fyne.CanvasObject {
+	grid := container.New(&tableLayout{columns: len(s.table.header)})
+	s.fill(grid)
+	return container.NewHScroll(grid)
+}
+
+// Update replaces the cells of an existing visual with this table's
+func (s *tableSegment) Update(o fyne.CanvasObject) {
+	grid := o.(*container.Scroll).Content.(*fyne.Container)
+	grid.Layout = &tableLayout{columns: len(s.table.header)}
+	s.fill(grid)
+	grid.Refresh()
+}
+
+// fill creates a cell for every header and body cell, row by row
+func (s *tableSegment) fill(grid *fyne.Container) {
+	objects := make([]fyne.CanvasObject, 0, len(s.table.header)*(len(s.table.rows)+1))
+	for col, text := range s.table.header {
+		// Follows the theme so the header stays readable when switching to dark mode
+		background := &themedRectangle{fill: theme.ColorNameHeaderBackground, square: true}
+		background.ExtendBaseWidget(background)
+		objects = append(objects, container.NewStack(background, newTableCell(text, s.table.align[col], true)))
+	}
+	for _, row := range s.table.rows {
+		for col, text := range row {
+			objects = append(objects, newTableCell(text, s.table.align[col], false))
+		}
+	}
+	grid.Objects = objects
+}
+
+// Select does nothing; table cells cannot be selected
+func (s *tableSegment) Select(_, _ fyne.Position) {
+}
+
+// SelectedText returns nothing as table cells cannot be selected
+func (s *tableSegment) SelectedText() string {
+	return ""
+}
+
+// Unselect does nothing; table cells cannot be selected
+func (s *tableSegment) Unselect() {
+}
+
+// newTableCell renders a cell's inline markdown, such as bold text and code spans
+func newTableCell(text string, align fyne.TextAlign, header bool) fyne.CanvasObject {
+	cell := widget.NewRichTextFromMarkdown(text)
+	for _, segment := range cell.Segments {
+		if seg, ok := segment.(*widget.TextSegment); ok {
+			seg.Style.Alignment = align
+			if header {
+				seg.Style.TextStyle.Bold = true
+			}
+		}
+	}
+	scaleMessageText(cell.Segments)
+	return cell
+}
+
+// tableLayout arranges cells row by row in columns as wide as their widest cell
+type tableLayout struct {
+	columns int
+}
+
+// sizes returns the width of each column and the height of each row
+func (l *tableLayout) sizes(objects []fyne.CanvasObject) ([]float32, []float32) {
+	widths := make([]float32, l.columns)
+	heights := make([]float32, (len(objects)+l.columns-1)/l.columns)
+	for i, o := range objects {
+		min := o.MinSize()
+		col, row := i%l.columns, i/l.columns
+		widths[col] = max(widths[col], min.Width)
+		heights[row] = max(heights[row], min.Height)
+	}
+	return widths, heights
+}
+
+// MinSize is the sum of the column widths and row heights
+func (l *tableLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	if l.columns == 0 {
+		return fyne.NewSize(0, 0)
+	}
+	widths, heights := l.sizes(objects)
+	var size fyne.Size
+	for _, w := range widths {
+		size.Width += w
+	}
+	for _, h := range heights {
+		size.Height += h
+	}
+	return size
+}
+
+// Layout places each cell in its column and row; the last column takes any extra width
+func (l *tableLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	if l.columns == 0 {
+		return
+	}
+	widths, heights := l.sizes(objects)
+	if extra := size.Width - l.MinSize(objects).Width; extra > 0 {
+		widths[l.columns-1] += extra
+	}
+
+	var y float32
+	for row, height := range heights {
+		var x float32
+		for col, width := range widths {
+			i := row*l.columns + col
+			if i >= len(objects) {
+				break
+			}
+			objects[i].Move(fyne.NewPos(x, y))
+			objects[i].Resize(fyne.NewSize(width, height))
+			x += width
+		}
+		y += height
+	}
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// messageActions holds the per-message copy buttons shown in the message header
+type messageActions struct {
+	box         *fyne.Container
+	copyBtn     *widget.Button
+	copyCodeBtn *widget.Button
+	content     func() string
+}
+
+// newMessageActions creates copy buttons that read the message markdown through content
+// at the time they are tapped
+func (cw *ChatWindow) newMessageActions(content func() string) *messageActions {
+	a := &messageActions{content: content}
+
+	a.copyBtn = widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+		cw.app.Clipboard().SetContent(a.content())
+	})
+	a.copyBtn.Importance = widget.LowImportance
+
+	a.copyCodeBtn = widget.NewButton("Copy code", func() {
+		blocks := ExtractCodeBlocks(a.content())
+		cw.app.Clipboard().SetContent(strings.Join(blocks, "\n\n"))
+	})
+	a.copyCodeBtn.Importance = widget.LowImportance
+
+	a.box = container.NewHBox(a.copyCodeBtn, a.copyBtn)
+	a.Refresh()
+
+	return a
+}
+
+// Refresh shows the "Copy code" action only when the message contains fenced code
+func (a *messageActions) Refresh() {
+	if len(ExtractCodeBlocks(a.content())) > 0 {
+		a.copyCodeBtn.Show()
+	} else {
+		a.copyCodeBtn.Hide()
+	}
+}
+
+// SetEnabled enables or disables the copy actions, e.g. while a reply is still streaming
+func (a *messageActions) SetEnabled(enabled bool) {
+	if enabled {
+		a.copyBtn.Enable()
+		a.copyCodeBtn.Enable()
+	} else {
+		a.copyBtn.Disable()
+		a.copyCodeBtn.Disable()
+	}
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// messagePageSize is how many messages are shown when a conversation is opened, and how
+// many more each press of the load button adds. Rendering every message of a long
+// conversation at once makes it slow to open and keeps all of its widgets in memory.
+const messagePageSize = 40
+
+// showConversationMessages replaces the chat with the latest messages of the current
+// conversation, below a button that loads earlier ones. A conversation without messages shows
+// the empty state instead.
+func (cw *ChatWindow) showConversationMessages() {
+	messages := cw.currentConversation.Messages
+	if len(messages) == 0 {
+		cw.showEmptyState()
+		return
+	}
+	cw.emptyState = nil
+	cw.firstShownMessage = max(0, len(messages)-messagePageSize)
+
+	objects := make([]fyne.CanvasObject, 0, len(messages)-cw.firstShownMessage+1)
+	if cw.firstShownMessage > 0 {
+		cw.updateEarlierButton()
+		objects = append(objects, cw.earlierMessages)
+	}
+	objects = append(objects, cw.newMessageRows(cw.firstShownMessage, len(messages))...)
+
+	cw.messagesContainer.Objects = objects
+	cw.messagesContainer.Refresh()
+}
+
+// loadEarlierMessages renders the page of messages before the first one shown, keeping the
+// messages on screen where they are
+func (cw *ChatWindow) loadEarlierMessages() {
+	if cw.currentConversation == nil || cw.firstShownMessage == 0 {
+		return
+	}
+
+	end := cw.firstShownMessage
+	cw.firstShownMessage = max(0, end-messagePageSize)
+	rows := cw.newMessageRows(cw.firstShownMessage, end)
+
+	// The load button is the first object while there are earlier messages
+	shown := cw.messagesContainer.Objects[1:]
+	objects := make([]fyne.CanvasObject, 0, len(rows)+len(shown)+1)
+	if cw.firstShownMessage > 0 {
+		cw.updateEarlierButton()
+		objects = append(objects, cw.earlierMessages)
+	}
+	objects = append(objects, rows...)
+	objects = append(objects, shown...)
+
+	heightBefore := cw.messagesContainer.MinSize().Height
+	cw.messagesContainer.Objects = objects
+	cw.messagesContainer.Refresh()
+
+	// Content was added above the view, so scroll down by its height
+	added := cw.messagesContainer.MinSize().Height - heightBefore
+	cw.chatArea.ScrollToOffset(fyne.NewPos(cw.chatArea.Offset.X, cw.chatArea.Offset.Y+added))
+}
+
+// updateEarlierButton shows how many messages are not yet rendered
+func (cw *ChatWindow) updateEarlierButton() {
+	cw.earlierBtn.SetText(fmt.Sprintf("Load earlier messages (%d more)", cw.firstShownMessage))
+}
+
+// newMessageRows creates the rows of the current conversation's messages from start up to
+// end, with a date separator before each message sent on a later day than the one before it
+func (cw *ChatWindow) newMessageRows(start, end int) []fyne.CanvasObject {
+	messages := cw.currentConversation.Messages
+	rows := make([]fyne.CanvasObject, 0, end-start)
+	for i := start; i < end; i++ {
+		if i == 0 || !sameDay(messages[i-1].Timestamp, messages[i].Timestamp) {
+			rows = append(rows, newDateSeparator(messages[i].Timestamp))
+		}
+		rows = append(rows, cw.newMessageRow(messages[i]))
+	}
+	return rows
+}
+
+// sameDay reports whether a and b fall on the same local calendar day
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Local().Date()
+	by, bm, bd := b.Local().Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// newDateSeparator creates the row above the first message of a day, showing its date
+func newDateSeparator(t time.Time) fyne.CanvasObject {
+	t = t.Local()
+	text := t.Format("2006-01-02 Monday")
+	now := time.Now()
+	switch {
+	case sameDay(t, now):
+		text = "Today"
+	case sameDay(t, now.AddDate(0, 0, -1)):
+		text = "Yesterday"
+	case t.Year() == now.Year():
+		text = t.Format("01-02 Monday")
+	}
+
+	label := widget.NewLabel(text)
+	label.Alignment = fyne.TextAlignCenter
+	label.Importance = widget.LowImportance
+	label.TextStyle = fyne.TextStyle{Bold: true}
+	return label
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"chatgo/internal/notify"
+	"errors"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// toastDuration is how long a toast stays up unless a newer one replaces it
+const toastDuration = 4 * time.Second
+
+// startNotifications shows notifications posted by background work on the UI goroutine.
+// Dialogs are shown one at a time; the next notification waits until the dialog is closed.
+func (cw *ChatWindow) startNotifications() {
+	go func() {
+		for {
+			n, ok := cw.notifications.Next()
+			if !ok {
+				return
+			}
+
+			shown := make(chan struct{})
+			fyne.Do(func() {
+				// A toast would be hidden behind an open dialog such as Settings
+				if !n.NeedsDialog() && cw.window.Canvas().Overlays().Top() == nil {
+					cw.showToast(n)
+					close(shown)
+					return
+				}
+				cw.showNotificationDialog(n, func() { close(shown) })
+			})
+			<-shown
+		}
+	}()
+}
+
+// showNotificationDialog shows a notification that must be acknowledged and calls onClosed once it is dismissed
+func (cw *ChatWindow) showNotificationDialog(n notify.Notification, onClosed func()) {
+	var d dialog.Dialog
+	switch {
+	case n.Action != nil:
+		action := n.Action
+		confirm := dialog.NewConfirm(n.Title, n.Message, func(ok bool) {
+			if ok {
+				action.Run()
+			}
+		}, cw.window)
+		confirm.SetConfirmText(action.Label)
+		confirm.SetDismissText(Translate("common.close"))
+		d = confirm
+	case n.Level == notify.Error:
+		d = dialog.NewError(errors.New(n.Message), cw.window)
+	default:
+		d = dialog.NewInformation(n.Title, n.Message, cw.window)
+	}
+	d.SetOnClosed(onClosed)
+	d.Show()
+}
+
+// newToastLayer creates the layer laid over the window content that shows toasts in the
+// bottom right corner. Only the toast itself takes input; the rest of the layer is empty.
+func (cw *ChatWindow) newToastLayer() fyne.CanvasObject {
+	cw.toastBox = container.NewHBox()
+	return container.NewPadded(container.NewBorder(nil, container.NewHBox(layout.NewSpacer(), cw.toastBox), nil, nil))
+}
+
+// setWindowContent shows content in the window beneath the toast and tooltip layers
+func (cw *ChatWindow) setWindowContent(content fyne.CanvasObject) {
+	cw.window.SetContent(container.NewStack(content, cw.toastLayer, cw.tooltipLayer))
+}
+
+// showToast briefly shows a notification in the bottom right corner of the window,
+// replacing any toast still showing
+func (cw *ChatWindow) showToast(n notify.Notification) {
+	icon := theme.InfoIcon()
+	if n.Level == notify.Warning {
+		icon = theme.WarningIcon()
+	}
+	title := widget.NewLabel(n.Title)
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	message := widget.NewLabel(n.Message)
+
+	var toast fyne.CanvasObject
+	hide := func() {
+		if len(cw.toastBox.Objects) > 0 && cw.toastBox.Objects[0] == toast {
+			cw.toastBox.RemoveAll()
+		}
+	}
+	closeBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), hide)
+	closeBtn.Importance = widget.LowImportance
+
+	background := canvas.NewRectangle(theme.Color(theme.ColorNameOverlayBackground))
+	background.CornerRadius = theme.InputRadiusSize()
+	background.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	background.StrokeWidth = 1
+	toast = container.NewStack(background, container.NewPadded(
+		container.NewBorder(nil, nil, widget.NewIcon(icon), closeBtn, container.NewVBox(title, message)),
+	))
+
+	cw.toastBox.Objects = []fyne.CanvasObject{toast}
+	cw.toastBox.Refresh()
+
+	time.AfterFunc(toastDuration, func() {
+		fyne.Do(hide)
+	})
+}
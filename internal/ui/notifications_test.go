@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"chatgo/internal/notify"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+// uiThreadDriver runs the functions passed to fyne.Do one at a time, as a real driver does on
+// its UI goroutine; the test driver runs them on the caller's goroutine at once. Tests read
+// widgets through do as well, so under the race detector a widget touched outside fyne.Do
+// is reported.
+type uiThreadDriver struct {
+	fyne.Driver
+	mu    sync.Mutex
+	calls atomic.Int32 // Functions passed to fyne.Do
+}
+
+func (d *uiThreadDriver) DoFromGoroutine(fn func(), wait bool) {
+	d.calls.Add(1)
+	d.do(fn)
+}
+
+// do runs fn on the stand-in UI goroutine
+func (d *uiThreadDriver) do(fn func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fn()
+}
+
+// waitFor waits until cond, checked on the stand-in UI goroutine, holds
+func (d *uiThreadDriver) waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var ok bool
+		d.do(func() { ok = cond() })
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// uiThreadApp is a test app whose driver is a uiThreadDriver
+type uiThreadApp struct {
+	fyne.App
+	driver *uiThreadDriver
+}
+
+func (a *uiThreadApp) Driver() fyne.Driver {
+	return a.driver
+}
+
+// newUIThreadApp makes a test app with a uiThreadDriver the current app
+func newUIThreadApp(t testing.TB) (*uiThreadApp, *uiThreadDriver) {
+	t.Helper()
+	base := test.NewTempApp(t)
+	driver := &uiThreadDriver{Driver: base.Driver()}
+	app := &uiThreadApp{App: base, driver: driver}
+	fyne.SetCurrentApp(app)
+	return app, driver
+}
+
+// texts returns the text drawn by an object and everything laid out in it
+func texts(o fyne.CanvasObject) []string {
+	var found []string
+	for _, object := range test.LaidOutObjects(o) {
+		if text, ok := object.(*canvas.Text); ok && text.Visible() && text.Text != "" {
+			found = append(found, text.Text)
+		}
+	}
+	return found
+}
+
+// tapButton taps the visible button with the given text within o
+func tapButton(t *testing.T, o fyne.CanvasObject, text string) {
+	t.Helper()
+	for _, object := range test.LaidOutObjects(o) {
+		if button, ok := object.(*widget.Button); ok && button.Visible() && button.Text == text {
+			test.Tap(button)
+			return
+		}
+	}
+	t.Fatalf("no %q button in %q", text, texts(o))
+}
+
+func TestNotificationsShowOneDialogAtATime(t *testing.T) {
+	app, driver := newUIThreadApp(t)
+	cw := &ChatWindow{app: app, window: app.NewWindow("test"), config: &config.Config{}}
+	cw.window.Resize(fyne.NewSize(800, 600))
+	cw.notifications = notify.NewQueue()
+	cw.toastLayer = cw.newToastLayer()
+	cw.tooltipLayer = newTooltipLayer()
+	cw.setWindowContent(widget.NewLabel("content"))
+	cw.startNotifications()
+	defer cw.notifications.Close()
+
+	// Posted by background work; rendering them is up to the notifications goroutine
+	var acted atomic.Bool
+	go func() {
+		cw.notifications.Post(notify.Notification{Level: notify.Error, Title: "First", Message: "First failure"})
+		cw.notifications.Post(notify.Notification{Level: notify.Warning, Title: "Second", Message: "Needs a look",
+			Action: &notify.Action{Label: "Look", Run: func() { acted.Store(true) }}})
+		cw.notifications.Post(notify.Notification{Level: notify.Info, Title: "Third", Message: "Just so you know"})
+	}()
+
+	overlays := cw.window.Canvas().Overlays()
+	showing := func(text string) func() bool {
+		return func() bool {
+			top := overlays.Top()
+			return top != nil && slices.Contains(texts(top), text)
+		}
+	}
+
+	driver.waitFor(t, "the first dialog", showing("First failure"))
+	// The next notification waits until the first is dismissed
+	time.Sleep(50 * time.Millisecond)
+	driver.do(func() {
+		if n := len(overlays.List()); n != 1 {
+			t.Errorf("%d dialogs are open, want only the first", n)
+		}
+		if len(cw.toastBox.Objects) != 0 {
+			t.Error("a toast is shown while a dialog is open")
+		}
+		tapButton(t, overlays.Top(), "OK")
+	})
+
+	driver.waitFor(t, "the second dialog", showing("Needs a look"))
+	driver.do(func() {
+		if n := len(overlays.List()); n != 1 {
+			t.Errorf("%d dialogs are open, want only the second", n)
+		}
+		tapButton(t, overlays.Top(), "Look")
+	})
+	if !acted.Load() {
+		t.Error("the notification's action didn't run")
+	}
+
+	driver.waitFor(t, "the toast", func() bool {
+		return len(cw.toastBox.Objects) == 1 && slices.Contains(texts(cw.toastBox), "Just so you know")
+	})
+	driver.do(func() {
+		if top := overlays.Top(); top != nil {
+			t.Errorf("a dialog is still open showing %q", texts(top))
+		}
+		// Closed now, so the toast's timer finds nothing left to hide
+		for _, object := range test.LaidOutObjects(cw.toastBox) {
+			if button, ok := object.(*widget.Button); ok && button.Text == "" {
+				test.Tap(button)
+			}
+		}
+	})
+
+	if driver.calls.Load() < 3 {
+		t.Errorf("fyne.Do was called %d times, want each notification shown through it", driver.calls.Load())
+	}
+}
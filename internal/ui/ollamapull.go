@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"chatgo/internal/llm"
+	"context"
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// newModelPullOffer creates the part of an error bubble that says the Ollama model isn't pulled,
+// with a button to pull it, and offers the pull in a dialog. retry is called once the pull completes.
+func (cw *ChatWindow) newModelPullOffer(missing *llm.OllamaModelMissingError, retry func()) fyne.CanvasObject {
+	label := widget.NewLabel(Translatef("ollama.model_missing", missing.Model))
+	label.Importance = widget.WarningImportance
+	label.Wrapping = fyne.TextWrapWord
+
+	var pullBtn *widget.Button
+	pull := func() {
+		pullBtn.Disable()
+		cw.pullOllamaModel(missing, func(err error) {
+			if err != nil {
+				pullBtn.Enable()
+				if !errors.Is(err, context.Canceled) {
+					dialog.ShowError(err, cw.window)
+				}
+				return
+			}
+			pullBtn.Hide()
+			retry()
+		})
+	}
+	pullBtn = widget.NewButtonWithIcon(Translate("ollama.pull"), theme.DownloadIcon(), pull)
+
+	dialog.ShowConfirm(Translate("ollama.pull_title"), Translatef("ollama.pull_confirm", missing.Model), func(ok bool) {
+		if ok && !pullBtn.Disabled() {
+			pull()
+		}
+	}, cw.window)
+
+	return container.NewVBox(label, container.NewHBox(pullBtn))
+}
+
+// pullOllamaModel pulls the missing model in a dialog showing Ollama's progress, which the
+// user can cancel. done is called on the UI goroutine with nil once the model is ready.
+func (cw *ChatWindow) pullOllamaModel(missing *llm.OllamaModelMissingError, done func(error)) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	status := widget.NewLabel(Translate("ollama.pull_starting"))
+	status.Wrapping = fyne.TextWrapWord
+	bar := widget.NewProgressBar()
+
+	cancelBtn := widget.NewButtonWithIcon(Translate("common.cancel"), theme.CancelIcon(), cancel)
+	content := container.NewVBox(status, bar, container.NewHBox(layout.NewSpacer(), cancelBtn))
+	d := dialog.NewCustomWithoutButtons(Translatef("ollama.pulling", missing.Model), content, cw.window)
+	d.Resize(fyne.NewSize(420, d.MinSize().Height))
+	d.SetOnClosed(cancel)
+	d.Show()
+
+	opts := cw.clientOptions()
+	go func() {
+		err := llm.OllamaPullModel(ctx, missing.BaseURL, missing.Model, opts, func(p llm.OllamaPullProgress) {
+			fyne.Do(func() {
+				status.SetText(pullStatusText(p))
+				if fraction := p.Fraction(); fraction >= 0 {
+					bar.SetValue(fraction)
+				}
+			})
+		})
+		cancel()
+		fyne.Do(func() {
+			d.Hide()
+			done(err)
+		})
+	}()
+}
+
+// pullStatusText describes a pull step, with the downloaded size when it has one
+func pullStatusText(p llm.OllamaPullProgress) string {
+	if p.Total <= 0 {
+		return p.Status
+	}
+	return fmt.Sprintf("%s · %s / %s", p.Status, formatByteSize(p.Completed), formatByteSize(p.Total))
+}
+
+// formatByteSize shows a byte count in the largest unit that keeps it at least 1, e.g. "4.7 GB"
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n)
+	units := []string{"KB", "MB", "GB", "TB"}
+	i := -1
+	for value >= unit && i < len(units)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
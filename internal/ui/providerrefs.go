@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"chatgo/pkg/models"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// providerUsers counts the stored conversations, archived ones included, using the named provider
+func (cw *ChatWindow) providerUsers(name string) int {
+	conversations, err := cw.convManager.ListConversations(models.ListOptions{IncludeArchived: true})
+	if err != nil {
+		logger.Warn("Failed to list conversations", "error", err)
+		return 0
+	}
+	users := 0
+	for _, conv := range conversations {
+		if conv.Provider == name {
+			users++
+		}
+	}
+	return users
+}
+
+// reassignProvider moves the stored conversations, the open one and the current provider from
+// the provider named from to the one named to. model, when not empty, becomes their model too.
+func (cw *ChatWindow) reassignProvider(parentWindow fyne.Window, from, to, model string) {
+	if _, err := cw.convManager.ReassignProvider(from, to, model); err != nil {
+		dialog.ShowError(fmt.Errorf("failed to update conversations: %w", err), parentWindow)
+	}
+	if cw.config.CurrentProvider == from {
+		cw.config.CurrentProvider = to
+	}
+	// Set without OnChanged, which would switch the open conversation again
+	for _, s := range []*tooltipSelect{cw.providerSelect, cw.homeProviderSelect} {
+		if s != nil && s.Selected == from {
+			s.Selected = to
+		}
+	}
+	if conv := cw.currentConversation; conv != nil && conv.Provider == from {
+		conv.Provider = to
+		if model != "" {
+			conv.Model = model
+		}
+		cw.setupCurrentProvider()
+	}
+}
+
+// offerProviderRename asks whether the current provider and the conversations using a provider
+// renamed from old should follow it to its new name; left as they are, they name a provider that
+// no longer exists. The provider selectors are updated once the user answers.
+func (cw *ChatWindow) offerProviderRename(parentWindow fyne.Window, old, name string) {
+	users := cw.providerUsers(old)
+	if users == 0 && cw.config.CurrentProvider != old {
+		cw.updateProviderSelector()
+		return
+	}
+	dialog.ShowConfirm(Translate("settings.rename_provider"), Translatef("settings.rename_provider_confirm", old, name, users), func(update bool) {
+		if update {
+			cw.reassignProvider(parentWindow, old, name, "")
+			if err := config.SaveConfig(cw.config); err != nil {
+				dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+			}
+		}
+		cw.updateProviderSelector()
+	}, parentWindow)
+}
+
+// confirmProviderDelete asks before deleting the named provider and calls remove to delete it.
+// When conversations use the provider, the user picks another provider to move them, and the
+// current provider, to.
+func (cw *ChatWindow) confirmProviderDelete(parentWindow fyne.Window, name string, remove func()) {
+	var others, enabled []string
+	for _, p := range cw.config.Providers {
+		if p.Name == name {
+			continue
+		}
+		others = append(others, p.Name)
+		if p.Enabled {
+			enabled = append(enabled, p.Name)
+		}
+	}
+	users := cw.providerUsers(name)
+	if users == 0 || len(others) == 0 {
+		dialog.ShowConfirm(Translate("settings.delete_provider"), Translatef("settings.delete_provider_confirm", name), func(confirmed bool) {
+			if confirmed {
+				remove()
+				cw.updateProviderSelector()
+			}
+		}, parentWindow)
+		return
+	}
+
+	// An enabled provider is preselected, as conversations can only be continued with those
+	replacement := widget.NewSelect(others, nil)
+	if len(enabled) > 0 {
+		replacement.SetSelected(enabled[0])
+	} else {
+		replacement.SetSelected(others[0])
+	}
+	message := widget.NewLabel(Translatef("settings.delete_provider_in_use", name, users))
+	message.Wrapping = fyne.TextWrapWord
+
+	content := container.NewVBox(
+		message,
+		newFormGrid(newFormLabel(Translate("settings.delete_provider_move_to")), replacement),
+	)
+	d := dialog.NewCustomConfirm(Translate("settings.delete_provider"), Translate("common.delete"), Translate("common.cancel"), content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		remove()
+		if to, ok := cw.providerNamed(replacement.Selected); ok {
+			cw.reassignProvider(parentWindow, name, to.Name, to.Model)
+			if err := config.SaveConfig(cw.config); err != nil {
+				dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+			}
+		}
+		cw.updateProviderSelector()
+	}, parentWindow)
+	d.Resize(fyne.NewSize(460, 0))
+	d.Show()
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"chatgo/internal/llm"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// providerQuotaText describes the latest free-tier quota of the named provider, e.g.
+// "About 120 requests left today / resets at 16:00", or returns "" when it reported none
+func providerQuotaText(provider string) string {
+	quota, ok := llm.Quotas.Get(provider)
+	if !ok {
+		return ""
+	}
+	now := time.Now()
+	switch {
+	case quota.Exhausted(now):
+		return Translatef("quota.exhausted", formatQuotaTime(quota.RetryAt, now))
+	case quota.Remaining < 0:
+		return ""
+	case quota.ResetAt.IsZero():
+		return Translatef("quota.remaining", quota.Remaining)
+	default:
+		return Translatef("quota.remaining_reset", quota.Remaining, formatQuotaTime(quota.ResetAt, now))
+	}
+}
+
+// formatQuotaTime shows when a quota resets in local time, with the date unless it is today
+func formatQuotaTime(t, now time.Time) string {
+	t = t.Local()
+	if sameDay(t, now) {
+		return t.Format("15:04")
+	}
+	return t.Format("01-02 15:04")
+}
+
+// newQuotaRetry creates the part of an error bubble that says when the exhausted quota resets,
+// with an option to call retry at that time
+func newQuotaRetry(quota llm.Quota, retry func()) fyne.CanvasObject {
+	resetAt := formatQuotaTime(quota.RetryAt, time.Now())
+	label := widget.NewLabel(Translatef("quota.exhausted", resetAt))
+	label.Importance = widget.WarningImportance
+	label.Wrapping = fyne.TextWrapWord
+
+	var timer *time.Timer
+	autoRetry := widget.NewCheck(Translatef("quota.auto_retry", resetAt), func(checked bool) {
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+		if checked {
+			timer = time.AfterFunc(time.Until(quota.RetryAt), func() {
+				fyne.Do(retry)
+			})
+		}
+	})
+	return container.NewVBox(label, autoRetry)
+}
@@ -1,12 +1,22 @@
 package ui
 
 import (
+	"chatgo/internal/autotitle"
 	"chatgo/internal/config"
+	"chatgo/internal/httpclient"
 	"chatgo/internal/mcp"
+	"chatgo/internal/notify"
+	"errors"
 	"fmt"
+	"image/color"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
@@ -14,43 +24,366 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-// showSettings displays the settings dialog with Providers, MCP Servers, and Built-in Tools tabs.
+// settingsMinSize is the smallest the settings window can be made; the lists and forms of the
+// tabs side by side need about this much room
+var settingsMinSize = fyne.NewSize(640, 420)
+
+// showSettings opens the settings window with General, Appearance, Providers, MCP Servers,
+// Built-in Tools, Agent and Logs tabs, or brings it to the front when it is already open. The window can be resized
+// and reopens at the size it was closed at.
 func (cw *ChatWindow) showSettings() {
-	// Create tabs for Providers, MCP Servers, and Built-in Tools
-	providersTab := cw.createProvidersTab(cw.window)
-	mcpServersTab := cw.createMCPServersTab(cw.window)
-	builtinToolsTab := cw.createBuiltinToolsTab(cw.window)
-
-	tabs := container.NewAppTabs(
-		container.NewTabItem("Providers", providersTab),
-		container.NewTabItem("MCP Servers", mcpServersTab),
-		container.NewTabItem("Built-in Tools", builtinToolsTab),
+	if cw.settingsWindow != nil {
+		cw.settingsWindow.RequestFocus()
+		return
+	}
+	w := cw.app.NewWindow(Translate("settings.title"))
+	cw.settingsWindow = w
+	cw.fillSettings(0)
+	w.Resize(cw.settingsSize())
+
+	w.SetOnClosed(func() {
+		state := loadUIState()
+		size := w.Canvas().Size()
+		state.SettingsWidth, state.SettingsHeight = size.Width, size.Height
+		saveUIState(state)
+
+		// Update tool check group when settings close
+		cw.toolSelectionMgr.RefreshToolCheckGroup()
+		cw.mcpManager.SetStatusListener(nil)
+		cw.stopFollowingLogs()
+		cw.settingsWindow = nil
+		cw.settingsTabs = nil
+	})
+	w.Show()
+}
+
+// fillSettings creates the tabs of the settings window, showing the tab at index selected
+func (cw *ChatWindow) fillSettings(selected int) {
+	w := cw.settingsWindow
+	cw.settingsTabs = container.NewAppTabs(
+		container.NewTabItem(Translate("settings.general"), cw.createGeneralTab(w)),
+		container.NewTabItem(Translate("settings.appearance"), cw.createAppearanceTab(w)),
+		container.NewTabItem(Translate("settings.providers"), cw.createProvidersTab(w)),
+		container.NewTabItem(Translate("settings.mcp_servers"), cw.createMCPServersTab(w)),
+		container.NewTabItem(Translate("settings.builtin_tools"), cw.createBuiltinToolsTab(w)),
+		container.NewTabItem(Translate("settings.agent"), cw.createAgentTab()),
+		container.NewTabItem(Translate("settings.logs"), cw.createLogsTab(w)),
 	)
+	cw.settingsTabs.SelectIndex(selected)
+
+	// The window can't be made smaller than its content
+	minSize := canvas.NewRectangle(color.Transparent)
+	minSize.SetMinSize(settingsMinSize)
+	w.SetContent(container.NewStack(minSize, cw.settingsTabs))
+}
 
-	// Create close button for top-right corner
-	closeBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {})
+// refreshSettings recreates the tabs of the settings window, if open, after the configuration
+// was changed from outside them
+func (cw *ChatWindow) refreshSettings() {
+	if cw.settingsWindow != nil {
+		cw.fillSettings(cw.settingsTabs.SelectedIndex())
+	}
+}
+
+// settingsSize returns the size the settings window opens at: the size it was last closed at,
+// or else most of the chat window, and never less than settingsMinSize
+func (cw *ChatWindow) settingsSize() fyne.Size {
+	size, ok := loadUIState().settingsSize()
+	if !ok {
+		size = cw.window.Canvas().Size()
+		size = fyne.NewSize(size.Width*0.85, size.Height*0.85)
+	}
+	return size.Max(settingsMinSize)
+}
 
-	// Create content with close button in top-right
-	content := container.NewBorder(
-		nil, // top
-		nil, // bottom
-		nil, // left
-		closeBtn, // right
-		tabs, // center
+// settingsSection heads a group of settings with its title and a separator
+func settingsSection(title string, content ...fyne.CanvasObject) fyne.CanvasObject {
+	heading := widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	return container.NewVBox(append([]fyne.CanvasObject{heading, widget.NewSeparator()}, content...)...)
+}
+
+// settingsDetails lays out the right side of a list tab: the selected item's details, which
+// scroll when they don't fit, above the buttons that act on it
+func settingsDetails(details, buttons fyne.CanvasObject) fyne.CanvasObject {
+	return container.NewBorder(nil, container.NewPadded(buttons), nil, nil, container.NewVScroll(container.NewPadded(details)))
+}
+
+// createGeneralTab creates the General settings tab for application-wide preferences.
+// Changes are saved immediately.
+func (cw *ChatWindow) createGeneralTab(parentWindow fyne.Window) fyne.CanvasObject {
+	// The language applies to windows and dialogs opened after a restart
+	languageValues := append([]string{""}, Locales()...)
+	languageOptions := []string{Translate("settings.language_system")}
+	for _, locale := range languageValues[1:] {
+		languageOptions = append(languageOptions, LocaleName(locale))
+	}
+	languageSelect := widget.NewSelect(languageOptions, nil)
+	languageSelect.SetSelectedIndex(max(slices.Index(languageValues, cw.config.Language), 0))
+	languageHint := widget.NewLabel(Translate("settings.language_restart"))
+	languageHint.Importance = widget.LowImportance
+	languageHint.Hide()
+	languageSelect.OnChanged = func(string) {
+		cw.config.Language = languageValues[languageSelect.SelectedIndex()]
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+		}
+		languageHint.Show()
+	}
+
+	sendOnEnterCheck := widget.NewCheck(Translate("settings.send_on_enter"), func(checked bool) {
+		cw.config.SendOnEnter = checked
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+		}
+		cw.refreshEmptyState()
+	})
+	sendOnEnterCheck.Checked = cw.config.SendOnEnter
+
+	attachmentLimitEntry := widget.NewEntry()
+	if cw.config.AttachmentMaxKB > 0 {
+		attachmentLimitEntry.SetText(strconv.Itoa(cw.config.AttachmentMaxKB))
+	}
+	attachmentLimitEntry.SetPlaceHolder(fmt.Sprintf("%d", config.DefaultAttachmentMaxKB))
+	attachmentLimitSaveBtn := widget.NewButton(Translate("common.save"), func() {
+		limit := 0
+		if text := strings.TrimSpace(attachmentLimitEntry.Text); text != "" {
+			var err error
+			limit, err = strconv.Atoi(text)
+			if err != nil || limit <= 0 {
+				dialog.ShowError(fmt.Errorf("attachment size limit must be a positive number of KB"), parentWindow)
+				return
+			}
+		}
+		cw.config.AttachmentMaxKB = limit
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+		}
+	})
+
+	systemPromptEntry := widget.NewMultiLineEntry()
+	systemPromptEntry.Wrapping = fyne.TextWrapWord
+	systemPromptEntry.SetMinRowsVisible(4)
+	systemPromptEntry.SetText(cw.config.SystemPrompt)
+	systemPromptEntry.SetPlaceHolder(Translate("settings.system_prompt_placeholder"))
+	systemPromptHint := widget.NewLabel(Translate("settings.system_prompt_hint"))
+	systemPromptHint.Wrapping = fyne.TextWrapWord
+	systemPromptHint.Importance = widget.LowImportance
+	systemPromptSaveBtn := widget.NewButton(Translate("common.save"), func() {
+		cw.config.SystemPrompt = strings.TrimSpace(systemPromptEntry.Text)
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+		}
+	})
+
+	editorEntry := widget.NewEntry()
+	editorEntry.SetText(cw.config.ExternalEditor)
+	editorEntry.SetPlaceHolder(Translate("settings.editor_placeholder"))
+	editorSaveBtn := widget.NewButton(Translate("common.save"), func() {
+		cw.config.ExternalEditor = strings.TrimSpace(editorEntry.Text)
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+		}
+	})
+
+	proxyEntry := widget.NewEntry()
+	proxyEntry.SetText(cw.config.Proxy)
+	proxyEntry.SetPlaceHolder(Translate("settings.proxy_placeholder"))
+	proxySaveBtn := widget.NewButton(Translate("common.save"), func() {
+		proxy := strings.TrimSpace(proxyEntry.Text)
+		if err := httpclient.ValidateProxyURL(proxy); err != nil {
+			dialog.ShowError(err, parentWindow)
+			return
+		}
+		cw.config.Proxy = proxy
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+			return
+		}
+		cw.applyProxy()
+		cw.setupCurrentProvider()
+	})
+
+	// Context strategy for long conversations
+	strategyNames := make([]string, len(config.ContextStrategies))
+	for i, strategy := range config.ContextStrategies {
+		strategyNames[i] = contextStrategyName(strategy)
+	}
+	strategySelect := widget.NewSelect(strategyNames, nil)
+	strategySelect.SetSelected(contextStrategyName(cw.config.ContextMode()))
+	lastNEntry := widget.NewEntry()
+	if cw.config.ContextLastN > 0 {
+		lastNEntry.SetText(strconv.Itoa(cw.config.ContextLastN))
+	}
+	lastNEntry.SetPlaceHolder(strconv.Itoa(config.DefaultContextLastN))
+	strategyHint := widget.NewLabel(Translate("settings.context_strategy_hint"))
+	strategyHint.Wrapping = fyne.TextWrapWord
+	strategyHint.Importance = widget.LowImportance
+	strategySaveBtn := widget.NewButton(Translate("common.save"), func() {
+		lastN := 0
+		if text := strings.TrimSpace(lastNEntry.Text); text != "" {
+			var err error
+			lastN, err = strconv.Atoi(text)
+			if err != nil || lastN <= 0 {
+				dialog.ShowError(fmt.Errorf("messages kept must be a positive number"), parentWindow)
+				return
+			}
+		}
+
+		cw.config.ContextStrategy = config.ContextStrategies[strategySelect.SelectedIndex()]
+		cw.config.ContextLastN = lastN
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+			return
+		}
+		cw.contextStrategySelect.SetSelected(contextStrategyName(cw.config.ContextMode()))
+	})
+
+	// Background title and summary generation
+	currentProviderOption := Translate("settings.current_provider")
+	titleProviderOptions := []string{currentProviderOption}
+	titleProviderOptions = append(titleProviderOptions, cw.enabledProviderNames()...)
+	titleProviderSelect := widget.NewSelect(titleProviderOptions, nil)
+	if cw.config.TitleProvider == "" {
+		titleProviderSelect.SetSelected(currentProviderOption)
+	} else {
+		titleProviderSelect.SetSelected(cw.config.TitleProvider)
+	}
+	titleRateEntry := widget.NewEntry()
+	if cw.config.TitleRatePerMinute > 0 {
+		titleRateEntry.SetText(strconv.Itoa(cw.config.TitleRatePerMinute))
+	}
+	titleRateEntry.SetPlaceHolder(fmt.Sprintf("%d", autotitle.DefaultRatePerMinute))
+	titleSaveBtn := widget.NewButton(Translate("common.save"), func() {
+		rate := 0
+		if text := strings.TrimSpace(titleRateEntry.Text); text != "" {
+			var err error
+			rate, err = strconv.Atoi(text)
+			if err != nil || rate <= 0 {
+				dialog.ShowError(fmt.Errorf("requests per minute must be a positive number"), parentWindow)
+				return
+			}
+		}
+
+		cw.config.TitleProvider = titleProviderSelect.Selected
+		if cw.config.TitleProvider == currentProviderOption {
+			cw.config.TitleProvider = ""
+		}
+		cw.config.TitleRatePerMinute = rate
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+			return
+		}
+		cw.titleQueue.SetRate(rate)
+	})
+
+	content := container.NewVBox(
+		settingsSection(Translate("settings.language_section"), widget.NewForm(
+			widget.NewFormItem(Translate("settings.language"), container.NewVBox(languageSelect, languageHint)),
+		)),
+		settingsSection(Translate("settings.network"), widget.NewForm(
+			widget.NewFormItem(Translate("settings.proxy"), container.NewBorder(nil, nil, nil, proxySaveBtn, proxyEntry)),
+		)),
+		settingsSection(Translate("settings.input"), widget.NewForm(
+			&widget.FormItem{Widget: sendOnEnterCheck, HintText: Translate("settings.send_on_enter_hint")},
+			widget.NewFormItem(Translate("settings.attachment_limit"), container.NewBorder(nil, nil, nil, attachmentLimitSaveBtn, attachmentLimitEntry)),
+		)),
+		settingsSection(Translate("settings.system_prompt"),
+			systemPromptEntry,
+			container.NewBorder(nil, nil, nil, systemPromptSaveBtn, systemPromptHint),
+		),
+		settingsSection(Translate("settings.conversation_files"), widget.NewForm(
+			widget.NewFormItem(Translate("settings.external_editor"), container.NewBorder(nil, nil, nil, editorSaveBtn, editorEntry)),
+		)),
+		settingsSection(Translate("settings.context_strategy"),
+			widget.NewForm(
+				widget.NewFormItem(Translate("settings.context_strategy_mode"), strategySelect),
+				widget.NewFormItem(Translate("settings.context_last_n"), lastNEntry),
+			),
+			strategyHint,
+			container.NewHBox(layout.NewSpacer(), strategySaveBtn),
+		),
+		settingsSection(Translate("settings.background_titles"),
+			widget.NewForm(
+				widget.NewFormItem(Translate("settings.provider"), titleProviderSelect),
+				widget.NewFormItem(Translate("settings.requests_per_minute"), titleRateEntry),
+			),
+			container.NewHBox(layout.NewSpacer(), titleSaveBtn),
+		),
+		cw.newBackupSection(parentWindow),
 	)
+	return container.NewVScroll(container.NewPadded(content))
+}
 
-	// Show as dialog without buttons
-	d := dialog.NewCustomWithoutButtons("Settings", content, cw.window)
+// createAppearanceTab creates the Appearance settings tab for the theme, accent color and
+// message text size. Changes are saved and applied immediately.
+func (cw *ChatWindow) createAppearanceTab(parentWindow fyne.Window) fyne.CanvasObject {
+	saveAppearance := func() {
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+		}
+		cw.applyTheme()
+	}
 
-	// Hook up close button to hide dialog
-	closeBtn.OnTapped = func() {
-		// Update tool check group when settings close
-		cw.toolSelectionMgr.RefreshToolCheckGroup()
-		d.Hide()
+	themeOptions := []string{Translate("settings.theme_system"), Translate("settings.theme_light"), Translate("settings.theme_dark")}
+	themeValues := []string{"", config.ThemeLight, config.ThemeDark}
+	themeSelect := widget.NewSelect(themeOptions, nil)
+	themeSelect.SetSelectedIndex(max(slices.Index(themeValues, cw.config.Theme), 0))
+	themeSelect.OnChanged = func(string) {
+		cw.config.Theme = themeValues[themeSelect.SelectedIndex()]
+		saveAppearance()
 	}
 
-	d.Resize(fyne.NewSize(800, 500))
-	d.Show()
+	accentSwatch := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+	accentSwatch.CornerRadius = theme.InputRadiusSize()
+	accentSwatch.SetMinSize(fyne.NewSquareSize(theme.IconInlineSize() * 1.5))
+	accentLabel := widget.NewLabel("")
+	showAccent := func() {
+		if cw.config.AccentColor == "" {
+			accentLabel.SetText(Translate("settings.accent_default"))
+		} else {
+			accentLabel.SetText(cw.config.AccentColor)
+		}
+		accentSwatch.FillColor = theme.Color(theme.ColorNamePrimary)
+		accentSwatch.Refresh()
+	}
+	showAccent()
+	accentPickBtn := widget.NewButton(Translate("settings.accent_choose"), func() {
+		picker := dialog.NewColorPicker(Translate("settings.accent_title"), Translate("settings.accent_message"), func(c color.Color) {
+			cw.config.AccentColor = hexColor(c)
+			saveAppearance()
+			showAccent()
+		}, parentWindow)
+		picker.Advanced = true
+		picker.Show()
+		picker.SetColor(theme.Color(theme.ColorNamePrimary))
+	})
+	accentResetBtn := widget.NewButton(Translate("settings.reset"), func() {
+		cw.config.AccentColor = ""
+		saveAppearance()
+		showAccent()
+	})
+
+	// Message text size, applied when the slider is released as every message is laid out again
+	textScaleLabel := widget.NewLabel("")
+	showTextScale := func(scale float64) {
+		textScaleLabel.SetText(fmt.Sprintf("%.0f%%", scale*100))
+	}
+	showTextScale(cw.config.MessageScale())
+	textScaleSlider := widget.NewSlider(config.MinMessageTextScale, config.MaxMessageTextScale)
+	textScaleSlider.Step = 0.1
+	textScaleSlider.SetValue(cw.config.MessageScale())
+	textScaleSlider.OnChanged = showTextScale
+	textScaleSlider.OnChangeEnded = func(scale float64) {
+		cw.config.MessageTextScale = math.Round(scale*10) / 10
+		saveAppearance()
+	}
+
+	content := widget.NewForm(
+		widget.NewFormItem(Translate("settings.theme"), themeSelect),
+		widget.NewFormItem(Translate("settings.accent_color"), container.NewHBox(container.NewCenter(accentSwatch), accentLabel, layout.NewSpacer(), accentPickBtn, accentResetBtn)),
+		widget.NewFormItem(Translate("settings.message_text_size"), container.NewBorder(nil, nil, nil, textScaleLabel, textScaleSlider)),
+	)
+	return container.NewVScroll(container.NewPadded(content))
 }
 
 // createBuiltinToolsTab creates the Built-in Tools configuration tab.
@@ -61,7 +394,8 @@ func (cw *ChatWindow) showSettings() {
 func (cw *ChatWindow) createBuiltinToolsTab(parentWindow fyne.Window) fyne.CanvasObject {
 	var selectedTool *config.BuiltinTool
 	var selectedToolIndex int = -1
-	enabledCheck := widget.NewCheck("Enabled", nil)
+	enabledCheck := widget.NewCheck(Translate("settings.enabled"), nil)
+	approvalCheck := widget.NewCheck(Translate("settings.ask_before_run"), nil)
 	configContainer := container.NewVBox()
 	var configEntries []*widget.Entry
 	var configFields []string
@@ -75,18 +409,18 @@ func (cw *ChatWindow) createBuiltinToolsTab(parentWindow fyne.Window) fyne.Canva
 		configFields = fields
 
 		if len(fields) == 0 {
-			configContainer.Add(widget.NewLabel("No additional configuration required for this tool type."))
+			configContainer.Add(widget.NewLabel(Translate("settings.tool_no_config")))
 			configContainer.Refresh()
 			return
 		}
 
-		form := container.NewGridWithColumns(2)
+		form := newFormGrid()
 		for _, field := range fields {
 			labelText := field + ":"
 			if contains(requiredFields, field) {
 				labelText = field + " *:"
 			}
-			label := widget.NewLabel(labelText)
+			label := newFormLabel(labelText)
 			entry := widget.NewEntry()
 			if selectedTool != nil && selectedTool.Config != nil {
 				if val, ok := selectedTool.Config[field]; ok {
@@ -111,25 +445,44 @@ func (cw *ChatWindow) createBuiltinToolsTab(parentWindow fyne.Window) fyne.Canva
 			label := cont.Objects[1].(*widget.Label)
 			if id < len(cw.config.BuiltinTools) {
 				tool := cw.config.BuiltinTools[id]
-				status := "disabled"
+				status := Translate("settings.status_disabled")
 				if tool.Enabled {
-					status = "enabled"
+					status = Translate("settings.status_enabled")
 				}
-				label.SetText(fmt.Sprintf("%s - %s", tool.Type, status))
+				name, _ := toolDisplay(cw.config, "builtin:"+tool.Name, tool.Type, "")
+				label.SetText(fmt.Sprintf("%s - %s", name, status))
 			}
 		},
 	)
 
-	toolTypeLabel := widget.NewLabel("Tool Type:")
-	descLabel := widget.NewLabel("(Select a tool from the list)")
+	toolTypeLabel := widget.NewLabel(Translate("settings.tool_type"))
+	descLabel := widget.NewLabel(Translate("settings.select_tool"))
+	descLabel.Wrapping = fyne.TextWrapWord
+	showDescription := func(tool *config.BuiltinTool) {
+		_, description := toolDisplay(cw.config, "builtin:"+tool.Name, tool.Name, builtinToolDescription(tool.Type))
+		descLabel.SetText(description)
+	}
+	overrideBtn := widget.NewButtonWithIcon(Translate("tools.override"), theme.DocumentCreateIcon(), func() {
+		if selectedTool == nil {
+			return
+		}
+		tool := selectedTool
+		cw.showToolOverrideDialog(parentWindow, "builtin:"+tool.Name, tool.Name, builtinToolDescription(tool.Type), func() {
+			showDescription(tool)
+			toolList.Refresh()
+		})
+	})
+	overrideBtn.Disable()
 
 	toolList.OnSelected = func(id widget.ListItemID) {
 		if id >= 0 && id < len(cw.config.BuiltinTools) {
 			selectedTool = &cw.config.BuiltinTools[id]
 			selectedToolIndex = id
 			enabledCheck.SetChecked(selectedTool.Enabled)
-			toolTypeLabel.SetText(fmt.Sprintf("Tool Type: %s", selectedTool.Type))
-			descLabel.SetText(config.GetBuiltinToolDescription(selectedTool.Type))
+			approvalCheck.SetChecked(selectedTool.NeedsApproval())
+			toolTypeLabel.SetText(Translatef("settings.tool_type_value", selectedTool.Type))
+			showDescription(selectedTool)
+			overrideBtn.Enable()
 			recreateConfigFields(selectedTool.Type)
 		}
 	}
@@ -139,29 +492,32 @@ func (cw *ChatWindow) createBuiltinToolsTab(parentWindow fyne.Window) fyne.Canva
 			selectedTool = nil
 			selectedToolIndex = -1
 			enabledCheck.SetChecked(false)
-			toolTypeLabel.SetText("Tool Type:")
-			descLabel.SetText("(Select a tool from the list)")
+			approvalCheck.SetChecked(false)
+			toolTypeLabel.SetText(Translate("settings.tool_type"))
+			descLabel.SetText(Translate("settings.select_tool"))
+			overrideBtn.Disable()
 			configContainer.Objects = nil
 			configContainer.Refresh()
 		}
 	}
 
 	form := container.NewVBox(
-		widget.NewLabel("Built-in Tool Configuration"),
-		widget.NewSeparator(),
-		toolTypeLabel,
-		descLabel,
-		widget.NewSeparator(),
-		container.NewGridWithColumns(2, widget.NewLabel(""), enabledCheck, widget.NewLabel(""), widget.NewLabel("")),
-		widget.NewSeparator(),
-		widget.NewLabel("Tool Configuration:"),
-		widget.NewLabel("* = Required field"),
-		configContainer,
+		settingsSection(Translate("settings.builtin_tool_config"),
+			toolTypeLabel,
+			descLabel,
+			container.NewHBox(overrideBtn),
+			enabledCheck,
+			approvalCheck,
+		),
+		settingsSection(Translate("settings.tool_config"),
+			widget.NewLabel(Translate("settings.required_field")),
+			configContainer,
+		),
 	)
 
-	saveBtn := widget.NewButton("Save Configuration", func() {
+	saveBtn := widget.NewButton(Translate("settings.save_config"), func() {
 		if selectedTool == nil {
-			dialog.ShowError(fmt.Errorf("Please select a tool to save"), parentWindow)
+			dialog.ShowError(errors.New(Translate("settings.select_tool_to_save")), parentWindow)
 			return
 		}
 		configMap := make(map[string]string)
@@ -170,8 +526,10 @@ func (cw *ChatWindow) createBuiltinToolsTab(parentWindow fyne.Window) fyne.Canva
 				configMap[configFields[i]] = entry.Text
 			}
 		}
+		requireApproval := approvalCheck.Checked
 		selectedTool.Enabled = enabledCheck.Checked
 		selectedTool.Config = configMap
+		selectedTool.RequireApproval = &requireApproval
 		if selectedTool.Enabled {
 			if err := config.ValidateBuiltinToolConfig(*selectedTool); err != nil {
 				dialog.ShowError(fmt.Errorf("validation failed: %w", err), parentWindow)
@@ -180,15 +538,93 @@ func (cw *ChatWindow) createBuiltinToolsTab(parentWindow fyne.Window) fyne.Canva
 		}
 		config.SaveConfig(cw.config)
 		toolList.Refresh()
-		dialog.ShowInformation("Success", fmt.Sprintf("Configuration for '%s' has been saved.", selectedTool.Type), parentWindow)
+		dialog.ShowInformation(Translate("common.success"), Translatef("settings.tool_config_saved", selectedTool.Type), parentWindow)
 	})
 
-	rightPanel := container.NewBorder(nil, container.NewHBox(saveBtn), nil, nil, form)
-	split := container.NewHSplit(toolList, rightPanel)
+	split := container.NewHSplit(toolList, settingsDetails(form, container.NewHBox(saveBtn)))
 	split.SetOffset(0.4)
 	return split
 }
 
+// createAgentTab creates the Agent settings tab: React Agent mode and the most steps the agent
+// may take per request. Changes are saved and applied to the current conversation right away.
+func (cw *ChatWindow) createAgentTab() fyne.CanvasObject {
+	steps := cw.config.ReactAgentSteps()
+
+	stepSlider := widget.NewSlider(config.MinReactAgentMaxStep, config.MaxReactAgentMaxStep)
+	stepSlider.Step = 1
+	stepSlider.SetValue(float64(steps))
+	stepEntry := widget.NewEntry()
+	stepEntry.SetText(fmt.Sprintf("%d", steps))
+
+	apply := func() {
+		config.SaveConfig(cw.config)
+		cw.setupCurrentProvider()
+	}
+
+	// setSteps clamps and stores the step count, keeping the slider and entry in sync
+	setSteps := func(value int) {
+		value = min(max(value, config.MinReactAgentMaxStep), config.MaxReactAgentMaxStep)
+		stepSlider.SetValue(float64(value))
+		stepEntry.SetText(fmt.Sprintf("%d", value))
+		if value != cw.config.ReactAgentMaxStep {
+			cw.config.ReactAgentMaxStep = value
+			apply()
+		}
+	}
+
+	// The entry follows the slider while dragging; the value is saved when the drag ends
+	stepSlider.OnChanged = func(value float64) {
+		stepEntry.SetText(fmt.Sprintf("%d", int(value)))
+	}
+	stepSlider.OnChangeEnded = func(value float64) {
+		setSteps(int(value))
+	}
+	stepEntry.OnSubmitted = func(text string) {
+		value, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil {
+			value = cw.config.ReactAgentSteps()
+		}
+		setSteps(value)
+	}
+
+	agentCheck := widget.NewCheck(Translate("settings.agent_enable"), func(checked bool) {
+		cw.config.UseReactAgent = checked
+		if checked {
+			stepSlider.Enable()
+			stepEntry.Enable()
+		} else {
+			stepSlider.Disable()
+			stepEntry.Disable()
+		}
+		apply()
+	})
+	// Set before OnChanged would save and rebuild the client
+	agentCheck.Checked = cw.config.UseReactAgent
+	if !agentCheck.Checked {
+		stepSlider.Disable()
+		stepEntry.Disable()
+	}
+
+	stepHint := widget.NewLabel(Translatef("settings.agent_steps_hint",
+		config.MinReactAgentMaxStep, config.MaxReactAgentMaxStep))
+	stepHint.Wrapping = fyne.TextWrapWord
+	stepHint.Importance = widget.LowImportance
+
+	// Keep the entry narrow so the slider takes the remaining width
+	stepEntryBox := container.NewGridWrap(fyne.NewSize(70, stepEntry.MinSize().Height), stepEntry)
+
+	content := container.NewVBox(
+		agentCheck,
+		widget.NewSeparator(),
+		widget.NewForm(
+			widget.NewFormItem(Translate("settings.agent_max_steps"), container.NewBorder(nil, nil, nil, stepEntryBox, stepSlider)),
+		),
+		stepHint,
+	)
+	return container.NewVScroll(container.NewPadded(content))
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -202,14 +638,22 @@ func (cw *ChatWindow) createProvidersTab(parentWindow fyne.Window) fyne.CanvasOb
 	var selectedProvider *config.Provider
 	var selectedProviderIndex int = -1
 
-	// Create form entries
-	nameEntry := widget.NewEntry()
-	typeEntry := widget.NewSelect([]string{"openai", "anthropic", "claude", "ollama", "custom", "qwen", "deepseek", "gemini"}, nil)
-	apiKeyEntry := widget.NewEntry()
-	apiKeyEntry.Password = true
-	baseURLEntry := widget.NewEntry()
-	modelEntry := widget.NewEntry()
-	enabledCheck := widget.NewCheck("Enabled", nil)
+	// Shared provider form
+	providerForm := NewProviderForm(parentWindow, cw.clientOptions)
+
+	// Free-tier quota the selected provider last reported, for providers that report one
+	quotaLabel := widget.NewLabel("")
+	quotaLabel.Importance = widget.LowImportance
+	quotaLabel.Hide()
+	showQuota := func() {
+		text := ""
+		if selectedProvider != nil {
+			text = providerQuotaText(selectedProvider.Name)
+		}
+		quotaLabel.SetText(text)
+		quotaLabel.Hidden = text == ""
+		quotaLabel.Refresh()
+	}
 
 	// Provider list
 	providerList := widget.NewList(
@@ -225,9 +669,12 @@ func (cw *ChatWindow) createProvidersTab(parentWindow fyne.Window) fyne.CanvasOb
 			label := container.Objects[1].(*widget.Label)
 			if id < len(cw.config.Providers) {
 				provider := cw.config.Providers[id]
-				status := "enabled"
+				status := Translate("settings.status_enabled")
+				// Disabled providers are greyed out; they are not offered for conversations
+				label.Importance = widget.MediumImportance
 				if !provider.Enabled {
-					status = "disabled"
+					status = Translate("settings.status_disabled")
+					label.Importance = widget.LowImportance
 				}
 				label.SetText(fmt.Sprintf("%s (%s) - %s", provider.Name, provider.Type, status))
 			}
@@ -240,13 +687,9 @@ func (cw *ChatWindow) createProvidersTab(parentWindow fyne.Window) fyne.CanvasOb
 			selectedProviderIndex = id
 
 			// Populate form
-			nameEntry.SetText(selectedProvider.Name)
-			typeEntry.SetSelected(selectedProvider.Type)
-			apiKeyEntry.SetText(selectedProvider.APIKey)
-			baseURLEntry.SetText(selectedProvider.BaseURL)
-			modelEntry.SetText(selectedProvider.Model)
-			enabledCheck.SetChecked(selectedProvider.Enabled)
+			providerForm.Bind(selectedProvider, false)
 		}
+		showQuota()
 	}
 
 	providerList.OnUnselected = func(id widget.ListItemID) {
@@ -255,64 +698,41 @@ func (cw *ChatWindow) createProvidersTab(parentWindow fyne.Window) fyne.CanvasOb
 			selectedProviderIndex = -1
 
 			// Clear form
-			nameEntry.SetText("")
-			typeEntry.SetSelected("")
-			apiKeyEntry.SetText("")
-			baseURLEntry.SetText("")
-			modelEntry.SetText("")
-			enabledCheck.SetChecked(false)
+			providerForm.Bind(nil, false)
 		}
+		showQuota()
 	}
 
 	// Form
-	form := container.NewVBox(
-		widget.NewLabel("Provider Details"),
-		widget.NewSeparator(),
-		container.NewGridWithColumns(2,
-			widget.NewLabel("Name:"), nameEntry,
-			widget.NewLabel("Type:"), typeEntry,
-			widget.NewLabel("API Key:"), apiKeyEntry,
-			widget.NewLabel("Base URL:"), baseURLEntry,
-			widget.NewLabel("Model:"), modelEntry,
-			widget.NewLabel(""), enabledCheck,
-		),
+	form := settingsSection(Translate("settings.provider_details"),
+		providerForm.Content,
+		quotaLabel,
 	)
 
 	// Buttons
-	addBtn := widget.NewButton("Add New", func() {
+	addBtn := widget.NewButton(Translate("settings.add_new"), func() {
 		// Clear form and deselect
 		selectedProvider = nil
 		selectedProviderIndex = -1
 		providerList.UnselectAll()
-		nameEntry.SetText("")
-		typeEntry.SetSelected("")
-		apiKeyEntry.SetText("")
-		baseURLEntry.SetText("")
-		modelEntry.SetText("")
-		enabledCheck.SetChecked(true)
+		providerForm.Bind(nil, true)
 	})
 
-	saveBtn := widget.NewButton("Save", func() {
-		if nameEntry.Text == "" {
-			dialog.ShowError(fmt.Errorf("Provider name cannot be empty"), parentWindow)
+	saveBtn := widget.NewButton(Translate("common.save"), func() {
+		newProvider, err := providerForm.Read()
+		if err != nil {
+			dialog.ShowError(err, parentWindow)
 			return
 		}
-		if typeEntry.Selected == "" {
-			dialog.ShowError(fmt.Errorf("Provider type must be selected"), parentWindow)
+		if err := cw.config.CheckProviderName(newProvider.Name, selectedProviderIndex); err != nil {
+			dialog.ShowError(err, parentWindow)
 			return
 		}
 
-		newProvider := config.Provider{
-			Name:    nameEntry.Text,
-			Type:    typeEntry.Selected,
-			APIKey:  apiKeyEntry.Text,
-			BaseURL: baseURLEntry.Text,
-			Model:   modelEntry.Text,
-			Enabled: enabledCheck.Checked,
-		}
-
+		oldName := ""
 		if selectedProvider != nil {
 			// Update existing provider
+			oldName = selectedProvider.Name
 			*selectedProvider = newProvider
 		} else {
 			// Add new provider
@@ -320,64 +740,51 @@ func (cw *ChatWindow) createProvidersTab(parentWindow fyne.Window) fyne.CanvasOb
 			selectedProviderIndex = len(cw.config.Providers) - 1
 			selectedProvider = &cw.config.Providers[selectedProviderIndex]
 		}
+		renamed := oldName != "" && oldName != newProvider.Name
+		if renamed {
+			cw.config.ProviderRenamed(oldName, newProvider.Name)
+		}
 
 		config.SaveConfig(cw.config)
 		providerList.Refresh()
-		cw.updateProviderSelector()
+		if renamed {
+			cw.offerProviderRename(parentWindow, oldName, newProvider.Name)
+		} else {
+			cw.updateProviderSelector()
+		}
 
 		// Select the updated/new provider
 		providerList.Select(selectedProviderIndex)
 	})
 
-	deleteBtn := widget.NewButton("Delete", func() {
+	deleteBtn := widget.NewButton(Translate("common.delete"), func() {
 		if selectedProvider == nil {
-			dialog.ShowError(fmt.Errorf("Please select a provider to delete"), parentWindow)
+			dialog.ShowError(errors.New(Translate("settings.select_provider_to_delete")), parentWindow)
 			return
 		}
 
-		dialog.ShowConfirm(
-			"Delete Provider",
-			fmt.Sprintf("Are you sure you want to delete provider '%s'?", selectedProvider.Name),
-			func(confirmed bool) {
-				if confirmed {
-					// Remove provider
-					cw.config.Providers = append(cw.config.Providers[:selectedProviderIndex], cw.config.Providers[selectedProviderIndex+1:]...)
-					config.SaveConfig(cw.config)
+		// Conversations still using the provider are moved to another one
+		cw.confirmProviderDelete(parentWindow, selectedProvider.Name, func() {
+			// Remove provider
+			cw.config.Providers = append(cw.config.Providers[:selectedProviderIndex], cw.config.Providers[selectedProviderIndex+1:]...)
+			config.SaveConfig(cw.config)
 
-					// Reset selection and clear form
-					selectedProvider = nil
-					selectedProviderIndex = -1
-					nameEntry.SetText("")
-					typeEntry.SetSelected("")
-					apiKeyEntry.SetText("")
-					baseURLEntry.SetText("")
-					modelEntry.SetText("")
-					enabledCheck.SetChecked(false)
-
-					// Update UI
-					providerList.Refresh()
-					cw.updateProviderSelector()
-				}
-			},
-			parentWindow,
-		)
+			// Reset selection and clear form
+			selectedProvider = nil
+			selectedProviderIndex = -1
+			providerForm.Bind(nil, false)
+
+			// Update UI
+			providerList.Refresh()
+		})
 	})
 
 	buttonContainer := container.NewHBox(addBtn, saveBtn, deleteBtn)
 
-	// Right side container with form and buttons
-	rightPanel := container.NewBorder(
-		nil,
-		buttonContainer,
-		nil,
-		nil,
-		form,
-	)
-
-	// Split left and right
+	// Split the list and the selected provider's details
 	split := container.NewHSplit(
 		providerList,
-		rightPanel,
+		settingsDetails(form, buttonContainer),
 	)
 	split.SetOffset(0.4)
 
@@ -386,118 +793,80 @@ func (cw *ChatWindow) createProvidersTab(parentWindow fyne.Window) fyne.CanvasOb
 
 // showProviderDialog displays a dialog for adding or editing a provider.
 func (cw *ChatWindow) showProviderDialog(settingsWin fyne.Window, provider *config.Provider, providerList *widget.List) {
-	title := "Add Provider"
+	title := Translate("settings.add_provider")
 	if provider != nil {
-		title = "Edit Provider"
+		title = Translate("settings.edit_provider")
 	}
 
-	nameEntry := widget.NewEntry()
-	typeEntry := widget.NewSelect([]string{"openai", "anthropic", "claude", "ollama", "custom", "qwen", "deepseek", "gemini"}, nil)
-	apiKeyEntry := widget.NewEntry()
-	apiKeyEntry.Password = true
-	baseURLEntry := widget.NewEntry()
-	modelEntry := widget.NewEntry()
-	enabledCheck := widget.NewCheck("Enabled", nil)
+	providerForm := NewProviderForm(settingsWin, cw.clientOptions)
+	providerForm.Bind(provider, true)
 
-	if provider != nil {
-		nameEntry.SetText(provider.Name)
-		typeEntry.SetSelected(provider.Type)
-		apiKeyEntry.SetText(provider.APIKey)
-		baseURLEntry.SetText(provider.BaseURL)
-		modelEntry.SetText(provider.Model)
-		enabledCheck.SetChecked(provider.Enabled)
-	} else {
-		enabledCheck.SetChecked(true)
-	}
-
-	form := container.NewGridWithColumns(2,
-		widget.NewLabel("Name:"), nameEntry,
-		widget.NewLabel("Type:"), typeEntry,
-		widget.NewLabel("API Key:"), apiKeyEntry,
-		widget.NewLabel("Base URL:"), baseURLEntry,
-		widget.NewLabel("Model:"), modelEntry,
-		widget.NewLabel(""), enabledCheck,
-	)
+	d := dialog.NewCustomWithoutButtons(title, providerForm.Content, settingsWin)
 
-	saveBtn := widget.NewButton("Save", func() {
-		if nameEntry.Text == "" {
-			dialog.ShowError(fmt.Errorf("Provider name cannot be empty"), settingsWin)
+	saveBtn := widget.NewButton(Translate("common.save"), func() {
+		newProvider, err := providerForm.Read()
+		if err != nil {
+			dialog.ShowError(err, settingsWin)
 			return
 		}
-		if typeEntry.Selected == "" {
-			dialog.ShowError(fmt.Errorf("Provider type must be selected"), settingsWin)
-			return
+		self := -1
+		if provider != nil {
+			self = slices.IndexFunc(cw.config.Providers, func(p config.Provider) bool { return p.Name == provider.Name })
 		}
-
-		newProvider := config.Provider{
-			Name:    nameEntry.Text,
-			Type:    typeEntry.Selected,
-			APIKey:  apiKeyEntry.Text,
-			BaseURL: baseURLEntry.Text,
-			Model:   modelEntry.Text,
-			Enabled: enabledCheck.Checked,
+		if err := cw.config.CheckProviderName(newProvider.Name, self); err != nil {
+			dialog.ShowError(err, settingsWin)
+			return
 		}
 
+		oldName := ""
 		if provider != nil {
 			// Update existing provider
+			oldName = provider.Name
 			*provider = newProvider
 		} else {
 			// Add new provider
 			cw.config.Providers = append(cw.config.Providers, newProvider)
 		}
+		renamed := oldName != "" && oldName != newProvider.Name
+		if renamed {
+			cw.config.ProviderRenamed(oldName, newProvider.Name)
+		}
 
 		config.SaveConfig(cw.config)
 		providerList.Refresh()
-		cw.updateProviderSelector()
-	})
-
-	content := container.NewVBox(
-		form,
-		container.NewHBox(layout.NewSpacer(), saveBtn),
-	)
-
-	d := dialog.NewCustomConfirm(title, "Save", "Cancel", content, func(response bool) {
-		if response {
-			// Save is handled in saveBtn
-		}
-	}, settingsWin)
-
-	// Hook up save button to close dialog
-	saveBtn.OnTapped = func() {
-		if nameEntry.Text != "" && typeEntry.Selected != "" {
-			newProvider := config.Provider{
-				Name:    nameEntry.Text,
-				Type:    typeEntry.Selected,
-				APIKey:  apiKeyEntry.Text,
-				BaseURL: baseURLEntry.Text,
-				Model:   modelEntry.Text,
-				Enabled: enabledCheck.Checked,
-			}
-
-			if provider != nil {
-				*provider = newProvider
-			} else {
-				cw.config.Providers = append(cw.config.Providers, newProvider)
-			}
-
-			config.SaveConfig(cw.config)
-			providerList.Refresh()
+		if renamed {
+			cw.offerProviderRename(settingsWin, oldName, newProvider.Name)
+		} else {
 			cw.updateProviderSelector()
-			d.Hide()
 		}
-	}
+		d.Hide()
+	})
+	cancelBtn := widget.NewButton(Translate("common.cancel"), func() {
+		d.Hide()
+	})
 
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, saveBtn})
 	d.Show()
 }
 
-// updateProviderSelector updates the provider selector dropdown with current providers.
+// updateProviderSelector updates the provider selector dropdowns of the chat interface and the
+// home page with the enabled providers. When the selected provider was disabled or deleted, the
+// first enabled one is selected.
 func (cw *ChatWindow) updateProviderSelector() {
-	providerNames := make([]string, len(cw.config.Providers))
-	for i, p := range cw.config.Providers {
-		providerNames[i] = p.Name
+	// The chat interface is only built once the first conversation is opened
+	if cw.providerSelect != nil {
+		cw.providerSelect.Options = cw.enabledProviderNames()
+		if !slices.Contains(cw.providerSelect.Options, cw.providerSelect.Selected) {
+			cw.selectProvider(cw.providerSelect.Selected)
+		}
+		cw.providerSelect.Refresh()
 	}
-	cw.providerSelect.Options = providerNames
-	cw.providerSelect.Refresh()
+
+	cw.homeProviderSelect.Options = cw.enabledProviderNames()
+	if !slices.Contains(cw.homeProviderSelect.Options, cw.homeProviderSelect.Selected) {
+		cw.selectHomeProvider(cw.config.CurrentProvider)
+	}
+	cw.homeProviderSelect.Refresh()
 }
 
 // createMCPServersTab creates the MCP Servers configuration tab.
@@ -508,60 +877,129 @@ func (cw *ChatWindow) createMCPServersTab(parentWindow fyne.Window) fyne.CanvasO
 	var selectedServer *config.MCPServer
 	var selectedServerIndex int = -1
 	var currentTools []mcp.MCPTool
-	enabledCheck := widget.NewCheck("Enabled", nil)
+
+	// Shared MCP server form
+	serverForm := NewMCPServerForm()
 
 	// Status and tools display
-	statusLabel := widget.NewLabel("状态: 未选择")
+	statusLabel := widget.NewLabel(Translate("mcp.status_none_selected"))
 	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
-	toolsLabel := widget.NewLabel("工具列表: 未选择")
+	toolsLabel := widget.NewLabel(Translate("mcp.tools_none_selected"))
 
-	// Tools list
-	toolsList := widget.NewList(
+	// Tools list, each tool with a button editing the name and description it's shown with
+	var toolsList *widget.List
+	toolsList = widget.NewList(
 		func() int { return len(currentTools) },
 		func() fyne.CanvasObject {
 			nameLabel := widget.NewLabel("")
 			nameLabel.TextStyle = fyne.TextStyle{Bold: true}
+			overrideBtn := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), nil)
+			overrideBtn.Importance = widget.LowImportance
 			descLabel := widget.NewLabel("")
 			descLabel.Wrapping = fyne.TextWrapWord
 			return container.NewVBox(
-				nameLabel,
+				container.NewBorder(nil, nil, nil, overrideBtn, nameLabel),
 				descLabel,
 				widget.NewSeparator(),
 			)
 		},
 		func(id widget.ListItemID, obj fyne.CanvasObject) {
 			cont := obj.(*fyne.Container)
-			if id < len(currentTools) {
+			if id < len(currentTools) && selectedServer != nil {
 				tool := currentTools[id]
-				nameLabel := cont.Objects[0].(*widget.Label)
+				header := cont.Objects[0].(*fyne.Container)
+				nameLabel := header.Objects[0].(*widget.Label)
+				overrideBtn := header.Objects[1].(*widget.Button)
 				descLabel := cont.Objects[1].(*widget.Label)
-				nameLabel.SetText(fmt.Sprintf("• %s", tool.Name))
-				descLabel.SetText(tool.Description)
+				toolID := fmt.Sprintf("mcp:%s:%s", selectedServer.Name, tool.Name)
+				name, description := toolDisplay(cw.config, toolID, tool.Name, tool.Description)
+				nameLabel.SetText(fmt.Sprintf("• %s", name))
+				descLabel.SetText(description)
+				overrideBtn.OnTapped = func() {
+					cw.showToolOverrideDialog(parentWindow, toolID, tool.Name, tool.Description, toolsList.Refresh)
+				}
 			}
 		},
 	)
 
+	// MCP server list, created below; the path warning refreshes it after a fix
+	var mcpList *widget.List
+
+	// Warning for path arguments that don't exist, with an action to choose the directory again
+	pathWarningLabel := widget.NewLabel("")
+	pathWarningLabel.Importance = widget.WarningImportance
+	pathWarningLabel.Wrapping = fyne.TextWrapWord
+	var refreshPathWarning func()
+	fixPathBtn := widget.NewButton(Translate("mcp.fix_path"), func() {
+		if selectedServer == nil {
+			return
+		}
+		missing := selectedServer.MissingPathArgs()
+		if len(missing) == 0 {
+			return
+		}
+		server, index := selectedServer, missing[0]
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(err, parentWindow)
+				return
+			}
+			if dir == nil || selectedServer != server {
+				return
+			}
+			server.Args[index] = filepath.FromSlash(dir.Path())
+			if err := config.SaveConfig(cw.config); err != nil {
+				dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parentWindow)
+			}
+			serverForm.Bind(server, false)
+			mcpList.Refresh()
+			refreshPathWarning()
+		}, parentWindow)
+	})
+	pathWarning := container.NewBorder(nil, nil, widget.NewIcon(theme.WarningIcon()), fixPathBtn, pathWarningLabel)
+	pathWarning.Hide()
+
+	refreshPathWarning = func() {
+		if selectedServer == nil {
+			pathWarning.Hide()
+			return
+		}
+		missing := selectedServer.MissingPathArgs()
+		if len(missing) == 0 {
+			pathWarning.Hide()
+			return
+		}
+		arg := selectedServer.Args[missing[0]]
+		if strings.TrimSpace(arg) == "" {
+			pathWarningLabel.SetText(Translatef("mcp.path_arg_empty", missing[0]+1))
+		} else {
+			pathWarningLabel.SetText(Translatef("mcp.path_arg_missing", arg))
+		}
+		pathWarning.Show()
+	}
+
 	// Refresh status and tools for selected server
 	refreshServerStatus := func(serverName string) {
+		refreshPathWarning()
 		if serverName == "" {
-			statusLabel.SetText("状态: 未选择")
-			toolsLabel.SetText("工具列表: 未选择")
+			statusLabel.SetText(Translate("mcp.status_none_selected"))
+			toolsLabel.SetText(Translate("mcp.tools_none_selected"))
 			currentTools = nil
 			toolsList.Refresh()
 			return
 		}
 
-		status, ok := cw.mcpManager.manager.GetServerStatus(serverName)
+		status, ok := cw.mcpManager.GetServerStatus(serverName)
 		if !ok {
-			statusLabel.SetText("状态: 未初始化")
-			toolsLabel.SetText("工具列表: 未初始化")
+			statusLabel.SetText(Translate("mcp.status_not_initialized"))
+			toolsLabel.SetText(Translate("mcp.tools_not_initialized"))
 			currentTools = nil
 			toolsList.Refresh()
 			return
 		}
 
 		// Update status
-		statusText := fmt.Sprintf("状态: %s", status.Status)
+		statusText := Translatef("mcp.status", status.Status)
 		if status.Error != nil {
 			statusText += fmt.Sprintf(" - %s", status.Error.Error())
 		}
@@ -569,145 +1007,122 @@ func (cw *ChatWindow) createMCPServersTab(parentWindow fyne.Window) fyne.CanvasO
 
 		// Update tools
 		if status.Status == "initialized" && len(status.Tools) > 0 {
-			toolsLabel.SetText(fmt.Sprintf("工具列表 (%d 个工具):", len(status.Tools)))
+			toolsLabel.SetText(Translatef("mcp.tools_count", len(status.Tools)))
 			currentTools = status.Tools
 		} else {
-			toolsLabel.SetText("工具列表: 无可用工具")
+			toolsLabel.SetText(Translate("mcp.tools_none"))
 			currentTools = nil
 		}
 		toolsList.Refresh()
 	}
 
 	// Initialize server button
-	initBtn := widget.NewButton("初始化", func() {
+	initBtn := widget.NewButton(Translate("mcp.initialize"), func() {
 		if selectedServer == nil {
-			dialog.ShowError(fmt.Errorf("请先选择一个服务器"), parentWindow)
+			dialog.ShowError(errors.New(Translate("mcp.select_server_first")), parentWindow)
 			return
 		}
 
 		// Show loading dialog
-		progress := dialog.NewProgress("正在初始化", fmt.Sprintf("正在初始化 MCP 服务器 '%s'...", selectedServer.Name), parentWindow)
+		progress := dialog.NewProgress(Translate("mcp.initializing"), Translatef("mcp.initializing_server", selectedServer.Name), parentWindow)
 		progress.Resize(fyne.NewSize(300, 100))
 		progress.Show()
 
 		// Initialize in goroutine to avoid blocking UI
+		server := *selectedServer
 		go func() {
-			status, err := cw.mcpManager.manager.InitializeServer(*selectedServer)
-			progress.Hide()
+			status, err := cw.mcpManager.InitializeServer(server)
 
+			// The result is reported through the notification queue, which shows it on the UI goroutine
 			if err != nil {
-				dialog.ShowError(fmt.Errorf("初始化失败: %w", err), parentWindow)
+				cw.notifications.Post(notify.Notification{
+					Level:   notify.Error,
+					Title:   Translate("mcp.init_failed_title"),
+					Message: Translatef("mcp.init_failed_message", err),
+				})
 			} else {
-				dialog.ShowInformation("成功", fmt.Sprintf("服务器 '%s' 初始化成功，获取到 %d 个工具", selectedServer.Name, len(status.Tools)), parentWindow)
+				cw.notifications.Post(notify.Notification{
+					Level:   notify.Info,
+					Title:   Translate("common.success"),
+					Message: Translatef("mcp.init_succeeded", server.Name, len(status.Tools)),
+				})
 			}
 
-			// Refresh status display
-			refreshServerStatus(selectedServer.Name)
+			// Widgets may only be touched from the UI goroutine
+			fyne.Do(func() {
+				progress.Hide()
+				refreshServerStatus(server.Name)
+			})
 		}()
 	})
 
 	// Disconnect server button
-	disconnectBtn := widget.NewButton("断开连接", func() {
+	disconnectBtn := widget.NewButton(Translate("mcp.disconnect"), func() {
 		if selectedServer == nil {
-			dialog.ShowError(fmt.Errorf("请先选择一个服务器"), parentWindow)
+			dialog.ShowError(errors.New(Translate("mcp.select_server_first")), parentWindow)
 			return
 		}
 
-		err := cw.mcpManager.manager.DisconnectServer(selectedServer.Name)
+		err := cw.mcpManager.DisconnectServer(selectedServer.Name)
 		if err != nil {
-			dialog.ShowError(fmt.Errorf("断开连接失败: %w", err), parentWindow)
+			dialog.ShowError(fmt.Errorf("%s: %w", Translate("mcp.disconnect_failed"), err), parentWindow)
 		} else {
-			dialog.ShowInformation("成功", fmt.Sprintf("服务器 '%s' 已断开连接", selectedServer.Name), parentWindow)
+			dialog.ShowInformation(Translate("common.success"), Translatef("mcp.disconnected", selectedServer.Name), parentWindow)
 		}
 
 		// Refresh status display
 		refreshServerStatus(selectedServer.Name)
 	})
 
-	// Create form entries
-	nameEntry := widget.NewEntry()
-	typeSelect := widget.NewSelect([]string{"stdio", "sse", "streamable_http"}, nil)
-
-	// StdIO fields
-	commandEntry := widget.NewEntry()
-	argsEntry := widget.NewMultiLineEntry()
-	argsEntry.SetPlaceHolder("Enter arguments separated by new lines\ne.g.:\n-y\n@modelcontextprotocol/server-filesystem\n/path/to/files")
-	envEntry := widget.NewMultiLineEntry()
-	envEntry.SetPlaceHolder("Enter environment variables as KEY=VALUE, one per line\ne.g.:\nPATH=/usr/local/bin\nNODE_ENV=production")
-
-	// SSE and StreamableHTTP fields
-	urlEntry := widget.NewEntry()
-	headersEntry := widget.NewMultiLineEntry()
-	headersEntry.SetPlaceHolder("Enter HTTP headers as KEY=VALUE, one per line\ne.g.:\nAuthorization=Bearer token\nContent-Type=application/json")
-	timeoutEntry := widget.NewEntry()
-	timeoutEntry.SetPlaceHolder("30")
-	timeoutEntry.SetText("30")
-
-	// Containers for different type fields
-	stdioContainer := container.NewVBox()
-	httpContainer := container.NewVBox()
-
-	// Function to update form fields visibility based on selected type
-	updateFormFields := func(serverType string) {
-		if serverType == "stdio" {
-			stdioContainer.Objects = []fyne.CanvasObject{
-				widget.NewSeparator(),
-				widget.NewLabel("StdIO Configuration:"),
-				container.NewGridWithColumns(2,
-					widget.NewLabel("Command:"), commandEntry,
-				),
-				container.NewGridWithColumns(2,
-					widget.NewLabel("Args:"),
-					container.NewScroll(argsEntry),
-				),
-				container.NewGridWithColumns(2,
-					widget.NewLabel("Env:"),
-					container.NewScroll(envEntry),
-				),
-			}
-			httpContainer.Objects = nil
-		} else {
-			stdioContainer.Objects = nil
-			httpContainer.Objects = []fyne.CanvasObject{
-				widget.NewSeparator(),
-				widget.NewLabel(serverType + " Configuration:"),
-				container.NewGridWithColumns(2,
-					widget.NewLabel("URL:"), urlEntry,
-				),
-				container.NewGridWithColumns(2,
-					widget.NewLabel("Headers:"),
-					container.NewScroll(headersEntry),
-				),
-				container.NewGridWithColumns(2,
-					widget.NewLabel("Timeout (seconds):"), timeoutEntry,
-				),
-			}
-		}
-		stdioContainer.Refresh()
-		httpContainer.Refresh()
-	}
-
 	// MCP Server list
-	mcpList := widget.NewList(
+	mcpList = widget.NewList(
 		func() int { return len(cw.config.MCPServers) },
 		func() fyne.CanvasObject {
+			activity := widget.NewActivity()
+			activity.Hide()
 			return container.NewHBox(
 				widget.NewIcon(theme.ComputerIcon()),
+				activity,
 				widget.NewLabel(""),
 			)
 		},
 		func(id widget.ListItemID, obj fyne.CanvasObject) {
 			container := obj.(*fyne.Container)
-			label := container.Objects[1].(*widget.Label)
+			icon := container.Objects[0].(*widget.Icon)
+			activity := container.Objects[1].(*widget.Activity)
+			label := container.Objects[2].(*widget.Label)
 			if id < len(cw.config.MCPServers) {
 				server := cw.config.MCPServers[id]
+
+				// Spinner while initializing, otherwise an icon reflecting the connection state
+				connStatus, _ := cw.mcpManager.GetServerStatus(server.Name)
+				if connStatus != nil && connStatus.Status == "initializing" {
+					icon.Hide()
+					activity.Show()
+					activity.Start()
+				} else {
+					activity.Stop()
+					activity.Hide()
+					switch {
+					case len(server.MissingPathArgs()) > 0:
+						icon.SetResource(theme.WarningIcon())
+					case connStatus != nil && connStatus.Status == "initialized":
+						icon.SetResource(theme.ConfirmIcon())
+					case connStatus != nil && connStatus.Status == "error":
+						icon.SetResource(theme.ErrorIcon())
+					default:
+						icon.SetResource(theme.ComputerIcon())
+					}
+					icon.Show()
+				}
+
 				serverType := string(server.Type)
 				if serverType == "" {
 					serverType = "stdio"
 				}
-				status := "enabled"
+				status := Translate("settings.status_enabled")
 				if !server.Enabled {
-					status = "disabled"
+					status = Translate("settings.status_disabled")
 				}
 				label.SetText(fmt.Sprintf("%s (%s) - %s", server.Name, serverType, status))
 			}
@@ -720,48 +1135,7 @@ func (cw *ChatWindow) createMCPServersTab(parentWindow fyne.Window) fyne.CanvasO
 			selectedServerIndex = id
 
 			// Populate form
-			nameEntry.SetText(selectedServer.Name)
-			serverType := string(selectedServer.Type)
-			if serverType == "" {
-				serverType = "stdio"
-			}
-			typeSelect.SetSelected(serverType)
-			enabledCheck.SetChecked(selectedServer.Enabled)
-			updateFormFields(serverType)
-
-			// Populate StdIO fields
-			commandEntry.SetText(selectedServer.Command)
-			if len(selectedServer.Args) > 0 {
-				argsEntry.SetText(strings.Join(selectedServer.Args, "\n"))
-			} else {
-				argsEntry.SetText("")
-			}
-			if len(selectedServer.Env) > 0 {
-				envLines := make([]string, 0, len(selectedServer.Env))
-				for k, v := range selectedServer.Env {
-					envLines = append(envLines, fmt.Sprintf("%s=%s", k, v))
-				}
-				envEntry.SetText(strings.Join(envLines, "\n"))
-			} else {
-				envEntry.SetText("")
-			}
-
-			// Populate HTTP fields
-			urlEntry.SetText(selectedServer.URL)
-			if len(selectedServer.Headers) > 0 {
-				headerLines := make([]string, 0, len(selectedServer.Headers))
-				for k, v := range selectedServer.Headers {
-					headerLines = append(headerLines, fmt.Sprintf("%s=%s", k, v))
-				}
-				headersEntry.SetText(strings.Join(headerLines, "\n"))
-			} else {
-				headersEntry.SetText("")
-			}
-			if selectedServer.TimeoutSeconds > 0 {
-				timeoutEntry.SetText(fmt.Sprintf("%d", selectedServer.TimeoutSeconds))
-			} else {
-				timeoutEntry.SetText("30")
-			}
+			serverForm.Bind(selectedServer, false)
 
 			// Refresh status and tools display
 			refreshServerStatus(selectedServer.Name)
@@ -774,15 +1148,7 @@ func (cw *ChatWindow) createMCPServersTab(parentWindow fyne.Window) fyne.CanvasO
 			selectedServerIndex = -1
 
 			// Clear form
-			nameEntry.SetText("")
-			typeSelect.SetSelected("")
-			commandEntry.SetText("")
-			argsEntry.SetText("")
-			envEntry.SetText("")
-			urlEntry.SetText("")
-			headersEntry.SetText("")
-			timeoutEntry.SetText("30")
-			updateFormFields("stdio")
+			serverForm.Bind(nil, false)
 
 			// Clear status and tools display
 			refreshServerStatus("")
@@ -790,119 +1156,28 @@ func (cw *ChatWindow) createMCPServersTab(parentWindow fyne.Window) fyne.CanvasO
 	}
 
 	// Form
-	form := container.NewVBox(
-		widget.NewLabel("MCP Server Details"),
-		widget.NewSeparator(),
-		container.NewGridWithColumns(2,
-			widget.NewLabel("Name:"), nameEntry,
-			widget.NewLabel("Type:"), typeSelect,
-			widget.NewLabel(""), enabledCheck,
-		),
+	form := settingsSection(Translate("mcp.server_details"),
+		pathWarning,
+		serverForm.Content,
 	)
 
-	// Add type change handler
-	typeSelect.OnChanged = func(serverType string) {
-		updateFormFields(serverType)
-	}
-
-	form.Add(stdioContainer)
-	form.Add(httpContainer)
-
-	// Set minimum sizes for multi-line entries
-	argsEntry.SetMinRowsVisible(3)
-	envEntry.SetMinRowsVisible(3)
-	headersEntry.SetMinRowsVisible(3)
-
 	// Buttons
-	addBtn := widget.NewButton("Add New", func() {
+	addBtn := widget.NewButton(Translate("settings.add_new"), func() {
 		// Clear form and deselect
 		selectedServer = nil
 		selectedServerIndex = -1
 		mcpList.UnselectAll()
-		nameEntry.SetText("")
-		typeSelect.SetSelected("stdio")
-		enabledCheck.SetChecked(true)
-		commandEntry.SetText("")
-		argsEntry.SetText("")
-		envEntry.SetText("")
-		urlEntry.SetText("")
-		headersEntry.SetText("")
-		timeoutEntry.SetText("30")
-		updateFormFields("stdio")
+		serverForm.Bind(nil, true)
 		refreshServerStatus("")
 	})
 
-	saveBtn := widget.NewButton("Save", func() {
-		if nameEntry.Text == "" {
-			dialog.ShowError(fmt.Errorf("Server name cannot be empty"), parentWindow)
-			return
-		}
-		if typeSelect.Selected == "" {
-			dialog.ShowError(fmt.Errorf("Server type must be selected"), parentWindow)
+	saveBtn := widget.NewButton(Translate("common.save"), func() {
+		newServer, err := serverForm.Read()
+		if err != nil {
+			dialog.ShowError(err, parentWindow)
 			return
 		}
 
-		newServer := config.MCPServer{
-			Name:    nameEntry.Text,
-			Type:    config.MCPServerType(typeSelect.Selected),
-			Enabled: enabledCheck.Checked,
-		}
-
-		// Set type-specific fields
-		if typeSelect.Selected == "stdio" {
-			if commandEntry.Text == "" {
-				dialog.ShowError(fmt.Errorf("Command cannot be empty for StdIO type"), parentWindow)
-				return
-			}
-			newServer.Command = commandEntry.Text
-
-			// Parse args
-			if strings.TrimSpace(argsEntry.Text) != "" {
-				newServer.Args = strings.Split(strings.TrimSpace(argsEntry.Text), "\n")
-			}
-
-			// Parse env
-			if strings.TrimSpace(envEntry.Text) != "" {
-				env := make(map[string]string)
-				envLines := strings.Split(strings.TrimSpace(envEntry.Text), "\n")
-				for _, line := range envLines {
-					parts := strings.SplitN(line, "=", 2)
-					if len(parts) == 2 {
-						env[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
-					}
-				}
-				newServer.Env = env
-			}
-		} else {
-			// SSE and StreamableHTTP
-			if urlEntry.Text == "" {
-				dialog.ShowError(fmt.Errorf("URL cannot be empty for %s type", typeSelect.Selected), parentWindow)
-				return
-			}
-			newServer.URL = urlEntry.Text
-
-			// Parse headers
-			if strings.TrimSpace(headersEntry.Text) != "" {
-				headers := make(map[string]string)
-				headerLines := strings.Split(strings.TrimSpace(headersEntry.Text), "\n")
-				for _, line := range headerLines {
-					parts := strings.SplitN(line, "=", 2)
-					if len(parts) == 2 {
-						headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
-					}
-				}
-				newServer.Headers = headers
-			}
-
-			// Parse timeout
-			if strings.TrimSpace(timeoutEntry.Text) != "" {
-				var timeout int
-				if _, err := fmt.Sscanf(timeoutEntry.Text, "%d", &timeout); err == nil {
-					newServer.TimeoutSeconds = timeout
-				}
-			}
-		}
-
 		if selectedServer != nil {
 			// Update existing server
 			oldName := selectedServer.Name
@@ -910,7 +1185,8 @@ func (cw *ChatWindow) createMCPServersTab(parentWindow fyne.Window) fyne.CanvasO
 
 			// If name changed, disconnect old connection
 			if oldName != newServer.Name {
-				_ = cw.mcpManager.manager.DisconnectServer(oldName)
+				cw.config.MCPServerRenamed(oldName, newServer.Name)
+				_ = cw.mcpManager.DisconnectServer(oldName)
 			}
 		} else {
 			// Add new server
@@ -920,43 +1196,38 @@ func (cw *ChatWindow) createMCPServersTab(parentWindow fyne.Window) fyne.CanvasO
 		}
 
 		config.SaveConfig(cw.config)
+		// A server saved as disabled isn't reconnected if it dropped
+		cw.mcpManager.ForgetDisabled(cw.config.MCPServers)
 		mcpList.Refresh()
 
 		// Select the updated/new server
 		mcpList.Select(selectedServerIndex)
 	})
+	serverForm.SetSaveButton(saveBtn)
 
-	deleteBtn := widget.NewButton("Delete", func() {
+	deleteBtn := widget.NewButton(Translate("common.delete"), func() {
 		if selectedServer == nil {
-			dialog.ShowError(fmt.Errorf("Please select a server to delete"), parentWindow)
+			dialog.ShowError(errors.New(Translate("mcp.select_server_to_delete")), parentWindow)
 			return
 		}
 
 		dialog.ShowConfirm(
-			"Delete MCP Server",
-			fmt.Sprintf("Are you sure you want to delete MCP server '%s'?", selectedServer.Name),
+			Translate("mcp.delete_server"),
+			Translatef("mcp.delete_server_confirm", selectedServer.Name),
 			func(confirmed bool) {
 				if confirmed {
 					// Disconnect if connected
-					_ = cw.mcpManager.manager.DisconnectServer(selectedServer.Name)
+					_ = cw.mcpManager.DisconnectServer(selectedServer.Name)
 
 					// Remove MCP server
 					cw.config.MCPServers = append(cw.config.MCPServers[:selectedServerIndex], cw.config.MCPServers[selectedServerIndex+1:]...)
 					config.SaveConfig(cw.config)
+					cw.mcpManager.ForgetDisabled(cw.config.MCPServers)
 
 					// Reset selection and clear form
 					selectedServer = nil
 					selectedServerIndex = -1
-					nameEntry.SetText("")
-					typeSelect.SetSelected("")
-					enabledCheck.SetChecked(false)
-					commandEntry.SetText("")
-					argsEntry.SetText("")
-					envEntry.SetText("")
-					urlEntry.SetText("")
-					headersEntry.SetText("")
-					timeoutEntry.SetText("30")
-					updateFormFields("stdio")
+					serverForm.Bind(nil, false)
 					refreshServerStatus("")
 
 					mcpList.Refresh()
@@ -966,24 +1237,35 @@ func (cw *ChatWindow) createMCPServersTab(parentWindow fyne.Window) fyne.CanvasO
 		)
 	})
 
+	// Refresh spinners and the selected server's details whenever a server's status changes
+	onStatusChanged := func() {
+		fyne.Do(func() {
+			mcpList.Refresh()
+			if selectedServer != nil {
+				refreshServerStatus(selectedServer.Name)
+			}
+		})
+	}
+	cw.mcpManager.SetStatusListener(onStatusChanged)
+
+	// Initialize all enabled servers without blocking; the list shows per-server spinners
+	initAllBtn := widget.NewButton(Translate("mcp.initialize_all"), func() {
+		cw.mcpManager.InitializeAllAsync(cw.config.MCPServers, nil)
+		mcpList.Refresh()
+		if selectedServer != nil {
+			refreshServerStatus(selectedServer.Name)
+		}
+	})
+
 	buttonContainer := container.NewVBox(
 		container.NewHBox(addBtn, saveBtn, deleteBtn),
-		container.NewHBox(initBtn, disconnectBtn),
+		container.NewHBox(initBtn, initAllBtn, disconnectBtn),
 	)
 
-	// Right side container with form and buttons
-	rightPanel := container.NewBorder(
-		nil,
-		buttonContainer,
-		nil,
-		nil,
-		form,
-	)
-
-	// Split left and right
+	// Split the list and the selected server's details
 	split := container.NewHSplit(
 		mcpList,
-		rightPanel,
+		settingsDetails(form, buttonContainer),
 	)
 	split.SetOffset(0.4)
 
@@ -992,193 +1274,40 @@ func (cw *ChatWindow) createMCPServersTab(parentWindow fyne.Window) fyne.CanvasO
 
 // showMCPServerDialog displays a dialog for adding or editing an MCP server.
 func (cw *ChatWindow) showMCPServerDialog(settingsWin fyne.Window, server *config.MCPServer, mcpList *widget.List) {
-	title := "Add MCP Server"
-	if server != nil {
-		title = "Edit MCP Server"
-	}
-
-	nameEntry := widget.NewEntry()
-	typeSelect := widget.NewSelect([]string{"stdio", "sse", "streamable_http"}, nil)
-	enabledCheck := widget.NewCheck("Enabled", nil)
-
-	// StdIO fields
-	commandEntry := widget.NewEntry()
-	argsEntry := widget.NewMultiLineEntry()
-	argsEntry.SetPlaceHolder("Enter arguments separated by new lines\ne.g.:\n-y\n@modelcontextprotocol/server-filesystem\n/path/to/files")
-	envEntry := widget.NewMultiLineEntry()
-	envEntry.SetPlaceHolder("Enter environment variables as KEY=VALUE, one per line\ne.g.:\nPATH=/usr/local/bin\nNODE_ENV=production")
-
-	// HTTP fields
-	urlEntry := widget.NewEntry()
-	headersEntry := widget.NewMultiLineEntry()
-	headersEntry.SetPlaceHolder("Enter HTTP headers as KEY=VALUE, one per line\ne.g.:\nAuthorization=Bearer token")
-	timeoutEntry := widget.NewEntry()
-	timeoutEntry.SetText("30")
-
-	// Containers for type-specific fields
-	stdioContainer := container.NewVBox()
-	httpContainer := container.NewVBox()
-
-	// Function to update form fields visibility
-	updateFormFields := func(serverType string) {
-		if serverType == "stdio" {
-			stdioContainer.Objects = []fyne.CanvasObject{
-				widget.NewLabel("Command:"), commandEntry,
-				widget.NewLabel("Args:"), container.NewGridWithColumns(1, argsEntry),
-				widget.NewLabel("Env:"), container.NewGridWithColumns(1, envEntry),
-			}
-			httpContainer.Objects = nil
-		} else {
-			stdioContainer.Objects = nil
-			httpContainer.Objects = []fyne.CanvasObject{
-				widget.NewLabel("URL:"), urlEntry,
-				widget.NewLabel("Headers:"), container.NewGridWithColumns(1, headersEntry),
-				widget.NewLabel("Timeout (sec):"), timeoutEntry,
-			}
-		}
-		stdioContainer.Refresh()
-		httpContainer.Refresh()
-	}
-
-	// Set initial state
-	typeSelect.SetSelected("stdio")
-	typeSelect.OnChanged = updateFormFields
-	updateFormFields("stdio")
-
-	if server != nil {
-		enabledCheck.SetChecked(server.Enabled)
-	} else {
-		enabledCheck.SetChecked(true)
-	}
-
+	title := Translate("mcp.add_server")
 	if server != nil {
-		nameEntry.SetText(server.Name)
-		serverType := string(server.Type)
-		if serverType == "" {
-			serverType = "stdio"
-		}
-		typeSelect.SetSelected(serverType)
-		commandEntry.SetText(server.Command)
-		if len(server.Args) > 0 {
-			argsEntry.SetText(strings.Join(server.Args, "\n"))
-		}
-		if len(server.Env) > 0 {
-			envLines := make([]string, 0, len(server.Env))
-			for k, v := range server.Env {
-				envLines = append(envLines, fmt.Sprintf("%s=%s", k, v))
-			}
-			envEntry.SetText(strings.Join(envLines, "\n"))
-		}
-		urlEntry.SetText(server.URL)
-		if len(server.Headers) > 0 {
-			headerLines := make([]string, 0, len(server.Headers))
-			for k, v := range server.Headers {
-				headerLines = append(headerLines, fmt.Sprintf("%s=%s", k, v))
-			}
-			headersEntry.SetText(strings.Join(headerLines, "\n"))
-		}
-		if server.TimeoutSeconds > 0 {
-			timeoutEntry.SetText(fmt.Sprintf("%d", server.TimeoutSeconds))
-		}
+		title = Translate("mcp.edit_server")
 	}
 
-	form := container.NewVBox(
-		container.NewGridWithColumns(2,
-			widget.NewLabel("Name:"), nameEntry,
-			widget.NewLabel("Type:"), typeSelect,
-			widget.NewLabel(""), enabledCheck,
-		),
-	)
-	form.Add(stdioContainer)
-	form.Add(httpContainer)
+	serverForm := NewMCPServerForm()
+	serverForm.Bind(server, true)
 
-	content := container.NewVBox(
-		form,
-	)
-
-	var d dialog.Dialog
+	d := dialog.NewCustomWithoutButtons(title, serverForm.Content, settingsWin)
 
-	saveBtn := widget.NewButton("Save", func() {
-		if nameEntry.Text == "" {
-			dialog.ShowError(fmt.Errorf("Server name cannot be empty"), settingsWin)
-			return
-		}
-		if typeSelect.Selected == "" {
-			dialog.ShowError(fmt.Errorf("Server type must be selected"), settingsWin)
+	saveBtn := widget.NewButton(Translate("common.save"), func() {
+		newServer, err := serverForm.Read()
+		if err != nil {
+			dialog.ShowError(err, settingsWin)
 			return
 		}
 
-		newServer := config.MCPServer{
-			Name:    nameEntry.Text,
-			Type:    config.MCPServerType(typeSelect.Selected),
-			Enabled: enabledCheck.Checked,
-		}
-
-		if typeSelect.Selected == "stdio" {
-			if commandEntry.Text == "" {
-				dialog.ShowError(fmt.Errorf("Command cannot be empty for StdIO type"), settingsWin)
-				return
-			}
-			newServer.Command = commandEntry.Text
-
-			if argsEntry.Text != "" {
-				newServer.Args = strings.Split(strings.TrimSpace(argsEntry.Text), "\n")
-			}
-
-			if envEntry.Text != "" {
-				env := make(map[string]string)
-				envLines := strings.Split(strings.TrimSpace(envEntry.Text), "\n")
-				for _, line := range envLines {
-					parts := strings.SplitN(line, "=", 2)
-					if len(parts) == 2 {
-						env[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
-					}
-				}
-				newServer.Env = env
-			}
-		} else {
-			if urlEntry.Text == "" {
-				dialog.ShowError(fmt.Errorf("URL cannot be empty for %s type", typeSelect.Selected), settingsWin)
-				return
-			}
-			newServer.URL = urlEntry.Text
-
-			if headersEntry.Text != "" {
-				headers := make(map[string]string)
-				headerLines := strings.Split(strings.TrimSpace(headersEntry.Text), "\n")
-				for _, line := range headerLines {
-					parts := strings.SplitN(line, "=", 2)
-					if len(parts) == 2 {
-						headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
-					}
-				}
-				newServer.Headers = headers
-			}
-
-			if timeoutEntry.Text != "" {
-				var timeout int
-				if _, err := fmt.Sscanf(timeoutEntry.Text, "%d", &timeout); err == nil {
-					newServer.TimeoutSeconds = timeout
-				}
-			}
-		}
-
 		if server != nil {
+			cw.config.MCPServerRenamed(server.Name, newServer.Name)
 			*server = newServer
 		} else {
 			cw.config.MCPServers = append(cw.config.MCPServers, newServer)
 		}
 
 		config.SaveConfig(cw.config)
+		cw.mcpManager.ForgetDisabled(cw.config.MCPServers)
 		mcpList.Refresh()
 		d.Hide()
 	})
+	serverForm.SetSaveButton(saveBtn)
+	cancelBtn := widget.NewButton(Translate("common.cancel"), func() {
+		d.Hide()
+	})
 
-	d = dialog.NewCustomConfirm(title, "Save", "Cancel", content, func(response bool) {
-		if response {
-			saveBtn.OnTapped()
-		}
-	}, settingsWin)
-
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, saveBtn})
 	d.Show()
 }
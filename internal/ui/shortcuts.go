@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// sendShortcut sends the message from either message entry, whatever Enter is set to do
+var sendShortcut = &desktop.CustomShortcut{KeyName: fyne.KeyReturn, Modifier: fyne.KeyModifierShortcutDefault}
+
+// appShortcut is a keyboard shortcut that works anywhere in the chat window
+type appShortcut struct {
+	shortcut *desktop.CustomShortcut
+	action   string // Translation key of what it does, for the shortcuts dialog
+	run      func()
+}
+
+// registerShortcuts adds the window's keyboard shortcuts. Shortcuts typed while an entry has
+// focus go to the entry instead, so the entries pass them on to runShortcut.
+func (cw *ChatWindow) registerShortcuts() {
+	mod := fyne.KeyModifierShortcutDefault
+	cw.shortcuts = []appShortcut{
+		{&desktop.CustomShortcut{KeyName: fyne.KeyN, Modifier: mod}, "shortcuts.new_chat", cw.newConversationFromShortcut},
+		{&desktop.CustomShortcut{KeyName: fyne.KeyComma, Modifier: mod}, "shortcuts.settings", cw.showSettings},
+		{&desktop.CustomShortcut{KeyName: fyne.KeyK, Modifier: mod}, "shortcuts.search", cw.focusSearch},
+		// Ctrl+Tab on every platform, as in browsers
+		{&desktop.CustomShortcut{KeyName: fyne.KeyTab, Modifier: fyne.KeyModifierControl}, "shortcuts.next_chat", func() { cw.cycleConversation(1) }},
+		{&desktop.CustomShortcut{KeyName: fyne.KeyTab, Modifier: fyne.KeyModifierControl | fyne.KeyModifierShift}, "shortcuts.previous_chat", func() { cw.cycleConversation(-1) }},
+		{&desktop.CustomShortcut{KeyName: fyne.KeySlash, Modifier: mod}, "shortcuts.help", cw.showShortcuts},
+	}
+	for _, s := range cw.shortcuts {
+		cw.window.Canvas().AddShortcut(s.shortcut, func(fyne.Shortcut) { s.run() })
+	}
+}
+
+// runShortcut runs the window shortcut matching shortcut. It reports false when there is none,
+// so the entry handles the shortcut itself.
+func (cw *ChatWindow) runShortcut(shortcut fyne.Shortcut) bool {
+	for _, s := range cw.shortcuts {
+		if s.shortcut.ShortcutName() == shortcut.ShortcutName() {
+			s.run()
+			return true
+		}
+	}
+	return false
+}
+
+// newConversationFromShortcut starts a new conversation, leaving the home page if shown, and
+// puts the cursor in the message entry
+func (cw *ChatWindow) newConversationFromShortcut() {
+	cw.confirmDiscardIncognito(func() {
+		cw.switchToChatUI()
+		cw.createNewConversation()
+		cw.window.Canvas().Focus(cw.messageEntry)
+	})
+}
+
+// focusSearch puts the cursor in the sidebar's search box, leaving the home page if shown
+func (cw *ChatWindow) focusSearch() {
+	cw.switchToChatUI()
+	cw.window.Canvas().Focus(cw.searchEntry)
+}
+
+// cycleConversation opens the conversation step rows below the open one in the sidebar,
+// wrapping around at either end
+func (cw *ChatWindow) cycleConversation(step int) {
+	cw.switchToChatUI()
+	n := len(cw.convListData)
+	if n == 0 {
+		return
+	}
+
+	next := 0
+	if step < 0 {
+		next = n - 1
+	}
+	if cw.currentConversation != nil {
+		if i := conversationIndex(cw.convListData, cw.currentConversation.ID); i >= 0 {
+			next = ((i+step)%n + n) % n
+		}
+	}
+	cw.convList.Select(next)
+}
+
+// showShortcuts lists the keyboard shortcuts
+func (cw *ChatWindow) showShortcuts() {
+	grid := newFormGrid(newFormLabel(shortcutText(sendShortcut)), widget.NewLabel(Translate("shortcuts.send")))
+	for _, s := range cw.shortcuts {
+		grid.Add(newFormLabel(shortcutText(s.shortcut)))
+		grid.Add(widget.NewLabel(Translate(s.action)))
+	}
+	dialog.ShowCustom(Translate("shortcuts.title"), Translate("common.close"), grid, cw.window)
+}
+
+// shortcutText writes a shortcut the way the platform does, e.g. "Ctrl+Shift+Tab" or "Cmd+N"
+func shortcutText(shortcut *desktop.CustomShortcut) string {
+	var parts []string
+	if shortcut.Modifier&fyne.KeyModifierControl != 0 {
+		parts = append(parts, "Ctrl")
+	}
+	if shortcut.Modifier&fyne.KeyModifierAlt != 0 {
+		parts = append(parts, "Alt")
+	}
+	if shortcut.Modifier&fyne.KeyModifierShift != 0 {
+		parts = append(parts, "Shift")
+	}
+	if shortcut.Modifier&fyne.KeyModifierSuper != 0 {
+		parts = append(parts, "Cmd")
+	}
+
+	key := string(shortcut.KeyName)
+	if shortcut.KeyName == fyne.KeyReturn {
+		key = "Enter"
+	}
+	return strings.Join(append(parts, key), "+")
+}
+
+// shortcutEntry is a single-line entry that passes the window's shortcuts on instead of
+// swallowing them while it has focus
+type shortcutEntry struct {
+	widget.Entry
+	onShortcut func(fyne.Shortcut) bool
+}
+
+// newShortcutEntry creates an entry that offers every shortcut to onShortcut first
+func newShortcutEntry(onShortcut func(fyne.Shortcut) bool) *shortcutEntry {
+	e := &shortcutEntry{onShortcut: onShortcut}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// TypedShortcut runs a window shortcut, or else handles the shortcut as an entry does
+func (e *shortcutEntry) TypedShortcut(shortcut fyne.Shortcut) {
+	if e.onShortcut != nil && e.onShortcut(shortcut) {
+		return
+	}
+	e.Entry.TypedShortcut(shortcut)
+}
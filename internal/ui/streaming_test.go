@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"chatgo/internal/llm"
+	"chatgo/pkg/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+// newTestChatWindow returns a chat window on the test driver showing an empty chat, without
+// the background work NewChatWindow starts
+func newTestChatWindow(t *testing.T, providers ...config.Provider) *ChatWindow {
+	t.Helper()
+	app := test.NewTempApp(t)
+	convManager, err := models.NewConversationManager()
+	if err != nil {
+		t.Fatalf("NewConversationManager: %v", err)
+	}
+
+	cw := &ChatWindow{
+		app:         app,
+		window:      app.NewWindow("test"),
+		config:      &config.Config{Providers: providers},
+		convManager: convManager,
+	}
+	cw.applyTheme()
+	cw.messagesContainer = container.NewVBox()
+	cw.chatArea = container.NewVScroll(cw.messagesContainer)
+	cw.window.SetContent(cw.chatArea)
+	cw.window.Resize(fyne.NewSize(800, 600))
+	return cw
+}
+
+// newChatServer answers OpenAI chat completions with chunks, sent gap apart when the request
+// streams and joined otherwise
+func newChatServer(t *testing.T, chunks []string, gap time.Duration) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Stream bool `json:"stream"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		if !request.Stream {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"id":"chatcmpl-test","object":"chat.completion","created":0,"model":"fake",`+
+				`"choices":[{"index":0,"message":{"role":"assistant","content":%s},"finish_reason":"stop"}]}`,
+				jsonString(strings.Join(chunks, "")))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, `data: {"id":"chatcmpl-test","object":"chat.completion.chunk","created":0,"model":"fake",`+
+				`"choices":[{"index":0,"delta":{"role":"assistant","content":%s}}]}`+"\n\n", jsonString(chunk))
+			flusher.Flush()
+			time.Sleep(gap)
+		}
+		fmt.Fprint(w, `data: {"id":"chatcmpl-test","object":"chat.completion.chunk","created":0,"model":"fake",`+
+			`"choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		flusher.Flush()
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// jsonString quotes s as a JSON string
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// sendAndWait streams the reply to a new conversation's first message into a new row and
+// returns the conversation once the reply, or the failure, is shown
+func sendAndWait(t *testing.T, cw *ChatWindow, provider config.Provider) (*models.Conversation, *fyne.Container) {
+	t.Helper()
+	client, err := llm.NewClient(provider, llm.ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	cw.llmClient = client
+
+	conv, err := cw.convManager.CreateConversation("Streaming", provider.Name, provider.Model)
+	if err != nil {
+		t.Fatalf("CreateConversation: %v", err)
+	}
+	conv.Messages = append(conv.Messages, models.Message{ID: models.NewMessageID(), Role: "user", Content: "Hi", Timestamp: time.Now()})
+
+	row := container.NewVBox()
+	cw.messagesContainer.Add(row)
+	shown := cw.streamAssistantResponse(conv, []llm.ChatMessage{{Role: "user", Content: "Hi"}}, row)
+
+	select {
+	case <-shown:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the reply wasn't shown")
+	}
+	return conv, row
+}
+
+// shownText returns the text of the rich texts in a row, and the text of its visible labels
+func shownText(object fyne.CanvasObject) (richText string, labels []string) {
+	switch o := object.(type) {
+	case *fyne.Container:
+		for _, child := range o.Objects {
+			r, l := shownText(child)
+			richText += r
+			labels = append(labels, l...)
+		}
+	case *widget.RichText:
+		richText = o.String()
+	case *widget.Label:
+		if o.Visible() {
+			labels = append(labels, o.Text)
+		}
+	}
+	return richText, labels
+}
+
+func TestStreamAssistantResponse(t *testing.T) {
+	chunks := []string{"Hello", ", wor", "ld. How ", "can I ", "help?"}
+	// Gaps longer than the flush interval, so the reply is rendered as it arrives
+	srv := newChatServer(t, chunks, streamFlushInterval+20*time.Millisecond)
+	provider := config.Provider{Name: t.Name(), Type: "openai", BaseURL: srv.URL + "/v1", APIKey: "test", Model: "fake"}
+	cw := newTestChatWindow(t, provider)
+
+	conv, row := sendAndWait(t, cw, provider)
+
+	want := strings.Join(chunks, "")
+	reply := conv.Messages[len(conv.Messages)-1]
+	if reply.Role != "assistant" || reply.Content != want {
+		t.Errorf("saved reply = %s %q, want assistant %q", reply.Role, reply.Content, want)
+	}
+	richText, labels := shownText(row)
+	if richText != want {
+		t.Errorf("shown reply = %q, want %q", richText, want)
+	}
+	for _, label := range labels {
+		if label == Translate("chat.not_streamed") || label == Translate("chat.waiting_for_model") {
+			t.Errorf("%q is shown for a streamed reply", label)
+		}
+	}
+
+	stored, err := cw.convManager.LoadConversation(conv.ID)
+	if err != nil {
+		t.Fatalf("LoadConversation: %v", err)
+	}
+	if len(stored.Messages) != 2 || stored.Messages[1].Content != want {
+		t.Errorf("stored messages = %+v, want the question and the reply", stored.Messages)
+	}
+}
+
+func TestStreamAssistantResponseWithoutStreaming(t *testing.T) {
+	chunks := []string{"Sent ", "whole"}
+	srv := newChatServer(t, chunks, 0)
+	provider := config.Provider{Name: t.Name(), Type: "openai", BaseURL: srv.URL + "/v1", APIKey: "test", Model: "fake",
+		DisableStreaming: true}
+	cw := newTestChatWindow(t, provider)
+
+	conv, row := sendAndWait(t, cw, provider)
+
+	if got := conv.Messages[len(conv.Messages)-1].Content; got != "Sent whole" {
+		t.Errorf("saved reply = %q, want %q", got, "Sent whole")
+	}
+	richText, labels := shownText(row)
+	if richText != "Sent whole" {
+		t.Errorf("shown reply = %q, want %q", richText, "Sent whole")
+	}
+	found := false
+	for _, label := range labels {
+		found = found || label == Translate("chat.not_streamed")
+	}
+	if !found {
+		t.Errorf("visible labels %q don't mark the reply as not streamed", labels)
+	}
+}
+
+func TestStreamAssistantResponseFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"Incorrect API key provided","type":"invalid_request_error","code":"invalid_api_key"}}`)
+	}))
+	defer srv.Close()
+	provider := config.Provider{Name: t.Name(), Type: "openai", BaseURL: srv.URL + "/v1", APIKey: "wrong", Model: "fake"}
+	cw := newTestChatWindow(t, provider)
+
+	conv, row := sendAndWait(t, cw, provider)
+
+	if len(conv.Messages) != 1 {
+		t.Errorf("conversation has %d messages after a failed request, want only the question", len(conv.Messages))
+	}
+	_, labels := shownText(row)
+	role, message := false, false
+	for _, label := range labels {
+		role = role || label == Translate("chat.error_role")
+		message = message || strings.Contains(label, "Incorrect API key provided")
+	}
+	if !role || !message {
+		t.Errorf("visible labels = %q, want the provider's error", labels)
+	}
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// scrollFollowSlack is how far from the bottom the chat may be scrolled and still follow new content
+const scrollFollowSlack = 24
+
+// streamRenderer renders a streamed reply into a container as it grows. Blocks that can no
+// longer change are parsed once into a RichText of their own placed before tail, which holds the
+// rest of the reply. Each render then parses and lays out only the text after the last finished
+// block; refreshing a single RichText holding the whole reply measures all of its text again,
+// which made long replies slower with every chunk. Render the complete reply into tail alone
+// with streamingMessage.showContent once it has arrived.
+type streamRenderer struct {
+	body    *fyne.Container  // The finished blocks' RichTexts, followed by tail
+	tail    *widget.RichText // Text after the finished blocks, rendered again each time
+	config  *RichTextConfig
+	doneLen int // Length of the markdown the finished blocks were parsed from
+}
+
+// newStreamRenderer creates a renderer for the streamed reply shown in body, which holds tail
+func newStreamRenderer(body *fyne.Container, tail *widget.RichText) *streamRenderer {
+	config := DefaultRichTextConfig()
+	// Partial content may end in a table that is still receiving rows
+	config.Streaming = true
+	return &streamRenderer{body: body, tail: tail, config: config}
+}
+
+// render shows markdown, the reply received so far, which extends what was last rendered
+func (r *streamRenderer) render(markdown string) {
+	// Finished blocks end outside any code block, so the search can start after them
+	if cut := r.doneLen + finishedMarkdownLength(markdown[r.doneLen:]); cut > r.doneLen {
+		finished := widget.NewRichText(markdownSegments(markdown[r.doneLen:cut], r.config)...)
+		finished.Wrapping = r.tail.Wrapping
+		r.body.Objects = slices.Insert(r.body.Objects, len(r.body.Objects)-1, fyne.CanvasObject(finished))
+		r.body.Refresh()
+		r.doneLen = cut
+	}
+
+	r.tail.Segments = markdownSegments(markdown[r.doneLen:], r.config)
+	r.tail.Refresh()
+}
+
+// finishedMarkdownLength returns the length of the leading blocks of markdown that later text
+// cannot change: everything up to a blank line outside a code block that is followed by a
+// complete line starting a new top-level block. Text after an indented or list line could
+// still continue the block before the blank line, so it is not cut there.
+func finishedMarkdownLength(markdown string) int {
+	finished := 0
+	fence := ""
+	blankAt := -1 // Offset just after the latest blank line, while the line after it is pending
+	offset := 0
+
+	for {
+		end := strings.IndexByte(markdown[offset:], '\n')
+		if end < 0 {
+			// The last line is incomplete
+			return finished
+		}
+		line := markdown[offset : offset+end]
+		next := offset + end + 1
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) && strings.TrimLeft(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+		case trimmed == "":
+			blankAt = next
+			offset = next
+			continue
+		default:
+			if blankAt >= 0 && startsTopLevelBlock(line) {
+				finished = blankAt
+			}
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				fence = trimmed[:3]
+			}
+		}
+		blankAt = -1
+		offset = next
+	}
+}
+
+// startsTopLevelBlock reports whether a line after a blank line begins a new block rather
+// than continuing a list item or an indented block
+func startsTopLevelBlock(line string) bool {
+	if line == "" || line[0] == ' ' || line[0] == '\t' {
+		return false
+	}
+	if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "+ ") {
+		return false
+	}
+	// Ordered list items such as "2. "
+	digits := strings.TrimLeftFunc(line, unicode.IsDigit)
+	if len(digits) < len(line) && (strings.HasPrefix(digits, ". ") || strings.HasPrefix(digits, ") ")) {
+		return false
+	}
+	return true
+}
+
+// chatAtBottom reports whether the chat is scrolled to, or close to, its end, so new content
+// should keep it there. When the user has scrolled up to read, new content leaves it in place.
+func (cw *ChatWindow) chatAtBottom() bool {
+	hidden := cw.chatArea.Content.Size().Height - cw.chatArea.Size().Height
+	return cw.chatArea.Offset.Y >= hidden-scrollFollowSlack
+}
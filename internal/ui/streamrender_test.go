@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"fmt"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+// streamChunkSize is about how many bytes of a reply each streamed chunk adds
+const streamChunkSize = 48
+
+// codeHeavyReply returns a long reply alternating short explanations with Go code blocks
+func codeHeavyReply(sections int) string {
+	var reply strings.Builder
+	for i := range sections {
+		fmt.Fprintf(&reply, "## Step %d\n\nThe handler below reads the request, validates **field %d** and writes the `result` back.\n\n", i+1, i)
+		fmt.Fprintf(&reply, "```go\nfunc handle%d(w http.ResponseWriter, r *http.Request) {\n", i)
+		reply.WriteString("\tvar req request\n\tif err := json.NewDecoder(r.Body).Decode(&req); err != nil {\n")
+		reply.WriteString("\t\thttp.Error(w, err.Error(), http.StatusBadRequest)\n\t\treturn\n\t}\n")
+		fmt.Fprintf(&reply, "\tjson.NewEncoder(w).Encode(process(req, %d))\n}\n```\n\n", i)
+	}
+	return reply.String()
+}
+
+// streamedPrefixes returns the reply received so far after each chunk of a streamed reply
+func streamedPrefixes(reply string) []string {
+	var prefixes []string
+	for end := streamChunkSize; end < len(reply); end += streamChunkSize {
+		prefixes = append(prefixes, reply[:end])
+	}
+	return append(prefixes, reply)
+}
+
+// newThemedTestApp makes a test app using the app's theme, which defines the message text size
+func newThemedTestApp(tb testing.TB) {
+	test.NewTempApp(tb).Settings().SetTheme(newAppTheme(&config.Config{}))
+}
+
+// streamReply renders each prefix of reply into a new message body shown in a window, the way
+// a streamed reply is, using render
+func streamReply(tb testing.TB, prefixes []string, render func(body *fyne.Container, tail *widget.RichText, markdown string)) *fyne.Container {
+	tail := widget.NewRichText()
+	tail.Wrapping = fyne.TextWrapWord
+	body := container.NewVBox(tail)
+	w := test.NewTempWindow(tb, container.NewVScroll(body))
+	w.Resize(fyne.NewSize(800, 600))
+
+	for _, markdown := range prefixes {
+		render(body, tail, markdown)
+	}
+	return body
+}
+
+// renderIncrementally renders with a streamRenderer kept for the whole reply
+func renderIncrementally() func(body *fyne.Container, tail *widget.RichText, markdown string) {
+	var renderer *streamRenderer
+	return func(body *fyne.Container, tail *widget.RichText, markdown string) {
+		if renderer == nil {
+			renderer = newStreamRenderer(body, tail)
+		}
+		renderer.render(markdown)
+	}
+}
+
+// renderWhole parses the whole reply into a single RichText each time
+func renderWhole(body *fyne.Container, tail *widget.RichText, markdown string) {
+	config := DefaultRichTextConfig()
+	config.Streaming = true
+	SetMarkdown(tail, markdown, config)
+}
+
+func TestStreamRendererMatchesWholeReply(t *testing.T) {
+	newThemedTestApp(t)
+	prefixes := streamedPrefixes(codeHeavyReply(10))
+
+	incremental, _ := shownText(streamReply(t, prefixes, renderIncrementally()))
+	whole, _ := shownText(streamReply(t, prefixes, renderWhole))
+	if incremental != whole {
+		t.Errorf("incrementally rendered reply differs from the reply rendered whole:\n%s\nwant:\n%s", incremental, whole)
+	}
+}
+
+// benchmarkStreaming measures rendering a long code-heavy reply chunk by chunk, reporting the
+// average time per chunk
+func benchmarkStreaming(b *testing.B, render func() func(body *fyne.Container, tail *widget.RichText, markdown string)) {
+	newThemedTestApp(b)
+	prefixes := streamedPrefixes(codeHeavyReply(40))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		streamReply(b, prefixes, render())
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(prefixes)), "ns/chunk")
+}
+
+func BenchmarkStreamRenderIncremental(b *testing.B) {
+	benchmarkStreaming(b, renderIncrementally)
+}
+
+func BenchmarkStreamRenderWhole(b *testing.B) {
+	benchmarkStreaming(b, func() func(body *fyne.Container, tail *widget.RichText, markdown string) {
+		return renderWhole
+	})
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"chatgo/internal/autotitle"
+	"chatgo/internal/config"
+	"chatgo/internal/llm"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// startTitleQueue starts the background job that fills in missing titles and summaries
+func (cw *ChatWindow) startTitleQueue() {
+	cw.titleQueue = autotitle.NewQueue(cw.convManager, cw.newTitleClient, cw.config.TitleRatePerMinute)
+	cw.titleQueue.SetOnProgress(func(progress autotitle.Progress) {
+		// Updated titles reach the sidebar as conversation events
+		fyne.Do(func() {
+			cw.titleProgress = progress
+			cw.updateTitleProgress()
+		})
+	})
+	cw.titleQueue.Start()
+}
+
+// newTitleClient creates a client for the configured title provider.
+// It is called from the queue goroutine, so configuration is read on the UI goroutine.
+func (cw *ChatWindow) newTitleClient() (*llm.Client, error) {
+	var provider *config.Provider
+	var opts llm.ClientOptions
+	fyne.DoAndWait(func() {
+		provider = cw.titleProvider()
+		opts = cw.clientOptions()
+	})
+
+	if provider == nil {
+		return nil, fmt.Errorf("no provider available for titles")
+	}
+	return llm.NewClient(*provider, opts)
+}
+
+// titleProvider returns a copy of the provider used for background titles:
+// the configured title provider, falling back to the current provider. Disabled providers are skipped.
+func (cw *ChatWindow) titleProvider() *config.Provider {
+	for _, name := range []string{cw.config.TitleProvider, cw.config.CurrentProvider} {
+		if name == "" {
+			continue
+		}
+		for _, p := range cw.config.Providers {
+			if p.Name == name && p.Enabled {
+				provider := p
+				return &provider
+			}
+		}
+	}
+	return nil
+}
+
+// newTitleProgressFooter creates the sidebar footer showing title queue progress
+func (cw *ChatWindow) newTitleProgressFooter() fyne.CanvasObject {
+	cw.titleProgressLabel = widget.NewLabel("")
+	cw.titleProgressLabel.Importance = widget.LowImportance
+	cw.titleProgressLabel.Truncation = fyne.TextTruncateEllipsis
+
+	cw.titlePauseBtn = widget.NewButtonWithIcon("", theme.MediaPauseIcon(), func() {
+		cw.titleQueue.SetPaused(!cw.titleQueue.Paused())
+	})
+	cw.titlePauseBtn.Importance = widget.LowImportance
+
+	cw.titleProgressBox = container.NewBorder(nil, nil, nil, cw.titlePauseBtn, cw.titleProgressLabel)
+	cw.updateTitleProgress()
+	return cw.titleProgressBox
+}
+
+// updateTitleProgress shows the last reported queue progress in the sidebar footer
+func (cw *ChatWindow) updateTitleProgress() {
+	if cw.titleProgressBox == nil {
+		return
+	}
+
+	progress := cw.titleProgress
+	if progress.Pending == 0 {
+		cw.titleProgressBox.Hide()
+		return
+	}
+
+	if progress.Paused {
+		cw.titleProgressLabel.SetText(Translatef("titles.paused", progress.Pending))
+		cw.titlePauseBtn.SetIcon(theme.MediaPlayIcon())
+	} else {
+		cw.titleProgressLabel.SetText(Translatef("titles.generating", progress.Pending))
+		cw.titlePauseBtn.SetIcon(theme.MediaPauseIcon())
+	}
+	cw.titleProgressBox.Show()
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// toolApproval is the user's answer to a tool call approval prompt
+type toolApproval int
+
+const (
+	toolDeclined toolApproval = iota
+	toolAllowedOnce
+	toolAllowedForSession
+)
+
+// toolApprovals remembers the tools the user allowed for the rest of the session.
+// Its lock also keeps approval prompts from stacking up when the agent calls tools in parallel.
+type toolApprovals struct {
+	mu      sync.Mutex
+	allowed map[string]bool
+}
+
+// toolNeedsApproval reports whether the named tool is an enabled built-in tool whose runs must be confirmed
+func (cw *ChatWindow) toolNeedsApproval(name string) bool {
+	for _, t := range cw.config.BuiltinTools {
+		if t.Name == name && t.Enabled {
+			return t.NeedsApproval()
+		}
+	}
+	return false
+}
+
+// approveToolCall asks the user before the agent runs a tool that needs approval. It is called
+// from the tool's goroutine and blocks until the user answers or the request is cancelled.
+func (cw *ChatWindow) approveToolCall(ctx context.Context, name, arguments string) (bool, error) {
+	if !cw.toolNeedsApproval(name) {
+		return true, nil
+	}
+
+	cw.toolApprovals.mu.Lock()
+	defer cw.toolApprovals.mu.Unlock()
+	if cw.toolApprovals.allowed[name] {
+		return true, nil
+	}
+
+	answer := make(chan toolApproval, 1)
+	var d *dialog.CustomDialog
+	fyne.Do(func() {
+		d = cw.showToolApprovalDialog(name, arguments, func(a toolApproval) {
+			select {
+			case answer <- a:
+			default:
+			}
+		})
+	})
+
+	select {
+	case a := <-answer:
+		logger.Debug("Tool call answered", "tool", name, "allowed", a != toolDeclined)
+		if a == toolAllowedForSession {
+			cw.toolApprovals.allowed[name] = true
+		}
+		return a != toolDeclined, nil
+	case <-ctx.Done():
+		fyne.Do(func() {
+			if d != nil {
+				d.Hide()
+			}
+		})
+		return false, ctx.Err()
+	}
+}
+
+// showToolApprovalDialog shows a tool call's name and arguments and reports the user's answer.
+// Closing the dialog any other way declines the call.
+func (cw *ChatWindow) showToolApprovalDialog(name, arguments string, onAnswer func(toolApproval)) *dialog.CustomDialog {
+	message := widget.NewLabel(Translatef("tool_approval.message", name))
+	message.Wrapping = fyne.TextWrapWord
+	message.TextStyle = fyne.TextStyle{Bold: true}
+
+	argsView := CreateMarkdownRichText("```json\n"+indentToolArguments(arguments)+"\n```", DefaultRichTextConfig())
+	argsScroll := container.NewScroll(argsView)
+	argsScroll.SetMinSize(fyne.NewSize(520, 220))
+
+	var d *dialog.CustomDialog
+	answer := func(a toolApproval) func() {
+		return func() {
+			onAnswer(a)
+			d.Hide()
+		}
+	}
+	declineBtn := widget.NewButtonWithIcon(Translate("tool_approval.decline"), theme.CancelIcon(), answer(toolDeclined))
+	sessionBtn := widget.NewButton(Translate("tool_approval.allow_session"), answer(toolAllowedForSession))
+	allowBtn := widget.NewButtonWithIcon(Translate("tool_approval.allow"), theme.ConfirmIcon(), answer(toolAllowedOnce))
+	allowBtn.Importance = widget.HighImportance
+
+	content := container.NewBorder(
+		message,
+		container.NewHBox(declineBtn, layout.NewSpacer(), sessionBtn, allowBtn),
+		nil, nil,
+		argsScroll,
+	)
+
+	d = dialog.NewCustomWithoutButtons(Translate("tool_approval.title"), content, cw.window)
+	d.SetOnClosed(func() { onAnswer(toolDeclined) })
+	d.Show()
+	return d
+}
+
+// indentToolArguments pretty-prints a tool call's JSON arguments, leaving other text as it is
+func indentToolArguments(arguments string) string {
+	var b bytes.Buffer
+	if err := json.Indent(&b, []byte(arguments), "", "  "); err != nil {
+		return arguments
+	}
+	return b.String()
+}
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"chatgo/internal/llm"
+	"chatgo/pkg/models"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// toolArgValueLength limits each argument value in a tool call title
+	toolArgValueLength = 30
+	// toolArgsLength limits the whole argument list in a tool call title
+	toolArgsLength = 80
+)
+
+// toolCallsFromRecords converts the agent's tool call records into persisted tool calls
+func toolCallsFromRecords(records []llm.ToolCallRecord) []models.ToolCall {
+	if len(records) == 0 {
+		return nil
+	}
+
+	calls := make([]models.ToolCall, len(records))
+	for i, record := range records {
+		calls[i] = models.ToolCall{
+			ID:         record.ID,
+			Name:       record.Name,
+			Arguments:  record.Arguments,
+			Result:     record.Result,
+			Error:      record.Error,
+			DurationMs: record.Duration.Milliseconds(),
+		}
+	}
+	return calls
+}
+
+// recordsFromToolCalls converts persisted tool calls back into records, to send them again with
+// the conversation's history
+func recordsFromToolCalls(calls []models.ToolCall) []llm.ToolCallRecord {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	records := make([]llm.ToolCallRecord, len(calls))
+	for i, call := range calls {
+		records[i] = llm.ToolCallRecord{
+			ID:        call.ID,
+			Name:      call.Name,
+			Arguments: call.Arguments,
+			Result:    call.Result,
+			Error:     call.Error,
+			Duration:  time.Duration(call.DurationMs) * time.Millisecond,
+			Done:      true,
+		}
+	}
+	return records
+}
+
+// newToolCallsView shows tool calls as an accordion, collapsed by default
+func newToolCallsView(calls []models.ToolCall) *widget.Accordion {
+	accordion := widget.NewAccordion()
+	accordion.MultiOpen = true
+
+	for _, call := range calls {
+		accordion.Append(widget.NewAccordionItem(toolCallTitle(call), toolCallDetails(call)))
+	}
+
+	return accordion
+}
+
+// toolCallDetails lists a tool call's arguments, result and error
+func toolCallDetails(call models.ToolCall) fyne.CanvasObject {
+	details := container.NewVBox()
+
+	if call.Arguments != "" {
+		argsLabel := widget.NewLabel(Translatef("tool_calls.arguments", call.Arguments))
+		argsLabel.Wrapping = fyne.TextWrapWord
+		argsLabel.TextStyle = fyne.TextStyle{Italic: true}
+		details.Add(argsLabel)
+	}
+
+	if call.Result != "" {
+		resultLabel := widget.NewLabel(Translatef("tool_calls.result", call.Result))
+		resultLabel.Wrapping = fyne.TextWrapWord
+		details.Add(resultLabel)
+	}
+
+	if call.Error != "" {
+		errorLabel := widget.NewLabel(Translatef("tool_calls.error", call.Error))
+		errorLabel.Wrapping = fyne.TextWrapWord
+		errorLabel.Importance = widget.DangerImportance
+		details.Add(errorLabel)
+	}
+
+	return details
+}
+
+// liveToolCalls shows the agent's tool calls while a response is streaming. Each call is
+// added when it starts and updated in place when it returns, so expanded items stay open.
+type liveToolCalls struct {
+	accordion *widget.Accordion
+	ids       []string // Call ID of each accordion item
+}
+
+// newLiveToolCalls creates an empty live tool call view
+func newLiveToolCalls() *liveToolCalls {
+	accordion := widget.NewAccordion()
+	accordion.MultiOpen = true
+	return &liveToolCalls{accordion: accordion}
+}
+
+// update shows the latest state of a call; running calls are marked with an ellipsis
+func (v *liveToolCalls) update(record llm.ToolCallRecord) {
+	call := toolCallsFromRecords([]llm.ToolCallRecord{record})[0]
+	title := toolCallTitle(call)
+	if !record.Done {
+		title += " …"
+	}
+
+	for i, id := range v.ids {
+		if id == record.ID {
+			v.accordion.Items[i].Title = title
+			v.accordion.Items[i].Detail = toolCallDetails(call)
+			v.accordion.Refresh()
+			return
+		}
+	}
+	v.ids = append(v.ids, record.ID)
+	v.accordion.Append(widget.NewAccordionItem(title, toolCallDetails(call)))
+}
+
+// toolCallTitle formats a one-line summary such as "🔧 read_file(path=main.go) → 1.2s"
+func toolCallTitle(call models.ToolCall) string {
+	title := fmt.Sprintf("🔧 %s(%s)", call.Name, formatToolArgs(call.Arguments))
+	if call.DurationMs > 0 {
+		title += fmt.Sprintf(" → %.1fs", (time.Duration(call.DurationMs) * time.Millisecond).Seconds())
+	}
+	if call.Error != "" {
+		title += " ❌"
+	}
+	return title
+}
+
+// formatToolArgs renders JSON object arguments as key=value pairs, truncating long values
+func formatToolArgs(arguments string) string {
+	var args map[string]interface{}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return truncateRunes(strings.TrimSpace(arguments), toolArgsLength)
+	}
+
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value, isString := args[key].(string)
+		if !isString {
+			encoded, _ := json.Marshal(args[key])
+			value = string(encoded)
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, truncateRunes(value, toolArgValueLength)))
+	}
+	return truncateRunes(strings.Join(pairs, ", "), toolArgsLength)
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut with an ellipsis
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"chatgo/internal/llm"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxToolLimitGroups is how many of the largest tool groups the tool limit dialog lists
+const maxToolLimitGroups = 5
+
+// currentProvider returns the configuration of the current conversation's provider
+func (cw *ChatWindow) currentProvider() (config.Provider, bool) {
+	if cw.currentConversation == nil {
+		return config.Provider{}, false
+	}
+	for _, p := range cw.config.Providers {
+		if p.Name == cw.currentConversation.Provider {
+			return p, true
+		}
+	}
+	return config.Provider{}, false
+}
+
+// toolLimit returns the most tools the current provider's model accepts per request, or 0 when unknown
+func (cw *ChatWindow) toolLimit() int {
+	p, ok := cw.currentProvider()
+	if !ok {
+		return 0
+	}
+	return llm.ModelCatalog.ToolLimit(p.Type, p.Model)
+}
+
+// exceedsToolLimit reports whether more tools are selected than the current provider accepts.
+// Tools are only sent in React Agent mode.
+func (cw *ChatWindow) exceedsToolLimit() bool {
+	if !cw.config.UseReactAgent {
+		return false
+	}
+	limit := cw.toolLimit()
+	return limit > 0 && len(cw.toolSelectionMgr.GetSelectedTools()) > limit
+}
+
+// toolSelectionKey identifies a tool selection regardless of order
+func toolSelectionKey(ids []string) string {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "\n")
+}
+
+// showToolLimitDialog is shown instead of sending text when the selected tools exceed the
+// provider's limit. It lists the groups contributing the most tools with quick deselect
+// actions, and can keep only the tools most relevant to text. Sending continues with the
+// resulting selection.
+func (cw *ChatWindow) showToolLimitDialog(text string) {
+	limit := cw.toolLimit()
+	provider, _ := cw.currentProvider()
+
+	// Resolve the selected tools and group them
+	builtinTools, mcpTools := cw.toolSelectionMgr.LoadToolSelections()
+	available := make(map[string]ToolSelection)
+	for _, tool := range builtinTools {
+		available[tool.ID] = tool
+	}
+	for _, tools := range mcpTools {
+		for _, tool := range tools {
+			if tool.Enabled {
+				available[tool.ID] = tool
+			}
+		}
+	}
+
+	var selectedTools []ToolSelection
+	selected := make(map[string]bool)
+	groups := make(map[string][]string)
+	for _, id := range cw.toolSelectionMgr.GetSelectedTools() {
+		tool, ok := available[id]
+		if !ok {
+			continue
+		}
+		selectedTools = append(selectedTools, tool)
+		selected[id] = true
+		groups[tool.Group] = append(groups[tool.Group], id)
+	}
+
+	// Largest groups first
+	groupNames := make([]string, 0, len(groups))
+	for group := range groups {
+		groupNames = append(groupNames, group)
+	}
+	sort.Slice(groupNames, func(i, j int) bool {
+		if len(groups[groupNames[i]]) != len(groups[groupNames[j]]) {
+			return len(groups[groupNames[i]]) > len(groups[groupNames[j]])
+		}
+		return groupNames[i] < groupNames[j]
+	})
+	if len(groupNames) > maxToolLimitGroups {
+		groupNames = groupNames[:maxToolLimitGroups]
+	}
+
+	// remaining returns the tools still selected, in their original order
+	remaining := func() []ToolSelection {
+		tools := make([]ToolSelection, 0, len(selected))
+		for _, tool := range selectedTools {
+			if selected[tool.ID] {
+				tools = append(tools, tool)
+			}
+		}
+		return tools
+	}
+
+	var d *dialog.CustomDialog
+
+	// send applies the selection and sends text; the agent is rebuilt with the new tools
+	send := func(tools []ToolSelection) {
+		ids := make([]string, len(tools))
+		for i, tool := range tools {
+			ids[i] = tool.ID
+		}
+		d.Hide()
+		cw.toolSelectionMgr.SetSelectedTools(ids)
+		cw.submitMessage(text)
+	}
+
+	countLabel := widget.NewLabel("")
+	sendBtn := widget.NewButton("", func() {
+		send(remaining())
+	})
+	trimBtn := widget.NewButton(Translatef("tool_limit.trim", limit), func() {
+		ranked := rankToolsByRelevance(remaining(), text)
+		send(ranked[:min(limit, len(ranked))])
+	})
+	trimBtn.Importance = widget.HighImportance
+
+	updateCount := func() {
+		countLabel.SetText(Translatef("tool_limit.count", len(selected), limit))
+		if len(selected) > limit {
+			countLabel.Importance = widget.WarningImportance
+			sendBtn.SetText(Translate("tool_limit.send_anyway"))
+			trimBtn.Enable()
+		} else {
+			countLabel.Importance = widget.SuccessImportance
+			sendBtn.SetText(Translate("chat.send"))
+			trimBtn.Disable()
+		}
+		countLabel.Refresh()
+	}
+
+	rows := container.NewVBox()
+	for _, group := range groupNames {
+		ids := groups[group]
+		var deselectBtn *widget.Button
+		deselectBtn = widget.NewButton(Translate("tool_limit.deselect"), func() {
+			for _, id := range ids {
+				delete(selected, id)
+			}
+			deselectBtn.Disable()
+			updateCount()
+		})
+		label := widget.NewLabel(Translatef("tool_limit.group", group, len(ids)))
+		label.Truncation = fyne.TextTruncateEllipsis
+		rows.Add(container.NewBorder(nil, nil, nil, deselectBtn, label))
+	}
+
+	message := widget.NewLabel(Translatef("tool_limit.message",
+		len(selectedTools), provider.Name, provider.Model, limit))
+	message.Wrapping = fyne.TextWrapWord
+
+	groupsLabel := widget.NewLabel(Translate("tool_limit.groups"))
+	groupsLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	content := container.NewVBox(
+		message,
+		widget.NewSeparator(),
+		groupsLabel,
+		rows,
+		widget.NewSeparator(),
+		countLabel,
+	)
+
+	cancelBtn := widget.NewButton(Translate("common.cancel"), func() {
+		d.Hide()
+	})
+	d = dialog.NewCustomWithoutButtons(Translate("tool_limit.title"), content, cw.window)
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, sendBtn, trimBtn})
+	updateCount()
+	d.Resize(fyne.NewSize(560, 0))
+	d.Show()
+}
+
+// rankToolsByRelevance orders tools by simple keyword matching against message: words of the
+// message found in a tool's name or description, and parts of the tool's name found in the
+// message, which count double. Tools with the same score keep their order.
+func rankToolsByRelevance(tools []ToolSelection, message string) []ToolSelection {
+	message = strings.ToLower(message)
+	words := keywords(message)
+
+	scores := make(map[string]int, len(tools))
+	for _, tool := range tools {
+		text := strings.ToLower(tool.DisplayName + " " + tool.Description)
+		score := 0
+		for _, word := range words {
+			if strings.Contains(text, word) {
+				score++
+			}
+		}
+		for _, part := range keywords(tool.DisplayName) {
+			if strings.Contains(message, part) {
+				score += 2
+			}
+		}
+		scores[tool.ID] = score
+	}
+
+	ranked := append([]ToolSelection(nil), tools...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return scores[ranked[i].ID] > scores[ranked[j].ID]
+	})
+	return ranked
+}
+
+// keywords splits text into lowercase words of at least three characters
+func keywords(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := fields[:0]
+	for _, field := range fields {
+		if utf8.RuneCountInString(field) >= 3 {
+			words = append(words, field)
+		}
+	}
+	return words
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"chatgo/internal/config"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// builtinToolDescription returns a built-in tool type's description in the UI language
+func builtinToolDescription(toolType string) string {
+	key := "tool_description." + toolType
+	if description := Translate(key); description != key {
+		return description
+	}
+	return config.GetBuiltinToolDescription(toolType)
+}
+
+// toolDisplay returns the name and description the tool with selection ID id is shown with. A
+// name the user gave is followed by the tool's own, which is what models are sent.
+func toolDisplay(cfg *config.Config, id, name, description string) (string, string) {
+	override, ok := cfg.ToolOverride(id)
+	if !ok {
+		return name, description
+	}
+	if override.DisplayName != "" {
+		name = fmt.Sprintf("%s (%s)", override.DisplayName, name)
+	}
+	if override.Description != "" {
+		description = override.Description
+	}
+	return name, description
+}
+
+// showToolOverrideDialog edits the name and description the tool with selection ID id is shown
+// with in place of its own name and description. onSaved is called once the override is saved.
+func (cw *ChatWindow) showToolOverrideDialog(parent fyne.Window, id, name, description string, onSaved func()) {
+	override, _ := cw.config.ToolOverride(id)
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder(name)
+	nameEntry.SetText(override.DisplayName)
+	descriptionEntry := widget.NewMultiLineEntry()
+	descriptionEntry.Wrapping = fyne.TextWrapWord
+	descriptionEntry.SetMinRowsVisible(4)
+	descriptionEntry.SetPlaceHolder(description)
+	descriptionEntry.SetText(override.Description)
+
+	hint := widget.NewLabel(Translate("tools.override_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	content := container.NewVBox(
+		newFormGrid(
+			newFormLabel(Translate("tools.override_tool")), widget.NewLabel(name),
+			newFormLabel(Translate("tools.override_name")), nameEntry,
+			newFormLabel(Translate("tools.override_description")), descriptionEntry,
+		),
+		hint,
+	)
+	d := dialog.NewCustomConfirm(Translate("tools.override_title"), Translate("common.save"), Translate("common.cancel"), content, func(save bool) {
+		if !save {
+			return
+		}
+		cw.config.SetToolOverride(config.ToolOverride{Tool: id, DisplayName: nameEntry.Text, Description: descriptionEntry.Text})
+		if err := config.SaveConfig(cw.config); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save config: %w", err), parent)
+			return
+		}
+		cw.toolSelectionMgr.RefreshToolCheckGroup()
+		if onSaved != nil {
+			onSaved()
+		}
+	}, parent)
+	d.Resize(fyne.NewSize(520, d.MinSize().Height))
+	d.Show()
+}
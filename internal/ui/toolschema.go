@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxToolRowDescription is how many characters of a tool's description its row in the tool
+// selection tree shows, about two lines; the rest is in the detail pane
+const maxToolRowDescription = 140
+
+// maxSchemaDepth is how deeply nested object properties are listed in the detail pane
+const maxSchemaDepth = 4
+
+// shortDescription returns description on one paragraph, cut to maxToolRowDescription
+// characters, and whether it was cut
+func shortDescription(description string) (string, bool) {
+	short := strings.Join(strings.Fields(description), " ")
+	if runes := []rune(short); len(runes) > maxToolRowDescription {
+		return strings.TrimSpace(string(runes[:maxToolRowDescription])) + "…", true
+	}
+	return short, short != strings.TrimSpace(description)
+}
+
+// schemaObject returns a tool's input schema as decoded JSON, or nil when it has none
+func schemaObject(schema any) map[string]any {
+	if schema == nil {
+		return nil
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil
+	}
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil
+	}
+	return object
+}
+
+// newSchemaView lists the properties of a JSON schema, each a collapsible item with its type,
+// whether it is required, its description and, for objects and arrays of objects, its own
+// properties
+func newSchemaView(schema map[string]any) fyne.CanvasObject {
+	properties, _ := schema["properties"].(map[string]any)
+	if len(properties) == 0 {
+		label := widget.NewLabel(Translate("tools.schema_no_parameters"))
+		label.Importance = widget.LowImportance
+		return label
+	}
+	return newSchemaProperties(schema, 0)
+}
+
+// newSchemaProperties creates the accordion listing schema's properties, required ones first
+func newSchemaProperties(schema map[string]any, depth int) *widget.Accordion {
+	properties, _ := schema["properties"].(map[string]any)
+	var required []string
+	if list, ok := schema["required"].([]any); ok {
+		for _, name := range list {
+			if name, ok := name.(string); ok {
+				required = append(required, name)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		ri, rj := slices.Contains(required, names[i]), slices.Contains(required, names[j])
+		if ri != rj {
+			return ri
+		}
+		return names[i] < names[j]
+	})
+
+	accordion := widget.NewAccordion()
+	for _, name := range names {
+		property, _ := properties[name].(map[string]any)
+		title := fmt.Sprintf("%s: %s", name, schemaType(property))
+		if slices.Contains(required, name) {
+			title += " *"
+		}
+		accordion.Append(widget.NewAccordionItem(title, newSchemaPropertyDetail(property, depth)))
+	}
+	return accordion
+}
+
+// newSchemaPropertyDetail shows a property's description, allowed values and nested properties
+func newSchemaPropertyDetail(property map[string]any, depth int) fyne.CanvasObject {
+	detail := container.NewVBox()
+	if description, _ := property["description"].(string); strings.TrimSpace(description) != "" {
+		label := widget.NewLabel(description)
+		label.Wrapping = fyne.TextWrapWord
+		detail.Add(label)
+	}
+	if values, ok := property["enum"].([]any); ok && len(values) > 0 {
+		options := make([]string, len(values))
+		for i, value := range values {
+			options[i] = fmt.Sprint(value)
+		}
+		label := widget.NewLabel(Translatef("tools.schema_values", strings.Join(options, ", ")))
+		label.Wrapping = fyne.TextWrapWord
+		label.Importance = widget.LowImportance
+		detail.Add(label)
+	}
+
+	nested := property
+	if items, ok := property["items"].(map[string]any); ok {
+		nested = items
+	}
+	if nestedProperties, ok := nested["properties"].(map[string]any); ok && len(nestedProperties) > 0 {
+		if depth+1 < maxSchemaDepth {
+			detail.Add(container.NewPadded(newSchemaProperties(nested, depth+1)))
+		} else {
+			label := widget.NewLabel(Translatef("tools.schema_more_properties", len(nestedProperties)))
+			label.Importance = widget.LowImportance
+			detail.Add(label)
+		}
+	}
+
+	if len(detail.Objects) == 0 {
+		label := widget.NewLabel(Translate("tools.schema_no_description"))
+		label.Importance = widget.LowImportance
+		detail.Add(label)
+	}
+	return detail
+}
+
+// schemaType describes a property's type, such as "string" or "array of integer"
+func schemaType(property map[string]any) string {
+	var kind string
+	switch t := property["type"].(type) {
+	case string:
+		kind = t
+	case []any:
+		kinds := make([]string, 0, len(t))
+		for _, k := range t {
+			kinds = append(kinds, fmt.Sprint(k))
+		}
+		kind = strings.Join(kinds, " | ")
+	}
+	if kind == "" {
+		kind = "any"
+	}
+	if items, ok := property["items"].(map[string]any); ok && kind == "array" {
+		kind += " of " + schemaType(items)
+	}
+	return kind
+}
@@ -3,6 +3,7 @@ package ui
 
 import (
 	"chatgo/internal/config"
+	"chatgo/internal/mcp"
 	"fmt"
 	"strings"
 
@@ -21,6 +22,7 @@ type ToolSelection struct {
 	Type        string // "builtin" or "mcp"
 	Enabled     bool   // Whether the tool is available
 	Description string // Tool description
+	Schema      any    // Input schema sent to the model, when known
 }
 
 // ToolSelectionManager manages tool selection UI and state
@@ -30,6 +32,8 @@ type ToolSelectionManager struct {
 	config     *config.Config
 	mcpManager *MCPManagerWrapper
 	window     fyne.Window
+	toolLimit  func() int // Most tools the current provider accepts per request; 0 when unknown
+	onChanged  func()     // Called after the selection or the button is updated
 }
 
 // NewToolSelectionManager creates a new tool selection manager
@@ -49,13 +53,15 @@ func (tm *ToolSelectionManager) LoadToolSelections() (builtinTools []ToolSelecti
 	// Add enabled built-in tools
 	for _, tool := range tm.config.BuiltinTools {
 		if tool.Enabled {
+			id := fmt.Sprintf("builtin:%s", tool.Name)
+			name, description := toolDisplay(tm.config, id, tool.Name, builtinToolDescription(tool.Type))
 			builtinTools = append(builtinTools, ToolSelection{
-				ID:          fmt.Sprintf("builtin:%s", tool.Name),
-				DisplayName: tool.Name,
+				ID:          id,
+				DisplayName: name,
 				Group:       "Built-in",
 				Type:        "builtin",
 				Enabled:     true,
-				Description: config.GetBuiltinToolDescription(tool.Type),
+				Description: description,
 			})
 		}
 	}
@@ -68,17 +74,21 @@ func (tm *ToolSelectionManager) LoadToolSelections() (builtinTools []ToolSelecti
 		}
 
 		// Check if server is initialized
-		status, ok := tm.mcpManager.manager.GetServerStatus(server.Name)
+		status, ok := tm.mcpManager.GetServerStatus(server.Name)
 		if ok && status.Status == "initialized" && len(status.Tools) > 0 {
 			serverTools := []ToolSelection{}
 			for _, tool := range status.Tools {
+				// Overrides are looked up by ID, so they apply again whenever the server reinitializes
+				id := fmt.Sprintf("mcp:%s:%s", server.Name, tool.Name)
+				name, description := toolDisplay(tm.config, id, tool.Name, tool.Description)
 				serverTools = append(serverTools, ToolSelection{
-					ID:          fmt.Sprintf("mcp:%s:%s", server.Name, tool.Name),
-					DisplayName: tool.Name,
+					ID:          id,
+					DisplayName: name,
 					Group:       fmt.Sprintf("MCP [%s] - %s", serverType, server.Name),
 					Type:        "mcp",
 					Enabled:     true,
-					Description: tool.Description,
+					Description: description,
+					Schema:      tool.InputSchema["inputSchema"],
 				})
 			}
 			mcpTools[fmt.Sprintf("MCP [%s] - %s", serverType, server.Name)] = serverTools
@@ -87,11 +97,11 @@ func (tm *ToolSelectionManager) LoadToolSelections() (builtinTools []ToolSelecti
 			mcpTools[fmt.Sprintf("MCP [%s] - %s", serverType, server.Name)] = []ToolSelection{
 				{
 					ID:          fmt.Sprintf("mcp:%s:uninitialized", server.Name),
-					DisplayName: fmt.Sprintf("(未初始化) %s", server.Name),
+					DisplayName: Translatef("tools.uninitialized_server", server.Name),
 					Group:       fmt.Sprintf("MCP [%s] - %s", serverType, server.Name),
 					Type:        "mcp",
 					Enabled:     false,
-					Description: "请先在设置中初始化此服务器",
+					Description: Translate("tools.initialize_in_settings"),
 				},
 			}
 		}
@@ -144,16 +154,59 @@ func (tm *ToolSelectionManager) SetButton(button *widget.Button) {
 	tm.button = button
 }
 
-// UpdateToolSelectButton updates the tool selection button text
+// SetToolLimit sets the function returning the current provider's tool limit
+func (tm *ToolSelectionManager) SetToolLimit(toolLimit func() int) {
+	tm.toolLimit = toolLimit
+}
+
+// SetOnChanged sets the function called whenever the selection or the button is updated
+func (tm *ToolSelectionManager) SetOnChanged(onChanged func()) {
+	tm.onChanged = onChanged
+}
+
+// limit returns the current provider's tool limit, or 0 when unknown or no tools are sent
+func (tm *ToolSelectionManager) limit() int {
+	if tm.toolLimit == nil || !tm.config.UseReactAgent {
+		return 0
+	}
+	return tm.toolLimit()
+}
+
+// UpdateToolSelectButton updates the tool selection button text, including the provider's
+// tool limit when it is known
 func (tm *ToolSelectionManager) UpdateToolSelectButton(count int) {
 	if tm.button == nil {
 		return
 	}
 
-	if count == 0 {
-		tm.button.SetText("选择工具 (0)")
+	if limit := tm.limit(); limit > 0 {
+		tm.button.SetText(Translatef("tools.select_count_limit", count, limit))
 	} else {
-		tm.button.SetText(fmt.Sprintf("选择工具 (%d)", count))
+		tm.button.SetText(Translatef("tools.select_count", count))
+	}
+	if tm.onChanged != nil {
+		tm.onChanged()
+	}
+}
+
+// RefreshButton updates the button text for the current selection and provider
+func (tm *ToolSelectionManager) RefreshButton() {
+	tm.UpdateToolSelectButton(len(tm.GetSelectedTools()))
+}
+
+// sendSummary describes how many tools the next request carries
+func (tm *ToolSelectionManager) sendSummary(count int) (text string, overLimit bool) {
+	if !tm.config.UseReactAgent {
+		return Translate("tools.agent_off"), false
+	}
+	limit := tm.limit()
+	switch {
+	case limit == 0:
+		return Translatef("tools.send_count", count), false
+	case count > limit:
+		return Translatef("tools.send_count_over_limit", count, limit), true
+	default:
+		return Translatef("tools.send_count_limit", count, limit), false
 	}
 }
 
@@ -165,6 +218,15 @@ func (tm *ToolSelectionManager) GetSelectedTools() []string {
 	return tm.checkGroup.Selected
 }
 
+// SetSelectedTools replaces the selection with the given tool IDs
+func (tm *ToolSelectionManager) SetSelectedTools(ids []string) {
+	if tm.checkGroup == nil {
+		return
+	}
+	tm.checkGroup.SetSelected(ids)
+	tm.UpdateToolSelectButton(len(ids))
+}
+
 // RefreshToolCheckGroup refreshes the tool check group with current configuration
 func (tm *ToolSelectionManager) RefreshToolCheckGroup() {
 	if tm.checkGroup == nil {
@@ -196,13 +258,19 @@ func (tm *ToolSelectionManager) RefreshToolCheckGroup() {
 		}
 	}
 
+	// Tools that weren't offered before, e.g. from a server that just connected, are selected by default
+	previousOptions := make(map[string]bool)
+	for _, option := range tm.checkGroup.Options {
+		previousOptions[option] = true
+	}
+
 	// Update options
 	tm.checkGroup.Options = newToolOptions
 
 	// Restore selections that still exist
 	validSelections := []string{}
 	for _, option := range newToolOptions {
-		if currentSelectionsMap[option] {
+		if currentSelectionsMap[option] || !previousOptions[option] {
 			validSelections = append(validSelections, option)
 		}
 	}
@@ -229,8 +297,6 @@ func (tm *ToolSelectionManager) ShowToolSelectionDialog() {
 	// Load tools by group
 	builtinTools, mcpTools := tm.LoadToolSelections()
 
-	fmt.Printf("[DEBUG] ShowToolSelectionDialog: builtinTools=%d, mcpTools=%d\n", len(builtinTools), len(mcpTools))
-
 	// Build tree data structure
 	// Root node ID: "root"
 	// Group nodes: "group:Built-in", "group:MCP [stdio] - server1", etc.
@@ -248,33 +314,32 @@ func (tm *ToolSelectionManager) ShowToolSelectionDialog() {
 	// We need to declare tree variable first so callbacks can reference it
 	var tree *widget.Tree
 
+	// Shows how many tools will actually be sent, counting only tools that are still available
+	countLabel := widget.NewLabel("")
+	countLabel.Wrapping = fyne.TextWrapWord
+	var updateCount func()
+
 	childUIDs := func(uid widget.TreeNodeID) []widget.TreeNodeID {
 		uidStr := string(uid)
-		fmt.Printf("[DEBUG] childUIDs called for: %s\n", uidStr)
 
 		if uidStr == "root" {
 			// Return group IDs
 			groups := []widget.TreeNodeID{}
 			if len(builtinTools) > 0 {
 				groups = append(groups, "group:Built-in")
-				fmt.Printf("[DEBUG] Adding Built-in group\n")
 			}
 			for groupName := range mcpTools {
 				groups = append(groups, widget.TreeNodeID("group:"+groupName))
-				fmt.Printf("[DEBUG] Adding MCP group: %s\n", groupName)
 			}
-			fmt.Printf("[DEBUG] Root has %d children\n", len(groups))
 			return groups
 		}
 
 		node, ok := treeData[uidStr]
 		if !ok {
-			fmt.Printf("[DEBUG] Node not found: %s\n", uidStr)
 			return []widget.TreeNodeID{}
 		}
 
 		if !node.IsBranch {
-			fmt.Printf("[DEBUG] Node is not a branch: %s\n", uidStr)
 			return []widget.TreeNodeID{}
 		}
 
@@ -282,60 +347,65 @@ func (tm *ToolSelectionManager) ShowToolSelectionDialog() {
 		for i, child := range node.Children {
 			result[i] = widget.TreeNodeID(child)
 		}
-		fmt.Printf("[DEBUG] Branch %s has %d children\n", uidStr, len(result))
 		return result
 	}
 
-	// Create root
-	treeData["root"] = &ToolNode{
-		ID:       "root",
-		IsBranch: true,
-		Children: []string{},
-	}
-
-	// Create built-in tools group
-	builtinGroupID := "group:Built-in"
-	builtinToolIDs := []string{}
-	for _, tool := range builtinTools {
-		builtinToolIDs = append(builtinToolIDs, tool.ID)
-		treeData[tool.ID] = &ToolNode{
-			ID:       tool.ID,
-			IsBranch: false,
-			Tool:     &tool,
-			Children: []string{},
+	// buildTree fills treeData from the current tool lists
+	buildTree := func() {
+		for id := range treeData {
+			delete(treeData, id)
 		}
-		fmt.Printf("[DEBUG] Added builtin tool: %s\n", tool.ID)
-	}
-	if len(builtinToolIDs) > 0 {
-		treeData[builtinGroupID] = &ToolNode{
-			ID:       builtinGroupID,
+
+		// Create root
+		treeData["root"] = &ToolNode{
+			ID:       "root",
 			IsBranch: true,
-			Children: builtinToolIDs,
+			Children: []string{},
 		}
-		treeData["root"].Children = append(treeData["root"].Children, builtinGroupID)
-	}
 
-	// Create MCP server groups
-	for groupName, tools := range mcpTools {
-		groupID := "group:" + groupName
-		toolIDs := []string{}
-		for _, tool := range tools {
-			toolIDs = append(toolIDs, tool.ID)
+		// Create built-in tools group
+		builtinGroupID := "group:Built-in"
+		builtinToolIDs := []string{}
+		for _, tool := range builtinTools {
+			builtinToolIDs = append(builtinToolIDs, tool.ID)
 			treeData[tool.ID] = &ToolNode{
 				ID:       tool.ID,
 				IsBranch: false,
 				Tool:     &tool,
 				Children: []string{},
 			}
-			fmt.Printf("[DEBUG] Added MCP tool: %s\n", tool.ID)
 		}
-		treeData[groupID] = &ToolNode{
-			ID:       groupID,
-			IsBranch: true,
-			Children: toolIDs,
+		if len(builtinToolIDs) > 0 {
+			treeData[builtinGroupID] = &ToolNode{
+				ID:       builtinGroupID,
+				IsBranch: true,
+				Children: builtinToolIDs,
+			}
+			treeData["root"].Children = append(treeData["root"].Children, builtinGroupID)
+		}
+
+		// Create MCP server groups
+		for groupName, tools := range mcpTools {
+			groupID := "group:" + groupName
+			toolIDs := []string{}
+			for _, tool := range tools {
+				toolIDs = append(toolIDs, tool.ID)
+				treeData[tool.ID] = &ToolNode{
+					ID:       tool.ID,
+					IsBranch: false,
+					Tool:     &tool,
+					Children: []string{},
+				}
+			}
+			treeData[groupID] = &ToolNode{
+				ID:       groupID,
+				IsBranch: true,
+				Children: toolIDs,
+			}
+			treeData["root"].Children = append(treeData["root"].Children, groupID)
 		}
-		treeData["root"].Children = append(treeData["root"].Children, groupID)
 	}
+	buildTree()
 
 	isBranch := func(uid widget.TreeNodeID) bool {
 		if node, ok := treeData[string(uid)]; ok {
@@ -353,15 +423,19 @@ func (tm *ToolSelectionManager) ShowToolSelectionDialog() {
 			label.TextStyle = fyne.TextStyle{Bold: true}
 			return container.NewBorder(nil, nil, check, label, layout.NewSpacer())
 		} else {
-			// Tool node: checkbox + label + description
+			// Tool node: checkbox + label + a button showing the details of a long description +
+			// the description cut to about two lines
 			check := widget.NewCheck("", nil)
 			nameLabel := widget.NewLabel("")
 			nameLabel.TextStyle = fyne.TextStyle{Bold: true}
-			descLabel := widget.NewLabel("")
+			moreBtn := widget.NewButton(Translate("tools.more"), nil)
+			moreBtn.Importance = widget.LowImportance
+			// Two lines of placeholder text give every row the height of a two-line description
+			descLabel := widget.NewLabel("\n")
 			descLabel.Wrapping = fyne.TextWrapWord
 			descLabel.TextStyle = fyne.TextStyle{Italic: true}
 			return container.NewVBox(
-				container.NewHBox(check, nameLabel),
+				container.NewHBox(check, nameLabel, moreBtn),
 				container.NewPadded(descLabel),
 			)
 		}
@@ -400,7 +474,11 @@ func (tm *ToolSelectionManager) ShowToolSelectionDialog() {
 				}
 			}
 
-			label.SetText(fmt.Sprintf("%s (%d/%d)", groupName, selectedCount, totalCount))
+			displayName := groupName
+			if groupName == "Built-in" {
+				displayName = Translate("tools.builtin_group")
+			}
+			label.SetText(fmt.Sprintf("%s (%d/%d)", displayName, selectedCount, totalCount))
 
 			// Update checkbox state
 			allSelected := selectedCount > 0 && selectedCount == totalCount
@@ -428,6 +506,7 @@ func (tm *ToolSelectionManager) ShowToolSelectionDialog() {
 				}
 				// Refresh the tree
 				tree.Refresh()
+				updateCount()
 			}
 		} else {
 			// Tool node
@@ -441,17 +520,24 @@ func (tm *ToolSelectionManager) ShowToolSelectionDialog() {
 			checkContainer := vbox.Objects[0].(*fyne.Container)
 			check := checkContainer.Objects[0].(*widget.Check)
 			nameLabel := checkContainer.Objects[1].(*widget.Label)
+			moreBtn := checkContainer.Objects[2].(*widget.Button)
+			descLabel := vbox.Objects[1].(*fyne.Container).Objects[0].(*widget.Label)
 
 			nameLabel.SetText(tool.DisplayName)
+			description, cut := shortDescription(tool.Description)
+			descLabel.SetText(description)
+			if cut {
+				moreBtn.OnTapped = func() {
+					tree.Select(uid)
+				}
+				moreBtn.Show()
+			} else {
+				moreBtn.Hide()
+			}
 
 			if !tool.Enabled {
 				check.Hide()
 				nameLabel.TextStyle = fyne.TextStyle{Italic: true}
-				if len(vbox.Objects) > 1 {
-					pad := vbox.Objects[1].(*fyne.Container)
-					descLabel := pad.Objects[0].(*widget.Label)
-					descLabel.SetText(tool.Description)
-				}
 				return
 			}
 
@@ -469,13 +555,7 @@ func (tm *ToolSelectionManager) ShowToolSelectionDialog() {
 				}
 				// Refresh parent group to update counts
 				tree.RefreshItem(widget.TreeNodeID("group:" + tool.Group))
-			}
-
-			// Update description
-			if len(vbox.Objects) > 1 {
-				pad := vbox.Objects[1].(*fyne.Container)
-				descLabel := pad.Objects[0].(*widget.Label)
-				descLabel.SetText(tool.Description)
+				updateCount()
 			}
 		}
 	}
@@ -497,35 +577,84 @@ func (tm *ToolSelectionManager) ShowToolSelectionDialog() {
 		tree.OpenBranch("group:" + groupName)
 	}
 
-	// Debug: print tree structure
-	fmt.Printf("Tool Selection Dialog - Built-in tools: %d, MCP groups: %d\n", len(builtinTools), len(mcpTools))
-	fmt.Printf("Root children: %v\n", childUIDs("root"))
-	fmt.Printf("Total tree nodes: %d\n", len(treeData))
+	logger.Debug("Showing tool selection", "builtin", len(builtinTools), "mcp_servers", len(mcpTools), "nodes", len(treeData))
 
-	// Create scroll container for tree with proper sizing
-	treeScroll := container.NewScroll(tree)
-	treeScroll.SetMinSize(fyne.NewSize(500, 350))
+	updateCount = func() {
+		count := 0
+		for sel := range currentSelections {
+			if node, ok := treeData[sel]; ok && node.Tool != nil && node.Tool.Enabled {
+				count++
+			}
+		}
+		text, overLimit := tm.sendSummary(count)
+		countLabel.SetText(text)
+		if overLimit {
+			countLabel.Importance = widget.WarningImportance
+		} else {
+			countLabel.Importance = widget.LowImportance
+		}
+		countLabel.Refresh()
+	}
+	updateCount()
+
+	// Detail pane with the full description, input schema and settings of the selected tool
+	detail := container.NewVBox()
+	showDetailPlaceholder := func() {
+		placeholder := widget.NewLabel(Translate("tools.detail_placeholder"))
+		placeholder.Importance = widget.LowImportance
+		placeholder.Wrapping = fyne.TextWrapWord
+		detail.Objects = []fyne.CanvasObject{placeholder}
+		detail.Refresh()
+	}
+	showDetailPlaceholder()
+	tree.OnSelected = func(uid widget.TreeNodeID) {
+		node := treeData[string(uid)]
+		if node == nil || node.Tool == nil {
+			showDetailPlaceholder()
+			return
+		}
+		detail.Objects = []fyne.CanvasObject{tm.newToolDetail(node.Tool)}
+		detail.Refresh()
+	}
 
 	// Create content with proper layout using Border to ensure tree fills space
-	titleLabel := widget.NewLabel("选择要使用的工具:")
+	titleLabel := widget.NewLabel(Translate("tools.choose"))
 	titleLabel.TextStyle = fyne.TextStyle{Bold: true}
 
-	// Use Border layout: title on top, tree fills the rest
+	// Use Border layout: title on top, tool count at the bottom, tree fills the rest
 	content := container.NewBorder(
 		container.NewVBox(titleLabel, widget.NewSeparator()), // top
-		nil,           // bottom
-		nil,           // left
-		nil,           // right
-		tree,    // center (fills remaining space)
+		container.NewVBox(widget.NewSeparator(), countLabel), // bottom
+		nil,                        // left
+		nil,                        // right
+		newToolSplit(tree, detail), // center (fills remaining space)
 	)
 
+	// Rebuild the tree when MCP servers connect, drop or reconnect while the dialog is open
+	unsubscribe := tm.mcpManager.Subscribe(func(string, *mcp.MCPServerStatus) {
+		fyne.Do(func() {
+			builtinTools, mcpTools = tm.LoadToolSelections()
+			buildTree()
+			for groupName := range mcpTools {
+				tree.OpenBranch("group:" + groupName)
+			}
+			tree.UnselectAll()
+			showDetailPlaceholder()
+			tree.Refresh()
+			updateCount()
+		})
+	})
+
 	// Show dialog
-	d := dialog.NewCustomConfirm("选择工具", "确定", "取消", content, func(confirmed bool) {
+	d := dialog.NewCustomConfirm(Translate("tools.select"), Translate("common.ok"), Translate("common.cancel"), content, func(confirmed bool) {
+		unsubscribe()
 		if confirmed {
-			// Convert selections to list
+			// Convert selections to list, skipping tools whose server dropped while the dialog was open
 			selections := make([]string, 0, len(currentSelections))
 			for sel := range currentSelections {
-				selections = append(selections, sel)
+				if node, ok := treeData[sel]; ok && node.Tool != nil && node.Tool.Enabled {
+					selections = append(selections, sel)
+				}
 			}
 			// Update the tool check group with selections
 			tm.checkGroup.SetSelected(selections)
@@ -537,7 +666,7 @@ func (tm *ToolSelectionManager) ShowToolSelectionDialog() {
 	}, tm.window)
 
 	// Resize dialog to ensure content is visible
-	d.Resize(fyne.NewSize(550, 450))
+	d.Resize(fyne.NewSize(860, 520))
 
 	// Show dialog and refresh to ensure proper rendering
 	d.Show()
@@ -545,3 +674,47 @@ func (tm *ToolSelectionManager) ShowToolSelectionDialog() {
 	// Force a refresh after showing to ensure tree renders
 	tree.Refresh()
 }
+
+// newToolSplit places the tool tree beside the detail pane of the selected tool
+func newToolSplit(tree *widget.Tree, detail *fyne.Container) *container.Split {
+	split := container.NewHSplit(tree, container.NewVScroll(container.NewPadded(detail)))
+	split.SetOffset(0.55)
+	return split
+}
+
+// newToolDetail shows a tool's full description, its input schema and the settings kept for it
+func (tm *ToolSelectionManager) newToolDetail(tool *ToolSelection) fyne.CanvasObject {
+	name := widget.NewLabel(tool.DisplayName)
+	name.TextStyle = fyne.TextStyle{Bold: true}
+	name.Wrapping = fyne.TextWrapBreak
+	id := widget.NewLabel(tool.ID)
+	id.Importance = widget.LowImportance
+	id.Wrapping = fyne.TextWrapBreak
+	description := widget.NewLabel(tool.Description)
+	description.Wrapping = fyne.TextWrapWord
+
+	content := container.NewVBox(name, id, description)
+
+	if schema := schemaObject(tool.Schema); schema != nil {
+		content.Add(settingsSection(Translate("tools.schema"), newSchemaView(schema)))
+	}
+
+	// Settings of built-in tools, which are kept in the config by name
+	if tool.Type == "builtin" {
+		for i := range tm.config.BuiltinTools {
+			builtin := &tm.config.BuiltinTools[i]
+			if "builtin:"+builtin.Name != tool.ID {
+				continue
+			}
+			approvalCheck := widget.NewCheck(Translate("settings.ask_before_run"), func(checked bool) {
+				builtin.RequireApproval = &checked
+				if err := config.SaveConfig(tm.config); err != nil {
+					dialog.ShowError(fmt.Errorf("failed to save config: %w", err), tm.window)
+				}
+			})
+			approvalCheck.Checked = builtin.NeedsApproval()
+			content.Add(settingsSection(Translate("tools.settings"), approvalCheck))
+		}
+	}
+	return content
+}
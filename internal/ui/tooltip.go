@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// newTooltipLayer creates the layer laid over the window content that shows tooltips. It is
+// part of the content rather than an overlay so it doesn't take the pointer from the widget
+// the tooltip belongs to.
+func newTooltipLayer() *fyne.Container {
+	return container.NewWithoutLayout()
+}
+
+// showTooltip shows text just below the object at the given absolute position and size,
+// kept inside the window
+func (cw *ChatWindow) showTooltip(text string, pos fyne.Position, size fyne.Size) {
+	background := canvas.NewRectangle(theme.Color(theme.ColorNameOverlayBackground))
+	background.CornerRadius = theme.InputRadiusSize()
+	background.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	background.StrokeWidth = 1
+	tip := container.NewStack(background, widget.NewLabel(text))
+
+	layerPos := fyne.CurrentApp().Driver().AbsolutePositionForObject(cw.tooltipLayer)
+	tipSize := tip.MinSize()
+	x := min(pos.X-layerPos.X, cw.tooltipLayer.Size().Width-tipSize.Width)
+	y := pos.Y - layerPos.Y + size.Height
+	if y+tipSize.Height > cw.tooltipLayer.Size().Height {
+		// No room below, so show it above
+		y = pos.Y - layerPos.Y - tipSize.Height
+	}
+	tip.Move(fyne.NewPos(max(x, 0), max(y, 0)))
+	tip.Resize(tipSize)
+
+	cw.tooltipLayer.Objects = []fyne.CanvasObject{tip}
+	cw.tooltipLayer.Refresh()
+}
+
+// hideTooltip removes the tooltip shown, if any
+func (cw *ChatWindow) hideTooltip() {
+	cw.tooltipLayer.RemoveAll()
+}
+
+// tooltipArea shows a tooltip while the pointer is over its content
+type tooltipArea struct {
+	widget.BaseWidget
+	content fyne.CanvasObject
+	text    string
+	cw      *ChatWindow
+}
+
+// newTooltipArea wraps content so hovering it shows text as a tooltip
+func (cw *ChatWindow) newTooltipArea(content fyne.CanvasObject, text string) *tooltipArea {
+	t := &tooltipArea{content: content, text: text, cw: cw}
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+// CreateRenderer shows the content
+func (t *tooltipArea) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(t.content)
+}
+
+// MouseIn implements desktop.Hoverable
+func (t *tooltipArea) MouseIn(*desktop.MouseEvent) {
+	if t.cw.tooltipLayer == nil {
+		return
+	}
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(t)
+	t.cw.showTooltip(t.text, pos, t.Size())
+}
+
+// MouseMoved implements desktop.Hoverable
+func (t *tooltipArea) MouseMoved(*desktop.MouseEvent) {
+}
+
+// MouseOut implements desktop.Hoverable
+func (t *tooltipArea) MouseOut() {
+	if t.cw.tooltipLayer != nil {
+		t.cw.hideTooltip()
+	}
+}
+
+// tooltipSelect is a Select that shows a tooltip while hovered. A tooltipArea can't be used
+// as the Select takes the pointer events itself.
+type tooltipSelect struct {
+	widget.Select
+	cw   *ChatWindow
+	text func() string // The tooltip, looked up on hover; empty shows none
+}
+
+// newTooltipSelect creates a Select showing the tooltip returned by text while hovered
+func (cw *ChatWindow) newTooltipSelect(options []string, changed func(string), text func() string) *tooltipSelect {
+	s := &tooltipSelect{cw: cw, text: text}
+	s.Options = options
+	s.OnChanged = changed
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+// MouseIn highlights the Select and shows the tooltip
+func (s *tooltipSelect) MouseIn(e *desktop.MouseEvent) {
+	s.Select.MouseIn(e)
+	if text := s.text(); text != "" && s.cw.tooltipLayer != nil {
+		pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(s)
+		s.cw.showTooltip(text, pos, s.Size())
+	}
+}
+
+// MouseOut removes the highlight and the tooltip
+func (s *tooltipSelect) MouseOut() {
+	s.Select.MouseOut()
+	if s.cw.tooltipLayer != nil {
+		s.cw.hideTooltip()
+	}
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"chatgo/pkg/models"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// transcriptPreviewLength is the number of characters of each turn shown in the import preview
+const transcriptPreviewLength = 120
+
+// transcriptPasteHandler returns an OnPaste handler that offers to import pasted chat
+// transcripts as structured messages instead of inserting them into entry
+func (cw *ChatWindow) transcriptPasteHandler(entry *chatEntry) func(text string) bool {
+	return func(text string) bool {
+		messages, ok := models.ParseTranscript(text)
+		if !ok {
+			return false
+		}
+		cw.showTranscriptImportDialog(entry, text, messages)
+		return true
+	}
+}
+
+// showTranscriptImportDialog previews the detected turns and lets the user correct roles,
+// skip turns, or fall back to pasting the raw text
+func (cw *ChatWindow) showTranscriptImportDialog(entry *chatEntry, text string, messages []models.Message) {
+	roleSelects := make([]*widget.Select, len(messages))
+	includeChecks := make([]*widget.Check, len(messages))
+
+	rows := container.NewVBox()
+	for i, msg := range messages {
+		roleSelects[i] = widget.NewSelect([]string{"user", "assistant", "system"}, nil)
+		roleSelects[i].SetSelected(msg.Role)
+		includeChecks[i] = widget.NewCheck("", nil)
+		includeChecks[i].SetChecked(true)
+
+		preview := strings.Join(strings.Fields(msg.Content), " ")
+		if runes := []rune(preview); len(runes) > transcriptPreviewLength {
+			preview = string(runes[:transcriptPreviewLength]) + "…"
+		}
+		previewLabel := widget.NewLabel(preview)
+		previewLabel.Wrapping = fyne.TextWrapWord
+
+		rows.Add(container.NewBorder(nil, nil,
+			container.NewHBox(includeChecks[i], roleSelects[i]), nil, previewLabel))
+		rows.Add(widget.NewSeparator())
+	}
+
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(550, 350))
+
+	hint := widget.NewLabel(Translatef("transcript.detected", len(messages)))
+	content := container.NewBorder(hint, nil, nil, nil, scroll)
+
+	d := dialog.NewCustomWithoutButtons(Translate("transcript.title"), content, cw.window)
+
+	importBtn := widget.NewButton(Translate("transcript.import"), func() {
+		selected := make([]models.Message, 0, len(messages))
+		for i, msg := range messages {
+			if !includeChecks[i].Checked {
+				continue
+			}
+			msg.Role = roleSelects[i].Selected
+			selected = append(selected, msg)
+		}
+		d.Hide()
+		cw.importTranscript(selected)
+	})
+	importBtn.Importance = widget.HighImportance
+
+	pasteBtn := widget.NewButton(Translate("transcript.paste_as_text"), func() {
+		d.Hide()
+		entry.PasteText(text)
+	})
+
+	d.SetButtons([]fyne.CanvasObject{pasteBtn, importBtn})
+	d.Show()
+}
+
+// importTranscript appends the messages to the current conversation, or to a new one
+// when importing from the home page or the current conversation can't be written
+func (cw *ChatWindow) importTranscript(messages []models.Message) {
+	if len(messages) == 0 {
+		return
+	}
+
+	if cw.isHomeMode {
+		cw.switchToChatUI()
+		cw.createNewConversation()
+	} else if cw.currentConversation == nil || cw.currentConversation.ReadOnly() ||
+		cw.convManager.SavingPaused(cw.currentConversation.ID) {
+		cw.createNewConversation()
+	}
+	if cw.currentConversation == nil {
+		return
+	}
+
+	now := time.Now()
+	for _, msg := range messages {
+		msg.ID = models.NewMessageID()
+		msg.Timestamp = now
+		cw.currentConversation.Messages = append(cw.currentConversation.Messages, msg)
+		cw.addMessageToUI(msg)
+	}
+
+	if err := cw.saveConversation(cw.currentConversation); err != nil {
+		dialog.ShowError(fmt.Errorf("failed to save imported messages: %w", err), cw.window)
+	}
+	cw.chatArea.ScrollToBottom()
+}
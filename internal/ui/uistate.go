@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"chatgo/internal/paths"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+)
+
+// uiState is the window layout remembered between runs, saved in ui_state.json
+type uiState struct {
+	WindowWidth        float32 `json:"window_width,omitempty"` // Size of the main window when last closed
+	WindowHeight       float32 `json:"window_height,omitempty"`
+	SplitOffset        float64 `json:"split_offset,omitempty"`         // Position of the chat view's sidebar divider, from 0 to 1; 0 when never shown
+	LastConversationID string  `json:"last_conversation_id,omitempty"` // Conversation open when the window was closed; empty on the home page
+	SettingsWidth      float32 `json:"settings_width,omitempty"`       // Size of the settings window when last closed; 0 when never opened
+	SettingsHeight     float32 `json:"settings_height,omitempty"`
+}
+
+// defaultWindowSize is the size of the main window on the first run
+var defaultWindowSize = fyne.NewSize(1000, 700)
+
+// defaultSplitOffset is the share of the chat view's width given to the sidebar on the first run
+const defaultSplitOffset = 0.25
+
+// windowSize returns the remembered main window size, or the default on the first run
+func (s uiState) windowSize() fyne.Size {
+	if s.WindowWidth <= 0 || s.WindowHeight <= 0 {
+		return defaultWindowSize
+	}
+	return fyne.NewSize(s.WindowWidth, s.WindowHeight)
+}
+
+// splitOffset returns the remembered position of the sidebar divider, or the default
+func (s uiState) splitOffset() float64 {
+	if s.SplitOffset <= 0 || s.SplitOffset >= 1 {
+		return defaultSplitOffset
+	}
+	return s.SplitOffset
+}
+
+// settingsSize returns the remembered settings window size, or false when there is none
+func (s uiState) settingsSize() (fyne.Size, bool) {
+	if s.SettingsWidth <= 0 || s.SettingsHeight <= 0 {
+		return fyne.Size{}, false
+	}
+	return fyne.NewSize(s.SettingsWidth, s.SettingsHeight), true
+}
+
+// loadUIState reads the saved UI state. A missing or invalid file gives the zero state.
+func loadUIState() uiState {
+	var state uiState
+	path, err := paths.UIStateFile()
+	if err != nil {
+		return state
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Warn("Failed to read UI state", "path", path, "error", err)
+		}
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		logger.Warn("Ignoring invalid UI state", "path", path, "error", err)
+		return uiState{}
+	}
+	return state
+}
+
+// saveUIState replaces ui_state.json with state
+func saveUIState(state uiState) {
+	path, err := paths.UIStateFile()
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	var data []byte
+	if err == nil {
+		data, err = json.MarshalIndent(state, "", "  ")
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		logger.Warn("Failed to save UI state", "error", err)
+	}
+}
+
+// saveWindowState remembers the main window's size, the sidebar divider and the open
+// conversation for the next run. It is called as the window closes.
+func (cw *ChatWindow) saveWindowState() {
+	state := loadUIState()
+	size := cw.window.Canvas().Size()
+	state.WindowWidth, state.WindowHeight = size.Width, size.Height
+	if cw.split != nil {
+		state.SplitOffset = cw.split.Offset
+	}
+	// An incognito conversation leaves no trace, not even its ID
+	state.LastConversationID = ""
+	if !cw.isHomeMode && cw.currentConversation != nil && !cw.currentConversation.Incognito() {
+		state.LastConversationID = cw.currentConversation.ID
+	}
+	saveUIState(state)
+}
+
+// restoreConversation opens the chat view on the conversation that was open when the window
+// was last closed. The home page stays when there was none, or it was deleted or can no longer
+// be read.
+func (cw *ChatWindow) restoreConversation(id string) {
+	if id == "" || conversationIndex(cw.allConversations, id) < 0 {
+		return
+	}
+	conv, err := cw.convManager.LoadConversation(id)
+	if err != nil {
+		logger.Debug("Not reopening conversation", "id", id, "error", err)
+		return
+	}
+
+	cw.switchToChatUI()
+	cw.openConversation(conv)
+	cw.syncConversationSelection()
+}
@@ -0,0 +1,208 @@
+package models
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// archiveDir is the folder conversations are stored under in an archive
+const archiveDir = "conversations"
+
+// ExportAll writes every conversation to w as a zip archive holding each conversation's file
+// as it is stored, for moving them to another machine. Files that can't be read are skipped.
+func (cm *ConversationManager) ExportAll(w io.Writer) (int, error) {
+	entries, err := os.ReadDir(cm.dataDir)
+	if err != nil {
+		return 0, err
+	}
+
+	archive := zip.NewWriter(w)
+	count := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(cm.ConversationPath(strings.TrimSuffix(entry.Name(), ".json")))
+		if err != nil {
+			continue
+		}
+		if _, err := decodeConversation(data); err != nil {
+			continue
+		}
+
+		file, err := archive.Create(path.Join(archiveDir, entry.Name()))
+		if err != nil {
+			return count, err
+		}
+		if _, err := file.Write(data); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := archive.Close(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// ArchiveImport is the outcome of ImportAll
+type ArchiveImport struct {
+	Imported int // Conversations added
+	Skipped  int // Conversations already here, created at the same time
+	Renamed  int // Imported conversations given a new ID as theirs was taken by another conversation
+}
+
+// ImportAll adds the conversations of an archive written by ExportAll. A conversation that is
+// already here is skipped; one whose ID is taken by a different conversation is given a new
+// ID, and links to it from the other imported conversations are updated.
+func (cm *ConversationManager) ImportAll(r io.Reader) (ArchiveImport, error) {
+	var result ArchiveImport
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return result, err
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return result, fmt.Errorf("not a conversation archive: %w", err)
+	}
+
+	// Read every conversation first, so IDs can be settled before any is written
+	var conversations []archivedConversation
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+		data, err := readArchiveFile(file)
+		if err != nil {
+			return result, fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+		conv, err := decodeConversation(data)
+		if err != nil || conv.ID == "" {
+			return result, fmt.Errorf("%s is not a conversation", file.Name)
+		}
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return result, fmt.Errorf("%s is not a conversation", file.Name)
+		}
+		conversations = append(conversations, archivedConversation{doc: doc, conv: conv})
+	}
+
+	// Conversations are told apart by their creation time, which an earlier import kept even
+	// when it gave the conversation a new ID
+	existing, err := cm.ListConversations(ListOptions{IncludeArchived: true})
+	if err != nil {
+		return result, err
+	}
+	storedIDs := make(map[time.Time]string, len(existing))
+	for _, conv := range existing {
+		if !conv.CreatedAt.IsZero() {
+			storedIDs[conv.CreatedAt.UTC()] = conv.ID
+		}
+	}
+
+	renamed := make(map[string]string)
+	taken := make(map[string]bool)
+	pending := conversations[:0]
+	for _, a := range conversations {
+		// The ID names the conversation's file, so one that could point outside the data
+		// directory is never used for a path; the conversation is given a new ID instead
+		valid := validConversationID(a.conv.ID)
+		stored := false
+		if valid {
+			_, statErr := os.Stat(cm.ConversationPath(a.conv.ID))
+			stored = !os.IsNotExist(statErr)
+		}
+		switch id, ok := storedIDs[a.conv.CreatedAt.UTC()]; {
+		case ok && !a.conv.CreatedAt.IsZero():
+			if id != a.conv.ID {
+				renamed[a.conv.ID] = id
+			}
+			result.Skipped++
+			continue
+		case !valid || stored || taken[a.conv.ID]:
+			id := cm.unusedID(taken)
+			renamed[a.conv.ID] = id
+			a.conv.ID = id
+			result.Renamed++
+		}
+		taken[a.conv.ID] = true
+		pending = append(pending, a)
+	}
+
+	for _, a := range pending {
+		if err := a.setID(renamed); err != nil {
+			return result, err
+		}
+		data, err := json.MarshalIndent(a.doc, "", "  ")
+		if err != nil {
+			return result, err
+		}
+		if err := os.WriteFile(cm.ConversationPath(a.conv.ID), data, 0644); err != nil {
+			return result, err
+		}
+		result.Imported++
+		cm.notify(ConversationEvent{Kind: ConversationAdded, ID: a.conv.ID, Conversation: a.conv.snapshot()})
+	}
+	return result, nil
+}
+
+// archivedConversation is a conversation read from an archive, with its document as stored so
+// fields this version doesn't know are written back unchanged
+type archivedConversation struct {
+	doc  map[string]json.RawMessage
+	conv *Conversation
+}
+
+// setID writes the conversation's ID into its document, and the new ID of the conversation it
+// continues if that was renamed
+func (a archivedConversation) setID(renamed map[string]string) error {
+	id, err := json.Marshal(a.conv.ID)
+	if err != nil {
+		return err
+	}
+	a.doc["id"] = id
+
+	link := a.conv.ContextFrom
+	if link == nil || renamed[link.ID] == "" {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(a.doc["context_from"], &fields); err != nil {
+		return err
+	}
+	link.ID = renamed[link.ID]
+	if fields["id"], err = json.Marshal(link.ID); err != nil {
+		return err
+	}
+	a.doc["context_from"], err = json.Marshal(fields)
+	return err
+}
+
+// readArchiveFile reads a file from an archive
+func readArchiveFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// unusedID returns a new conversation ID that is neither stored nor in taken, which may be nil
+func (cm *ConversationManager) unusedID(taken map[string]bool) string {
+	base := generateID()
+	id := base
+	for n := 2; ; n++ {
+		if _, err := os.Stat(cm.ConversationPath(id)); os.IsNotExist(err) && !taken[id] {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// archiveOf returns a zip archive holding a conversation file for each document
+func archiveOf(t *testing.T, docs map[string]string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, doc := range docs {
+		file, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Write([]byte(doc)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestImportAllRenamesUnsafeIDs(t *testing.T) {
+	cm := newTestManager(t)
+	ids := []string{"../../escaped", "sub/dir", "..", "20240101120000/../../x"}
+
+	docs := make(map[string]string)
+	for i, id := range ids {
+		docs[filepath.Join(archiveDir, string(rune('a'+i))+".json")] = `{"schema_version": 1, "id": "` + id + `", "title": "t", "messages": [], "created_at": "2024-01-0` + string(rune('1'+i)) + `T00:00:00Z"}`
+	}
+	result, err := cm.ImportAll(archiveOf(t, docs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != len(ids) || result.Renamed != len(ids) {
+		t.Fatalf("got %+v, want all %d imported under new IDs", result, len(ids))
+	}
+
+	// Nothing may be written next to or above the data directory
+	if _, err := os.Stat(filepath.Join(cm.dataDir, "../../escaped.json")); !os.IsNotExist(err) {
+		t.Error("a conversation was written above the data directory")
+	}
+	parent := filepath.Dir(cm.dataDir)
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("files written outside the data directory: %v", entries)
+	}
+
+	conversations, err := cm.ListConversations(ListOptions{IncludeArchived: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, conv := range conversations {
+		if !validConversationID(conv.ID) {
+			t.Errorf("imported conversation kept unsafe ID %q", conv.ID)
+		}
+	}
+}
+
+func TestValidConversationID(t *testing.T) {
+	valid := []string{"20240101120000", "20240101120000-a1b2c3", "20240101120000-2", "20240101120000-a1b2c3-3"}
+	invalid := []string{"", "..", "../20240101120000", "20240101120000/x", `20240101120000\x`, "2024", "20240101120000-zzzzzz"}
+	for _, id := range valid {
+		if !validConversationID(id) {
+			t.Errorf("validConversationID(%q) = false, want true", id)
+		}
+	}
+	for _, id := range invalid {
+		if validConversationID(id) {
+			t.Errorf("validConversationID(%q) = true, want false", id)
+		}
+	}
+}
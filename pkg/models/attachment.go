@@ -0,0 +1,133 @@
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Attachment is a file sent along with a message: a text file included in the prompt, or an image
+type Attachment struct {
+	Name     string `json:"name"`
+	MimeType string `json:"mime"`
+	Content  string `json:"content,omitempty"` // Text of a text file
+	Path     string `json:"path,omitempty"`    // Where an image was attached from
+	Data     string `json:"data,omitempty"`    // Base64-encoded image
+}
+
+// imageMimeTypes are the image formats every multimodal provider accepts
+var imageMimeTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// ReadTextAttachment reads a text file for attaching to a message. Files larger than
+// maxBytes and files that are not UTF-8 text are rejected.
+func ReadTextAttachment(name string, r io.Reader, maxBytes int64) (Attachment, error) {
+	// Read one byte past the limit to tell a file of exactly maxBytes from a larger one
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return Attachment{}, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if int64(len(data)) > maxBytes {
+		return Attachment{}, fmt.Errorf("%s is larger than the attachment limit of %d KB", name, maxBytes/1024)
+	}
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return Attachment{}, fmt.Errorf("%s is not a text file; only text files can be attached", name)
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(name))
+	if mimeType == "" {
+		mimeType = "text/plain; charset=utf-8"
+	}
+
+	return Attachment{
+		Name:     name,
+		MimeType: mimeType,
+		Content:  string(data),
+	}, nil
+}
+
+// ReadImageAttachment reads a PNG, JPEG, GIF or WebP image for attaching to a message. The
+// format is detected from the content rather than the file name. Images larger than maxBytes
+// are rejected.
+func ReadImageAttachment(path string, r io.Reader, maxBytes int64) (Attachment, error) {
+	name := filepath.Base(path)
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return Attachment{}, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if int64(len(data)) > maxBytes {
+		return Attachment{}, fmt.Errorf("%s is larger than the image limit of %d MB", name, maxBytes/(1024*1024))
+	}
+
+	mimeType := http.DetectContentType(data)
+	if !imageMimeTypes[mimeType] {
+		return Attachment{}, fmt.Errorf("%s is not a PNG, JPEG, GIF or WebP image", name)
+	}
+
+	return Attachment{
+		Name:     name,
+		MimeType: mimeType,
+		Path:     path,
+		Data:     base64.StdEncoding.EncodeToString(data),
+	}, nil
+}
+
+// IsImage reports whether the attachment is an image rather than a text file
+func (a Attachment) IsImage() bool {
+	return strings.HasPrefix(a.MimeType, "image/")
+}
+
+// ImageBytes decodes an image attachment's data
+func (a Attachment) ImageBytes() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(a.Data)
+}
+
+// Fenced returns the attachment as a fenced code block preceded by its file name. The fence
+// is longer than any backtick run in the content so the block cannot be closed early.
+func (a Attachment) Fenced() string {
+	fence := "```"
+	for strings.Contains(a.Content, fence) {
+		fence += "`"
+	}
+	lang := strings.TrimPrefix(filepath.Ext(a.Name), ".")
+	return fmt.Sprintf("File: %s\n%s%s\n%s\n%s", a.Name, fence, lang, strings.TrimRight(a.Content, "\n"), fence)
+}
+
+// PromptContent is the message text sent to the model: the content followed by each attached
+// text file as a fenced block. Images are not part of the text; see Images.
+func (m Message) PromptContent() string {
+	if len(m.Attachments) == 0 {
+		return m.Content
+	}
+	parts := make([]string, 0, len(m.Attachments)+1)
+	if m.Content != "" {
+		parts = append(parts, m.Content)
+	}
+	for _, attachment := range m.Attachments {
+		if !attachment.IsImage() {
+			parts = append(parts, attachment.Fenced())
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// Images returns the images attached to the message
+func (m Message) Images() []Attachment {
+	var images []Attachment
+	for _, attachment := range m.Attachments {
+		if attachment.IsImage() {
+			images = append(images, attachment)
+		}
+	}
+	return images
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupTimeLayout is how the time a backup was made is written in its file name
+const backupTimeLayout = "20060102-150405"
+
+// Backup is a backup of the conversations, an archive in the format ExportAll writes
+type Backup struct {
+	Path string
+	Time time.Time
+	Size int64
+}
+
+// BackupsDir returns the directory backups are kept in
+func (cm *ConversationManager) BackupsDir() string {
+	return cm.backupDir
+}
+
+// ListBackups returns the backups, newest first
+func (cm *ConversationManager) ListBackups() ([]Backup, error) {
+	entries, err := os.ReadDir(cm.backupDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "conversations-") || !strings.HasSuffix(name, ".zip") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		// The time in the name survives copying the file, unlike its modification time
+		made, err := time.ParseInLocation(backupTimeLayout, strings.TrimSuffix(strings.TrimPrefix(name, "conversations-"), ".zip"), time.Local)
+		if err != nil {
+			made = info.ModTime()
+		}
+		backups = append(backups, Backup{Path: filepath.Join(cm.backupDir, name), Time: made, Size: info.Size()})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// BackupDue reports whether no backup has been made today
+func (cm *ConversationManager) BackupDue() (bool, error) {
+	backups, err := cm.ListBackups()
+	if err != nil || len(backups) == 0 {
+		return true, err
+	}
+	y, m, d := backups[0].Time.Date()
+	ty, tm, td := time.Now().Date()
+	return y != ty || m != tm || d != td, nil
+}
+
+// BackUp writes every conversation to a new backup and removes the oldest backups beyond the
+// newest keep. Deleted conversations in the trash are left out.
+func (cm *ConversationManager) BackUp(keep int) (Backup, error) {
+	if err := os.MkdirAll(cm.backupDir, 0755); err != nil {
+		return Backup{}, err
+	}
+
+	// Written under a temporary name so a backup cut short is never listed
+	made := time.Now()
+	path := filepath.Join(cm.backupDir, "conversations-"+made.Format(backupTimeLayout)+".zip")
+	file, err := os.CreateTemp(cm.backupDir, "backup-*.tmp")
+	if err != nil {
+		return Backup{}, err
+	}
+	_, err = cm.ExportAll(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return Backup{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Backup{}, err
+	}
+	backup := Backup{Path: path, Time: made, Size: info.Size()}
+
+	backups, err := cm.ListBackups()
+	if err != nil {
+		return backup, err
+	}
+	for _, old := range backups[min(max(keep, 1), len(backups)):] {
+		if err := os.Remove(old.Path); err != nil {
+			return backup, err
+		}
+	}
+	return backup, nil
+}
+
+// BackupConversations returns the conversations in a backup
+func (cm *ConversationManager) BackupConversations(path string) ([]Conversation, error) {
+	var conversations []Conversation
+	err := readBackup(path, func(conv *Conversation, data []byte) error {
+		conversations = append(conversations, *conv)
+		return nil
+	})
+	return conversations, err
+}
+
+// RestoreBackup puts the conversations in a backup back as they were then, replacing their
+// current versions; conversations created since are kept. With id set, only that conversation
+// is restored. It returns how many conversations were restored.
+func (cm *ConversationManager) RestoreBackup(path, id string) (int, error) {
+	restored := 0
+	err := readBackup(path, func(conv *Conversation, data []byte) error {
+		if id != "" && conv.ID != id {
+			return nil
+		}
+		if cm.SavingPaused(conv.ID) {
+			return ErrSavingPaused
+		}
+
+		target := cm.ConversationPath(conv.ID)
+		kind := ConversationUpdated
+		if _, err := os.Stat(target); os.IsNotExist(err) {
+			kind = ConversationAdded
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return err
+		}
+		restored++
+		cm.notify(ConversationEvent{Kind: kind, ID: conv.ID, Conversation: conv.snapshot()})
+		return nil
+	})
+	if err == nil && id != "" && restored == 0 {
+		err = fmt.Errorf("conversation %s is not in the backup", id)
+	}
+	return restored, err
+}
+
+// readBackup calls fn with each conversation in a backup and the file it was read from
+func readBackup(path string, fn func(conv *Conversation, data []byte) error) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("not a conversation backup: %w", err)
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+		data, err := readArchiveFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+		// The ID names the file a conversation is restored to, so one that could point outside
+		// the data directory is skipped
+		conv, err := decodeConversation(data)
+		if err != nil || !validConversationID(conv.ID) {
+			continue
+		}
+		if err := fn(conv, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,64 +1,241 @@
 package models
 
 import (
+	"chatgo/internal/paths"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
 // ToolCall represents a tool invocation
 type ToolCall struct {
-	ID       string                 `json:"id"`       // Unique identifier for this tool call
-	Name     string                 `json:"name"`     // Tool name
-	Arguments string                `json:"arguments"` // Tool arguments as JSON string
-	Result   string                 `json:"result"`   // Tool execution result
-	Error    string                 `json:"error,omitempty"` // Error message if tool call failed
-	Metadata map[string]interface{} `json:"metadata,omitempty"` // Additional metadata
+	ID         string                 `json:"id"`                    // Unique identifier for this tool call
+	Name       string                 `json:"name"`                  // Tool name
+	Arguments  string                 `json:"arguments"`             // Tool arguments as JSON string
+	Result     string                 `json:"result"`                // Tool execution result
+	Error      string                 `json:"error,omitempty"`       // Error message if tool call failed
+	DurationMs int64                  `json:"duration_ms,omitempty"` // How long the call took
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`    // Additional metadata
 }
 
 // Message represents a single message in a conversation
 type Message struct {
-	ID        string     `json:"id"`
-	Role      string     `json:"role"` // user, assistant, system
-	Content   string     `json:"content"`
-	Timestamp time.Time  `json:"timestamp"`
-	ToolCalls []ToolCall `json:"tool_calls,omitempty"` // Tool calls made by this message
+	ID          string       `json:"id"`
+	Role        string       `json:"role"` // user, assistant, system
+	Content     string       `json:"content"`
+	Timestamp   time.Time    `json:"timestamp"`
+	ToolCalls   []ToolCall   `json:"tool_calls,omitempty"`  // Tool calls made by this message
+	Attachments []Attachment `json:"attachments,omitempty"` // Text files and images sent with a user message
+
+	extra map[string]json.RawMessage // Fields written by newer versions, kept for round-trip
+}
+
+// messageFields is Message without its JSON methods
+type messageFields Message
+
+// UnmarshalJSON decodes a message and retains unknown fields
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var fields messageFields
+	extra, err := unmarshalWithExtra(data, &fields)
+	if err != nil {
+		return err
+	}
+	*m = Message(fields)
+	m.extra = extra
+	return nil
+}
+
+// MarshalJSON encodes a message including any retained unknown fields
+func (m Message) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(messageFields(m), m.extra)
 }
 
 // Conversation represents a chat conversation
 type Conversation struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Messages    []Message `json:"messages"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Provider    string    `json:"provider"`
-	Model       string    `json:"model"`
+	SchemaVersion int       `json:"schema_version"`
+	ID            string    `json:"id"`
+	Title         string    `json:"title"`
+	Summary       string    `json:"summary,omitempty"`
+	Messages      []Message `json:"messages"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Provider      string    `json:"provider"`
+	Model         string    `json:"model"`
+	Pinned        bool      `json:"pinned,omitempty"`   // Kept at the top of the sidebar
+	Tags          []string  `json:"tags,omitempty"`     // Labels for filtering the sidebar, e.g. "work"
+	Archived      bool      `json:"archived,omitempty"` // Hidden from the sidebar unless archived conversations are shown
+
+	ContextFrom    *ConversationLink `json:"context_from,omitempty"`    // Earlier conversation this one continues
+	TrimmedSummary *TrimmedSummary   `json:"trimmed_summary,omitempty"` // Summary of the oldest messages, sent in their place once they no longer fit the context
+
+	extra     map[string]json.RawMessage // Fields written by newer versions, kept for round-trip
+	readOnly  bool                       // Set when the file uses a newer schema than this build
+	incognito bool                       // Set for a conversation kept in memory only
+}
+
+// ConversationLink records the earlier conversation a conversation continues. The summary is
+// copied so the context stays the same when the earlier conversation changes or is deleted.
+type ConversationLink struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+}
+
+// ContextPrompt is the system prompt that gives the model the earlier conversation's summary
+func (l *ConversationLink) ContextPrompt() string {
+	return fmt.Sprintf("This conversation continues an earlier one titled %q. Summary of the earlier conversation:\n%s", l.Title, l.Summary)
 }
 
+// TrimmedSummary is a summary of a conversation's leading messages, which are left out of
+// requests to fit the model's context
+type TrimmedSummary struct {
+	Through       int    `json:"through"`         // Number of leading messages summarized
+	LastMessageID string `json:"last_message_id"` // ID of the last message summarized, to notice edits
+	Summary       string `json:"summary"`
+}
+
+// Covers reports whether the summary is of exactly the first n messages of conv
+func (s *TrimmedSummary) Covers(conv *Conversation, n int) bool {
+	return s != nil && s.Through == n && n > 0 && n <= len(conv.Messages) && conv.Messages[n-1].ID == s.LastMessageID
+}
+
+// ContextPrompt is the system prompt that gives the model the summary in place of the messages
+func (s *TrimmedSummary) ContextPrompt() string {
+	return fmt.Sprintf("The %d oldest messages of this conversation are left out to fit the context. Summary of them:\n%s", s.Through, s.Summary)
+}
+
+// conversationFields is Conversation without its JSON methods
+type conversationFields Conversation
+
+// UnmarshalJSON decodes a conversation and retains unknown fields
+func (c *Conversation) UnmarshalJSON(data []byte) error {
+	var fields conversationFields
+	extra, err := unmarshalWithExtra(data, &fields)
+	if err != nil {
+		return err
+	}
+	*c = Conversation(fields)
+	c.extra = extra
+	return nil
+}
+
+// MarshalJSON encodes a conversation including any retained unknown fields
+func (c Conversation) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(conversationFields(c), c.extra)
+}
+
+// ReadOnly reports whether the conversation was written by a newer app version
+// and must not be overwritten
+func (c *Conversation) ReadOnly() bool {
+	return c.readOnly
+}
+
+// HasTag reports whether the conversation is tagged with tag, ignoring case
+func (c *Conversation) HasTag(tag string) bool {
+	for _, t := range c.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAnyTag reports whether the conversation is tagged with any of tags, ignoring case
+func (c *Conversation) HasAnyTag(tags []string) bool {
+	for _, tag := range tags {
+		if c.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrSavingPaused is returned when saving a conversation whose file is being edited externally
+var ErrSavingPaused = errors.New("saving is paused while the conversation is open in an external editor")
+
 // ConversationManager manages conversation storage
 type ConversationManager struct {
-	dataDir string
+	dataDir   string
+	backupDir string
+
+	mu     sync.Mutex
+	paused map[string]bool // Conversation IDs whose saves are suspended
+
+	listenersMu    sync.Mutex
+	listeners      map[int]ConversationListener
+	nextListenerID int
 }
 
 // NewConversationManager creates a new conversation manager
 func NewConversationManager() (*ConversationManager, error) {
-	homeDir, err := os.UserHomeDir()
+	chatgoDir, err := paths.ConversationsDir()
 	if err != nil {
 		return nil, err
 	}
 
-	chatgoDir := filepath.Join(homeDir, ".chatgo", "conversations")
 	if err := os.MkdirAll(chatgoDir, 0755); err != nil {
 		return nil, err
 	}
+	backupDir, err := paths.BackupsDir()
+	if err != nil {
+		return nil, err
+	}
 
-	return &ConversationManager{dataDir: chatgoDir}, nil
+	return &ConversationManager{
+		dataDir:   chatgoDir,
+		backupDir: backupDir,
+		paused:    make(map[string]bool),
+		listeners: make(map[int]ConversationListener),
+	}, nil
 }
 
-// ListConversations returns all conversations
-func (cm *ConversationManager) ListConversations() ([]Conversation, error) {
+// DataDir returns the directory conversations are stored in
+func (cm *ConversationManager) DataDir() string {
+	return cm.dataDir
+}
+
+// ConversationPath returns the file a conversation is stored in
+func (cm *ConversationManager) ConversationPath(id string) string {
+	return filepath.Join(cm.dataDir, id+".json")
+}
+
+// PauseSaving suspends writes for a conversation, e.g. while it is edited externally
+func (cm *ConversationManager) PauseSaving(id string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.paused[id] = true
+}
+
+// ResumeSaving re-enables writes for a conversation paused with PauseSaving
+func (cm *ConversationManager) ResumeSaving(id string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	delete(cm.paused, id)
+}
+
+// SavingPaused reports whether writes are currently suspended for a conversation
+func (cm *ConversationManager) SavingPaused(id string) bool {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.paused[id]
+}
+
+// ListOptions selects the conversations ListConversations returns
+type ListOptions struct {
+	IncludeArchived bool // Also return archived conversations
+}
+
+// ListConversations returns all conversations, leaving out archived ones unless opts includes them
+func (cm *ConversationManager) ListConversations(opts ListOptions) ([]Conversation, error) {
 	entries, err := os.ReadDir(cm.dataDir)
 	if err != nil {
 		return nil, err
@@ -75,34 +252,95 @@ func (cm *ConversationManager) ListConversations() ([]Conversation, error) {
 			continue
 		}
 
-		var conv Conversation
-		if err := json.Unmarshal(data, &conv); err != nil {
+		conv, err := decodeConversation(data)
+		if err != nil || (conv.Archived && !opts.IncludeArchived) {
 			continue
 		}
 
-		conversations = append(conversations, conv)
+		conversations = append(conversations, *conv)
 	}
 
 	return conversations, nil
 }
 
-// LoadConversation loads a conversation by ID
-func (cm *ConversationManager) LoadConversation(id string) (*Conversation, error) {
-	data, err := os.ReadFile(filepath.Join(cm.dataDir, id+".json"))
+// ListTags returns the distinct tags used by the conversations opts selects, sorted
+func (cm *ConversationManager) ListTags(opts ListOptions) ([]string, error) {
+	conversations, err := cm.ListConversations(opts)
 	if err != nil {
 		return nil, err
 	}
+	return CollectTags(conversations), nil
+}
 
-	var conv Conversation
-	if err := json.Unmarshal(data, &conv); err != nil {
+// ListConversationsByTag returns the conversations opts selects that are tagged with tag,
+// ignoring case
+func (cm *ConversationManager) ListConversationsByTag(tag string, opts ListOptions) ([]Conversation, error) {
+	conversations, err := cm.ListConversations(opts)
+	if err != nil {
 		return nil, err
 	}
+	tagged := conversations[:0]
+	for _, conv := range conversations {
+		if conv.HasTag(tag) {
+			tagged = append(tagged, conv)
+		}
+	}
+	return tagged, nil
+}
 
-	return &conv, nil
+// CollectTags returns the distinct tags used by the given conversations, sorted
+func CollectTags(conversations []Conversation) []string {
+	var tags []string
+	for _, conv := range conversations {
+		tags = append(tags, conv.Tags...)
+	}
+	tags = NormalizeTags(tags)
+	sort.Slice(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i]) < strings.ToLower(tags[j])
+	})
+	return tags
+}
+
+// NormalizeTags trims tags and drops empty ones and duplicates, which are compared
+// ignoring case. The first spelling of a tag is kept.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// LoadConversation loads a conversation by ID
+func (cm *ConversationManager) LoadConversation(id string) (*Conversation, error) {
+	data, err := os.ReadFile(cm.ConversationPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeConversation(data)
 }
 
 // SaveConversation saves a conversation
 func (cm *ConversationManager) SaveConversation(conv *Conversation) error {
+	if conv.incognito {
+		return ErrIncognito
+	}
+	if conv.readOnly {
+		return ErrNewerSchema
+	}
+	if cm.SavingPaused(conv.ID) {
+		return ErrSavingPaused
+	}
+
+	conv.SchemaVersion = CurrentSchemaVersion
 	conv.UpdatedAt = time.Now()
 
 	data, err := json.MarshalIndent(conv, "", "  ")
@@ -110,24 +348,100 @@ func (cm *ConversationManager) SaveConversation(conv *Conversation) error {
 		return err
 	}
 
-	return os.WriteFile(filepath.Join(cm.dataDir, conv.ID+".json"), data, 0644)
+	path := cm.ConversationPath(conv.ID)
+	kind := ConversationUpdated
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		kind = ConversationAdded
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	cm.notify(ConversationEvent{Kind: kind, ID: conv.ID, Conversation: conv.snapshot()})
+	return nil
+}
+
+// ReassignProvider moves the conversations using the provider named from to the provider named
+// to, and to model when it isn't empty. Only those fields are rewritten, so the conversations
+// keep their place in the list and fields this version doesn't know. It returns how many
+// conversations were moved.
+func (cm *ConversationManager) ReassignProvider(from, to, model string) (int, error) {
+	conversations, err := cm.ListConversations(ListOptions{IncludeArchived: true})
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, conv := range conversations {
+		if conv.Provider != from {
+			continue
+		}
+		if cm.SavingPaused(conv.ID) {
+			return moved, ErrSavingPaused
+		}
+
+		path := cm.ConversationPath(conv.ID)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return moved, err
+		}
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return moved, fmt.Errorf("failed to read conversation %s: %w", conv.ID, err)
+		}
+		conv.Provider = to
+		if doc["provider"], err = json.Marshal(to); err != nil {
+			return moved, err
+		}
+		if model != "" {
+			conv.Model = model
+			if doc["model"], err = json.Marshal(model); err != nil {
+				return moved, err
+			}
+		}
+		if data, err = json.MarshalIndent(doc, "", "  "); err != nil {
+			return moved, err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return moved, err
+		}
+		moved++
+		cm.notify(ConversationEvent{Kind: ConversationUpdated, ID: conv.ID, Conversation: conv.snapshot()})
+	}
+	return moved, nil
 }
 
-// DeleteConversation deletes a conversation
+// DeleteConversation moves a conversation to the trash, from where PurgeTrash removes it
+// once it has been there for TrashRetention
 func (cm *ConversationManager) DeleteConversation(id string) error {
-	return os.Remove(filepath.Join(cm.dataDir, id+".json"))
+	if err := os.MkdirAll(cm.TrashDir(), 0755); err != nil {
+		return err
+	}
+	trashed := cm.trashPath(id)
+	if err := os.Rename(cm.ConversationPath(id), trashed); err != nil {
+		return err
+	}
+	// A moved file keeps its modification time, which PurgeTrash takes as the time it was deleted
+	now := time.Now()
+	if err := os.Chtimes(trashed, now, now); err != nil {
+		return err
+	}
+
+	cm.notify(ConversationEvent{Kind: ConversationRemoved, ID: id})
+	return nil
 }
 
 // CreateConversation creates a new conversation
 func (cm *ConversationManager) CreateConversation(title, provider, model string) (*Conversation, error) {
 	conv := &Conversation{
-		ID:        generateID(),
-		Title:     title,
-		Messages:  []Message{},
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-		Provider:  provider,
-		Model:     model,
+		SchemaVersion: CurrentSchemaVersion,
+		ID:            cm.unusedID(nil),
+		Title:         title,
+		Messages:      []Message{},
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+		Provider:      provider,
+		Model:         model,
 	}
 
 	if err := cm.SaveConversation(conv); err != nil {
@@ -137,6 +451,31 @@ func (cm *ConversationManager) CreateConversation(title, provider, model string)
 	return conv, nil
 }
 
+// generateID returns a new conversation ID: the time, so IDs sort by creation to the second,
+// and a random suffix telling apart conversations created within the same second
 func generateID() string {
-	return time.Now().Format("20060102150405")
+	return time.Now().Format("20060102150405") + "-" + randomHex(3)
+}
+
+// conversationIDPattern matches the IDs generateID and unusedID make: the time to the second,
+// the random suffix added later, and a counter unusedID appends on a collision
+var conversationIDPattern = regexp.MustCompile(`^[0-9]{14}(-[0-9a-f]{6})?(-[0-9]+)?$`)
+
+// validConversationID reports whether id has the form of a conversation ID, which keeps the
+// file it names inside the data directory
+func validConversationID(id string) bool {
+	return conversationIDPattern.MatchString(id)
+}
+
+// NewMessageID returns a new message ID: the time in nanoseconds and a random suffix, so
+// messages created together, such as a user message and the reply to it, don't share one
+func NewMessageID() string {
+	return fmt.Sprintf("%d-%s", time.Now().UnixNano(), randomHex(4))
+}
+
+// randomHex returns n random bytes in hex
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
 }
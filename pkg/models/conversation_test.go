@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestManager returns a manager storing conversations in a temporary directory
+func newTestManager(t *testing.T) *ConversationManager {
+	t.Helper()
+	dir := t.TempDir()
+	return &ConversationManager{
+		dataDir:   dir,
+		backupDir: dir + "-backups",
+		paused:    make(map[string]bool),
+		listeners: make(map[int]ConversationListener),
+	}
+}
+
+func TestIDsAreUniqueInATightLoop(t *testing.T) {
+	cm := newTestManager(t)
+	const n = 100
+
+	conversations := make(map[string]bool)
+	messages := make(map[string]bool)
+	for i := 0; i < n; i++ {
+		conv, err := cm.CreateConversation("Conversation", "OpenAI", "gpt-4")
+		if err != nil {
+			t.Fatalf("CreateConversation: %v", err)
+		}
+		if conversations[conv.ID] {
+			t.Fatalf("conversation ID %s was given out twice", conv.ID)
+		}
+		if !validConversationID(conv.ID) {
+			t.Errorf("conversation ID %s isn't a valid ID", conv.ID)
+		}
+		conversations[conv.ID] = true
+
+		// A user message and its reply are created together
+		for _, role := range []string{"user", "assistant"} {
+			msg := Message{ID: NewMessageID(), Role: role, Content: role, Timestamp: time.Now()}
+			if messages[msg.ID] {
+				t.Fatalf("message ID %s was given out twice", msg.ID)
+			}
+			messages[msg.ID] = true
+			conv.Messages = append(conv.Messages, msg)
+		}
+		if err := cm.SaveConversation(conv); err != nil {
+			t.Fatalf("SaveConversation: %v", err)
+		}
+	}
+
+	stored, err := cm.ListConversations(ListOptions{IncludeArchived: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != n {
+		t.Errorf("%d conversations are stored, want %d", len(stored), n)
+	}
+}
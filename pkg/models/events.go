@@ -0,0 +1,58 @@
+package models
+
+// ConversationEventKind tells what happened to a stored conversation
+type ConversationEventKind int
+
+const (
+	ConversationAdded ConversationEventKind = iota
+	ConversationUpdated
+	ConversationRemoved
+)
+
+// ConversationEvent describes a conversation saved or deleted through a ConversationManager
+type ConversationEvent struct {
+	Kind         ConversationEventKind
+	ID           string
+	Conversation Conversation // Copy of the saved conversation; empty for ConversationRemoved
+}
+
+// ConversationListener is called after a conversation is saved or deleted, on the goroutine that made the change
+type ConversationListener func(ConversationEvent)
+
+// Subscribe registers a listener for saved and deleted conversations and returns a function that removes it
+func (cm *ConversationManager) Subscribe(listener ConversationListener) (unsubscribe func()) {
+	cm.listenersMu.Lock()
+	defer cm.listenersMu.Unlock()
+
+	id := cm.nextListenerID
+	cm.nextListenerID++
+	cm.listeners[id] = listener
+
+	return func() {
+		cm.listenersMu.Lock()
+		defer cm.listenersMu.Unlock()
+		delete(cm.listeners, id)
+	}
+}
+
+// notify calls every listener with an event
+func (cm *ConversationManager) notify(event ConversationEvent) {
+	cm.listenersMu.Lock()
+	listeners := make([]ConversationListener, 0, len(cm.listeners))
+	for _, listener := range cm.listeners {
+		listeners = append(listeners, listener)
+	}
+	cm.listenersMu.Unlock()
+
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// snapshot copies a conversation so listeners don't share its slices with the caller
+func (c *Conversation) snapshot() Conversation {
+	copied := *c
+	copied.Messages = append([]Message(nil), c.Messages...)
+	copied.Tags = append([]string(nil), c.Tags...)
+	return copied
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UserMessagesMarkdown renders the user's messages of a conversation as markdown, in order,
+// under the conversation title and the title of the conversation it continues, if any. Numbered prefixes each message with its position among them;
+// otherwise messages are separated by horizontal rules.
+func UserMessagesMarkdown(conv *Conversation, numbered bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", conv.Title)
+	if conv.ContextFrom != nil {
+		fmt.Fprintf(&b, "\n> Continues: %s\n", conv.ContextFrom.Title)
+	}
+
+	n := 0
+	for _, msg := range conv.Messages {
+		if msg.Role != "user" {
+			continue
+		}
+		content := strings.TrimSpace(msg.Content)
+		if content == "" {
+			continue
+		}
+		n++
+		if numbered {
+			fmt.Fprintf(&b, "\n## %d\n\n%s\n", n, content)
+		} else {
+			if n > 1 {
+				b.WriteString("\n---\n")
+			}
+			fmt.Fprintf(&b, "\n%s\n", content)
+		}
+	}
+	return b.String()
+}
+
+// ConversationMarkdown renders a conversation as markdown under its title, each message after a
+// bold role marker such as "**User:**"
+func ConversationMarkdown(conv *Conversation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", conv.Title)
+	for _, msg := range conv.Messages {
+		content := strings.TrimSpace(msg.Content)
+		if content == "" {
+			continue
+		}
+		role := "Assistant"
+		switch msg.Role {
+		case "user":
+			role = "User"
+		case "system":
+			role = "System"
+		}
+		fmt.Fprintf(&b, "\n**%s:**\n\n%s\n", role, content)
+	}
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrIncognito is returned when saving an incognito conversation, which is kept in memory only
+var ErrIncognito = errors.New("incognito conversations are never saved")
+
+// NewIncognitoConversation creates a conversation that lives in memory only. SaveConversation
+// refuses it, so nothing of it is ever written under the data directory; it is gone once
+// dropped. Its ID doesn't clash with a stored conversation.
+func (cm *ConversationManager) NewIncognitoConversation(title, provider, model string) *Conversation {
+	return &Conversation{
+		SchemaVersion: CurrentSchemaVersion,
+		ID:            cm.unusedID(nil),
+		Title:         title,
+		Messages:      []Message{},
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+		Provider:      provider,
+		Model:         model,
+		incognito:     true,
+	}
+}
+
+// Incognito reports whether the conversation is kept in memory only and never saved
+func (c *Conversation) Incognito() bool {
+	return c.incognito
+}
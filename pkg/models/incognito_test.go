@@ -0,0 +1,64 @@
+package models
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIncognitoConversationIsNeverWritten(t *testing.T) {
+	cm := newTestManager(t)
+	events := 0
+	unsubscribe := cm.Subscribe(func(ConversationEvent) { events++ })
+	defer unsubscribe()
+
+	conv := cm.NewIncognitoConversation("Private", "OpenAI", "gpt-4")
+	if !conv.Incognito() {
+		t.Fatal("NewIncognitoConversation made a conversation that isn't incognito")
+	}
+	if err := cm.SaveConversation(conv); !errors.Is(err, ErrIncognito) {
+		t.Errorf("SaveConversation of a new incognito conversation = %v, want ErrIncognito", err)
+	}
+
+	conv.Title = "Renamed"
+	if err := cm.SaveConversation(conv); !errors.Is(err, ErrIncognito) {
+		t.Errorf("SaveConversation after renaming = %v, want ErrIncognito", err)
+	}
+
+	for _, role := range []string{"user", "assistant"} {
+		conv.Messages = append(conv.Messages, Message{ID: NewMessageID(), Role: role, Content: "secret", Timestamp: time.Now()})
+		if err := cm.SaveConversation(conv); !errors.Is(err, ErrIncognito) {
+			t.Errorf("SaveConversation after adding a %s message = %v, want ErrIncognito", role, err)
+		}
+	}
+
+	// Rewriting stored conversations doesn't reach it either
+	if _, err := cm.ReassignProvider("OpenAI", "Claude", ""); err != nil {
+		t.Fatalf("ReassignProvider: %v", err)
+	}
+
+	var written []string
+	err := filepath.WalkDir(cm.DataDir(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			written = append(written, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) > 0 {
+		t.Errorf("files were written for an incognito conversation: %v", written)
+	}
+	if events != 0 {
+		t.Errorf("listeners were told of %d changes to an incognito conversation", events)
+	}
+	if _, err := cm.LoadConversation(conv.ID); err == nil {
+		t.Error("the incognito conversation can be loaded from disk")
+	}
+}
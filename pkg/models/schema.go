@@ -0,0 +1,153 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CurrentSchemaVersion is the conversation file format version written by this build
+const CurrentSchemaVersion = 1
+
+// ErrNewerSchema is returned when saving a conversation that was written by a newer app version
+var ErrNewerSchema = errors.New("conversation was saved by a newer version of ChatGo and is read-only")
+
+// SchemaChange describes a single conversation file format version
+type SchemaChange struct {
+	Version     int
+	Description string
+}
+
+// SchemaChangelog lists every conversation file format version, oldest first
+var SchemaChangelog = []SchemaChange{
+	{Version: 0, Description: "Initial format without schema_version"},
+	{Version: 1, Description: "Added schema_version and round-trip preservation of unknown fields"},
+}
+
+// migration upgrades a raw conversation document from version N to N+1
+type migration func(doc map[string]json.RawMessage) error
+
+// migrations is indexed by the version being migrated from
+var migrations = []migration{
+	migrateV0ToV1,
+}
+
+// migrateV0ToV1 upgrades files written before schema_version existed.
+// The field layout is unchanged, so only the version needs to be stamped.
+func migrateV0ToV1(doc map[string]json.RawMessage) error {
+	doc["schema_version"] = json.RawMessage("1")
+	return nil
+}
+
+// migrateDocument runs every migration needed to bring doc up to CurrentSchemaVersion
+func migrateDocument(doc map[string]json.RawMessage) (int, error) {
+	version := 0
+	if raw, ok := doc["schema_version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return 0, fmt.Errorf("invalid schema_version: %w", err)
+		}
+	}
+
+	if version < 0 {
+		return 0, fmt.Errorf("invalid schema_version: %d", version)
+	}
+	if version > CurrentSchemaVersion {
+		return version, nil
+	}
+
+	for v := version; v < CurrentSchemaVersion; v++ {
+		if err := migrations[v](doc); err != nil {
+			return v, fmt.Errorf("failed to migrate conversation from v%d: %w", v, err)
+		}
+	}
+
+	return version, nil
+}
+
+// decodeConversation parses a conversation file, migrating older formats and
+// marking files from newer formats as read-only
+func decodeConversation(data []byte) (*Conversation, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	version, err := migrateDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var conv Conversation
+	if err := json.Unmarshal(migrated, &conv); err != nil {
+		return nil, err
+	}
+	conv.readOnly = version > CurrentSchemaVersion
+
+	return &conv, nil
+}
+
+// unmarshalWithExtra decodes data into v and returns the fields v does not declare
+func unmarshalWithExtra(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	for name := range knownJSONFields(reflect.TypeOf(v).Elem()) {
+		delete(all, name)
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+	return all, nil
+}
+
+// marshalWithExtra encodes v and merges back any fields retained by unmarshalWithExtra
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for name, raw := range extra {
+		if _, ok := all[name]; !ok {
+			all[name] = raw
+		}
+	}
+	return json.Marshal(all)
+}
+
+// knownJSONFields returns the JSON field names declared by a struct type
+func knownJSONFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = true
+	}
+	return fields
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// storeFixture copies a file from testdata into the manager's data directory and returns
+// the conversation ID it is stored under
+func storeFixture(t *testing.T, cm *ConversationManager, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cm.ConversationPath(doc.ID), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return doc.ID
+}
+
+// storedDocument reads a stored conversation file as raw JSON fields
+func storedDocument(t *testing.T, cm *ConversationManager, id string) map[string]json.RawMessage {
+	t.Helper()
+	data, err := os.ReadFile(cm.ConversationPath(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestMigrateV0ToV1(t *testing.T) {
+	cm := newTestManager(t)
+	id := storeFixture(t, cm, "v0.json")
+
+	conv, err := cm.LoadConversation(id)
+	if err != nil {
+		t.Fatalf("LoadConversation: %v", err)
+	}
+	if conv.SchemaVersion != 1 {
+		t.Errorf("schema version = %d, want 1", conv.SchemaVersion)
+	}
+	if conv.ReadOnly() {
+		t.Error("a v0 conversation is read-only")
+	}
+	if conv.Title != "Written before schema versions" || len(conv.Messages) != 1 || conv.Messages[0].Content != "Hello" {
+		t.Errorf("v0 conversation wasn't read intact: %+v", conv)
+	}
+
+	if err := cm.SaveConversation(conv); err != nil {
+		t.Fatalf("SaveConversation: %v", err)
+	}
+	if got := string(storedDocument(t, cm, id)["schema_version"]); got != "1" {
+		t.Errorf("saved schema_version = %s, want 1", got)
+	}
+}
+
+func TestUnknownFieldsSurviveSaving(t *testing.T) {
+	cm := newTestManager(t)
+	id := storeFixture(t, cm, "v1_unknown_fields.json")
+
+	conv, err := cm.LoadConversation(id)
+	if err != nil {
+		t.Fatalf("LoadConversation: %v", err)
+	}
+	conv.Title = "Renamed"
+	if err := cm.SaveConversation(conv); err != nil {
+		t.Fatalf("SaveConversation: %v", err)
+	}
+
+	doc := storedDocument(t, cm, id)
+	if got := string(doc["color"]); got != `"teal"` {
+		t.Errorf("saved color = %s, want \"teal\"", got)
+	}
+	if got := string(doc["title"]); got != `"Renamed"` {
+		t.Errorf("saved title = %s, want \"Renamed\"", got)
+	}
+	var messages []map[string]json.RawMessage
+	if err := json.Unmarshal(doc["messages"], &messages); err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("saved %d messages, want 1", len(messages))
+	}
+	var rating bytes.Buffer
+	if err := json.Compact(&rating, messages[0]["rating"]); err != nil {
+		t.Fatalf("message rating wasn't saved: %v", err)
+	}
+	if rating.String() != `{"score":5}` {
+		t.Errorf("saved message rating = %s, want {\"score\":5}", rating.String())
+	}
+}
+
+func TestNewerSchemaIsReadOnly(t *testing.T) {
+	cm := newTestManager(t)
+	id := storeFixture(t, cm, "v99_newer.json")
+	before, err := os.ReadFile(cm.ConversationPath(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conv, err := cm.LoadConversation(id)
+	if err != nil {
+		t.Fatalf("LoadConversation: %v", err)
+	}
+	if !conv.ReadOnly() {
+		t.Fatal("a conversation with a newer schema isn't read-only")
+	}
+	if conv.SchemaVersion != 99 {
+		t.Errorf("schema version = %d, want 99 as written", conv.SchemaVersion)
+	}
+
+	conv.Title = "Changed"
+	if err := cm.SaveConversation(conv); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("SaveConversation = %v, want ErrNewerSchema", err)
+	}
+	after, err := os.ReadFile(cm.ConversationPath(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("the file of a conversation with a newer schema was rewritten")
+	}
+}
+
+func TestNegativeSchemaVersionIsInvalid(t *testing.T) {
+	cm := newTestManager(t)
+	id := storeFixture(t, cm, "negative_version.json")
+
+	_, err := cm.LoadConversation(id)
+	if err == nil || !strings.Contains(err.Error(), "invalid schema_version") {
+		t.Errorf("LoadConversation = %v, want an invalid schema_version error", err)
+	}
+
+	// Listing skips the file rather than failing or crashing
+	if _, err := cm.ListConversations(ListOptions{IncludeArchived: true}); err != nil {
+		t.Errorf("ListConversations: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// transcriptMarker matches a role marker line such as "User: hi", "**Assistant:**" or ChatGPT's "You said:"
+var transcriptMarker = regexp.MustCompile(`(?i)^\s*(?:\*\*)?(user|you|human|me|assistant|chatgpt|gpt|ai|claude|gemini|bot|model|system)(?:\s+said)?(?:\*\*)?\s*:(?:\*\*)?\s?(.*)$`)
+
+// transcriptRoles maps marker names to message roles
+var transcriptRoles = map[string]string{
+	"user":   "user",
+	"you":    "user",
+	"human":  "user",
+	"me":     "user",
+	"system": "system",
+}
+
+// ParseTranscript splits a chat transcript copied from another tool into messages.
+// Turns start at role marker lines and may span multiple lines; marker-like lines inside
+// fenced code blocks are treated as content. It reports false when the text doesn't look
+// like a transcript, i.e. it has text before the first marker or lacks a user and an assistant turn.
+func ParseTranscript(text string) ([]Message, bool) {
+	var messages []Message
+	var current []string
+	fence := ""
+
+	flush := func() {
+		if len(messages) > 0 {
+			messages[len(messages)-1].Content = strings.TrimSpace(strings.Join(current, "\n"))
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if fence == "" {
+			if match := transcriptMarker.FindStringSubmatch(line); match != nil {
+				flush()
+				role, ok := transcriptRoles[strings.ToLower(match[1])]
+				if !ok {
+					role = "assistant"
+				}
+				messages = append(messages, Message{Role: role})
+				if match[2] != "" {
+					current = append(current, match[2])
+				}
+				continue
+			}
+			if len(messages) == 0 {
+				if trimmed != "" {
+					return nil, false
+				}
+				continue
+			}
+		}
+
+		// Track fenced code blocks so role markers inside them are kept as content
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if fence == "" {
+				fence = trimmed[:3]
+			} else if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	hasUser, hasAssistant := false, false
+	for _, msg := range messages {
+		switch msg.Role {
+		case "user":
+			hasUser = true
+		case "assistant":
+			hasAssistant = true
+		}
+	}
+	if len(messages) < 2 || !hasUser || !hasAssistant {
+		return nil, false
+	}
+
+	return messages, true
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TrashRetention is how long a deleted conversation is kept in the trash
+const TrashRetention = 30 * 24 * time.Hour
+
+// TrashDir returns the folder deleted conversations are kept in until they are purged. A
+// conversation is restored by moving its file back to DataDir.
+func (cm *ConversationManager) TrashDir() string {
+	return filepath.Join(cm.dataDir, "trash")
+}
+
+// trashPath returns the file a deleted conversation is kept in
+func (cm *ConversationManager) trashPath(id string) string {
+	return filepath.Join(cm.TrashDir(), id+".json")
+}
+
+// PurgeTrash permanently removes the conversations deleted longer than maxAge ago and returns
+// how many were removed
+func (cm *ConversationManager) PurgeTrash(maxAge time.Duration) (int, error) {
+	entries, err := os.ReadDir(cm.TrashDir())
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(cm.TrashDir(), entry.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}